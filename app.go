@@ -3,101 +3,67 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"image"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
+	"time"
 
+	"screenocr-wails/internal/archive"
+	"screenocr-wails/internal/config"
+	"screenocr-wails/internal/history"
 	"screenocr-wails/internal/hotkey"
 	"screenocr-wails/internal/ocr"
 	"screenocr-wails/internal/overlay"
+	"screenocr-wails/internal/redact"
 	"screenocr-wails/internal/screenshot"
+	"screenocr-wails/internal/stitch"
 	"screenocr-wails/internal/translator"
 	"screenocr-wails/internal/tray"
+	"screenocr-wails/internal/watch"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
-// Config 应用配置
-type Config struct {
-	TriggerDelayMs    int    `json:"trigger_delay_ms"`
-	Hotkey            string `json:"hotkey"`
-	AutoCopy          bool   `json:"auto_copy"`
-	ShowDebug         bool   `json:"show_debug"`
-	ImagePreprocess   bool   `json:"image_preprocess"`
-	OcrEngine         string `json:"ocr_engine"`
-	EnableTranslation bool   `json:"enable_translation"`
-	TranslationSource string `json:"translation_source"`
-	TranslationTarget string `json:"translation_target"`
-	TencentSecretId   string `json:"tencent_secret_id"`
-	TencentSecretKey  string `json:"tencent_secret_key"`
-	FirstRun          bool   `json:"first_run"`
-	ShowWelcome       bool   `json:"show_welcome"`
-	ShowStartupNotify bool   `json:"show_startup_notification"`
-}
-
 // App 应用结构
 type App struct {
 	ctx        context.Context
-	config     Config
+	config     config.Config
 	configPath string
 	mu         sync.RWMutex
 	enabled    bool
 	quitting   bool // 标记是否正在退出程序
 
 	// 组件
-	ocrEngine   ocr.Engine
-	screenshoot *screenshot.Capturer
+	ocrEngine   *ocr.LimitedEngine // 所有识别共用，按引擎声明的并发数排队
+	overlayOCR  *ocr.LimitedEngine // 热键识别专用：单路执行，连续触发时只保留最新请求
+	screenshoot screenshot.Capturer
+	layout      *screenshot.Layout // 最近一次截图的显示器布局
+	frame       image.Image        // 最近一次截图（区域识别从中裁剪）
+	source      string             // 最近一次截图的来源窗口，全屏截图时为空
+	scrollRec   *stitch.Recorder   // 进行中的滚动截图
+	scrollFrom  string             // 滚动截图的来源窗口
+	history     *history.Buffer    // 时光回溯截图缓冲，未开启时为 nil
+	historyOpen bool               // 正在覆盖层中回看历史帧
+	historyBack int                // 正在回看的历史帧（0 为最新的一帧）
 	hotkeyMgr   *hotkey.Manager
 	trayIcon    *tray.SystemTray
-	translator  *translator.TencentTranslator
+	translator  translator.Provider // 当前的翻译服务，保存配置时替换
+	redactor    *redact.Redactor    // 翻译和目录监视共用，保存配置时更新策略
 	overlay     *overlay.Overlay
 	popup       *overlay.TranslationPopup
 	welcome     *overlay.WelcomePage
+	watcher     *watch.Watcher
 }
 
 // NewApp 创建新应用实例
 func NewApp() *App {
 	return &App{
 		enabled: true,
-		config:  defaultConfig(),
-	}
-}
-
-// getConfigDir 获取配置文件目录
-func getConfigDir() string {
-	// 优先使用 APPDATA 环境变量
-	appData := os.Getenv("APPDATA")
-	if appData != "" {
-		return filepath.Join(appData, "ScreenOCR")
-	}
-	// 回退到用户主目录
-	home, err := os.UserHomeDir()
-	if err == nil {
-		return filepath.Join(home, ".screenocr")
-	}
-	// 最后回退到可执行文件目录
-	exe, _ := os.Executable()
-	return filepath.Dir(exe)
-}
-
-// defaultConfig 默认配置
-func defaultConfig() Config {
-	return Config{
-		TriggerDelayMs:    300,
-		Hotkey:            "alt",
-		AutoCopy:          true,
-		ShowDebug:         false,
-		ImagePreprocess:   false,
-		OcrEngine:         "windows",
-		EnableTranslation: true,
-		TranslationSource: "auto",
-		TranslationTarget: "zh",
-		TencentSecretId:   "",
-		TencentSecretKey:  "",
-		FirstRun:          true,
-		ShowWelcome:       true,
-		ShowStartupNotify: true,
+		config:  config.Default(),
 	}
 }
 
@@ -106,29 +72,34 @@ func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
 
 	// 获取配置文件路径（使用 %APPDATA%\ScreenOCR 目录）
-	configDir := getConfigDir()
+	configDir := config.Dir()
 	os.MkdirAll(configDir, 0755) // 确保目录存在
-	a.configPath = filepath.Join(configDir, "config.json")
+	a.configPath = filepath.Join(configDir, config.FileName)
 
 	// 加载配置
 	a.loadConfig()
 
+	// 敏感内容打码（审计日志写在配置目录中）
+	a.redactor = redact.New(a.config.Redaction, redact.NewAudit(filepath.Join(configDir, redact.AuditName)))
+
 	// 初始化 OCR 引擎
 	fmt.Println("正在初始化 OCR 引擎...")
 	a.initOCREngine()
 
 	// 初始化截图器
-	a.screenshoot = screenshot.NewCapturer()
+	capturer, err := screenshot.NewCapturer()
+	if err != nil {
+		fmt.Println("初始化截图器失败:", err)
+	}
+	a.screenshoot = capturer
 
-	// 初始化翻译器
-	a.translator = translator.NewTencentTranslator(
-		a.config.TencentSecretId,
-		a.config.TencentSecretKey,
-	)
+	// 初始化翻译服务
+	a.translator = newTranslator(a.config)
 
 	// 初始化覆盖层
 	a.overlay = overlay.NewOverlay()
 	a.overlay.OnTextSelected = a.onTextSelected
+	a.overlay.OnRegionSelected = a.onRegionSelected
 	a.overlay.OnClose = a.onOverlayClose
 
 	// 初始化翻译弹窗
@@ -139,6 +110,17 @@ func (a *App) startup(ctx context.Context) {
 	a.hotkeyMgr.OnTrigger = a.onHotkeyTriggered
 	a.hotkeyMgr.OnKeyRelease = a.onHotkeyReleased // 按键松开时关闭覆盖层
 	a.hotkeyMgr.OnEscape = a.onEscapePressed      // 全局 ESC 键关闭（与 Python 一致）
+	a.hotkeyMgr.OnRegion = a.onRegionHotkey       // 框选区域识别
+	a.hotkeyMgr.SetRegionHotkey(a.config.RegionHotkey)
+	a.hotkeyMgr.OnWindow = a.onWindowHotkey // 窗口识别
+	a.hotkeyMgr.SetWindowHotkey(a.config.WindowHotkey)
+	a.hotkeyMgr.OnScroll = a.onScrollHotkey // 滚动截图
+	a.hotkeyMgr.SetScrollHotkey(a.config.ScrollHotkey)
+	a.hotkeyMgr.OnHistory = a.onHistoryHotkey // 时光回溯
+	a.hotkeyMgr.OnArrow = a.onArrowKey        // 回看时左/右方向键切换历史帧
+	a.hotkeyMgr.SetHistoryHotkey(a.config.HistoryHotkey)
+	a.hotkeyMgr.OnArchive = a.onArchiveHotkey // 保存覆盖层中的截图
+	a.hotkeyMgr.SetArchiveHotkey(a.config.ArchiveHotkey)
 
 	// 初始化系统托盘
 	a.initTray()
@@ -146,6 +128,12 @@ func (a *App) startup(ctx context.Context) {
 	// 启动热键监听
 	go a.hotkeyMgr.Start()
 
+	// 监视截图目录
+	a.initWatcher()
+
+	// 时光回溯截图缓冲（默认关闭）
+	a.initHistory()
+
 	// 处理首次启动引导
 	a.handleStartupGuide()
 
@@ -200,6 +188,26 @@ func (a *App) shutdown(ctx context.Context) {
 		a.hotkeyMgr.Stop()
 	}
 
+	// 停止目录监视
+	if a.watcher != nil {
+		a.watcher.Stop()
+	}
+
+	// 停止时光回溯截图并释放缓冲
+	if a.history != nil {
+		a.history.Stop()
+	}
+
+	// 关闭 OCR 引擎
+	if a.overlayOCR != nil {
+		a.overlayOCR.Close()
+	}
+
+	// 释放截图器
+	if a.screenshoot != nil {
+		a.screenshoot.Close()
+	}
+
 	// 关闭覆盖层
 	if a.overlay != nil {
 		a.overlay.Close()
@@ -242,20 +250,38 @@ func (a *App) beforeClose(ctx context.Context) (prevent bool) {
 func (a *App) initOCREngine() {
 	engineName := a.config.OcrEngine
 	if engineName == "" {
-		engineName = "windows-native" // 默认值
+		engineName = "windows" // 默认值
+	}
+
+	engine := createOCREngine(engineName)
+
+	// 按书写系统路由：首轮引擎识别全图，再把区域交给更合适的引擎
+	if a.config.OcrRouting && engine != nil {
+		rules := a.config.OcrRouteRules
+		if len(rules) == 0 {
+			rules = ocr.DefaultRouteRules()
+		}
+		engine = ocr.NewRouterEngine(engine, engineName, rules, func(name string) (ocr.Engine, error) {
+			return ocr.New(name)
+		})
+		fmt.Printf("✓ 已启用 OCR 路由 (%d 条规则)\n", len(rules))
 	}
 
-	switch a.config.OcrEngine {
-	case "wechat":
-		// 使用 CGO 版本调用微信 OCR
-		// 注意：需要编译时启用 CGO 和 C 编译器
-		a.ocrEngine = ocr.NewWeChatOCRCGO()
-	case "windows-python":
-		// 保留 Python 版本作为备选（如果需要）
-		a.ocrEngine = ocr.NewWindowsOCR()
-	default:
-		// 默认使用 PowerShell 版本（无需 Python 依赖）
-		a.ocrEngine = ocr.NewWindowsOCRNative()
+	// 替换引擎前关闭旧引擎（等待其正在进行的识别结束）
+	if old := a.overlayOCR; old != nil {
+		go old.Close()
+	}
+	a.ocrEngine, a.overlayOCR = nil, nil
+
+	if engine != nil {
+		// 微信 OCR 通过全局 C 变量返回结果，不能并发调用：统一经过并发限制
+		a.ocrEngine = ocr.NewLimitedEngine(engine, ocr.LimitOptions{})
+		// 覆盖层在上一次识别结束前再次触发时，旧请求已没有意义，只保留最新的
+		a.overlayOCR = ocr.NewLimitedEngine(a.ocrEngine, ocr.LimitOptions{
+			MaxConcurrent: 1,
+			Coalesce:      true,
+			Timeout:       overlayOCRTimeout,
+		})
 	}
 
 	if a.ocrEngine != nil && a.ocrEngine.IsAvailable() {
@@ -270,6 +296,104 @@ func (a *App) initOCREngine() {
 	}
 }
 
+// overlayOCRTimeout 热键识别的最长等待时间
+const overlayOCRTimeout = 20 * time.Second
+
+// createOCREngine 按名称创建 OCR 引擎
+// wechat: CGO 调用微信 OCR（需要编译时启用 CGO 和 C 编译器）
+// windows-python: 保留 Python 版本作为备选
+// 其他: 默认使用 PowerShell 版本（无需 Python 依赖）
+func createOCREngine(name string) ocr.Engine {
+	engine, err := ocr.New(name)
+	if err != nil {
+		fmt.Printf("⚠ %v，使用默认引擎\n", err)
+		engine, _ = ocr.New("windows")
+	}
+	return engine
+}
+
+// initWatcher 按配置（重新）启动截图目录监视
+func (a *App) initWatcher() {
+	if a.watcher != nil {
+		a.watcher.Stop()
+		a.watcher = nil
+	}
+
+	a.mu.RLock()
+	dirs := a.config.WatchFolders
+	preprocess := a.config.ImagePreprocess
+	a.mu.RUnlock()
+
+	if len(dirs) == 0 || a.ocrEngine == nil {
+		return
+	}
+
+	a.watcher = watch.NewWatcher(a.ocrEngine, dirs, watch.Options{Preprocess: preprocess, Redactor: a.redactor})
+	a.watcher.OnResult = a.onWatchResult
+	a.watcher.Start()
+}
+
+// initHistory 按配置（重新）启动时光回溯截图缓冲，未开启时释放已有的截图
+func (a *App) initHistory() {
+	a.closeHistory()
+	if a.history != nil {
+		a.history.Stop()
+		a.history = nil
+	}
+
+	a.mu.RLock()
+	enabled := a.config.HistoryEnabled
+	opts := history.Options{
+		Interval:  time.Duration(a.config.HistoryIntervalMs) * time.Millisecond,
+		MaxFrames: a.config.HistoryFrames,
+		MaxBytes:  int64(a.config.HistoryMaxMB) << 20,
+	}
+	a.mu.RUnlock()
+
+	if !enabled || a.screenshoot == nil {
+		return
+	}
+
+	a.history = history.NewBuffer(a.screenshoot, opts)
+	a.history.Start()
+	fmt.Printf("[时光回溯] ✓ 已开启：每 %v 截图一次，最多 %d 帧\n", opts.Interval, opts.MaxFrames)
+}
+
+// onWatchResult 监视目录中的新截图识别完成（r.Text 已经打码）
+func (a *App) onWatchResult(r watch.Result) {
+	if r.Err != nil || r.Text == "" {
+		return
+	}
+
+	a.mu.RLock()
+	copyText := a.config.WatchCopy
+	translate := a.config.WatchTranslate
+	source := a.config.TranslationSource
+	target := a.config.TranslationTarget
+	a.mu.RUnlock()
+
+	if copyText {
+		if err := overlay.CopyToClipboard(r.Text); err != nil {
+			fmt.Println("[Watch] 复制到剪贴板失败:", err)
+		}
+	}
+
+	a.mu.RLock()
+	t := a.translator
+	a.mu.RUnlock()
+
+	if translate && t != nil && t.IsConfigured() {
+		result, err := t.Translate(r.Text, source, target)
+		if err != nil {
+			fmt.Println("[Watch] 翻译失败:", err)
+			return
+		}
+		if err := watch.WriteSidecar(r.Path, target, result); err != nil {
+			fmt.Println("[Watch]", err)
+		}
+	}
+}
+
 // initTray 初始化系统托盘
 func (a *App) initTray() {
 	a.trayIcon = tray.NewSystemTray()
@@ -299,22 +423,118 @@ func (a *App) onHotkeyTriggered() {
 	}
 
 	fmt.Println("热键触发，开始截图...")
+	a.closeHistory()
+
+	if a.screenshoot == nil {
+		fmt.Println("截图器不可用")
+		return
+	}
+
+	// 截图（同时记录显示器布局，覆盖层和翻译弹窗据此换算坐标）
+	img, layout, err := screenshot.CaptureLayout(a.screenshoot)
+	if err != nil {
+		fmt.Println("截图失败:", err)
+		return
+	}
+	a.mu.Lock()
+	a.layout, a.frame, a.source = layout, img, ""
+	a.mu.Unlock()
+
+	a.showAndRecognize(img, layout, "")
+}
 
-	// 截图
-	img, err := a.screenshoot.CaptureScreen()
+// onWindowHotkey 窗口识别热键回调：只截取并识别前台窗口（或鼠标下的窗口），覆盖层只覆盖该窗口
+func (a *App) onWindowHotkey() {
+	a.mu.RLock()
+	enabled := a.enabled
+	underCursor := a.config.WindowUnderCursor
+	a.mu.RUnlock()
+
+	if !enabled || a.screenshoot == nil {
+		return
+	}
+
+	a.closeHistory()
+	window, err := a.pickWindow(underCursor)
+	if err != nil {
+		fmt.Println("获取窗口失败:", err)
+		return
+	}
+	fmt.Printf("窗口识别热键触发，截取窗口: %s %v\n", window.Label(), window.Bounds)
+
+	img, layout, err := screenshot.CaptureWindow(a.screenshoot, window)
 	if err != nil {
 		fmt.Println("截图失败:", err)
 		return
 	}
+	a.mu.Lock()
+	a.layout, a.frame, a.source = layout, img, window.Label()
+	a.mu.Unlock()
+
+	a.showAndRecognize(img, layout, "")
+}
+
+// onScrollHotkey 滚动截图热键回调：第一次按下开始截取窗口，用户滚动页面，再次按下时拼接并识别
+func (a *App) onScrollHotkey() {
+	a.mu.RLock()
+	enabled := a.enabled
+	recording := a.scrollRec != nil
+	underCursor := a.config.WindowUnderCursor
+	autoCopy := a.config.AutoCopy
+	a.mu.RUnlock()
+
+	if !enabled || a.screenshoot == nil {
+		return
+	}
 
+	if !recording {
+		// 开始时不显示通知，避免通知窗口被截进长图
+		source, err := a.StartScrollCapture(underCursor)
+		if err != nil {
+			fmt.Println("开始滚动截图失败:", err)
+			overlay.NewStartupToast().ShowMessage("滚动截图失败", err.Error())
+			return
+		}
+		fmt.Printf("开始滚动截图: %s，滚动页面后再次按热键结束，ESC 取消\n", source)
+		return
+	}
+
+	result, err := a.StopScrollCapture()
+	if err != nil {
+		fmt.Println("滚动截图失败:", err)
+		overlay.NewStartupToast().ShowMessage("滚动截图失败", err.Error())
+		return
+	}
+	message := fmt.Sprintf("长图 %d 像素，识别到 %d 个文本块", result.Height, len(result.Blocks))
+	if autoCopy && result.Text != "" {
+		if err := overlay.CopyToClipboard(result.Text); err != nil {
+			fmt.Println("复制到剪贴板失败:", err)
+		} else {
+			message += "，已复制"
+		}
+	}
+	overlay.NewStartupToast().ShowInfo("✓ 滚动截图完成", message)
+}
+
+// pickWindow 前台窗口，underCursor 为 true 时为鼠标下的窗口
+func (a *App) pickWindow(underCursor bool) (screenshot.Window, error) {
+	if underCursor {
+		return screenshot.WindowUnderCursor(a.screenshoot)
+	}
+	return screenshot.ForegroundWindow(a.screenshoot)
+}
+
+// showAndRecognize 显示等待状态的覆盖层，异步识别截图后显示结果；caption 不为空时显示在覆盖层顶部
+func (a *App) showAndRecognize(img image.Image, layout *screenshot.Layout, caption string) {
 	// 显示等待状态的覆盖层
-	if err := a.overlay.ShowWaiting(img); err != nil {
+	if err := a.overlay.ShowCaptioned(img, layout, caption); err != nil {
 		fmt.Println("显示覆盖层失败:", err)
 		return
 	}
 
 	// OCR 识别
-	if a.ocrEngine == nil || !a.ocrEngine.IsAvailable() {
+	engine := a.overlayOCR
+	if engine == nil || !engine.IsAvailable() {
 		fmt.Println("OCR 引擎不可用")
 		a.overlay.Hide()
 		return
@@ -323,20 +543,227 @@ func (a *App) onHotkeyTriggered() {
 	// 异步执行 OCR
 	go func() {
 		fmt.Println("开始 OCR 识别...")
-		results, err := a.ocrEngine.Recognize(img, a.config.ImagePreprocess)
+		results, err := engine.Recognize(img, a.config.ImagePreprocess)
+		if errors.Is(err, ocr.ErrSuperseded) {
+			// 已有更新的识别请求，由它更新覆盖层
+			fmt.Println("OCR 请求已被新的请求取代")
+			return
+		}
 		if err != nil {
 			fmt.Println("OCR 识别失败:", err)
 			a.overlay.Hide()
 			return
 		}
 
-		fmt.Printf("识别到 %d 个文本块\n", len(results))
+		a.mu.RLock()
+		source := a.source
+		a.mu.RUnlock()
+		if source != "" {
+			fmt.Printf("识别到 %d 个文本块（来源窗口: %s）\n", len(results), source)
+		} else {
+			fmt.Printf("识别到 %d 个文本块\n", len(results))
+		}
 
 		// 更新覆盖层显示结果
 		a.overlay.UpdateResults(results)
 	}()
 }
 
+// onRegionHotkey 区域识别热键回调：冻结当前屏幕，等待用户框选识别区域
+func (a *App) onRegionHotkey() {
+	a.mu.RLock()
+	enabled := a.enabled
+	a.mu.RUnlock()
+
+	if !enabled || a.screenshoot == nil {
+		return
+	}
+
+	fmt.Println("区域识别热键触发，开始截图...")
+	a.closeHistory()
+	img, layout, err := screenshot.CaptureLayout(a.screenshoot)
+	if err != nil {
+		fmt.Println("截图失败:", err)
+		return
+	}
+	a.mu.Lock()
+	a.layout, a.frame, a.source = layout, img, ""
+	a.mu.Unlock()
+
+	if err := a.overlay.ShowRegionSelect(img, layout); err != nil {
+		fmt.Println("显示覆盖层失败:", err)
+	}
+}
+
+// onRegionSelected 用户框选识别区域后只识别该区域，结果映射回整张截图的坐标
+func (a *App) onRegionSelected(rect image.Rectangle) {
+	a.mu.RLock()
+	frame := a.frame
+	preprocess := a.config.ImagePreprocess
+	a.mu.RUnlock()
+
+	engine := a.overlayOCR
+	if frame == nil || engine == nil || !engine.IsAvailable() {
+		fmt.Println("OCR 引擎不可用")
+		a.overlay.Hide()
+		return
+	}
+
+	fmt.Printf("开始识别区域 %v...\n", rect)
+	results, err := ocr.RecognizeRegion(engine, frame, rect, preprocess)
+	if errors.Is(err, ocr.ErrSuperseded) {
+		fmt.Println("OCR 请求已被新的请求取代")
+		return
+	}
+	if err != nil {
+		fmt.Println("区域识别失败:", err)
+		a.overlay.Hide()
+		return
+	}
+
+	fmt.Printf("区域内识别到 %d 个文本块\n", len(results))
+	a.overlay.UpdateResults(results)
+}
+
+// cancelScrollCapture 取消进行中的滚动截图（丢弃已截取的内容）
+func (a *App) cancelScrollCapture() {
+	a.mu.Lock()
+	rec := a.scrollRec
+	a.scrollRec = nil
+	a.mu.Unlock()
+
+	if rec != nil {
+		rec.Stop()
+		fmt.Println("滚动截图已取消")
+	}
+}
+
+// onHistoryHotkey 时光回溯热键回调：暂停截图，在覆盖层中显示最新的一帧，左/右方向键切换，再次按下热键或 ESC 关闭
+func (a *App) onHistoryHotkey() {
+	a.mu.RLock()
+	enabled := a.enabled
+	buf := a.history
+	open := a.historyOpen
+	a.mu.RUnlock()
+
+	if !enabled {
+		return
+	}
+	if open {
+		a.closeHistory()
+		a.overlay.Hide()
+		return
+	}
+	if buf == nil {
+		overlay.NewStartupToast().ShowMessage("时光回溯未开启", "请在设置中开启时光回溯后再使用")
+		return
+	}
+	if buf.Len() == 0 {
+		overlay.NewStartupToast().ShowMessage("时光回溯", "还没有保存的截图，请稍后再试")
+		return
+	}
+
+	// 回看期间暂停截图，避免截到覆盖层本身
+	buf.SetPaused(true)
+	a.mu.Lock()
+	a.historyOpen = true
+	a.mu.Unlock()
+	a.hotkeyMgr.SetArrowKeys(true)
+	a.showHistoryFrame(0)
+}
+
+// onArrowKey 回看历史帧时切换：左方向键为更早的一帧，右方向键为更新的一帧
+func (a *App) onArrowKey(step int) {
+	a.mu.RLock()
+	open := a.historyOpen
+	buf := a.history
+	back := a.historyBack - step
+	a.mu.RUnlock()
+
+	// 已经是最早/最新的一帧时不再重新识别
+	if !open || buf == nil || back < 0 || back >= buf.Len() {
+		return
+	}
+	a.showHistoryFrame(back)
+}
+
+// showHistoryFrame 在覆盖层中显示第 back 帧历史截图并识别（超出范围时取最近的一帧）
+func (a *App) showHistoryFrame(back int) {
+	a.mu.RLock()
+	buf := a.history
+	open := a.historyOpen
+	a.mu.RUnlock()
+	if buf == nil || !open {
+		return
+	}
+
+	n := buf.Len()
+	back = max(0, min(back, n-1))
+	frame, err := buf.Frame(back)
+	if err != nil {
+		fmt.Println("[时光回溯] ❌", err)
+		return
+	}
+
+	a.mu.Lock()
+	a.historyBack = back
+	a.layout, a.frame, a.source = frame.Layout, frame.Image, ""
+	a.mu.Unlock()
+
+	ago := time.Since(frame.Time).Round(time.Second)
+	caption := fmt.Sprintf("⏪ %v 前（%d/%d）  ← 更早  → 更新  ESC 关闭", ago, n-back, n)
+	fmt.Printf("[时光回溯] 显示 %v 前的截图（%d/%d）\n", ago, n-back, n)
+	a.showAndRecognize(frame.Image, frame.Layout, caption)
+}
+
+// closeHistory 结束回看并恢复截图（没有在回看时什么也不做）
+func (a *App) closeHistory() {
+	a.mu.Lock()
+	open := a.historyOpen
+	buf := a.history
+	a.historyOpen = false
+	a.historyBack = 0
+	a.mu.Unlock()
+
+	if open && buf != nil {
+		buf.SetPaused(false)
+	}
+	if open && a.hotkeyMgr != nil {
+		a.hotkeyMgr.SetArrowKeys(false)
+	}
+}
+
+// onArchiveHotkey 保存截图热键回调：把覆盖层中的截图（选中了文字或框选了区域时只保存该部分）和识别结果保存到存档
+func (a *App) onArchiveHotkey() {
+	img, blocks, selection, ok := a.overlay.Snapshot()
+	if !ok {
+		fmt.Println("覆盖层没有显示识别结果，忽略保存截图热键")
+		return
+	}
+
+	a.mu.RLock()
+	source := a.source
+	opts := a.config.Archive
+	a.mu.RUnlock()
+
+	path, err := a.archiveCapture(img, blocks, selection, source, opts)
+	if err != nil {
+		fmt.Println("保存截图失败:", err)
+		overlay.NewStartupToast().ShowMessage("保存截图失败", err.Error())
+		return
+	}
+	fmt.Println("✓ 截图已保存:", path)
+	overlay.NewStartupToast().ShowInfo("✓ 截图已保存", filepath.Base(path))
+}
+
+// archiveCapture 按打码策略处理后裁剪出选中部分并保存到存档，返回截图路径
+func (a *App) archiveCapture(img image.Image, blocks []ocr.TextBlock, selection image.Rectangle, source string, opts archive.Options) (string, error) {
+	// 先在整张截图上打码（文本块坐标相对于整张截图），再裁剪
+	img, blocks = a.redactor.Page(img, blocks, "archive", source)
+	img, blocks = archive.Crop(img, blocks, selection)
+	return archive.Save(archive.Capture{Time: time.Now(), Source: source, Image: img, Blocks: blocks}, opts)
+}
+
 // onTextSelected 文字选中回调
 func (a *App) onTextSelected(text string, x, y int) {
 	fmt.Printf("选中文字: %s\n", text)
@@ -346,22 +773,41 @@ func (a *App) onTextSelected(text string, x, y int) {
 	enableTranslation := a.config.EnableTranslation
 	translationSource := a.config.TranslationSource
 	translationTarget := a.config.TranslationTarget
+	layout := a.layout
+	source := a.source
+	t := a.translator
 	a.mu.RUnlock()
 
-	if !enableTranslation || a.translator == nil {
+	if !enableTranslation || t == nil {
 		return
 	}
 
-	// 显示翻译弹窗
+	// 显示翻译弹窗（限制在选中位置所在显示器的可用区域内）
 	if a.popup != nil {
-		a.popup.Show(text, x, y)
+		var workArea image.Rectangle
+		if layout != nil {
+			if m := layout.MonitorAt(image.Pt(x, y)); m >= 0 {
+				workArea = layout.Monitors[m].WorkArea
+			}
+		}
+		a.popup.Show(text, x, y, workArea)
+
+		// 大模型翻译时附带选中部分附近的文字，帮助理解按钮、菜单等短文本
+		nearby := ""
+		if a.overlay != nil {
+			if _, blocks, selection, ok := a.overlay.Snapshot(); ok {
+				nearby = ocr.NearbyText(blocks, selection, maxContextRunes)
+			}
+		}
 
-		// 异步翻译（使用本地变量副本，避免并发问题）
+		// 异步翻译（使用本地变量副本，避免并发问题）；流式输出时逐步显示译文
 		go func() {
-			result, err := a.translator.Translate(
-				text,
+			result, err := translator.TranslateWithContext(t,
+				a.redactor.Text(text, "translate", source),
+				a.redactor.Text(nearby, "translate", source),
 				translationSource,
 				translationTarget,
+				a.popup.UpdateTranslation,
 			)
 			if err != nil {
 				a.popup.ShowError(err.Error())
@@ -372,6 +818,9 @@ func (a *App) onTextSelected(text string, x, y int) {
 	}
 }
 
+// maxContextRunes 翻译时附带的附近文字的最大字数
+const maxContextRunes = 600
+
 // onOverlayClose 覆盖层关闭回调
 func (a *App) onOverlayClose() {
 	fmt.Println("覆盖层已关闭")
@@ -383,6 +832,7 @@ func (a *App) onOverlayClose() {
 // onHotkeyReleased 热键松开回调 - 关闭覆盖层和翻译窗口（与 Python cleanup_windows 一致）
 func (a *App) onHotkeyReleased() {
 	fmt.Println("热键松开，关闭覆盖层和翻译窗口")
+	a.closeHistory()
 	if a.overlay != nil {
 		a.overlay.Hide()
 	}
@@ -395,6 +845,8 @@ func (a *App) onHotkeyReleased() {
 // onEscapePressed 全局 ESC 键回调（与 Python cleanup_windows 一致）
 func (a *App) onEscapePressed() {
 	fmt.Println("ESC 键按下，关闭覆盖层和翻译窗口")
+	a.cancelScrollCapture()
+	a.closeHistory()
 	if a.overlay != nil {
 		a.overlay.Hide()
 	}
@@ -411,11 +863,29 @@ func (a *App) loadConfig() {
 		return
 	}
 
-	if err := json.Unmarshal(data, &a.config); err != nil {
-		fmt.Println("解析配置文件失败:", err)
+	cfg, migrated, err := config.Parse(data)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	a.config = cfg
+	if migrated {
+		fmt.Println("✓ 已把腾讯云凭证迁移到 translators.tencent")
+		if err := a.saveConfig(); err != nil {
+			fmt.Println("保存迁移后的配置失败:", err)
+		}
 	}
 }
 
+// newTranslator 按配置创建翻译服务，未知的服务回退到默认服务
+func newTranslator(cfg config.Config) translator.Provider {
+	t, err := cfg.NewTranslator()
+	if err != nil {
+		fmt.Println("创建翻译服务失败:", err)
+	}
+	return t
+}
+
 // saveConfig 保存配置文件
 func (a *App) saveConfig() error {
 	a.mu.RLock()
@@ -432,66 +902,327 @@ func (a *App) saveConfig() error {
 // ======== 暴露给前端的方法 ========
 
 // GetConfig 获取配置
-func (a *App) GetConfig() Config {
+func (a *App) GetConfig() config.Config {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
 	return a.config
 }
 
 // SaveConfig 保存配置
-func (a *App) SaveConfig(cfg Config) error {
+func (a *App) SaveConfig(cfg config.Config) error {
+	// 热键包含不支持的按键时不保存，否则按下其余的键就会触发
+	for _, h := range []string{cfg.Hotkey, cfg.RegionHotkey, cfg.WindowHotkey, cfg.ScrollHotkey, cfg.HistoryHotkey, cfg.ArchiveHotkey} {
+		if err := hotkey.Validate(h); err != nil {
+			return err
+		}
+	}
+
 	a.mu.Lock()
-	oldEngine := a.config.OcrEngine
-	
+	// 引擎、路由开关或路由规则变化时重新创建引擎
+	engineChanged := a.config.OcrEngine != cfg.OcrEngine ||
+		a.config.OcrRouting != cfg.OcrRouting ||
+		!slices.Equal(a.config.OcrRouteRules, cfg.OcrRouteRules)
+	// 监视目录的选项在创建时确定，变化时重启目录监视
+	watchChanged := !slices.Equal(a.config.WatchFolders, cfg.WatchFolders) ||
+		a.config.WatchCopy != cfg.WatchCopy ||
+		a.config.WatchTranslate != cfg.WatchTranslate ||
+		a.config.ImagePreprocess != cfg.ImagePreprocess
+	historyChanged := a.config.HistoryEnabled != cfg.HistoryEnabled ||
+		a.config.HistoryIntervalMs != cfg.HistoryIntervalMs ||
+		a.config.HistoryFrames != cfg.HistoryFrames ||
+		a.config.HistoryMaxMB != cfg.HistoryMaxMB
+
 	// 合并配置，保留不在 UI 中显示的字段（避免被覆盖）
 	// 只更新 UI 中可配置的字段
 	a.config.TriggerDelayMs = cfg.TriggerDelayMs
 	a.config.Hotkey = cfg.Hotkey
+	a.config.RegionHotkey = cfg.RegionHotkey
+	a.config.WindowHotkey = cfg.WindowHotkey
+	a.config.WindowUnderCursor = cfg.WindowUnderCursor
+	a.config.ScrollHotkey = cfg.ScrollHotkey
 	a.config.AutoCopy = cfg.AutoCopy
 	a.config.ShowDebug = cfg.ShowDebug
 	a.config.ImagePreprocess = cfg.ImagePreprocess
 	a.config.OcrEngine = cfg.OcrEngine
+	a.config.OcrRouting = cfg.OcrRouting
+	a.config.OcrRouteRules = cfg.OcrRouteRules
+	a.config.WatchFolders = cfg.WatchFolders
+	a.config.WatchCopy = cfg.WatchCopy
+	a.config.WatchTranslate = cfg.WatchTranslate
 	a.config.EnableTranslation = cfg.EnableTranslation
 	a.config.TranslationSource = cfg.TranslationSource
 	a.config.TranslationTarget = cfg.TranslationTarget
-	a.config.TencentSecretId = cfg.TencentSecretId
-	a.config.TencentSecretKey = cfg.TencentSecretKey
+	a.config.TranslationProvider = cfg.TranslationProvider
+	a.config.Translators = cfg.Translators
+	a.config.Redaction = cfg.Redaction
+	a.config.HistoryEnabled = cfg.HistoryEnabled
+	a.config.HistoryHotkey = cfg.HistoryHotkey
+	a.config.HistoryIntervalMs = cfg.HistoryIntervalMs
+	a.config.HistoryFrames = cfg.HistoryFrames
+	a.config.HistoryMaxMB = cfg.HistoryMaxMB
+	a.config.ArchiveHotkey = cfg.ArchiveHotkey
+	a.config.Archive = cfg.Archive
 	// 保留原有值，不覆盖（这些字段不在 UI 中显示）
 	// a.config.FirstRun 保持不变
 	// a.config.ShowWelcome 保持不变
 	// a.config.ShowStartupNotify 保持不变
-	
+
+	// 按新的服务和设置重新创建翻译服务
+	a.translator = newTranslator(a.config)
 	a.mu.Unlock()
 
-	// 更新翻译器凭证
-	if a.translator != nil {
-		a.translator.SetCredentials(cfg.TencentSecretId, cfg.TencentSecretKey)
+	// 更新打码策略
+	if a.redactor != nil {
+		a.redactor.SetPolicy(cfg.Redaction)
 	}
 
 	// 更新热键
 	if a.hotkeyMgr != nil {
 		a.hotkeyMgr.UpdateHotkey(cfg.Hotkey, cfg.TriggerDelayMs)
+		a.hotkeyMgr.SetRegionHotkey(cfg.RegionHotkey)
+		a.hotkeyMgr.SetWindowHotkey(cfg.WindowHotkey)
+		a.hotkeyMgr.SetScrollHotkey(cfg.ScrollHotkey)
+		a.hotkeyMgr.SetHistoryHotkey(cfg.HistoryHotkey)
+		a.hotkeyMgr.SetArchiveHotkey(cfg.ArchiveHotkey)
+	}
+
+	// 开启、关闭或按新的间隔和上限重启时光回溯（关闭时立即释放已保存的截图）
+	if historyChanged {
+		a.initHistory()
 	}
 
-	// 更新 OCR 引擎
-	if oldEngine != cfg.OcrEngine {
+	// 更新 OCR 引擎（目录监视使用同一个引擎，需要一起重启）
+	if engineChanged {
 		a.initOCREngine()
 	}
+	if engineChanged || watchChanged {
+		a.initWatcher()
+	}
 
 	return a.saveConfig()
 }
 
-// Translate 翻译文本
+// Translate 翻译文本（发送前按策略打码敏感内容）
 func (a *App) Translate(text string) (string, error) {
-	if a.translator == nil {
+	a.mu.RLock()
+	t := a.translator
+	source := a.config.TranslationSource
+	target := a.config.TranslationTarget
+	a.mu.RUnlock()
+
+	if t == nil {
 		return "", fmt.Errorf("翻译器未初始化")
 	}
 
-	return a.translator.Translate(
-		text,
-		a.config.TranslationSource,
-		a.config.TranslationTarget,
-	)
+	return t.Translate(a.redactor.Text(text, "translate", ""), source, target)
+}
+
+// GetTranslators 已注册的翻译服务及各自需要填写的设置
+func (a *App) GetTranslators() []translator.Info {
+	return translator.Providers()
+}
+
+// GetTranslationUsage 当前翻译服务本计费周期的用量；服务不支持查询或未配置时返回 nil
+func (a *App) GetTranslationUsage() (*translator.Usage, error) {
+	a.mu.RLock()
+	t := a.translator
+	a.mu.RUnlock()
+
+	reporter, ok := t.(translator.UsageReporter)
+	if !ok || !t.IsConfigured() {
+		return nil, nil
+	}
+	usage, err := reporter.Usage()
+	if err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
+
+// RecognizeRegion 截取并识别虚拟屏幕坐标中的矩形区域，返回的文本块使用虚拟屏幕坐标
+func (a *App) RecognizeRegion(x, y, width, height int) ([]ocr.TextBlock, error) {
+	if a.screenshoot == nil {
+		return nil, fmt.Errorf("截图器不可用")
+	}
+	if a.ocrEngine == nil || !a.ocrEngine.IsAvailable() {
+		return nil, fmt.Errorf("OCR 引擎不可用")
+	}
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("无效的识别区域: %dx%d", width, height)
+	}
+
+	// 先裁剪到屏幕范围，截图左上角才能对应回虚拟屏幕坐标
+	monitors, err := a.screenshoot.Monitors()
+	if err != nil {
+		return nil, err
+	}
+	rect := image.Rect(x, y, x+width, y+height).Intersect(screenshot.VirtualBounds(monitors))
+	if rect.Empty() {
+		return nil, fmt.Errorf("识别区域不在屏幕范围内")
+	}
+	img, err := a.screenshoot.CaptureRect(rect)
+	if err != nil {
+		return nil, fmt.Errorf("截图失败: %w", err)
+	}
+
+	a.mu.RLock()
+	preprocess := a.config.ImagePreprocess
+	a.mu.RUnlock()
+
+	blocks, err := a.ocrEngine.Recognize(img, preprocess)
+	if err != nil {
+		return nil, err
+	}
+	ocr.OffsetBlocks(blocks, rect.Min.X, rect.Min.Y)
+	return blocks, nil
+}
+
+// WindowResult 窗口识别结果，坐标均为虚拟屏幕坐标
+type WindowResult struct {
+	Title   string          `json:"title"`
+	Process string          `json:"process"`
+	PID     int             `json:"pid"`
+	X       int             `json:"x"`
+	Y       int             `json:"y"`
+	Width   int             `json:"width"`
+	Height  int             `json:"height"`
+	Blocks  []ocr.TextBlock `json:"blocks"`
+}
+
+// RecognizeWindow 截取并识别前台窗口（underCursor 为 true 时为鼠标下的窗口），结果附带窗口标题、进程和范围
+func (a *App) RecognizeWindow(underCursor bool) (WindowResult, error) {
+	if a.screenshoot == nil {
+		return WindowResult{}, fmt.Errorf("截图器不可用")
+	}
+	if a.ocrEngine == nil || !a.ocrEngine.IsAvailable() {
+		return WindowResult{}, fmt.Errorf("OCR 引擎不可用")
+	}
+
+	window, err := a.pickWindow(underCursor)
+	if err != nil {
+		return WindowResult{}, fmt.Errorf("获取窗口失败: %w", err)
+	}
+	img, layout, err := screenshot.CaptureWindow(a.screenshoot, window)
+	if err != nil {
+		return WindowResult{}, fmt.Errorf("截图失败: %w", err)
+	}
+
+	a.mu.RLock()
+	preprocess := a.config.ImagePreprocess
+	a.mu.RUnlock()
+
+	blocks, err := a.ocrEngine.Recognize(img, preprocess)
+	if err != nil {
+		return WindowResult{}, err
+	}
+	bounds := layout.Bounds
+	ocr.OffsetBlocks(blocks, bounds.Min.X, bounds.Min.Y)
+	return WindowResult{
+		Title:   window.Title,
+		Process: window.Process,
+		PID:     window.PID,
+		X:       bounds.Min.X,
+		Y:       bounds.Min.Y,
+		Width:   bounds.Dx(),
+		Height:  bounds.Dy(),
+		Blocks:  blocks,
+	}, nil
+}
+
+// ScrollResult 滚动截图的识别结果，文本块坐标相对于拼接后的长图
+type ScrollResult struct {
+	Source   string          `json:"source"`   // 来源窗口
+	Width    int             `json:"width"`    // 长图宽度
+	Height   int             `json:"height"`   // 长图高度
+	Rejected int             `json:"rejected"` // 滚动过快被丢弃的帧数
+	Text     string          `json:"text"`     // 按阅读顺序拼接的文字
+	Blocks   []ocr.TextBlock `json:"blocks"`
+}
+
+// StartScrollCapture 开始滚动截图：定时截取前台窗口（underCursor 为 true 时为鼠标下的窗口），
+// 用户滚动页面，调用 StopScrollCapture 结束；返回窗口的来源标签
+func (a *App) StartScrollCapture(underCursor bool) (string, error) {
+	if a.screenshoot == nil {
+		return "", fmt.Errorf("截图器不可用")
+	}
+	window, err := a.pickWindow(underCursor)
+	if err != nil {
+		return "", fmt.Errorf("获取窗口失败: %w", err)
+	}
+
+	rec := stitch.NewRecorder(func() (image.Image, error) {
+		img, _, err := screenshot.CaptureWindow(a.screenshoot, window)
+		return img, err
+	}, 0, stitch.Options{})
+	rec.OnFrame = func(added, height int) {
+		fmt.Printf("[滚动截图] +%d 行，共 %d 行\n", added, height)
+	}
+
+	a.mu.Lock()
+	if a.scrollRec != nil {
+		a.mu.Unlock()
+		return "", fmt.Errorf("滚动截图已在进行中")
+	}
+	a.scrollRec, a.scrollFrom = rec, window.Label()
+	a.mu.Unlock()
+
+	if err := rec.Start(); err != nil {
+		a.mu.Lock()
+		a.scrollRec = nil
+		a.mu.Unlock()
+		return "", err
+	}
+	return window.Label(), nil
+}
+
+// StopScrollCapture 结束滚动截图，拼接后的长图分块识别，重叠部分的文字只保留一份
+func (a *App) StopScrollCapture() (ScrollResult, error) {
+	a.mu.Lock()
+	rec, source := a.scrollRec, a.scrollFrom
+	a.scrollRec = nil
+	preprocess := a.config.ImagePreprocess
+	a.mu.Unlock()
+
+	if rec == nil {
+		return ScrollResult{}, fmt.Errorf("滚动截图没有开始")
+	}
+	img, rejected, err := rec.Stop()
+	if img == nil {
+		return ScrollResult{}, err
+	}
+	if err != nil {
+		// 截图失败或超过最大高度时录制提前停止，已拼接的部分照常识别
+		fmt.Println("⚠ 滚动截图提前停止:", err)
+	}
+	if rejected > 0 {
+		fmt.Printf("⚠ %d 帧与上一帧没有重叠（滚动过快），已丢弃\n", rejected)
+	}
+
+	if a.ocrEngine == nil || !a.ocrEngine.IsAvailable() {
+		return ScrollResult{}, fmt.Errorf("OCR 引擎不可用")
+	}
+	fmt.Printf("[滚动截图] 拼接完成 %dx%d，开始识别...\n", img.Rect.Dx(), img.Rect.Dy())
+	blocks, err := stitch.Recognize(a.ocrEngine, img, preprocess)
+	if err != nil {
+		return ScrollResult{}, err
+	}
+
+	return ScrollResult{
+		Source:   source,
+		Width:    img.Rect.Dx(),
+		Height:   img.Rect.Dy(),
+		Rejected: rejected,
+		Text:     ocr.JoinText(blocks),
+		Blocks:   blocks,
+	}, nil
+}
+
+// GetOCRStats 获取 OCR 队列统计
+func (a *App) GetOCRStats() ocr.LimitStats {
+	if a.ocrEngine == nil {
+		return ocr.LimitStats{}
+	}
+	return a.ocrEngine.Stats()
 }
 
 // IsEnabled 获取服务状态
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"screenocr-wails/internal/cli"
+	"screenocr-wails/internal/overlay"
+)
+
+// isCLICommand 检查参数是否是命令行子命令
+func isCLICommand(arg string) bool {
+	return cli.IsCommand(arg)
+}
+
+// runCLI 以命令行模式运行（不启动界面和热键），返回进程退出码
+func runCLI(args []string) int {
+	attachParentConsole()
+	cli.CopyToClipboard = overlay.CopyToClipboard
+	return cli.Run(args)
+}
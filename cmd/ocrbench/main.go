@@ -0,0 +1,107 @@
+// ocrbench 评测 OCR 引擎在标注测试集上的准确率和耗时
+//
+// 用法:
+//
+//	ocrbench -data testdata/ocr -engines windows,wechat -preprocess both -out report.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"screenocr-wails/internal/bench"
+	"screenocr-wails/internal/ocr"
+)
+
+func main() {
+	dataDir := flag.String("data", "", "测试集目录（图片 + 同名 .txt/.json 标注）")
+	engines := flag.String("engines", "", "要评测的引擎，逗号分隔（默认全部已注册引擎）")
+	preprocess := flag.String("preprocess", "off", "图像预处理: off / on / both")
+	runs := flag.Int("runs", 1, "每张图片识别次数（用于统计耗时）")
+	iou := flag.Float64("iou", 0.5, "文本框匹配的 IoU 阈值")
+	out := flag.String("out", "", "JSON 报告输出路径（默认不输出）")
+	flag.Parse()
+
+	if *dataDir == "" {
+		fmt.Fprintln(os.Stderr, "请使用 -data 指定测试集目录")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var preprocessModes []bool
+	switch *preprocess {
+	case "off":
+		preprocessModes = []bool{false}
+	case "on":
+		preprocessModes = []bool{true}
+	case "both":
+		preprocessModes = []bool{false, true}
+	default:
+		fmt.Fprintf(os.Stderr, "无效的 -preprocess 值: %s\n", *preprocess)
+		os.Exit(2)
+	}
+
+	names := ocr.Names()
+	if *engines != "" {
+		names = strings.Split(*engines, ",")
+	}
+	if len(names) == 0 {
+		fmt.Fprintln(os.Stderr, "当前平台没有可用的 OCR 引擎")
+		os.Exit(1)
+	}
+
+	samples, err := bench.LoadDataset(*dataDir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Printf("[Bench] 加载 %d 个样本\n", len(samples))
+
+	var reports []bench.Report
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		engine, err := ocr.New(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			continue
+		}
+		if !engine.IsAvailable() {
+			fmt.Fprintf(os.Stderr, "引擎 %s 不可用: %s\n", name, engine.GetError())
+			engine.Close()
+			continue
+		}
+
+		for _, mode := range preprocessModes {
+			reports = append(reports, bench.Run(name, engine, samples, bench.Options{
+				Preprocess:   mode,
+				Runs:         *runs,
+				IoUThreshold: *iou,
+			}))
+		}
+		engine.Close()
+	}
+
+	if len(reports) == 0 {
+		fmt.Fprintln(os.Stderr, "没有完成任何评测")
+		os.Exit(1)
+	}
+
+	fmt.Println()
+	bench.WriteTable(os.Stdout, reports)
+
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "创建报告文件失败:", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		if err := bench.WriteJSON(f, reports); err != nil {
+			fmt.Fprintln(os.Stderr, "写入报告失败:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("\n✓ JSON 报告已保存: %s\n", *out)
+	}
+}
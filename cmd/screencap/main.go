@@ -0,0 +1,101 @@
+// screencap 使用当前平台的截图器截图并保存为 PNG，用于检查截图后端（Linux 可在 Xvfb 下运行）
+//
+// 用法:
+//
+//	screencap -list
+//	screencap -monitor 0 monitor.png
+//	screencap -rect 100,100,800,600 region.png
+//	screencap -window foreground window.png
+//	xvfb-run -s "-screen 0 1920x1080x24" screencap screen.png
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+
+	"screenocr-wails/internal/screenshot"
+)
+
+func main() {
+	list := flag.Bool("list", false, "列出显示器")
+	monitor := flag.Int("monitor", -1, "只截取第 N 个显示器")
+	rect := flag.String("rect", "", "截取区域 x,y,宽,高（虚拟屏幕坐标）")
+	window := flag.String("window", "", "截取窗口: foreground（前台窗口）/ cursor（鼠标下的窗口）")
+	flag.Parse()
+
+	capturer, err := screenshot.NewCapturer()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer capturer.Close()
+
+	if *list {
+		monitors, err := capturer.Monitors()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		for _, m := range monitors {
+			primary := ""
+			if m.Primary {
+				primary = " (主显示器)"
+			}
+			fmt.Printf("%d\t%s\t%v\t工作区 %v\t%d%%%s\n", m.Index, m.Name, m.Bounds, m.WorkArea, int(m.Scale()*100), primary)
+		}
+		return
+	}
+
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "请指定输出的 PNG 文件")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var img image.Image
+	switch {
+	case *rect != "":
+		var x, y, w, h int
+		if _, err := fmt.Sscanf(*rect, "%d,%d,%d,%d", &x, &y, &w, &h); err != nil {
+			fmt.Fprintf(os.Stderr, "无效的 -rect: %s\n", *rect)
+			os.Exit(2)
+		}
+		img, err = capturer.CaptureRect(image.Rect(x, y, x+w, y+h))
+	case *window != "":
+		var w screenshot.Window
+		if *window == "cursor" {
+			w, err = screenshot.WindowUnderCursor(capturer)
+		} else {
+			w, err = screenshot.ForegroundWindow(capturer)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "获取窗口失败:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("窗口: %s (pid %d) %v\n", w.Label(), w.PID, w.Bounds)
+		img, _, err = screenshot.CaptureWindow(capturer, w)
+	case *monitor >= 0:
+		img, err = capturer.CaptureMonitor(*monitor)
+	default:
+		img, err = capturer.CaptureScreen()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "截图失败:", err)
+		os.Exit(1)
+	}
+
+	f, err := os.Create(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		fmt.Fprintln(os.Stderr, "保存失败:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✓ 已保存 %dx%d 截图: %s\n", img.Bounds().Dx(), img.Bounds().Dy(), flag.Arg(0))
+}
@@ -1,16 +1,41 @@
 // Wails 运行时绑定
-const { GetConfig, SaveConfig, Translate, HideWindow } = window.go?.main?.App || {};
+const { GetConfig, SaveConfig, Translate, HideWindow, GetTranslators, GetTranslationUsage } = window.go?.main?.App || {};
+
+// 绑定不可用时（如直接在浏览器中打开）使用的翻译服务说明
+const fallbackTranslators = [{
+    name: 'tencent',
+    label: '腾讯云翻译',
+    fields: [
+        { key: 'secret_id', label: 'SecretId', placeholder: '请输入 SecretId' },
+        { key: 'secret_key', label: 'SecretKey', placeholder: '请输入 SecretKey', secret: true },
+    ],
+    link: 'https://console.cloud.tencent.com/cam/capi',
+}];
 
 // DOM 元素
 const elements = {
     delaySlider: document.getElementById('delaySlider'),
     delayValue: document.getElementById('delayValue'),
     hotkeyBtn: document.getElementById('hotkeyBtn'),
+    regionHotkeyBtn: document.getElementById('regionHotkeyBtn'),
+    windowHotkeyBtn: document.getElementById('windowHotkeyBtn'),
+    windowUnderCursor: document.getElementById('windowUnderCursor'),
+    scrollHotkeyBtn: document.getElementById('scrollHotkeyBtn'),
     ocrEngineRadios: document.querySelectorAll('input[name="ocrEngine"]'),
     enableTranslation: document.getElementById('enableTranslation'),
     targetLang: document.getElementById('targetLang'),
-    secretId: document.getElementById('secretId'),
-    secretKey: document.getElementById('secretKey'),
+    translationProvider: document.getElementById('translationProvider'),
+    providerFields: document.getElementById('providerFields'),
+    apiHint: document.getElementById('apiHint'),
+    usageHint: document.getElementById('usageHint'),
+    redactEnabled: document.getElementById('redactEnabled'),
+    redactImage: document.getElementById('redactImage'),
+    historyEnabled: document.getElementById('historyEnabled'),
+    historyHotkeyBtn: document.getElementById('historyHotkeyBtn'),
+    archiveHotkeyBtn: document.getElementById('archiveHotkeyBtn'),
+    archiveDir: document.getElementById('archiveDir'),
+    archiveFormat: document.getElementById('archiveFormat'),
+    archiveRetention: document.getElementById('archiveRetention'),
     autoCopy: document.getElementById('autoCopy'),
     imagePreprocess: document.getElementById('imagePreprocess'),
     showDebug: document.getElementById('showDebug'),
@@ -22,6 +47,11 @@ const elements = {
 // 当前配置
 let currentConfig = {};
 
+// 翻译服务说明，以及各服务的设置（切换服务时保留已填写的内容）
+let translators = fallbackTranslators;
+let providerSettings = {};
+let shownProvider = '';
+
 function setTranslationUIEnabled(enabled) {
     const section = document.getElementById('translationSection');
     const apiSection = document.getElementById('apiSection');
@@ -32,8 +62,8 @@ function setTranslationUIEnabled(enabled) {
 
     const toToggle = [
         elements.targetLang,
-        elements.secretId,
-        elements.secretKey,
+        elements.translationProvider,
+        ...elements.providerFields.querySelectorAll('input, textarea, select'),
         apiSection,
     ];
 
@@ -63,9 +93,22 @@ async function init() {
 // 加载配置
 async function loadConfig() {
     try {
+        if (GetTranslators) {
+            const list = await GetTranslators();
+            if (list?.length) translators = list;
+        }
+        elements.translationProvider.innerHTML = '';
+        translators.forEach((info) => {
+            const option = document.createElement('option');
+            option.value = info.name;
+            option.textContent = info.label;
+            elements.translationProvider.appendChild(option);
+        });
+
         if (GetConfig) {
             currentConfig = await GetConfig();
             applyConfigToUI(currentConfig);
+            refreshUsage();
         }
     } catch (err) {
         console.error('加载配置失败:', err);
@@ -80,6 +123,10 @@ function applyConfigToUI(config) {
 
     // 快捷键
     elements.hotkeyBtn.textContent = (config.hotkey || 'ALT').toUpperCase();
+    showHotkey(elements.regionHotkeyBtn, config.region_hotkey ?? 'ctrl+shift+2');
+    showHotkey(elements.windowHotkeyBtn, config.window_hotkey);
+    elements.windowUnderCursor.checked = config.window_under_cursor === true;
+    showHotkey(elements.scrollHotkeyBtn, config.scroll_hotkey);
 
     // OCR 引擎
     elements.ocrEngineRadios.forEach(radio => {
@@ -89,11 +136,30 @@ function applyConfigToUI(config) {
     // 翻译设置
     elements.enableTranslation.checked = config.enable_translation !== false;
     elements.targetLang.value = config.translation_target || 'zh';
-    elements.secretId.value = config.tencent_secret_id || '';
-    elements.secretKey.value = config.tencent_secret_key || '';
+    providerSettings = structuredClone(config.translators || {});
+    shownProvider = '';
+    elements.translationProvider.value = config.translation_provider || 'tencent';
+    if (!elements.translationProvider.value) {
+        elements.translationProvider.value = translators[0].name;
+    }
+    renderProviderFields(elements.translationProvider.value);
 
     setTranslationUIEnabled(elements.enableTranslation.checked);
 
+    // 敏感内容打码
+    elements.redactEnabled.checked = config.redaction?.enabled !== false;
+    elements.redactImage.checked = config.redaction?.mask_image !== false;
+
+    // 时光回溯（默认关闭）
+    elements.historyEnabled.checked = config.history_enabled === true;
+    showHotkey(elements.historyHotkeyBtn, config.history_hotkey);
+
+    // 截图存档
+    showHotkey(elements.archiveHotkeyBtn, config.archive_hotkey);
+    elements.archiveDir.value = config.archive?.dir || '';
+    elements.archiveFormat.value = config.archive?.format === 'jpeg' ? 'jpeg' : 'png';
+    elements.archiveRetention.value = config.archive?.retention_days || '';
+
     // 其他选项
     elements.autoCopy.checked = config.auto_copy !== false;
     elements.imagePreprocess.checked = config.image_preprocess === true;
@@ -110,12 +176,41 @@ function getConfigFromUI() {
     return {
         trigger_delay_ms: parseInt(elements.delaySlider.value),
         hotkey: elements.hotkeyBtn.textContent.toLowerCase(),
+        region_hotkey: readHotkey(elements.regionHotkeyBtn),
+        window_hotkey: readHotkey(elements.windowHotkeyBtn),
+        window_under_cursor: elements.windowUnderCursor.checked,
+        scroll_hotkey: readHotkey(elements.scrollHotkeyBtn),
         ocr_engine: selectedEngine,
+        ocr_routing: currentConfig.ocr_routing ?? false,
+        ocr_route_rules: currentConfig.ocr_route_rules ?? [],
+        watch_folders: currentConfig.watch_folders ?? [],
+        watch_copy: currentConfig.watch_copy ?? false,
+        watch_translate: currentConfig.watch_translate ?? false,
         enable_translation: elements.enableTranslation.checked,
         translation_source: 'auto',
         translation_target: elements.targetLang.value,
-        tencent_secret_id: elements.secretId.value,
-        tencent_secret_key: elements.secretKey.value,
+        translation_provider: elements.translationProvider.value,
+        translators: collectProviderSettings(),
+        redaction: {
+            enabled: elements.redactEnabled.checked,
+            kinds: currentConfig.redaction?.kinds ?? [],
+            mask_image: elements.redactImage.checked,
+            audit: currentConfig.redaction?.audit ?? true,
+        },
+        history_enabled: elements.historyEnabled.checked,
+        history_hotkey: readHotkey(elements.historyHotkeyBtn),
+        history_interval_ms: currentConfig.history_interval_ms ?? 1000,
+        history_frames: currentConfig.history_frames ?? 30,
+        history_max_mb: currentConfig.history_max_mb ?? 100,
+        archive_hotkey: readHotkey(elements.archiveHotkeyBtn),
+        archive: {
+            dir: elements.archiveDir.value.trim(),
+            template: currentConfig.archive?.template ?? '',
+            format: elements.archiveFormat.value,
+            jpeg_quality: currentConfig.archive?.jpeg_quality ?? 90,
+            retention_days: Math.max(0, parseInt(elements.archiveRetention.value) || 0),
+            max_files: currentConfig.archive?.max_files ?? 0,
+        },
         auto_copy: elements.autoCopy.checked,
         image_preprocess: elements.imagePreprocess.checked,
         show_debug: elements.showDebug.checked,
@@ -135,32 +230,42 @@ function bindEvents() {
         elements.delayValue.textContent = `${value} ms`;
     });
 
-    // 快捷键按钮
-    let recording = false;
+    // 快捷键按钮（各快捷键共用录制逻辑）
+    let recordingBtn = null;
     let pressedKeys = new Set();
 
-    elements.hotkeyBtn.addEventListener('click', () => {
-        if (!recording) {
-            recording = true;
-            elements.hotkeyBtn.textContent = '按下快捷键...';
-            elements.hotkeyBtn.classList.add('recording');
-            pressedKeys.clear();
-        }
+    [elements.hotkeyBtn, elements.regionHotkeyBtn, elements.windowHotkeyBtn, elements.scrollHotkeyBtn, elements.historyHotkeyBtn, elements.archiveHotkeyBtn].forEach((btn) => {
+        btn.addEventListener('click', () => {
+            if (!recordingBtn) {
+                recordingBtn = btn;
+                btn.textContent = '按下快捷键...';
+                btn.classList.add('recording');
+                pressedKeys.clear();
+            }
+        });
     });
 
     document.addEventListener('keydown', (e) => {
-        if (!recording) return;
+        if (!recordingBtn) return;
         e.preventDefault();
 
+        // 附加热键可以用 Backspace/Delete 清空（禁用）
+        if ((e.key === 'Backspace' || e.key === 'Delete') && pressedKeys.size === 0 && recordingBtn !== elements.hotkeyBtn) {
+            showHotkey(recordingBtn, '');
+            recordingBtn.classList.remove('recording');
+            recordingBtn = null;
+            return;
+        }
+
         const keyName = getKeyName(e);
         if (keyName) {
             pressedKeys.add(keyName);
-            elements.hotkeyBtn.textContent = Array.from(pressedKeys).join('+');
+            recordingBtn.textContent = Array.from(pressedKeys).join('+');
         }
     });
 
     document.addEventListener('keyup', (e) => {
-        if (!recording) return;
+        if (!recordingBtn) return;
         
         const keyName = getKeyName(e);
         if (keyName && pressedKeys.has(keyName)) {
@@ -168,8 +273,8 @@ function bindEvents() {
         }
 
         if (pressedKeys.size === 0) {
-            recording = false;
-            elements.hotkeyBtn.classList.remove('recording');
+            recordingBtn.classList.remove('recording');
+            recordingBtn = null;
             // 保持当前显示的快捷键
         }
     });
@@ -180,11 +285,13 @@ function bindEvents() {
             const config = getConfigFromUI();
             if (SaveConfig) {
                 await SaveConfig(config);
+                currentConfig = { ...currentConfig, ...config };
                 showToast('设置已保存');
+                refreshUsage();
             }
         } catch (err) {
             console.error('保存配置失败:', err);
-            showToast('保存失败: ' + err.message, 'error');
+            showToast('保存失败: ' + (err?.message ?? err), 'error'); // Go 返回的错误是字符串
         }
     });
 
@@ -195,15 +302,149 @@ function bindEvents() {
         }
     });
 
+    // 切换翻译服务
+    elements.translationProvider.addEventListener('change', () => {
+        collectProviderSettings();
+        renderProviderFields(elements.translationProvider.value);
+    });
+
     // API 链接
     elements.apiLink.addEventListener('click', (e) => {
         e.preventDefault();
-        if (window.runtime?.BrowserOpenURL) {
-            window.runtime.BrowserOpenURL('https://console.cloud.tencent.com/cam/capi');
+        const link = providerInfo(elements.translationProvider.value)?.link;
+        if (link && window.runtime?.BrowserOpenURL) {
+            window.runtime.BrowserOpenURL(link);
         }
     });
 }
 
+// 翻译服务说明
+function providerInfo(name) {
+    return translators.find((info) => info.name === name);
+}
+
+// 显示翻译服务需要填写的设置
+function renderProviderFields(name) {
+    const info = providerInfo(name) || translators[0];
+    const settings = providerSettings[info.name] || {};
+
+    elements.providerFields.innerHTML = '';
+    info.fields.forEach((field) => {
+        const row = document.createElement('div');
+        row.className = 'form-row';
+
+        const label = document.createElement('label');
+        label.textContent = `${field.label}:`;
+
+        const value = settings[field.key];
+        let input;
+        if (field.type === 'textarea') {
+            input = document.createElement('textarea');
+            row.classList.add('tall');
+        } else if (field.type === 'select') {
+            input = document.createElement('select');
+            (field.options || []).forEach((opt) => {
+                const option = document.createElement('option');
+                option.value = opt.value;
+                option.textContent = opt.label;
+                input.appendChild(option);
+            });
+        } else {
+            input = document.createElement('input');
+            input.type = field.type === 'number' || field.type === 'checkbox'
+                ? field.type
+                : (field.secret ? 'password' : 'text');
+        }
+        input.dataset.key = field.key;
+        input.dataset.type = field.type || 'text';
+        if (field.type === 'checkbox') {
+            input.checked = value === true;
+        } else if (field.type === 'select') {
+            input.value = value ?? '';
+        } else {
+            if (field.type === 'number') input.step = '0.1';
+            input.placeholder = field.placeholder || (field.optional ? '可选' : '');
+            input.value = value ?? '';
+        }
+
+        row.append(label, input);
+        elements.providerFields.appendChild(row);
+    });
+
+    elements.apiHint.textContent = `${info.label}设置 (翻译功能需要)`;
+    elements.apiLink.style.display = info.link ? '' : 'none';
+    if (info.name !== currentConfig.translation_provider) {
+        elements.usageHint.style.display = 'none';
+    }
+    shownProvider = info.name;
+    setTranslationUIEnabled(elements.enableTranslation.checked);
+}
+
+// 显示当前翻译服务的剩余字符（仅支持查询用量的服务）
+async function refreshUsage() {
+    elements.usageHint.style.display = 'none';
+    if (!GetTranslationUsage) return;
+    try {
+        const usage = await GetTranslationUsage();
+        if (!usage || elements.translationProvider.value !== currentConfig.translation_provider) return;
+        elements.usageHint.textContent = usage.character_limit > 0
+            ? `本计费周期剩余 ${(usage.character_limit - usage.character_count).toLocaleString()} / ${usage.character_limit.toLocaleString()} 字符`
+            : `本计费周期已用 ${usage.character_count.toLocaleString()} 字符`;
+        elements.usageHint.style.display = '';
+    } catch (e) {
+        console.warn('查询翻译用量失败:', e);
+    }
+}
+
+// 把正在显示的设置写回 providerSettings，返回全部服务的设置
+function collectProviderSettings() {
+    if (shownProvider) {
+        const settings = { ...(providerSettings[shownProvider] || {}) };
+        elements.providerFields.querySelectorAll('input, textarea, select').forEach((input) => {
+            const key = input.dataset.key;
+            let value;
+            switch (input.dataset.type) {
+                case 'checkbox':
+                    value = input.checked || undefined;
+                    break;
+                case 'number':
+                    value = input.value.trim() === '' ? undefined : Number(input.value);
+                    if (Number.isNaN(value)) value = undefined;
+                    break;
+                case 'textarea':
+                    value = input.value.trim() ? input.value : undefined;
+                    break;
+                case 'select':
+                    value = input.value || undefined;
+                    break;
+                default:
+                    value = input.value.trim() || undefined;
+            }
+            if (value === undefined) {
+                delete settings[key];
+            } else {
+                settings[key] = value;
+            }
+        });
+        providerSettings[shownProvider] = settings;
+    }
+    return providerSettings;
+}
+
+// 未设置的快捷键显示的文字
+const HOTKEY_UNSET = '未设置';
+
+// showHotkey 在按钮上显示快捷键，为空时显示未设置
+function showHotkey(btn, hotkey) {
+    btn.textContent = hotkey ? hotkey.toUpperCase() : HOTKEY_UNSET;
+}
+
+// readHotkey 读取按钮上的快捷键，未设置时返回空字符串
+function readHotkey(btn) {
+    const text = btn.textContent;
+    return text === HOTKEY_UNSET ? '' : text.toLowerCase();
+}
+
 // 获取键名
 function getKeyName(e) {
     const keyMap = {
@@ -1,17 +1,34 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {config} from '../models';
 import {main} from '../models';
+import {ocr} from '../models';
+import {translator} from '../models';
 
-export function GetConfig():Promise<main.Config>;
+export function GetConfig():Promise<config.Config>;
+
+export function GetOCRStats():Promise<ocr.LimitStats>;
+
+export function GetTranslationUsage():Promise<translator.Usage>;
+
+export function GetTranslators():Promise<Array<translator.Info>>;
 
 export function HideWindow():Promise<void>;
 
 export function IsEnabled():Promise<boolean>;
 
-export function SaveConfig(arg1:main.Config):Promise<void>;
+export function RecognizeRegion(arg1:number,arg2:number,arg3:number,arg4:number):Promise<Array<ocr.TextBlock>>;
+
+export function RecognizeWindow(arg1:boolean):Promise<main.WindowResult>;
+
+export function SaveConfig(arg1:config.Config):Promise<void>;
 
 export function SetEnabled(arg1:boolean):Promise<void>;
 
 export function ShowWindow():Promise<void>;
 
+export function StartScrollCapture(arg1:boolean):Promise<string>;
+
+export function StopScrollCapture():Promise<main.ScrollResult>;
+
 export function Translate(arg1:string):Promise<string>;
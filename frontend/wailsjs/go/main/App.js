@@ -6,6 +6,18 @@ export function GetConfig() {
   return window['go']['main']['App']['GetConfig']();
 }
 
+export function GetOCRStats() {
+  return window['go']['main']['App']['GetOCRStats']();
+}
+
+export function GetTranslationUsage() {
+  return window['go']['main']['App']['GetTranslationUsage']();
+}
+
+export function GetTranslators() {
+  return window['go']['main']['App']['GetTranslators']();
+}
+
 export function HideWindow() {
   return window['go']['main']['App']['HideWindow']();
 }
@@ -14,6 +26,14 @@ export function IsEnabled() {
   return window['go']['main']['App']['IsEnabled']();
 }
 
+export function RecognizeRegion(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['RecognizeRegion'](arg1, arg2, arg3, arg4);
+}
+
+export function RecognizeWindow(arg1) {
+  return window['go']['main']['App']['RecognizeWindow'](arg1);
+}
+
 export function SaveConfig(arg1) {
   return window['go']['main']['App']['SaveConfig'](arg1);
 }
@@ -26,6 +46,14 @@ export function ShowWindow() {
   return window['go']['main']['App']['ShowWindow']();
 }
 
+export function StartScrollCapture(arg1) {
+  return window['go']['main']['App']['StartScrollCapture'](arg1);
+}
+
+export function StopScrollCapture() {
+  return window['go']['main']['App']['StopScrollCapture']();
+}
+
 export function Translate(arg1) {
   return window['go']['main']['App']['Translate'](arg1);
 }
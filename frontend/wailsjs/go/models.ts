@@ -1,8 +1,39 @@
-export namespace main {
+export namespace archive {
+	
+	export class Options {
+	    dir: string;
+	    template: string;
+	    format: string;
+	    jpeg_quality: number;
+	    retention_days: number;
+	    max_files: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Options(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.dir = source["dir"];
+	        this.template = source["template"];
+	        this.format = source["format"];
+	        this.jpeg_quality = source["jpeg_quality"];
+	        this.retention_days = source["retention_days"];
+	        this.max_files = source["max_files"];
+	    }
+	}
+
+}
+
+export namespace config {
 	
 	export class Config {
 	    trigger_delay_ms: number;
 	    hotkey: string;
+	    region_hotkey: string;
+	    window_hotkey: string;
+	    window_under_cursor: boolean;
+	    scroll_hotkey: string;
 	    auto_copy: boolean;
 	    show_debug: boolean;
 	    image_preprocess: boolean;
@@ -10,8 +41,23 @@ export namespace main {
 	    enable_translation: boolean;
 	    translation_source: string;
 	    translation_target: string;
-	    tencent_secret_id: string;
-	    tencent_secret_key: string;
+	    translation_provider: string;
+	    translators: Record<string, translator.Settings>;
+	    tencent_secret_id?: string;
+	    tencent_secret_key?: string;
+	    ocr_routing: boolean;
+	    ocr_route_rules: ocr.RouteRule[];
+	    watch_folders: string[];
+	    watch_copy: boolean;
+	    watch_translate: boolean;
+	    redaction: redact.Policy;
+	    history_enabled: boolean;
+	    history_hotkey: string;
+	    history_interval_ms: number;
+	    history_frames: number;
+	    history_max_mb: number;
+	    archive_hotkey: string;
+	    archive: archive.Options;
 	    first_run: boolean;
 	    show_welcome: boolean;
 	    show_startup_notification: boolean;
@@ -24,6 +70,10 @@ export namespace main {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.trigger_delay_ms = source["trigger_delay_ms"];
 	        this.hotkey = source["hotkey"];
+	        this.region_hotkey = source["region_hotkey"];
+	        this.window_hotkey = source["window_hotkey"];
+	        this.window_under_cursor = source["window_under_cursor"];
+	        this.scroll_hotkey = source["scroll_hotkey"];
 	        this.auto_copy = source["auto_copy"];
 	        this.show_debug = source["show_debug"];
 	        this.image_preprocess = source["image_preprocess"];
@@ -31,12 +81,374 @@ export namespace main {
 	        this.enable_translation = source["enable_translation"];
 	        this.translation_source = source["translation_source"];
 	        this.translation_target = source["translation_target"];
+	        this.translation_provider = source["translation_provider"];
+	        this.translators = this.convertValues(source["translators"], translator.Settings, true);
 	        this.tencent_secret_id = source["tencent_secret_id"];
 	        this.tencent_secret_key = source["tencent_secret_key"];
+	        this.ocr_routing = source["ocr_routing"];
+	        this.ocr_route_rules = this.convertValues(source["ocr_route_rules"], ocr.RouteRule);
+	        this.watch_folders = source["watch_folders"];
+	        this.watch_copy = source["watch_copy"];
+	        this.watch_translate = source["watch_translate"];
+	        this.redaction = this.convertValues(source["redaction"], redact.Policy);
+	        this.history_enabled = source["history_enabled"];
+	        this.history_hotkey = source["history_hotkey"];
+	        this.history_interval_ms = source["history_interval_ms"];
+	        this.history_frames = source["history_frames"];
+	        this.history_max_mb = source["history_max_mb"];
+	        this.archive_hotkey = source["archive_hotkey"];
+	        this.archive = this.convertValues(source["archive"], archive.Options);
 	        this.first_run = source["first_run"];
 	        this.show_welcome = source["show_welcome"];
 	        this.show_startup_notification = source["show_startup_notification"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace main {
+	
+	export class ScrollResult {
+	    source: string;
+	    width: number;
+	    height: number;
+	    rejected: number;
+	    text: string;
+	    blocks: ocr.TextBlock[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ScrollResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.source = source["source"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.rejected = source["rejected"];
+	        this.text = source["text"];
+	        this.blocks = this.convertValues(source["blocks"], ocr.TextBlock);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WindowResult {
+	    title: string;
+	    process: string;
+	    pid: number;
+	    x: number;
+	    y: number;
+	    width: number;
+	    height: number;
+	    blocks: ocr.TextBlock[];
+	
+	    static createFrom(source: any = {}) {
+	        return new WindowResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.title = source["title"];
+	        this.process = source["process"];
+	        this.pid = source["pid"];
+	        this.x = source["x"];
+	        this.y = source["y"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.blocks = this.convertValues(source["blocks"], ocr.TextBlock);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+
+export namespace ocr {
+	
+	export class LimitStats {
+	    limit: number;
+	    active: number;
+	    queued: number;
+	    max_queued: number;
+	    completed: number;
+	    failed: number;
+	    superseded: number;
+	    timed_out: number;
+	    rejected: number;
+	    avg_wait_ms: number;
+	    avg_run_ms: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LimitStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.limit = source["limit"];
+	        this.active = source["active"];
+	        this.queued = source["queued"];
+	        this.max_queued = source["max_queued"];
+	        this.completed = source["completed"];
+	        this.failed = source["failed"];
+	        this.superseded = source["superseded"];
+	        this.timed_out = source["timed_out"];
+	        this.rejected = source["rejected"];
+	        this.avg_wait_ms = source["avg_wait_ms"];
+	        this.avg_run_ms = source["avg_run_ms"];
+	    }
+	}
+	export class TextBlock {
+	    text: string;
+	    x: number;
+	    y: number;
+	    width: number;
+	    height: number;
+	    confidence?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new TextBlock(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.text = source["text"];
+	        this.x = source["x"];
+	        this.y = source["y"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.confidence = source["confidence"];
+	    }
+	}
+	export class RouteRule {
+	    script: string;
+	    engine: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RouteRule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.script = source["script"];
+	        this.engine = source["engine"];
+	    }
+	}
+
+}
+
+export namespace redact {
+	
+	export class Policy {
+	    enabled: boolean;
+	    kinds: string[];
+	    mask_image: boolean;
+	    audit: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Policy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.kinds = source["kinds"];
+	        this.mask_image = source["mask_image"];
+	        this.audit = source["audit"];
+	    }
+	}
+
+}
+
+export namespace translator {
+	
+	export class Field {
+	    key: string;
+	    label: string;
+	    placeholder?: string;
+	    type?: string;
+	    options?: Option[];
+	    secret?: boolean;
+	    optional?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Field(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.label = source["label"];
+	        this.placeholder = source["placeholder"];
+	        this.type = source["type"];
+	        this.options = this.convertValues(source["options"], Option);
+	        this.secret = source["secret"];
+	        this.optional = source["optional"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Info {
+	    name: string;
+	    label: string;
+	    fields: Field[];
+	    link?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Info(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.label = source["label"];
+	        this.fields = this.convertValues(source["fields"], Field);
+	        this.link = source["link"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Option {
+	    value: string;
+	    label: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Option(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.value = source["value"];
+	        this.label = source["label"];
+	    }
+	}
+	export class Settings {
+	    secret_id?: string;
+	    secret_key?: string;
+	    api_key?: string;
+	    endpoint?: string;
+	    model?: string;
+	    prompt?: string;
+	    temperature?: number;
+	    stream?: boolean;
+	    formality?: string;
+	    tag_handling?: string;
+	    glossary?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Settings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.secret_id = source["secret_id"];
+	        this.secret_key = source["secret_key"];
+	        this.api_key = source["api_key"];
+	        this.endpoint = source["endpoint"];
+	        this.model = source["model"];
+	        this.prompt = source["prompt"];
+	        this.temperature = source["temperature"];
+	        this.stream = source["stream"];
+	        this.formality = source["formality"];
+	        this.tag_handling = source["tag_handling"];
+	        this.glossary = source["glossary"];
+	    }
+	}
+	export class Usage {
+	    character_count: number;
+	    character_limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Usage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.character_count = source["character_count"];
+	        this.character_limit = source["character_limit"];
+	    }
 	}
 
 }
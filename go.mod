@@ -6,6 +6,7 @@ require github.com/wailsapp/wails/v2 v2.11.0
 
 require (
 	github.com/energye/systray v1.0.2
+	golang.org/x/image v0.24.0
 	golang.org/x/sys v0.30.0
 )
 
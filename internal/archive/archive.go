@@ -0,0 +1,352 @@
+// Package archive 截图存档：把冻结的截图连同识别结果保存到磁盘
+//
+// 识别出的文字写入图片元数据（PNG 的 iTXt 和 XMP、JPEG 的 XMP），文本块写入同名的 .json 文件，
+// 桌面搜索工具可以按文字找到截图。文件名按模板生成，按保留天数和最多文件数清理旧的存档。
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/draw"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"screenocr-wails/internal/ocr"
+)
+
+// DefaultTemplate 默认文件名模板
+const DefaultTemplate = "ScreenOCR_{date}_{time}_{source}"
+
+// Options 存档设置
+type Options struct {
+	Dir           string `json:"dir"`            // 存档目录，为空时使用 DefaultDir
+	Template      string `json:"template"`       // 文件名模板（不含扩展名），见 FileName
+	Format        string `json:"format"`         // png / jpeg
+	JPEGQuality   int    `json:"jpeg_quality"`   // JPEG 质量 1-100，默认 90
+	RetentionDays int    `json:"retention_days"` // 保留天数，0 为永久保留
+	MaxFiles      int    `json:"max_files"`      // 最多保留的存档数，0 为不限制
+}
+
+// DefaultOptions 默认设置：PNG，永久保留
+func DefaultOptions() Options {
+	return Options{Template: DefaultTemplate, Format: "png", JPEGQuality: 90}
+}
+
+// DefaultDir 默认存档目录：用户图片目录下的 ScreenOCR
+func DefaultDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "ScreenOCR"
+	}
+	return filepath.Join(home, "Pictures", "ScreenOCR")
+}
+
+// withDefaults 补全默认设置
+func (o Options) withDefaults() Options {
+	if o.Dir == "" {
+		o.Dir = DefaultDir()
+	}
+	if strings.TrimSpace(o.Template) == "" {
+		o.Template = DefaultTemplate
+	}
+	if o.Format == "jpg" {
+		o.Format = "jpeg"
+	}
+	if o.Format != "jpeg" {
+		o.Format = "png"
+	}
+	if o.JPEGQuality <= 0 || o.JPEGQuality > 100 {
+		o.JPEGQuality = 90
+	}
+	return o
+}
+
+// Capture 要保存的一张截图
+type Capture struct {
+	Time   time.Time
+	Source string          // 来源窗口，全屏截图时为空
+	Image  image.Image     // 截图（全屏或选中的部分）
+	Blocks []ocr.TextBlock // 文本块，坐标相对于 Image 左上角
+}
+
+// Sidecar 与截图同名的 .json 文件内容
+type Sidecar struct {
+	Time   time.Time       `json:"time"`
+	Source string          `json:"source,omitempty"`
+	Image  string          `json:"image"` // 截图文件名
+	Width  int             `json:"width"`
+	Height int             `json:"height"`
+	Text   string          `json:"text"`
+	Blocks []ocr.TextBlock `json:"blocks"`
+}
+
+// Save 保存截图和 .json 文件，返回截图路径；保存后按保留设置清理旧的存档
+func Save(c Capture, opts Options) (string, error) {
+	opts = opts.withDefaults()
+	if c.Time.IsZero() {
+		c.Time = time.Now()
+	}
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		return "", fmt.Errorf("创建存档目录失败: %w", err)
+	}
+
+	ext := ".png"
+	if opts.Format == "jpeg" {
+		ext = ".jpg"
+	}
+	path, err := reserve(opts.Dir, FileName(opts.Template, c), ext)
+	if err != nil {
+		return "", err
+	}
+
+	text := ocr.JoinText(c.Blocks)
+	meta := Metadata{Title: title(text, c.Source), Description: text, Source: c.Source, Time: c.Time}
+	if err := writeImage(path, c.Image, opts, meta); err != nil {
+		os.Remove(path)
+		return "", err
+	}
+
+	bounds := c.Image.Bounds()
+	blocks := c.Blocks
+	if blocks == nil {
+		blocks = []ocr.TextBlock{}
+	}
+	sidecar := Sidecar{
+		Time: c.Time, Source: c.Source, Image: filepath.Base(path),
+		Width: bounds.Dx(), Height: bounds.Dy(), Text: text, Blocks: blocks,
+	}
+	data, err := json.MarshalIndent(sidecar, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(SidecarPath(path), data, 0644); err != nil {
+		return "", fmt.Errorf("写入文本块失败: %w", err)
+	}
+
+	if _, err := Prune(opts, c.Time); err != nil {
+		fmt.Println("[存档] ⚠ 清理旧存档失败:", err)
+	}
+	return path, nil
+}
+
+// Crop 裁剪出 selection 部分（截图像素坐标），只保留中心在其中的文本块并换算为相对于裁剪结果左上角的坐标；
+// selection 为空时原样返回
+func Crop(img image.Image, blocks []ocr.TextBlock, selection image.Rectangle) (image.Image, []ocr.TextBlock) {
+	selection = selection.Intersect(img.Bounds())
+	if selection.Empty() {
+		return img, blocks
+	}
+	out := image.NewRGBA(image.Rect(0, 0, selection.Dx(), selection.Dy()))
+	draw.Draw(out, out.Rect, img, selection.Min, draw.Src)
+
+	var kept []ocr.TextBlock
+	for _, b := range blocks {
+		center := image.Pt(b.X+b.Width/2, b.Y+b.Height/2)
+		if !center.In(selection) {
+			continue
+		}
+		b.X -= selection.Min.X
+		b.Y -= selection.Min.Y
+		kept = append(kept, b)
+	}
+	return out, kept
+}
+
+// writeImage 编码截图并写入 path（path 已由 reserve 创建）
+func writeImage(path string, img image.Image, opts Options, meta Metadata) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("创建截图文件失败: %w", err)
+	}
+	if opts.Format == "jpeg" {
+		err = EncodeJPEG(f, img, opts.JPEGQuality, meta)
+	} else {
+		err = EncodePNG(f, img, meta)
+	}
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("保存截图失败: %w", err)
+	}
+	return f.Close()
+}
+
+// reserve 创建不与已有存档重名的空文件，重名时依次加上 _2、_3……
+func reserve(dir, name, ext string) (string, error) {
+	for i := 1; i < 1000; i++ {
+		candidate := name
+		if i > 1 {
+			candidate = fmt.Sprintf("%s_%d", name, i)
+		}
+		path := filepath.Join(dir, candidate+ext)
+		if _, err := os.Stat(SidecarPath(path)); err == nil {
+			continue
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("创建截图文件失败: %w", err)
+		}
+		f.Close()
+		return path, nil
+	}
+	return "", fmt.Errorf("存档目录中同名文件过多: %s", name)
+}
+
+// SidecarPath 截图对应的 .json 文件路径
+func SidecarPath(imagePath string) string {
+	return strings.TrimSuffix(imagePath, filepath.Ext(imagePath)) + ".json"
+}
+
+// FileName 按模板生成文件名（不含扩展名）。模板中的占位符：
+//
+//	{date}    日期，如 2024-10-18
+//	{time}    时间，如 12-30-05
+//	{source}  来源窗口（全屏截图为 screen）
+//	{text}    识别出的第一行文字（最多 24 个字）
+//
+// 文件名中不允许的字符替换为 _
+func FileName(template string, c Capture) string {
+	if strings.TrimSpace(template) == "" {
+		template = DefaultTemplate
+	}
+	t := c.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	source := c.Source
+	if source == "" {
+		source = "screen"
+	}
+	name := strings.NewReplacer(
+		"{date}", t.Format("2006-01-02"),
+		"{time}", t.Format("15-04-05"),
+		"{source}", clip(source, 40),
+		"{text}", clip(firstLine(ocr.JoinText(c.Blocks)), 24),
+	).Replace(template)
+
+	name = sanitize(name)
+	if name == "" {
+		name = "ScreenOCR_" + t.Format("2006-01-02_15-04-05")
+	}
+	return name
+}
+
+// sanitize 替换文件名中不允许的字符，合并连续的 _（连同两侧的空格）并去掉首尾的 _、空格和点
+func sanitize(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`\/:*?"<>|—`, r) || unicode.IsControl(r) {
+			return '_'
+		}
+		return r
+	}, name)
+	name = underscores.ReplaceAllString(name, "_")
+	return strings.Trim(name, "_ .")
+}
+
+// underscores 连续的 _ 和两侧的空格
+var underscores = regexp.MustCompile(`[ _]*_[ _]*`)
+
+// clip 截取前 n 个字符
+func clip(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// firstLine 第一行非空文字
+func firstLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// title 截图标题：来源窗口和第一行文字
+func title(text, source string) string {
+	line := clip(firstLine(text), 60)
+	switch {
+	case source != "" && line != "":
+		return source + " — " + line
+	case source != "":
+		return source
+	case line != "":
+		return line
+	default:
+		return "ScreenOCR"
+	}
+}
+
+// Prune 清理 opts.Dir 中超过保留天数或超出最多文件数的存档（截图和 .json 一起删除），返回删除的存档数。
+// 只处理由 Save 保存的存档（同名 .json 指向该截图），目录中的其他文件不受影响
+func Prune(opts Options, now time.Time) (int, error) {
+	opts = opts.withDefaults()
+	if opts.RetentionDays <= 0 && opts.MaxFiles <= 0 {
+		return 0, nil
+	}
+
+	entries, err := os.ReadDir(opts.Dir)
+	if err != nil {
+		return 0, err
+	}
+	type saved struct {
+		path    string
+		modTime time.Time
+	}
+	var files []saved
+	for _, e := range entries {
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if e.IsDir() || (ext != ".png" && ext != ".jpg" && ext != ".jpeg") {
+			continue
+		}
+		path := filepath.Join(opts.Dir, e.Name())
+		if !isArchived(path) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, saved{path, info.ModTime()})
+	}
+	// 最新的在前
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.After(files[j].modTime) })
+
+	cutoff := now.AddDate(0, 0, -opts.RetentionDays)
+	removed := 0
+	for i, f := range files {
+		expired := opts.RetentionDays > 0 && f.modTime.Before(cutoff)
+		overflow := opts.MaxFiles > 0 && i >= opts.MaxFiles
+		if !expired && !overflow {
+			continue
+		}
+		if err := os.Remove(f.path); err != nil {
+			return removed, err
+		}
+		os.Remove(SidecarPath(f.path))
+		removed++
+	}
+	return removed, nil
+}
+
+// isArchived 截图是否由 Save 保存：同名 .json 是 Sidecar 且指向该截图
+func isArchived(path string) bool {
+	data, err := os.ReadFile(SidecarPath(path))
+	if err != nil {
+		return false
+	}
+	var sidecar Sidecar
+	return json.Unmarshal(data, &sidecar) == nil && sidecar.Image == filepath.Base(path)
+}
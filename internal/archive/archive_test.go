@@ -0,0 +1,236 @@
+package archive
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"screenocr-wails/internal/ocr"
+	"screenocr-wails/internal/raster"
+)
+
+// testScreen 合成的截图：渐变背景上的深色文字行
+func testScreen(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.RGBA{uint8(200 + x*40/width), uint8(210 + y*30/height), 235, 255}
+			if (y/24)%2 == 1 && (x/3)%4 != 0 && x > 20 && x < width-20 {
+				c = color.RGBA{20, 20, 30, 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+var testBlocks = []ocr.TextBlock{
+	{Text: "发票号码 <No.2024> & 金额", X: 40, Y: 60, Width: 300, Height: 24},
+	{Text: "Invoice \"total\" 1,280.00", X: 40, Y: 100, Width: 280, Height: 24},
+}
+
+func TestSaveRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	img := testScreen(640, 400)
+	at := time.Date(2024, 10, 18, 12, 30, 5, 0, time.Local)
+	long := make([]ocr.TextBlock, 0, 4000)
+	for i := 0; i < 4000; i++ {
+		long = append(long, ocr.TextBlock{Text: fmt.Sprintf("第 %d 行 很长的聊天记录", i), X: 10, Y: 10 + i*20, Width: 200, Height: 18})
+	}
+	// 选中第一行文字：裁剪后只保留该文本块
+	cropped, croppedBlocks := Crop(img, testBlocks, image.Rect(30, 50, 360, 92))
+
+	// 按顺序保存：第二个与第一个重名
+	cases := []struct {
+		name    string
+		capture Capture
+		opts    Options
+		want    string // 期望的文件名
+	}{
+		{"png-screen", Capture{Time: at, Image: img, Blocks: testBlocks},
+			Options{Format: "png"}, "ScreenOCR_2024-10-18_12-30-05_screen.png"},
+		{"png-duplicate", Capture{Time: at, Image: img, Blocks: testBlocks},
+			Options{Format: "png"}, "ScreenOCR_2024-10-18_12-30-05_screen_2.png"},
+		{"png-selection", Capture{Time: at, Image: cropped, Blocks: croppedBlocks},
+			Options{Format: "png", Template: "{text}"}, "发票号码_No.2024_& 金额.png"},
+		{"jpeg-window", Capture{Time: at, Source: `chrome.exe — 发票: "2024"`, Image: img, Blocks: testBlocks},
+			Options{Format: "jpeg", Template: "{date}_{source}_{text}"}, "2024-10-18_chrome.exe_发票_2024_发票号码_No.2024_& 金额.jpg"},
+		{"jpeg-long-text", Capture{Time: at, Image: img, Blocks: long},
+			Options{Format: "jpeg", Template: "long"}, "long.jpg"},
+		{"no-blocks", Capture{Time: at, Image: img},
+			Options{Format: "jpg", Template: "empty"}, "empty.jpg"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.opts.Dir = dir
+			path, err := Save(c.capture, c.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := filepath.Base(path); got != c.want {
+				t.Errorf("文件名 %q，期望 %q", got, c.want)
+			}
+			checkSaved(t, path, c.capture, strings.HasSuffix(path, ".jpg"))
+		})
+	}
+}
+
+// checkSaved 读回截图核对元数据、像素（PNG 无损）和 .json 文件
+func checkSaved(t *testing.T, path string, c Capture, isJPEG bool) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := ocr.JoinText(c.Blocks)
+
+	meta, err := ReadMetadata(data)
+	switch {
+	case err != nil:
+		t.Errorf("读取元数据: %v", err)
+	case isJPEG && len(text) > 60000:
+		// 放不进 APP1 段的文字被截断，开头须一致
+		prefix := strings.TrimSuffix(meta.Description, "…")
+		if len(prefix) == 0 || len(prefix) == len(text) || !strings.HasPrefix(text, prefix) {
+			t.Errorf("截断的描述（%d 字节）与文字开头不一致", len(meta.Description))
+		}
+	case meta.Description != text:
+		t.Errorf("描述 %q，期望 %q", meta.Description, text)
+	}
+	if err == nil && (meta.Source != c.Source || !meta.Time.Equal(c.Time)) {
+		t.Errorf("来源/时间 %q %v，期望 %q %v", meta.Source, meta.Time, c.Source, c.Time)
+	}
+
+	// 插入元数据后仍是有效的图片
+	var decoded image.Image
+	if isJPEG {
+		decoded, err = jpeg.Decode(bytes.NewReader(data))
+	} else {
+		decoded, err = png.Decode(bytes.NewReader(data))
+	}
+	switch {
+	case err != nil:
+		t.Errorf("解码: %v", err)
+	case decoded.Bounds().Size() != c.Image.Bounds().Size():
+		t.Errorf("尺寸 %v，期望 %v", decoded.Bounds().Size(), c.Image.Bounds().Size())
+	case !isJPEG && !bytes.Equal(raster.ToRGBA(decoded).Pix, raster.ToRGBA(c.Image).Pix):
+		t.Error("像素与原图不一致")
+	}
+
+	var sidecar Sidecar
+	raw, err := os.ReadFile(SidecarPath(path))
+	if err == nil {
+		err = json.Unmarshal(raw, &sidecar)
+	}
+	switch {
+	case err != nil:
+		t.Errorf(".json: %v", err)
+	case sidecar.Text != text || len(sidecar.Blocks) != len(c.Blocks) || sidecar.Image != filepath.Base(path):
+		t.Errorf(".json 内容不一致: %+v", sidecar)
+	case sidecar.Blocks == nil:
+		t.Error(".json 中的文本块应为空数组而不是 null")
+	}
+}
+
+func TestCrop(t *testing.T) {
+	img := testScreen(640, 400)
+	cropped, blocks := Crop(img, testBlocks, image.Rect(30, 50, 360, 92))
+	if cropped.Bounds().Dx() != 330 || cropped.Bounds().Dy() != 42 {
+		t.Errorf("裁剪尺寸 %v", cropped.Bounds())
+	}
+	if len(blocks) != 1 || blocks[0].X != 10 || blocks[0].Y != 10 || blocks[0].Text != testBlocks[0].Text {
+		t.Errorf("裁剪后的文本块 %+v", blocks)
+	}
+	if testBlocks[0].X != 40 {
+		t.Error("Crop 修改了传入的文本块")
+	}
+
+	whole, all := Crop(img, testBlocks, image.Rectangle{})
+	if whole != image.Image(img) || len(all) != len(testBlocks) {
+		t.Error("selection 为空时应原样返回")
+	}
+}
+
+func TestFileName(t *testing.T) {
+	at := time.Date(2024, 10, 18, 9, 5, 7, 0, time.Local)
+	c := Capture{Time: at, Source: "notepad.exe — a/b\\c.txt", Blocks: testBlocks}
+	cases := []struct {
+		template string
+		want     string
+	}{
+		{DefaultTemplate, "ScreenOCR_2024-10-18_09-05-07_notepad.exe_a_b_c.txt"},
+		{"{date}", "2024-10-18"},
+		{"{text}", "发票号码_No.2024_& 金额"},
+		{"plain", "plain"},
+	}
+	for _, tc := range cases {
+		if got := FileName(tc.template, c); got != tc.want {
+			t.Errorf("FileName(%q) = %q，期望 %q", tc.template, got, tc.want)
+		}
+	}
+}
+
+// TestPrune 按保留天数和最多文件数清理：过期的存档和超出数量的最旧存档被删除，不是存档的文件保留
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	small := testScreen(64, 64)
+
+	var paths []string
+	for i, age := range []int{40, 20, 3, 2, 1, 0} { // 存档距今的天数
+		path, err := Save(Capture{Time: now, Image: small}, Options{Dir: dir, Template: fmt.Sprintf("a%d", i)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		mtime := now.AddDate(0, 0, -age)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	// 没有 .json 的图片和 .json 不指向自己的图片都不是存档，即使过期也不删除
+	other := filepath.Join(dir, "other.png")
+	foreign := filepath.Join(dir, "foreign.png")
+	os.WriteFile(other, []byte("x"), 0644)
+	os.WriteFile(foreign, []byte("x"), 0644)
+	os.WriteFile(SidecarPath(foreign), []byte(`{"image":"a0.png"}`), 0644)
+	old := now.AddDate(0, 0, -100)
+	os.Chtimes(other, old, old)
+	os.Chtimes(foreign, old, old)
+
+	removed, err := Prune(Options{Dir: dir, RetentionDays: 30, MaxFiles: 3}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 40 天前的过期，其余 5 个中只保留最新的 3 个
+	if removed != 3 {
+		t.Errorf("删除了 %d 个存档，应为 3 个", removed)
+	}
+	for i, path := range paths {
+		_, errImg := os.Stat(path)
+		_, errJSON := os.Stat(SidecarPath(path))
+		kept := i >= 3
+		if (errImg == nil) != kept || (errJSON == nil) != kept {
+			t.Errorf("%s: 应保留 %v，截图 %v，.json %v", filepath.Base(path), kept, errImg, errJSON)
+		}
+	}
+	for _, path := range []string{other, foreign} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("删除了不是存档的文件 %s", filepath.Base(path))
+		}
+	}
+
+	// 没有设置保留天数和最多文件数时不清理
+	if removed, err := Prune(Options{Dir: dir}, now.AddDate(1, 0, 0)); err != nil || removed != 0 {
+		t.Errorf("不限制时删除了 %d 个存档: %v", removed, err)
+	}
+}
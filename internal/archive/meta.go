@@ -0,0 +1,268 @@
+package archive
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+	"time"
+)
+
+// Metadata 写入图片的元数据
+type Metadata struct {
+	Title       string    // 标题：来源窗口和第一行文字
+	Description string    // 识别出的全部文字
+	Source      string    // 来源窗口
+	Time        time.Time // 截图时间
+}
+
+// xmpNamespace JPEG APP1 段中 XMP 数据的标识
+const xmpNamespace = "http://ns.adobe.com/xap/1.0/\x00"
+
+// maxJPEGXMP JPEG 中 XMP 数据包的最大长度（APP1 段最长 65535 字节，扣除长度字段和标识）
+const maxJPEGXMP = 65535 - 2 - len(xmpNamespace)
+
+// EncodePNG 编码 PNG，在 IHDR 之后插入 iTXt 文本块（Title / Description / Source / Creation Time / Software）和 XMP
+func EncodePNG(w io.Writer, img image.Image, meta Metadata) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return err
+	}
+	data := buf.Bytes()
+
+	// PNG 签名 8 字节，IHDR 块 25 字节（长度 + 类型 + 13 字节数据 + CRC）
+	const ihdrEnd = 8 + 25
+	if len(data) < ihdrEnd || string(data[12:16]) != "IHDR" {
+		return errors.New("PNG 编码结果无效")
+	}
+
+	var chunks bytes.Buffer
+	for _, kv := range [][2]string{
+		{"Title", meta.Title},
+		{"Description", meta.Description},
+		{"Source", meta.Source},
+		{"Creation Time", creationTime(meta.Time)},
+		{"Software", "ScreenOCR"},
+		{"XML:com.adobe.xmp", xmpPacket(meta)},
+	} {
+		if kv[1] != "" {
+			writeChunk(&chunks, "iTXt", iTXt(kv[0], kv[1]))
+		}
+	}
+
+	for _, part := range [][]byte{data[:ihdrEnd], chunks.Bytes(), data[ihdrEnd:]} {
+		if _, err := w.Write(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// iTXt 未压缩的 iTXt 块数据：关键字、压缩标志、压缩方法、语言标签、翻译后的关键字、UTF-8 文字（规范要求有效的 UTF-8）
+func iTXt(keyword, text string) []byte {
+	var b bytes.Buffer
+	b.WriteString(keyword)
+	b.Write([]byte{0, 0, 0}) // 关键字结束，不压缩，压缩方法 0
+	b.WriteByte(0)           // 语言标签为空
+	b.WriteByte(0)           // 翻译后的关键字为空
+	b.WriteString(strings.ToValidUTF8(text, "�"))
+	return b.Bytes()
+}
+
+// writeChunk 写入一个 PNG 块：长度、类型、数据、CRC
+func writeChunk(w *bytes.Buffer, typ string, data []byte) {
+	binary.Write(w, binary.BigEndian, uint32(len(data)))
+	crc := crc32.NewIEEE()
+	crc.Write([]byte(typ))
+	crc.Write(data)
+	w.WriteString(typ)
+	w.Write(data)
+	binary.Write(w, binary.BigEndian, crc.Sum32())
+}
+
+// creationTime PNG 规范建议的 RFC 1123 时间格式
+func creationTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC1123Z)
+}
+
+// EncodeJPEG 编码 JPEG，在 SOI 之后插入 XMP（APP1 段）；文字过长时截断 XMP 中的描述，完整文字见 .json 文件
+func EncodeJPEG(w io.Writer, img image.Image, quality int, meta Metadata) error {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return err
+	}
+	data := buf.Bytes()
+	if len(data) < 2 || data[0] != 0xFF || data[1] != 0xD8 {
+		return errors.New("JPEG 编码结果无效")
+	}
+
+	packet := xmpPacket(meta)
+	for len(packet) > maxJPEGXMP && meta.Description != "" {
+		// 每次截掉约四分之一的描述，直到放得下
+		runes := []rune(meta.Description)
+		meta.Description = string(runes[:len(runes)*3/4]) + "…"
+		if len(runes) < 8 {
+			meta.Description = ""
+		}
+		packet = xmpPacket(meta)
+	}
+	if len(packet) > maxJPEGXMP {
+		return fmt.Errorf("XMP 元数据过长 (%d 字节)", len(packet))
+	}
+
+	segment := make([]byte, 0, 4+len(xmpNamespace)+len(packet))
+	segment = append(segment, 0xFF, 0xE1)
+	segment = binary.BigEndian.AppendUint16(segment, uint16(2+len(xmpNamespace)+len(packet)))
+	segment = append(segment, xmpNamespace...)
+	segment = append(segment, packet...)
+
+	for _, part := range [][]byte{data[:2], segment, data[2:]} {
+		if _, err := w.Write(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// xmpPacket 生成 XMP 数据包：dc:title、dc:description、dc:source、xmp:CreateDate、xmp:CreatorTool
+func xmpPacket(meta Metadata) string {
+	var b strings.Builder
+	b.WriteString("<?xpacket begin=\"\uFEFF\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n")
+	b.WriteString(`<x:xmpmeta xmlns:x="adobe:ns:meta/">` + "\n")
+	b.WriteString(`<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">` + "\n")
+	b.WriteString(`<rdf:Description rdf:about="" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:xmp="http://ns.adobe.com/xap/1.0/">` + "\n")
+	alt := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "<%s><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></%s>\n", name, escape(value), name)
+		}
+	}
+	alt("dc:title", meta.Title)
+	alt("dc:description", meta.Description)
+	if meta.Source != "" {
+		fmt.Fprintf(&b, "<dc:source>%s</dc:source>\n", escape(meta.Source))
+	}
+	if !meta.Time.IsZero() {
+		fmt.Fprintf(&b, "<xmp:CreateDate>%s</xmp:CreateDate>\n", meta.Time.Format(time.RFC3339))
+	}
+	b.WriteString("<xmp:CreatorTool>ScreenOCR</xmp:CreatorTool>\n")
+	b.WriteString("</rdf:Description>\n</rdf:RDF>\n</x:xmpmeta>\n")
+	b.WriteString(`<?xpacket end="w"?>`)
+	return b.String()
+}
+
+// escape 转义 XML 特殊字符（换行转为 &#xA;，解析后还原）
+func escape(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(strings.ToValidUTF8(s, "�")))
+	return b.String()
+}
+
+// xmpDoc 解析 XMP 时使用的结构（只按本地名称匹配）
+type xmpDoc struct {
+	Description struct {
+		Title       string `xml:"title>Alt>li"`
+		Description string `xml:"description>Alt>li"`
+		Source      string `xml:"source"`
+		CreateDate  string `xml:"CreateDate"`
+	} `xml:"RDF>Description"`
+}
+
+// ReadMetadata 读取 EncodePNG / EncodeJPEG 写入的元数据：PNG 优先使用 iTXt 文本块，JPEG 使用 XMP
+func ReadMetadata(data []byte) (Metadata, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return readPNG(data)
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8}):
+		return readJPEG(data)
+	default:
+		return Metadata{}, errors.New("不是 PNG 或 JPEG 图片")
+	}
+}
+
+// readPNG 读取 PNG 的 iTXt 文本块，没有 Title/Description 时回退到 XMP
+func readPNG(data []byte) (Metadata, error) {
+	texts := map[string]string{}
+	for pos := 8; pos+12 <= len(data); {
+		n := int(binary.BigEndian.Uint32(data[pos:]))
+		typ := string(data[pos+4 : pos+8])
+		if pos+12+n > len(data) {
+			return Metadata{}, errors.New("PNG 数据不完整")
+		}
+		body := data[pos+8 : pos+8+n]
+		pos += 12 + n
+		if typ == "IEND" {
+			break
+		}
+		if typ != "iTXt" {
+			continue
+		}
+		keyword, rest, ok := bytes.Cut(body, []byte{0})
+		if !ok || len(rest) < 2 || rest[0] != 0 {
+			continue // 压缩的 iTXt 不是本程序写入的
+		}
+		rest = rest[2:]
+		if _, rest, ok = bytes.Cut(rest, []byte{0}); !ok { // 语言标签
+			continue
+		}
+		if _, rest, ok = bytes.Cut(rest, []byte{0}); !ok { // 翻译后的关键字
+			continue
+		}
+		texts[string(keyword)] = string(rest)
+	}
+
+	if xmp, ok := texts["XML:com.adobe.xmp"]; ok && texts["Title"] == "" && texts["Description"] == "" {
+		return parseXMP([]byte(xmp))
+	}
+	meta := Metadata{Title: texts["Title"], Description: texts["Description"], Source: texts["Source"]}
+	if t, err := time.Parse(time.RFC1123Z, texts["Creation Time"]); err == nil {
+		meta.Time = t
+	}
+	if meta.Title == "" && meta.Description == "" {
+		return meta, errors.New("PNG 中没有文字元数据")
+	}
+	return meta, nil
+}
+
+// readJPEG 在 SOS 之前的 APP1 段中查找 XMP
+func readJPEG(data []byte) (Metadata, error) {
+	for pos := 2; pos+4 <= len(data) && data[pos] == 0xFF; {
+		marker := data[pos+1]
+		if marker == 0xDA { // SOS：之后是图像数据
+			break
+		}
+		n := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if n < 2 || pos+2+n > len(data) {
+			return Metadata{}, errors.New("JPEG 数据不完整")
+		}
+		body := data[pos+4 : pos+2+n]
+		pos += 2 + n
+		if marker == 0xE1 && bytes.HasPrefix(body, []byte(xmpNamespace)) {
+			return parseXMP(body[len(xmpNamespace):])
+		}
+	}
+	return Metadata{}, errors.New("JPEG 中没有 XMP 元数据")
+}
+
+// parseXMP 解析 XMP 数据包
+func parseXMP(packet []byte) (Metadata, error) {
+	var doc xmpDoc
+	if err := xml.Unmarshal(packet, &doc); err != nil {
+		return Metadata{}, fmt.Errorf("解析 XMP 失败: %w", err)
+	}
+	d := doc.Description
+	meta := Metadata{Title: d.Title, Description: d.Description, Source: d.Source}
+	if t, err := time.Parse(time.RFC3339, d.CreateDate); err == nil {
+		meta.Time = t
+	}
+	return meta, nil
+}
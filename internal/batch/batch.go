@@ -0,0 +1,343 @@
+// Package batch 批量识别目录中的图片：有界并发、按格式输出结果、通过清单支持中断后续跑
+package batch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"screenocr-wails/internal/ocr"
+	"screenocr-wails/internal/ocrformat"
+	"screenocr-wails/internal/pdf"
+	"screenocr-wails/internal/redact"
+
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/webp"
+)
+
+// imageExts 支持的图片扩展名
+var imageExts = map[string]bool{
+	".png":  true,
+	".jpg":  true,
+	".jpeg": true,
+	".bmp":  true,
+	".webp": true,
+}
+
+// IsImage 按扩展名判断是否是支持的图片（PNG/JPEG/BMP/WebP）
+func IsImage(path string) bool {
+	return imageExts[strings.ToLower(filepath.Ext(path))]
+}
+
+// Options 批量识别选项
+type Options struct {
+	Format     string           // 输出格式：ocrformat.Formats 之一或 pdf，默认 text
+	OutDir     string           // 输出目录，为空时写在输入目录中
+	Workers    int              // 并发数，默认 CPU 核数；引擎声明了并发限制时取较小值
+	Preprocess bool             // 启用图像预处理
+	Recursive  bool             // 递归处理子目录
+	Resume     bool             // 跳过清单中已成功处理且未修改的图片
+	Redactor   *redact.Redactor // 写入结果前打码敏感内容（pdf 格式同时遮盖截图），为 nil 时不打码
+	Progress   func(Progress)   // 每处理完一张图片回调一次（串行调用）
+}
+
+// Progress 处理进度
+type Progress struct {
+	Path     string        // 图片路径（相对于输入目录）
+	Done     int           // 已处理数量（含跳过和失败）
+	Total    int           // 总数量
+	Blocks   int           // 本张图片识别出的文本块数
+	Skipped  bool          // 是否因续跑而跳过
+	Err      error         // 失败原因
+	Duration time.Duration // 本张图片耗时
+}
+
+// Summary 批量识别汇总
+type Summary struct {
+	Total     int
+	Succeeded int
+	Skipped   int
+	Failed    int
+	Blocks    int
+	Workers   int
+	Duration  time.Duration
+	Failures  map[string]string // 图片路径 → 失败原因
+}
+
+// FindImages 列出目录中支持的图片（相对路径，按名称排序）
+func FindImages(dir string, recursive bool) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if IsImage(path) {
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			files = append(files, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("读取图片目录失败: %w", err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Run 批量识别 dir 中的图片
+// ctx 取消后不再开始新的图片，等待进行中的识别完成后返回已完成部分的汇总和 ctx.Err()
+func Run(ctx context.Context, engine ocr.Engine, dir string, opts Options) (Summary, error) {
+	start := time.Now()
+
+	if opts.Format == "" {
+		opts.Format = "text"
+	}
+	if !isFormat(opts.Format) {
+		return Summary{}, fmt.Errorf("不支持的输出格式: %s", opts.Format)
+	}
+	if opts.OutDir == "" {
+		opts.OutDir = dir
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if limit := ocr.MaxConcurrency(engine); limit > 0 && workers > limit {
+		fmt.Printf("[Batch] 引擎最多支持 %d 个并发识别，并发数从 %d 调整为 %d\n", limit, workers, limit)
+		workers = limit
+	}
+
+	files, err := FindImages(dir, opts.Recursive)
+	if err != nil {
+		return Summary{}, err
+	}
+	outputs, err := outputNames(files, opts.Format)
+	if err != nil {
+		return Summary{}, err
+	}
+	if err := os.MkdirAll(opts.OutDir, 0755); err != nil {
+		return Summary{}, fmt.Errorf("创建输出目录失败: %w", err)
+	}
+
+	m, err := openManifest(filepath.Join(opts.OutDir, ManifestName))
+	if err != nil {
+		return Summary{}, err
+	}
+	defer m.Close()
+
+	summary := Summary{Total: len(files), Workers: workers, Failures: make(map[string]string)}
+	results := make(chan Progress)
+	jobs := make(chan string)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for rel := range jobs {
+				// 取消时分派方可能已送出下一张图片，不再开始识别
+				if ctx.Err() != nil {
+					continue
+				}
+				results <- process(engine, m, dir, rel, outputs[rel], opts)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, rel := range files {
+			select {
+			case jobs <- rel:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for p := range results {
+		switch {
+		case p.Skipped:
+			summary.Skipped++
+		case p.Err != nil:
+			summary.Failed++
+			summary.Failures[p.Path] = p.Err.Error()
+		default:
+			summary.Succeeded++
+			summary.Blocks += p.Blocks
+		}
+		p.Done = summary.Skipped + summary.Failed + summary.Succeeded
+		p.Total = summary.Total
+		if opts.Progress != nil {
+			opts.Progress(p)
+		}
+	}
+
+	summary.Duration = time.Since(start)
+	return summary, ctx.Err()
+}
+
+// outputNames 确定每张图片的结果文件（相对于输出目录）：把图片扩展名替换为输出格式的扩展名，
+// 只有扩展名不同的图片（如 a.png 与 a.jpg）保留原扩展名（a.png.txt、a.jpg.txt），避免互相覆盖
+// 按不区分大小写比较，与 Windows 文件系统一致
+func outputNames(files []string, format string) (map[string]string, error) {
+	ext := ocrformat.Extension(format)
+	stems := make(map[string]int, len(files))
+	for _, rel := range files {
+		stems[strings.ToLower(strings.TrimSuffix(rel, filepath.Ext(rel)))]++
+	}
+
+	outputs := make(map[string]string, len(files))
+	owners := make(map[string]string, len(files))
+	for _, rel := range files {
+		stem := strings.TrimSuffix(rel, filepath.Ext(rel))
+		if stems[strings.ToLower(stem)] > 1 {
+			stem = rel
+		}
+		output := stem + ext
+		key := strings.ToLower(output)
+		if other, ok := owners[key]; ok {
+			return nil, fmt.Errorf("%s 与 %s 的结果文件重名: %s", other, rel, output)
+		}
+		owners[key] = rel
+		outputs[rel] = output
+	}
+	return outputs, nil
+}
+
+// process 识别一张图片并写入结果和清单
+func process(engine ocr.Engine, m *manifest, dir, rel, output string, opts Options) Progress {
+	start := time.Now()
+	p := Progress{Path: rel}
+	path := filepath.Join(dir, rel)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		p.Err = err
+		return p
+	}
+	if opts.Resume {
+		if e, ok := m.done(rel, info); ok {
+			if _, err := os.Stat(filepath.Join(opts.OutDir, e.Output)); err == nil {
+				p.Skipped = true
+				p.Blocks = e.Blocks
+				return p
+			}
+		}
+	}
+
+	p.Blocks, p.Err = recognizeFile(engine, path, filepath.Join(opts.OutDir, output), opts)
+	p.Duration = time.Since(start)
+
+	entry := Entry{
+		Path:    rel,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+		Output:  output,
+		Blocks:  p.Blocks,
+		Time:    time.Now(),
+	}
+	if p.Err != nil {
+		entry.Error = p.Err.Error()
+	}
+	if err := m.record(entry); err != nil && p.Err == nil {
+		p.Err = err
+	}
+	return p
+}
+
+// recognizeFile 识别图片并写入结果文件，返回文本块数量
+// 结果先写入临时文件再重命名，中断时不会留下不完整的结果
+func recognizeFile(engine ocr.Engine, path, outPath string, opts Options) (int, error) {
+	img, err := LoadImage(path)
+	if err != nil {
+		return 0, err
+	}
+
+	blocks, err := engine.Recognize(img, opts.Preprocess)
+	if err != nil {
+		return 0, fmt.Errorf("识别失败: %w", err)
+	}
+	img, blocks = opts.Redactor.Page(img, blocks, "batch", path)
+
+	var buf bytes.Buffer
+	if opts.Format == "pdf" {
+		err = pdf.Write(&buf, img, blocks, pdf.Options{Title: filepath.Base(path)})
+	} else {
+		err = ocrformat.Write(&buf, ocrformat.Page{
+			Image:  filepath.Base(path),
+			Width:  img.Bounds().Dx(),
+			Height: img.Bounds().Dy(),
+			Blocks: blocks,
+		}, opts.Format)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
+		return 0, fmt.Errorf("创建输出目录失败: %w", err)
+	}
+	tmpPath := outPath + ".tmp"
+	if err := os.WriteFile(tmpPath, buf.Bytes(), 0644); err != nil {
+		return 0, fmt.Errorf("写入结果失败: %w", err)
+	}
+	if err := os.Rename(tmpPath, outPath); err != nil {
+		os.Remove(tmpPath)
+		return 0, fmt.Errorf("写入结果失败: %w", err)
+	}
+	return len(blocks), nil
+}
+
+// LoadImage 读取并解码图片
+func LoadImage(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("解码图片失败: %w", err)
+	}
+	return img, nil
+}
+
+// isFormat 检查输出格式是否受支持
+func isFormat(format string) bool {
+	if format == "pdf" {
+		return true
+	}
+	for _, f := range ocrformat.Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,309 @@
+package batch
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"screenocr-wails/internal/ocr"
+	"screenocr-wails/internal/ocr/ocrtest"
+)
+
+// writeImage 在 dir 下写入宽为 width 的图片，按扩展名选择 PNG 或 JPEG
+func writeImage(t *testing.T, dir, rel string, width int) {
+	t.Helper()
+	path := filepath.Join(dir, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	img := image.NewGray(image.Rect(0, 0, width, 10))
+	if strings.HasSuffix(rel, ".jpg") {
+		err = jpeg.Encode(f, img, nil)
+	} else {
+		err = png.Encode(f, img)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readManifest 读取清单中的全部记录（按写入顺序）
+func readManifest(t *testing.T, dir string) []Entry {
+	t.Helper()
+	f, err := os.Open(filepath.Join(dir, ManifestName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("清单记录 %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestOutputNames(t *testing.T) {
+	cases := []struct {
+		name    string
+		files   []string
+		format  string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name:   "distinct",
+			files:  []string{"a.png", "b.jpg", filepath.Join("sub", "a.png")},
+			format: "text",
+			want:   map[string]string{"a.png": "a.txt", "b.jpg": "b.txt", filepath.Join("sub", "a.png"): filepath.Join("sub", "a.txt")},
+		},
+		{
+			// 只有扩展名不同的图片保留原扩展名，其他图片不受影响
+			name:   "same-stem",
+			files:  []string{"a.jpg", "a.png", "b.png"},
+			format: "alto",
+			want:   map[string]string{"a.jpg": "a.jpg.alto.xml", "a.png": "a.png.alto.xml", "b.png": "b.alto.xml"},
+		},
+		{
+			name:   "case-insensitive",
+			files:  []string{"Shot.PNG", "shot.webp"},
+			format: "json",
+			want:   map[string]string{"Shot.PNG": "Shot.PNG.json", "shot.webp": "shot.webp.json"},
+		},
+		{
+			// 保留扩展名后仍与其他图片的结果重名时报错
+			name:    "unresolvable",
+			files:   []string{"a.png", "a.jpg", "a.png.bmp"},
+			format:  "text",
+			wantErr: "a.png 与 a.png.bmp 的结果文件重名: a.png.txt",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := outputNames(c.files, c.format)
+			if c.wantErr != "" {
+				if err == nil || err.Error() != c.wantErr {
+					t.Errorf("错误 %v，期望 %q", err, c.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for rel, want := range c.want {
+				if got[rel] != want {
+					t.Errorf("%s → %q，期望 %q", rel, got[rel], want)
+				}
+			}
+		})
+	}
+}
+
+// TestRunSameStem a.png 与 a.jpg 分别写入结果，不互相覆盖
+func TestRunSameStem(t *testing.T) {
+	dir := t.TempDir()
+	writeImage(t, dir, "a.png", 20)
+	writeImage(t, dir, "a.jpg", 30)
+
+	engine := ocrtest.NewFakeEngine()
+	engine.RecognizeFunc = func(img image.Image, _ bool) ([]ocr.TextBlock, error) {
+		return []ocr.TextBlock{{Text: "width " + strings.Repeat("x", img.Bounds().Dx()/10), Width: 10, Height: 10}}, nil
+	}
+	summary, err := Run(context.Background(), engine, dir, Options{})
+	if err != nil || summary.Succeeded != 2 {
+		t.Fatalf("Run = %+v, %v", summary, err)
+	}
+	for name, want := range map[string]string{"a.png.txt": "width xx\n", "a.jpg.txt": "width xxx\n"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v，期望 %q", name, data, err, want)
+		}
+	}
+}
+
+func TestRunResume(t *testing.T) {
+	dir, out := t.TempDir(), t.TempDir()
+	for i, rel := range []string{"a.png", "b.png", filepath.Join("sub", "c.png"), "bad.png"} {
+		writeImage(t, dir, rel, 10*(i+1))
+	}
+	// 宽为 40 的 bad.png 识别失败
+	engine := ocrtest.NewFakeEngine(ocr.TextBlock{Text: "hello", Width: 10, Height: 10})
+	engine.RecognizeFunc = func(img image.Image, _ bool) ([]ocr.TextBlock, error) {
+		if img.Bounds().Dx() == 40 {
+			return nil, errors.New("引擎出错")
+		}
+		return []ocr.TextBlock{{Text: "hello", Width: 10, Height: 10}}, nil
+	}
+	opts := Options{OutDir: out, Recursive: true, Resume: true, Format: "json"}
+
+	summary, err := Run(context.Background(), engine, dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Total != 4 || summary.Succeeded != 3 || summary.Failed != 1 || summary.Blocks != 3 ||
+		!strings.Contains(summary.Failures["bad.png"], "引擎出错") {
+		t.Fatalf("第一次运行 %+v", summary)
+	}
+	if _, err := os.Stat(filepath.Join(out, "sub", "c.json")); err != nil {
+		t.Errorf("子目录的结果: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(out, "bad.json")); err == nil {
+		t.Error("失败的图片不应留下结果文件")
+	}
+	entries := readManifest(t, out)
+	if len(entries) != 4 {
+		t.Fatalf("清单 %+v", entries)
+	}
+	for _, e := range entries {
+		if (e.Path == "bad.png") != (e.Error != "") || e.Output != strings.TrimSuffix(e.Path, ".png")+".json" {
+			t.Errorf("清单记录 %+v", e)
+		}
+	}
+
+	// 续跑：已成功的图片跳过，失败的重试
+	calls := len(engine.Calls())
+	summary, err = Run(context.Background(), engine, dir, opts)
+	if err != nil || summary.Skipped != 3 || summary.Failed != 1 || len(engine.Calls()) != calls+1 {
+		t.Fatalf("续跑 %+v, %v（识别 %d 次）", summary, err, len(engine.Calls())-calls)
+	}
+
+	// 修改过的图片和结果文件被删除的图片重新识别
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "a.png"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(out, "b.json")); err != nil {
+		t.Fatal(err)
+	}
+	var redone []string
+	opts.Progress = func(p Progress) {
+		if !p.Skipped && p.Err == nil {
+			redone = append(redone, p.Path)
+		}
+	}
+	summary, err = Run(context.Background(), engine, dir, opts)
+	if err != nil || summary.Succeeded != 2 || summary.Skipped != 1 {
+		t.Fatalf("修改后续跑 %+v, %v", summary, err)
+	}
+	if strings.Join(redone, ",") != "a.png,b.png" && strings.Join(redone, ",") != "b.png,a.png" {
+		t.Errorf("重新识别了 %v", redone)
+	}
+
+	// 关闭续跑时全部重新识别
+	opts.Resume, opts.Progress = false, nil
+	if summary, _ := Run(context.Background(), engine, dir, opts); summary.Skipped != 0 || summary.Succeeded != 3 {
+		t.Errorf("不续跑 %+v", summary)
+	}
+}
+
+func TestRunWorkers(t *testing.T) {
+	dir := t.TempDir()
+	for _, rel := range []string{"1.png", "2.png", "3.png", "4.png", "5.png", "6.png"} {
+		writeImage(t, dir, rel, 10)
+	}
+	cases := []struct {
+		name        string
+		workers     int
+		concurrency int
+		want        int
+	}{
+		// 引擎声明的并发限制优先于指定的并发数
+		{"clamped", 8, 1, 1},
+		{"clamped-2", 8, 2, 2},
+		{"below-limit", 2, 4, 2},
+		{"default", 0, 0, runtime.NumCPU()},
+		{"default-clamped", 0, 1, 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			engine := ocrtest.NewFakeEngine(ocr.TextBlock{Text: "x", Width: 1, Height: 1})
+			engine.Concurrency = c.concurrency
+			engine.Delay = 20 * time.Millisecond
+			summary, err := Run(context.Background(), engine, dir, Options{OutDir: t.TempDir(), Workers: c.workers})
+			if err != nil || summary.Succeeded != 6 {
+				t.Fatalf("Run = %+v, %v", summary, err)
+			}
+			if summary.Workers != c.want {
+				t.Errorf("并发数 %d，期望 %d", summary.Workers, c.want)
+			}
+			if c.concurrency > 0 && engine.MaxActive() > c.concurrency {
+				t.Errorf("最多同时识别 %d 张，超过引擎限制 %d", engine.MaxActive(), c.concurrency)
+			}
+		})
+	}
+}
+
+// TestRunCancel 取消后不再开始新的图片，进行中的图片完成并记入清单，重新运行时从中断处继续
+func TestRunCancel(t *testing.T) {
+	dir := t.TempDir()
+	for _, rel := range []string{"1.png", "2.png", "3.png", "4.png"} {
+		writeImage(t, dir, rel, 10)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	engine := ocrtest.NewFakeEngine()
+	engine.RecognizeFunc = func(image.Image, bool) ([]ocr.TextBlock, error) {
+		cancel()
+		return []ocr.TextBlock{{Text: "x", Width: 1, Height: 1}}, nil
+	}
+	summary, err := Run(ctx, engine, dir, Options{Workers: 1, Resume: true})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("错误 %v，期望 context.Canceled", err)
+	}
+	if summary.Total != 4 || summary.Succeeded != 1 || len(engine.Calls()) != 1 {
+		t.Fatalf("取消后 %+v（识别 %d 次）", summary, len(engine.Calls()))
+	}
+	if entries := readManifest(t, dir); len(entries) != 1 || entries[0].Path != "1.png" || entries[0].Error != "" {
+		t.Errorf("清单 %+v", entries)
+	}
+
+	engine.RecognizeFunc = nil
+	engine.Blocks = []ocr.TextBlock{{Text: "x", Width: 1, Height: 1}}
+	summary, err = Run(context.Background(), engine, dir, Options{Workers: 1, Resume: true})
+	if err != nil || summary.Skipped != 1 || summary.Succeeded != 3 {
+		t.Errorf("重新运行 %+v, %v", summary, err)
+	}
+}
+
+func TestRunInvalid(t *testing.T) {
+	engine := ocrtest.NewFakeEngine()
+	if _, err := Run(context.Background(), engine, t.TempDir(), Options{Format: "docx"}); err == nil || !strings.Contains(err.Error(), "不支持的输出格式") {
+		t.Errorf("未知格式: %v", err)
+	}
+	if _, err := Run(context.Background(), engine, filepath.Join(t.TempDir(), "missing"), Options{}); err == nil {
+		t.Error("目录不存在时应当报错")
+	}
+
+	dir := t.TempDir()
+	writeImage(t, dir, "a.png", 10)
+	writeImage(t, dir, "a.jpg", 10)
+	writeImage(t, dir, "a.png.bmp", 10)
+	if _, err := Run(context.Background(), engine, dir, Options{}); err == nil || !strings.Contains(err.Error(), "重名") {
+		t.Errorf("结果文件重名: %v", err)
+	}
+	if len(engine.Calls()) != 0 {
+		t.Error("结果文件重名时不应开始识别")
+	}
+}
@@ -0,0 +1,91 @@
+package batch
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// ManifestName 批量识别清单文件名，保存在输出目录中
+const ManifestName = ".screenocr-batch.jsonl"
+
+// Entry 清单中一张图片的处理记录
+type Entry struct {
+	Path    string    `json:"path"`            // 相对于输入目录的路径
+	Size    int64     `json:"size"`            // 图片文件大小
+	ModTime time.Time `json:"mod_time"`        // 图片修改时间
+	Output  string    `json:"output"`          // 相对于输出目录的结果路径
+	Blocks  int       `json:"blocks"`          // 文本块数量
+	Error   string    `json:"error,omitempty"` // 失败原因，成功时为空
+	Time    time.Time `json:"time"`            // 处理完成时间
+}
+
+// manifest 追加写入的处理清单（每行一条 JSON 记录），后写入的记录覆盖先前的同名记录
+// 每条记录写入后立即同步到磁盘，中断后可以据此跳过已完成的图片
+type manifest struct {
+	mu      sync.Mutex
+	file    *os.File
+	entries map[string]Entry
+}
+
+// openManifest 打开（必要时创建）清单并读取已有记录
+func openManifest(path string) (*manifest, error) {
+	m := &manifest{entries: make(map[string]Entry)}
+
+	if f, err := os.Open(path); err == nil {
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			var e Entry
+			// 中断时可能留下不完整的最后一行，忽略即可
+			if err := json.Unmarshal(scanner.Bytes(), &e); err == nil && e.Path != "" {
+				m.entries[e.Path] = e
+			}
+		}
+		f.Close()
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("打开清单文件失败: %w", err)
+	}
+	m.file = f
+	return m, nil
+}
+
+// done 检查图片是否已成功处理且之后没有修改过
+func (m *manifest) done(path string, info os.FileInfo) (Entry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[path]
+	if !ok || e.Error != "" || e.Size != info.Size() || !e.ModTime.Equal(info.ModTime()) {
+		return e, false
+	}
+	return e, true
+}
+
+// record 追加一条记录
+func (m *manifest) record(e Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[e.Path] = e
+	if _, err := m.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("写入清单失败: %w", err)
+	}
+	return m.file.Sync()
+}
+
+// Close 关闭清单文件
+func (m *manifest) Close() error {
+	return m.file.Close()
+}
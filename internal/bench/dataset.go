@@ -0,0 +1,102 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"screenocr-wails/internal/ocr"
+)
+
+// Sample 测试样本：一张图片及其标注
+type Sample struct {
+	Name      string          // 文件名（不含扩展名）
+	ImagePath string          // 图片路径
+	Text      string          // 标注文本
+	Boxes     []ocr.TextBlock // 标注文本框（可选）
+}
+
+// imageExts 支持的图片扩展名
+var imageExts = map[string]bool{
+	".png":  true,
+	".jpg":  true,
+	".jpeg": true,
+	".gif":  true,
+}
+
+// LoadDataset 加载测试集目录
+// 每张图片 name.png 对应标注文件 name.txt（纯文本）和/或 name.json（TextBlock 数组）
+// 只有 name.json 时，标注文本由文本框按阅读顺序拼接得到
+func LoadDataset(dir string) ([]Sample, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取测试集目录失败: %w", err)
+	}
+
+	var samples []Sample
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if !imageExts[ext] {
+			continue
+		}
+
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		sample := Sample{
+			Name:      name,
+			ImagePath: filepath.Join(dir, entry.Name()),
+		}
+
+		hasTruth := false
+		if data, err := os.ReadFile(filepath.Join(dir, name+".json")); err == nil {
+			if err := json.Unmarshal(data, &sample.Boxes); err != nil {
+				return nil, fmt.Errorf("解析标注文件 %s.json 失败: %w", name, err)
+			}
+			sample.Text = ocr.JoinText(sample.Boxes)
+			hasTruth = true
+		}
+		if data, err := os.ReadFile(filepath.Join(dir, name+".txt")); err == nil {
+			sample.Text = strings.TrimSpace(string(data))
+			hasTruth = true
+		}
+
+		if !hasTruth {
+			fmt.Printf("[Bench] 跳过 %s：缺少标注文件\n", entry.Name())
+			continue
+		}
+		samples = append(samples, sample)
+	}
+
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("测试集 %s 中没有带标注的图片", dir)
+	}
+
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].Name < samples[j].Name
+	})
+	return samples, nil
+}
+
+// loadImage 读取并解码图片
+func loadImage(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("解码图片失败: %w", err)
+	}
+	return img, nil
+}
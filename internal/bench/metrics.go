@@ -0,0 +1,186 @@
+package bench
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"screenocr-wails/internal/ocr"
+)
+
+// CharErrorRate 字符错误率（CER）：编辑距离 / 标注字符数
+// 比较前去掉所有空白，避免不同引擎对中文空格处理不一致影响结果
+func CharErrorRate(reference, hypothesis string) float64 {
+	ref := []rune(stripSpace(reference))
+	hyp := []rune(stripSpace(hypothesis))
+	return errorRate(ref, hyp)
+}
+
+// WordErrorRate 词错误率（WER）：按词计算的编辑距离 / 标注词数
+// 英文按空白分词，中日韩文字每个字视为一个词
+func WordErrorRate(reference, hypothesis string) float64 {
+	return errorRate(tokenize(reference), tokenize(hypothesis))
+}
+
+// errorRate 计算编辑距离与参考长度之比
+func errorRate[T comparable](ref, hyp []T) float64 {
+	if len(ref) == 0 {
+		if len(hyp) == 0 {
+			return 0
+		}
+		return 1
+	}
+	return float64(levenshtein(ref, hyp)) / float64(len(ref))
+}
+
+// levenshtein 计算编辑距离（插入、删除、替换代价均为 1）
+func levenshtein[T comparable](a, b []T) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// stripSpace 去掉所有空白字符
+func stripSpace(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// tokenize 分词：中日韩文字单独成词，其余按空白分隔
+func tokenize(s string) []string {
+	var tokens []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			tokens = append(tokens, string(current))
+			current = current[:0]
+		}
+	}
+
+	for _, r := range s {
+		switch {
+		case unicode.IsSpace(r):
+			flush()
+		case unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+			unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r):
+			flush()
+			tokens = append(tokens, string(r))
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+// IoU 计算两个文本框的交并比
+func IoU(a, b ocr.TextBlock) float64 {
+	x1 := max(a.X, b.X)
+	y1 := max(a.Y, b.Y)
+	x2 := min(a.X+a.Width, b.X+b.Width)
+	y2 := min(a.Y+a.Height, b.Y+b.Height)
+	if x2 <= x1 || y2 <= y1 {
+		return 0
+	}
+
+	inter := float64((x2 - x1) * (y2 - y1))
+	union := float64(a.Width*a.Height+b.Width*b.Height) - inter
+	if union <= 0 {
+		return 0
+	}
+	return inter / union
+}
+
+// BoxRecall 文本框召回率：与某个识别框 IoU 不低于阈值的标注框占比
+// 识别框只能匹配一个标注框，按二分图最大匹配计算，不受 IoU 相同时匹配顺序的影响
+func BoxRecall(truth, predicted []ocr.TextBlock, threshold float64) float64 {
+	if len(truth) == 0 {
+		return 1
+	}
+
+	// candidates[i] 为 IoU 达到阈值的识别框，按 IoU 从大到小（相同时按下标）排列
+	candidates := make([][]int, len(truth))
+	for i, t := range truth {
+		ious := make(map[int]float64)
+		for j, p := range predicted {
+			if iou := IoU(t, p); iou >= threshold {
+				candidates[i] = append(candidates[i], j)
+				ious[j] = iou
+			}
+		}
+		sort.SliceStable(candidates[i], func(a, b int) bool {
+			return ious[candidates[i][a]] > ious[candidates[i][b]]
+		})
+	}
+
+	// 增广路径：为标注框 i 找到空闲的识别框，或让占用者改配其他识别框
+	owner := make([]int, len(predicted))
+	for j := range owner {
+		owner[j] = -1
+	}
+	var augment func(i int, visited []bool) bool
+	augment = func(i int, visited []bool) bool {
+		for _, j := range candidates[i] {
+			if visited[j] {
+				continue
+			}
+			visited[j] = true
+			if owner[j] < 0 || augment(owner[j], visited) {
+				owner[j] = i
+				return true
+			}
+		}
+		return false
+	}
+
+	matched := 0
+	for i := range truth {
+		if augment(i, make([]bool, len(predicted))) {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(truth))
+}
+
+// Percentile 计算耗时分位数（p 取 0-100，最近秩法）
+func Percentile(durations []time.Duration, p float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sorted := make([]time.Duration, len(durations))
+	copy(sorted, durations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	// 先乘后除：p / 100 * n 的舍入误差会让 7% × 100 的秩变成 8
+	rank := int(math.Ceil(p * float64(len(sorted)) / 100))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,193 @@
+package bench
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"screenocr-wails/internal/ocr"
+)
+
+// almostEqual 比较手算的分数（如 1/3）
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestCharErrorRate(t *testing.T) {
+	cases := []struct {
+		name     string
+		ref, hyp string
+		want     float64
+	}{
+		{"identical", "hello", "hello", 0},
+		{"substitution", "abc", "abd", 1.0 / 3},
+		{"deletion", "你好世界", "你好", 0.5},
+		// 插入的字符多于标注时错误率超过 1
+		{"insertion", "ab", "abcdef", 2},
+		// 空白不参与比较
+		{"spaces", "识别 结果\n", " 识别结果", 0},
+		{"empty-both", "", "", 0},
+		{"empty-reference", "", "abc", 1},
+		{"blank-reference", " \t", "", 0},
+		{"empty-hypothesis", "abc", "", 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := CharErrorRate(c.ref, c.hyp); !almostEqual(got, c.want) {
+				t.Errorf("CharErrorRate(%q, %q) = %v，期望 %v", c.ref, c.hyp, got, c.want)
+			}
+		})
+	}
+}
+
+func TestWordErrorRate(t *testing.T) {
+	cases := []struct {
+		name     string
+		ref, hyp string
+		want     float64
+	}{
+		{"identical", "the quick brown fox", "the quick  brown\nfox", 0},
+		{"substitution", "the quick brown fox", "the quick brown box", 0.25},
+		// 删除 the、插入 jumps
+		{"shifted", "the quick brown fox", "quick brown fox jumps", 0.5},
+		// 中日韩文字每个字一个词，与空格无关
+		{"cjk", "打开设置", "打开 设置", 0},
+		{"cjk-substitution", "hello 世界", "hello 世间", 1.0 / 3},
+		{"mixed", "OCR识别", "OCR 识别", 0},
+		// 标点与大小写不做归一化
+		{"punctuation", "Hello, world", "hello world", 0.5},
+		{"empty-both", "", "", 0},
+		{"empty-reference", "", "a", 1},
+		{"empty-hypothesis", "a b", "", 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := WordErrorRate(c.ref, c.hyp); !almostEqual(got, c.want) {
+				t.Errorf("WordErrorRate(%q, %q) = %v，期望 %v", c.ref, c.hyp, got, c.want)
+			}
+		})
+	}
+}
+
+// box 创建只有坐标的文本块
+func box(x, y, width, height int) ocr.TextBlock {
+	return ocr.TextBlock{X: x, Y: y, Width: width, Height: height}
+}
+
+func TestIoU(t *testing.T) {
+	cases := []struct {
+		name string
+		a, b ocr.TextBlock
+		want float64
+	}{
+		{"identical", box(0, 0, 10, 10), box(0, 0, 10, 10), 1},
+		// 交集 50，并集 150
+		{"half-overlap", box(0, 0, 10, 10), box(5, 0, 10, 10), 1.0 / 3},
+		// 交集 4，并集 28
+		{"corner", box(0, 0, 4, 4), box(2, 2, 4, 4), 1.0 / 7},
+		{"contained", box(0, 0, 10, 10), box(2, 2, 5, 5), 0.25},
+		{"touching", box(0, 0, 10, 10), box(10, 0, 10, 10), 0},
+		{"disjoint", box(0, 0, 10, 10), box(50, 50, 10, 10), 0},
+		{"zero-area", box(0, 0, 0, 10), box(0, 0, 0, 10), 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := IoU(c.a, c.b); !almostEqual(got, c.want) {
+				t.Errorf("IoU = %v，期望 %v", got, c.want)
+			}
+			if got := IoU(c.b, c.a); !almostEqual(got, c.want) {
+				t.Errorf("交换参数后 IoU = %v，期望 %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestBoxRecall(t *testing.T) {
+	// wide 与 left、right 的 IoU 都是 0.5，tall 只与 left 的 IoU 为 0.5
+	wide, tall := box(0, 0, 20, 10), box(0, 0, 10, 20)
+	left, right := box(0, 0, 10, 10), box(10, 0, 10, 10)
+
+	cases := []struct {
+		name      string
+		truth     []ocr.TextBlock
+		predicted []ocr.TextBlock
+		threshold float64
+		want      float64
+	}{
+		{"no-truth", nil, []ocr.TextBlock{left}, 0.5, 1},
+		{"no-prediction", []ocr.TextBlock{left, right}, nil, 0.5, 0},
+		{"all-matched", []ocr.TextBlock{left, right}, []ocr.TextBlock{right, left}, 0.5, 1},
+		// IoU 为 1/3
+		{"below-threshold", []ocr.TextBlock{box(0, 0, 10, 10)}, []ocr.TextBlock{box(5, 0, 10, 10)}, 0.5, 0},
+		{"lower-threshold", []ocr.TextBlock{box(0, 0, 10, 10)}, []ocr.TextBlock{box(5, 0, 10, 10)}, 0.3, 1},
+		// IoU 恰好等于阈值时算匹配
+		{"at-threshold", []ocr.TextBlock{wide}, []ocr.TextBlock{left}, 0.5, 1},
+		// 一个识别框只能匹配一个标注框
+		{"shared-prediction", []ocr.TextBlock{left, left}, []ocr.TextBlock{left}, 0.5, 0.5},
+		// IoU 相同时不论先处理哪个组合，wide 都让出 left 给 tall
+		{"tie", []ocr.TextBlock{wide, tall}, []ocr.TextBlock{left, right}, 0.5, 1},
+		{"tie-reversed", []ocr.TextBlock{tall, wide}, []ocr.TextBlock{right, left}, 0.5, 1},
+		// IoU 最大的组合（box(0,0,11,10) 与 left，0.91）让位：改配 box(1,0,11,10)（0.83）后 tall 才能匹配 left
+		{"not-greedy", []ocr.TextBlock{box(0, 0, 11, 10), tall}, []ocr.TextBlock{left, box(1, 0, 11, 10)}, 0.5, 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := BoxRecall(c.truth, c.predicted, c.threshold); !almostEqual(got, c.want) {
+				t.Errorf("BoxRecall = %v，期望 %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	ms := func(values ...int) []time.Duration {
+		durations := make([]time.Duration, len(values))
+		for i, v := range values {
+			durations[i] = time.Duration(v) * time.Millisecond
+		}
+		return durations
+	}
+	hundred := make([]int, 100)
+	for i := range hundred {
+		hundred[i] = 100 - i
+	}
+
+	cases := []struct {
+		name   string
+		values []time.Duration
+		p      float64
+		want   time.Duration
+	}{
+		{"empty", nil, 50, 0},
+		{"single", ms(7), 99, 7 * time.Millisecond},
+		// 最近秩法：秩为 ceil(p / 100 × n)
+		{"p50", ms(40, 10, 30, 20), 50, 20 * time.Millisecond},
+		{"p25", ms(40, 10, 30, 20), 25, 10 * time.Millisecond},
+		{"p26", ms(40, 10, 30, 20), 26, 20 * time.Millisecond},
+		{"p90", ms(40, 10, 30, 20), 90, 40 * time.Millisecond},
+		{"p0", ms(40, 10, 30, 20), 0, 10 * time.Millisecond},
+		{"p100", ms(40, 10, 30, 20), 100, 40 * time.Millisecond},
+		{"above-100", ms(40, 10, 30, 20), 150, 40 * time.Millisecond},
+		{"negative", ms(40, 10, 30, 20), -5, 10 * time.Millisecond},
+		// 相同的耗时
+		{"ties-p75", ms(5, 9, 5, 5), 75, 5 * time.Millisecond},
+		{"ties-p76", ms(5, 9, 5, 5), 76, 9 * time.Millisecond},
+		// 7 × 100 / 100 恰好为整数，不能因浮点误差取到第 8 个
+		{"exact-rank", ms(hundred...), 7, 7 * time.Millisecond},
+		{"p99", ms(hundred...), 99, 99 * time.Millisecond},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := Percentile(c.values, c.p); got != c.want {
+				t.Errorf("Percentile(p%v) = %v，期望 %v", c.p, got, c.want)
+			}
+		})
+	}
+
+	// 不修改传入的切片
+	values := ms(3, 1, 2)
+	Percentile(values, 50)
+	if values[0] != 3*time.Millisecond || values[1] != time.Millisecond {
+		t.Errorf("传入的切片被排序: %v", values)
+	}
+}
@@ -0,0 +1,34 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// WriteJSON 输出 JSON 格式报告
+func WriteJSON(w io.Writer, reports []Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(reports)
+}
+
+// WriteTable 输出便于阅读的对比表格
+func WriteTable(w io.Writer, reports []Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "引擎\t预处理\t样本\t失败\tCER\tWER\t框召回\tp50(ms)\tp90(ms)\tp99(ms)\t")
+	for _, r := range reports {
+		s := r.Summary
+		preprocess := "关"
+		if r.Preprocess {
+			preprocess = "开"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%.2f%%\t%.2f%%\t%.2f%%\t%.0f\t%.0f\t%.0f\t\n",
+			r.Engine, preprocess, s.Samples, s.Failures,
+			s.CER*100, s.WER*100, s.BoxRecall*100,
+			s.P50Ms, s.P90Ms, s.P99Ms)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,141 @@
+package bench
+
+import (
+	"fmt"
+	"time"
+
+	"screenocr-wails/internal/ocr"
+)
+
+// Options 评测选项
+type Options struct {
+	Preprocess   bool    // 是否启用图像预处理
+	Runs         int     // 每张图片识别次数（用于统计耗时，精度指标取第一次结果）
+	IoUThreshold float64 // 文本框匹配的 IoU 阈值
+}
+
+// SampleResult 单张图片的评测结果
+type SampleResult struct {
+	Name      string   `json:"name"`
+	CER       float64  `json:"cer"`
+	WER       float64  `json:"wer"`
+	BoxRecall *float64 `json:"box_recall,omitempty"` // 无文本框标注时为空
+	Blocks    int      `json:"blocks"`
+	LatencyMs []int64  `json:"latency_ms"`
+	Text      string   `json:"text"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// Summary 汇总指标
+type Summary struct {
+	Samples   int     `json:"samples"`
+	Failures  int     `json:"failures"`
+	CER       float64 `json:"cer"`
+	WER       float64 `json:"wer"`
+	BoxRecall float64 `json:"box_recall"`
+	P50Ms     float64 `json:"p50_ms"`
+	P90Ms     float64 `json:"p90_ms"`
+	P99Ms     float64 `json:"p99_ms"`
+	MeanMs    float64 `json:"mean_ms"`
+}
+
+// Report 一个引擎 + 预处理组合的评测报告
+type Report struct {
+	Engine     string         `json:"engine"`
+	Preprocess bool           `json:"preprocess"`
+	Summary    Summary        `json:"summary"`
+	Results    []SampleResult `json:"results"`
+}
+
+// Run 用指定引擎评测整个测试集
+func Run(name string, engine ocr.Engine, samples []Sample, opts Options) Report {
+	if opts.Runs < 1 {
+		opts.Runs = 1
+	}
+	if opts.IoUThreshold <= 0 {
+		opts.IoUThreshold = 0.5
+	}
+
+	report := Report{Engine: name, Preprocess: opts.Preprocess}
+	var latencies []time.Duration
+	var cerSum, werSum, recallSum float64
+	recallCount := 0
+
+	for i, sample := range samples {
+		fmt.Printf("[Bench] %s (预处理=%v) [%d/%d] %s\n", name, opts.Preprocess, i+1, len(samples), sample.Name)
+
+		result := SampleResult{Name: sample.Name}
+		img, err := loadImage(sample.ImagePath)
+		if err != nil {
+			result.Error = err.Error()
+			report.Results = append(report.Results, result)
+			report.Summary.Failures++
+			continue
+		}
+
+		var blocks []ocr.TextBlock
+		for run := 0; run < opts.Runs; run++ {
+			start := time.Now()
+			got, err := engine.Recognize(img, opts.Preprocess)
+			elapsed := time.Since(start)
+			if err != nil {
+				result.Error = err.Error()
+				break
+			}
+			if run == 0 {
+				blocks = got
+			}
+			latencies = append(latencies, elapsed)
+			result.LatencyMs = append(result.LatencyMs, elapsed.Milliseconds())
+		}
+
+		if result.Error != "" {
+			report.Results = append(report.Results, result)
+			report.Summary.Failures++
+			continue
+		}
+
+		result.Blocks = len(blocks)
+		result.Text = ocr.JoinText(blocks)
+		result.CER = CharErrorRate(sample.Text, result.Text)
+		result.WER = WordErrorRate(sample.Text, result.Text)
+		cerSum += result.CER
+		werSum += result.WER
+
+		if len(sample.Boxes) > 0 {
+			recall := BoxRecall(sample.Boxes, blocks, opts.IoUThreshold)
+			result.BoxRecall = &recall
+			recallSum += recall
+			recallCount++
+		}
+
+		report.Results = append(report.Results, result)
+	}
+
+	succeeded := len(samples) - report.Summary.Failures
+	report.Summary.Samples = len(samples)
+	if succeeded > 0 {
+		report.Summary.CER = cerSum / float64(succeeded)
+		report.Summary.WER = werSum / float64(succeeded)
+	}
+	if recallCount > 0 {
+		report.Summary.BoxRecall = recallSum / float64(recallCount)
+	}
+	if len(latencies) > 0 {
+		var total time.Duration
+		for _, d := range latencies {
+			total += d
+		}
+		report.Summary.MeanMs = toMs(total / time.Duration(len(latencies)))
+		report.Summary.P50Ms = toMs(Percentile(latencies, 50))
+		report.Summary.P90Ms = toMs(Percentile(latencies, 90))
+		report.Summary.P99Ms = toMs(Percentile(latencies, 99))
+	}
+
+	return report
+}
+
+// toMs 转换为毫秒（保留小数）
+func toMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
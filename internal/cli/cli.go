@@ -0,0 +1,590 @@
+// Package cli 命令行模式的子命令（recognize、batch、watch、translate 等），不依赖界面、热键和托盘，可在任意平台构建和测试
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"screenocr-wails/internal/archive"
+	"screenocr-wails/internal/batch"
+	"screenocr-wails/internal/config"
+	"screenocr-wails/internal/ocr"
+	"screenocr-wails/internal/ocrformat"
+	"screenocr-wails/internal/pdf"
+	"screenocr-wails/internal/redact"
+	"screenocr-wails/internal/screenshot"
+	"screenocr-wails/internal/translator"
+	"screenocr-wails/internal/watch"
+
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/webp"
+)
+
+// CopyToClipboard 把文字复制到剪贴板，由桌面程序设置（watch -copy 使用），为 nil 时不支持复制
+var CopyToClipboard func(text string) error
+
+// commands 命令行子命令，cfg 为配置文件中的设置，作为选项的默认值
+var commands = map[string]func(cfg config.Config, args []string, stdout io.Writer) error{
+	"recognize":   cmdRecognize,
+	"batch":       cmdBatch,
+	"watch":       cmdWatch,
+	"translate":   cmdTranslate,
+	"engines":     cmdEngines,
+	"translators": cmdTranslators,
+	"help":        cmdHelp,
+}
+
+// IsCommand 检查参数是否是命令行子命令
+func IsCommand(arg string) bool {
+	_, ok := commands[arg]
+	return ok || arg == "-h" || arg == "--help"
+}
+
+// Run 以命令行模式运行子命令（args[0] 为子命令名称），返回进程退出码
+func Run(args []string) int {
+	// 结果写入标准输出，各组件的日志改写到标准错误，避免污染管道
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = stdout }()
+
+	name := args[0]
+	if name == "-h" || name == "--help" {
+		name = "help"
+	}
+
+	if err := commands[name](loadConfig(), args[1:], stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "错误:", err)
+		return 1
+	}
+	return 0
+}
+
+// cmdHelp 输出帮助信息
+func cmdHelp(_ config.Config, args []string, stdout io.Writer) error {
+	fmt.Fprint(stdout, `ScreenOCR 命令行用法:
+
+  screenocr recognize [选项] <图片|->   识别图片中的文字（- 表示从标准输入读取）
+  screenocr recognize -window foreground|cursor [-delay 3s]
+                                        识别前台窗口或鼠标下的窗口
+  screenocr batch [选项] <目录>         批量识别目录中的图片（可中断后续跑）
+  screenocr watch [选项] [目录...]      监视目录，自动识别新截图并写入同名 .txt
+  screenocr translate [选项] <文本|->   翻译文本（- 或省略表示从标准输入读取）
+  screenocr engines                     列出 OCR 引擎及其可用状态
+  screenocr translators [-usage] [-glossaries]
+                                        列出翻译服务及其配置状态、剩余字符和 DeepL 术语表
+
+使用 screenocr <子命令> -h 查看子命令选项。
+`)
+	return nil
+}
+
+// cmdRecognize 识别图片
+func cmdRecognize(cfg config.Config, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("recognize", flag.ContinueOnError)
+	engineName := fs.String("engine", cfg.OcrEngine, "OCR 引擎名称（见 screenocr engines）")
+	preprocess := fs.Bool("preprocess", cfg.ImagePreprocess, "启用图像预处理（增强对比度）")
+	route := fs.Bool("route", cfg.OcrRouting, "按书写系统把区域路由到不同引擎")
+	split := fs.Bool("split", false, "把文本块拆分为单字（中文）/单词（英文）")
+	format := fs.String("format", "text", "输出格式: text / json / tsv / hocr / alto / page / pdf")
+	jpegQuality := fs.Int("jpeg", 0, "pdf 格式中截图的 JPEG 质量（1-100），0 表示无损")
+	window := fs.String("window", "", "识别窗口而不是图片: foreground（前台窗口）/ cursor（鼠标下的窗口）")
+	delay := fs.Duration("delay", 0, "截取窗口前等待的时间（用于切换到目标窗口）")
+	redactOut := fs.Bool("redact", false, "打码结果中的敏感内容（pdf 格式同时遮盖截图）")
+	archiveOut := fs.Bool("archive", false, "同时把图片和识别结果保存到截图存档（按配置打码）")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	input := "-"
+	if fs.NArg() > 0 {
+		input = fs.Arg(0)
+	}
+
+	var img image.Image
+	var source string
+	var err error
+	if *window != "" {
+		if fs.NArg() > 0 {
+			return fmt.Errorf("-window 不能与图片参数同时使用")
+		}
+		var w screenshot.Window
+		img, w, err = captureWindow(*window, *delay)
+		source = w.Label()
+		fmt.Printf("✓ 已截取窗口: %s %v\n", source, w.Bounds)
+	} else {
+		img, err = readImage(input)
+	}
+	if err != nil {
+		return err
+	}
+
+	engine, err := newEngine(cfg, *engineName, *route)
+	if err != nil {
+		return err
+	}
+	defer engine.Close()
+
+	blocks, err := engine.Recognize(img, *preprocess)
+	if err != nil {
+		return err
+	}
+	label := source
+	if label == "" {
+		label = input
+	}
+	if *archiveOut {
+		archived, archivedBlocks := newRedactor(cfg, cfg.Redaction.Enabled).Page(img, blocks, "archive", label)
+		path, err := archive.Save(archive.Capture{Time: time.Now(), Source: source, Image: archived, Blocks: archivedBlocks}, cfg.Archive)
+		if err != nil {
+			return err
+		}
+		fmt.Println("✓ 已保存到截图存档:", path)
+	}
+	if *redactOut {
+		img, blocks = newRedactor(cfg, true).Page(img, blocks, "recognize", label)
+	}
+	if *split {
+		blocks = ocr.SplitTextBlocks(blocks)
+	}
+
+	page := ocrformat.Page{
+		Source: source,
+		Width:  img.Bounds().Dx(),
+		Height: img.Bounds().Dy(),
+		Blocks: blocks,
+	}
+	if input != "-" && *window == "" {
+		page.Image = filepath.Base(input)
+	}
+
+	if *format == "pdf" {
+		opts := pdf.Options{Title: page.Image}
+		if source != "" {
+			opts.Title = source
+		}
+		if *jpegQuality > 0 {
+			opts.Format = pdf.JPEG
+			opts.Quality = *jpegQuality
+		}
+		return pdf.Write(stdout, img, blocks, opts)
+	}
+	return ocrformat.Write(stdout, page, *format)
+}
+
+// captureWindow 等待 delay 后截取前台窗口（mode 为 foreground）或鼠标下的窗口（cursor）
+func captureWindow(mode string, delay time.Duration) (image.Image, screenshot.Window, error) {
+	var pick func(screenshot.Capturer) (screenshot.Window, error)
+	switch mode {
+	case "foreground":
+		pick = screenshot.ForegroundWindow
+	case "cursor":
+		pick = screenshot.WindowUnderCursor
+	default:
+		return nil, screenshot.Window{}, fmt.Errorf("未知的窗口选择方式 %q（可选 foreground / cursor）", mode)
+	}
+
+	capturer, err := screenshot.NewCapturer()
+	if err != nil {
+		return nil, screenshot.Window{}, err
+	}
+	defer capturer.Close()
+
+	time.Sleep(delay)
+	w, err := pick(capturer)
+	if err != nil {
+		return nil, w, fmt.Errorf("获取窗口失败: %w", err)
+	}
+
+	img, _, err := screenshot.CaptureWindow(capturer, w)
+	if err != nil {
+		return nil, w, fmt.Errorf("截取窗口失败: %w", err)
+	}
+	return img, w, nil
+}
+
+// newRedactor 按配置中的打码策略创建打码器，enabled 为 false 时返回 nil（不打码）
+func newRedactor(cfg config.Config, enabled bool) *redact.Redactor {
+	if !enabled {
+		return nil
+	}
+	policy := cfg.Redaction
+	policy.Enabled = true
+	return redact.New(policy, redact.NewAudit(filepath.Join(config.Dir(), redact.AuditName)))
+}
+
+// newEngine 创建 OCR 引擎，route 为 true 时包装为路由引擎
+func newEngine(cfg config.Config, name string, route bool) (ocr.Engine, error) {
+	if name == "" {
+		name = "windows"
+	}
+	engine, err := ocr.New(name)
+	if err != nil {
+		return nil, err
+	}
+	if route {
+		rules := cfg.OcrRouteRules
+		if len(rules) == 0 {
+			rules = ocr.DefaultRouteRules()
+		}
+		engine = ocr.NewRouterEngine(engine, name, rules, ocr.New)
+	}
+
+	if !engine.IsAvailable() {
+		msg := engine.GetError()
+		engine.Close()
+		return nil, fmt.Errorf("OCR 引擎 %s 不可用: %s", name, msg)
+	}
+	return engine, nil
+}
+
+// cmdBatch 批量识别目录中的图片
+func cmdBatch(cfg config.Config, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("batch", flag.ContinueOnError)
+	engineName := fs.String("engine", cfg.OcrEngine, "OCR 引擎名称（见 screenocr engines）")
+	preprocess := fs.Bool("preprocess", cfg.ImagePreprocess, "启用图像预处理（增强对比度）")
+	route := fs.Bool("route", cfg.OcrRouting, "按书写系统把区域路由到不同引擎")
+	format := fs.String("format", "text", "输出格式: text / json / tsv / hocr / alto / page / pdf")
+	outDir := fs.String("out", "", "输出目录（默认与图片相同）")
+	workers := fs.Int("workers", 0, "并发数（默认 CPU 核数，不支持并发的引擎自动串行）")
+	recursive := fs.Bool("r", false, "递归处理子目录")
+	resume := fs.Bool("resume", true, "跳过清单中已完成且未修改的图片")
+	redactOut := fs.Bool("redact", cfg.Redaction.Enabled, "写入结果前打码敏感内容（pdf 格式同时遮盖截图）")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("用法: screenocr batch [选项] <目录>")
+	}
+
+	engine, err := newEngine(cfg, *engineName, *route)
+	if err != nil {
+		return err
+	}
+	defer engine.Close()
+
+	// Ctrl+C 时停止分派新图片，已完成的结果保留在清单中，重新运行即可续跑
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	summary, err := batch.Run(ctx, engine, fs.Arg(0), batch.Options{
+		Format:     *format,
+		OutDir:     *outDir,
+		Workers:    *workers,
+		Preprocess: *preprocess,
+		Recursive:  *recursive,
+		Resume:     *resume,
+		Redactor:   newRedactor(cfg, *redactOut),
+		Progress: func(p batch.Progress) {
+			switch {
+			case p.Skipped:
+				fmt.Fprintf(os.Stderr, "[%d/%d] %s 已完成，跳过\n", p.Done, p.Total, p.Path)
+			case p.Err != nil:
+				fmt.Fprintf(os.Stderr, "[%d/%d] %s ❌ %v\n", p.Done, p.Total, p.Path, p.Err)
+			default:
+				fmt.Fprintf(os.Stderr, "[%d/%d] %s ✓ %d 个文本块 (%dms)\n", p.Done, p.Total, p.Path, p.Blocks, p.Duration.Milliseconds())
+			}
+		},
+	})
+
+	fmt.Fprintf(stdout, "共 %d 张图片: 成功 %d，跳过 %d，失败 %d，文本块 %d，并发 %d，耗时 %s\n",
+		summary.Total, summary.Succeeded, summary.Skipped, summary.Failed, summary.Blocks, summary.Workers,
+		summary.Duration.Round(time.Millisecond))
+	if err != nil {
+		return fmt.Errorf("批量识别中断（重新运行可继续）: %w", err)
+	}
+	if summary.Failed > 0 {
+		return fmt.Errorf("%d 张图片识别失败", summary.Failed)
+	}
+	return nil
+}
+
+// cmdWatch 监视目录中的新截图（省略目录时使用配置中的 watch_folders）
+func cmdWatch(cfg config.Config, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	engineName := fs.String("engine", cfg.OcrEngine, "OCR 引擎名称（见 screenocr engines）")
+	preprocess := fs.Bool("preprocess", cfg.ImagePreprocess, "启用图像预处理（增强对比度）")
+	route := fs.Bool("route", cfg.OcrRouting, "按书写系统把区域路由到不同引擎")
+	copyText := fs.Bool("copy", cfg.WatchCopy, "识别后把文字复制到剪贴板")
+	existing := fs.Bool("existing", false, "同时处理目录中已有且没有 .txt 的图片")
+	settle := fs.Duration("settle", time.Second, "文件保持不变多久后才识别")
+	redactOut := fs.Bool("redact", cfg.Redaction.Enabled, "写入 .txt 前打码敏感内容")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *copyText && CopyToClipboard == nil {
+		return fmt.Errorf("当前平台不支持复制到剪贴板")
+	}
+
+	dirs := fs.Args()
+	if len(dirs) == 0 {
+		dirs = cfg.WatchFolders
+	}
+	if len(dirs) == 0 {
+		return fmt.Errorf("用法: screenocr watch [选项] <目录...>（或在配置中设置 watch_folders）")
+	}
+
+	engine, err := newEngine(cfg, *engineName, *route)
+	if err != nil {
+		return err
+	}
+	defer engine.Close()
+
+	w := watch.NewWatcher(engine, dirs, watch.Options{
+		Settle:          *settle,
+		Preprocess:      *preprocess,
+		ProcessExisting: *existing,
+		Redactor:        newRedactor(cfg, *redactOut),
+	})
+	w.OnResult = func(r watch.Result) {
+		if r.Err != nil {
+			return
+		}
+		fmt.Fprintf(stdout, "%s\n%s\n\n", r.Path, r.Text)
+		if *copyText && r.Text != "" {
+			if err := CopyToClipboard(r.Text); err != nil {
+				fmt.Fprintln(os.Stderr, "复制到剪贴板失败:", err)
+			}
+		}
+	}
+	w.Start()
+	defer w.Stop()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	<-ctx.Done()
+	return nil
+}
+
+// cmdTranslate 翻译文本
+func cmdTranslate(cfg config.Config, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("translate", flag.ContinueOnError)
+	source := fs.String("from", cfg.TranslationSource, "源语言（auto 表示自动检测）")
+	target := fs.String("to", cfg.TranslationTarget, "目标语言")
+	provider := fs.String("provider", cfg.TranslationProvider, "翻译服务（见 screenocr translators）")
+	secretID := fs.String("secret-id", "", "SecretId 等成对凭证中的 ID（默认读取配置文件）")
+	secretKey := fs.String("secret-key", "", "成对凭证中的密钥（默认读取配置文件）")
+	apiKey := fs.String("api-key", "", "API 密钥（默认读取配置文件）")
+	endpoint := fs.String("endpoint", "", "服务地址（默认读取配置文件）")
+	model := fs.String("model", "", "模型名称（默认读取配置文件）")
+	prompt := fs.String("prompt", "", "大模型的系统提示词模板（默认读取配置文件）")
+	temperature := fs.Float64("temperature", 0, "大模型的采样温度（默认读取配置文件）")
+	stream := fs.Bool("stream", false, "大模型使用流式输出，边生成边输出（默认读取配置文件）")
+	nearbyText := fs.String("context", "", "翻译时参考的上下文（如截图中附近的文字），大模型翻译时使用")
+	formality := fs.String("formality", "", "正式程度: more / less / prefer_more / prefer_less（DeepL，默认读取配置文件）")
+	tagHandling := fs.String("tag-handling", "", "按标签处理文本: xml / html（DeepL，默认读取配置文件）")
+	glossary := fs.String("glossary", "", "术语表 ID（DeepL，默认读取配置文件）")
+	detect := fs.Bool("detect", false, "只检测文本的语言")
+	redactText := fs.Bool("redact", cfg.Redaction.Enabled, "发送前打码敏感内容")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var text string
+	if fs.NArg() == 0 || (fs.NArg() == 1 && fs.Arg(0) == "-") {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("读取标准输入失败: %w", err)
+		}
+		text = string(data)
+	} else {
+		text = strings.Join(fs.Args(), " ")
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fmt.Errorf("没有需要翻译的文本")
+	}
+
+	redactor := newRedactor(cfg, *redactText)
+	text = redactor.Text(text, "translate", "")
+
+	// 命令行参数覆盖配置文件中的设置
+	settings := cfg.Translators[*provider]
+	for _, o := range []struct {
+		value string
+		field *string
+	}{
+		{*secretID, &settings.SecretID},
+		{*secretKey, &settings.SecretKey},
+		{*apiKey, &settings.APIKey},
+		{*endpoint, &settings.Endpoint},
+		{*model, &settings.Model},
+		{*prompt, &settings.Prompt},
+		{*formality, &settings.Formality},
+		{*tagHandling, &settings.TagHandling},
+		{*glossary, &settings.Glossary},
+	} {
+		if o.value != "" {
+			*o.field = o.value
+		}
+	}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "temperature":
+			settings.Temperature = temperature
+		case "stream":
+			settings.Stream = *stream
+		}
+	})
+	t, err := translator.New(*provider, settings)
+	if err != nil {
+		return err
+	}
+
+	if *detect {
+		result, err := t.Detect(text)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(stdout, result)
+		return err
+	}
+
+	// 流式输出时边生成边写入标准输出
+	printed := ""
+	result, err := translator.TranslateWithContext(t, text,
+		redactor.Text(*nearbyText, "translate", ""),
+		*source, *target,
+		func(partial string) {
+			if strings.HasPrefix(partial, printed) {
+				fmt.Fprint(stdout, partial[len(printed):])
+				printed = partial
+			}
+		})
+	if err != nil {
+		return err
+	}
+	if printed != "" && strings.HasPrefix(result, printed) {
+		_, err = fmt.Fprintln(stdout, result[len(printed):])
+		return err
+	}
+	if printed != "" {
+		fmt.Fprintln(stdout)
+	}
+	_, err = fmt.Fprintln(stdout, result)
+	return err
+}
+
+// cmdTranslators 列出翻译服务及其配置状态
+func cmdTranslators(cfg config.Config, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("translators", flag.ContinueOnError)
+	usage := fs.Bool("usage", false, "查询已配置服务本计费周期的剩余字符（支持的服务，如 DeepL）")
+	glossaries := fs.Bool("glossaries", false, "列出 DeepL 账户中的术语表")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	for _, info := range translator.Providers() {
+		t, err := translator.New(info.Name, cfg.Translators[info.Name])
+		if err != nil {
+			return err
+		}
+
+		mark := " "
+		if info.Name == cfg.TranslationProvider {
+			mark = "*"
+		}
+		status := "未配置"
+		if t.IsConfigured() {
+			status = "已配置"
+		}
+		fmt.Fprintf(stdout, "%s %-16s %s（%s）\n", mark, info.Name, status, info.Label)
+
+		reporter, ok := t.(translator.UsageReporter)
+		if !*usage || !ok || !t.IsConfigured() {
+			continue
+		}
+		u, err := reporter.Usage()
+		switch {
+		case err != nil:
+			fmt.Fprintf(stdout, "  %-16s 查询用量失败: %v\n", "", err)
+		case u.Remaining() < 0:
+			fmt.Fprintf(stdout, "  %-16s 已用 %d 字符（不限）\n", "", u.CharacterCount)
+		default:
+			fmt.Fprintf(stdout, "  %-16s 剩余 %d / %d 字符\n", "", u.Remaining(), u.CharacterLimit)
+		}
+	}
+
+	if *glossaries {
+		deepl := translator.NewDeepLTranslator(cfg.Translators["deepl"])
+		list, err := deepl.Glossaries()
+		if err != nil {
+			return fmt.Errorf("列出术语表失败: %w", err)
+		}
+		fmt.Fprintln(stdout)
+		if len(list) == 0 {
+			fmt.Fprintln(stdout, "DeepL 账户中没有术语表")
+		}
+		for _, g := range list {
+			fmt.Fprintf(stdout, "%s  %s → %s  %d 条  %s\n", g.ID, g.SourceLang, g.TargetLang, g.EntryCount, g.Name)
+		}
+	}
+	return nil
+}
+
+// cmdEngines 列出 OCR 引擎
+func cmdEngines(cfg config.Config, args []string, stdout io.Writer) error {
+	for _, name := range ocr.Names() {
+		engine, err := ocr.New(name)
+		if err != nil {
+			return err
+		}
+
+		mark := " "
+		if name == cfg.OcrEngine {
+			mark = "*"
+		}
+		if engine.IsAvailable() {
+			fmt.Fprintf(stdout, "%s %-16s 可用\n", mark, name)
+		} else {
+			fmt.Fprintf(stdout, "%s %-16s 不可用: %s\n", mark, name, engine.GetError())
+		}
+		engine.Close()
+	}
+	return nil
+}
+
+// readImage 从文件或标准输入（"-"）读取图片
+func readImage(path string) (image.Image, error) {
+	var r io.Reader
+	if path == "-" {
+		r = os.Stdin
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("打开图片失败: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("解码图片失败: %w", err)
+	}
+	return img, nil
+}
+
+// loadConfig 读取配置文件（不存在时使用默认配置）
+func loadConfig() config.Config {
+	data, err := os.ReadFile(filepath.Join(config.Dir(), config.FileName))
+	if err != nil {
+		return config.Default()
+	}
+	cfg, _, err := config.Parse(data)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	return cfg
+}
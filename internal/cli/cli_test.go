@@ -0,0 +1,291 @@
+package cli
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"screenocr-wails/internal/config"
+	"screenocr-wails/internal/ocr"
+	"screenocr-wails/internal/ocr/ocrtest"
+	"screenocr-wails/internal/translator"
+)
+
+// fakeEngine 最近一次由 "cli-fake" 创建的假引擎
+var fakeEngine *ocrtest.FakeEngine
+
+// fakeTranslator 记录创建时的设置和翻译参数的假翻译服务
+type fakeTranslator struct {
+	settings translator.Settings
+	calls    []string
+}
+
+// lastTranslator 最近一次由 "zz-cli" 创建的假翻译服务
+var lastTranslator *fakeTranslator
+
+func (f *fakeTranslator) Translate(text, source, target string) (string, error) {
+	f.calls = append(f.calls, source+"→"+target+": "+text)
+	return "[" + target + "] " + text, nil
+}
+
+func (f *fakeTranslator) Detect(text string) (string, error) { return "xx", nil }
+
+func (f *fakeTranslator) Supports(source, target string) bool { return true }
+
+func (f *fakeTranslator) IsConfigured() bool { return true }
+
+func init() {
+	ocr.Register("cli-fake", func() ocr.Engine {
+		fakeEngine = ocrtest.NewFakeEngine(ocr.TextBlock{Text: "Hello 世界", X: 2, Y: 1, Width: 16, Height: 8})
+		return fakeEngine
+	})
+	translator.Register(translator.Info{Name: "zz-cli", Label: "测试"}, func(settings translator.Settings) translator.Provider {
+		lastTranslator = &fakeTranslator{settings: settings}
+		return lastTranslator
+	})
+}
+
+// testConfig 默认配置，引擎为 cli-fake；配置目录（打码审计日志）指向临时目录
+func testConfig(t *testing.T) config.Config {
+	t.Helper()
+	t.Setenv("APPDATA", t.TempDir())
+	cfg := config.Default()
+	cfg.OcrEngine = "cli-fake"
+	cfg.ImagePreprocess = true
+	return cfg
+}
+
+// writePNG 在 dir 下写入 20x10 的空白 PNG，返回路径
+func writePNG(t *testing.T, dir, name string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, image.NewGray(image.Rect(0, 0, 20, 10))); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestIsCommand(t *testing.T) {
+	for _, arg := range []string{"recognize", "batch", "watch", "translate", "engines", "translators", "help", "-h", "--help"} {
+		if !IsCommand(arg) {
+			t.Errorf("%q 应当是子命令", arg)
+		}
+	}
+	// 其他参数（如 Wails 的启动参数）交给界面模式
+	for _, arg := range []string{"", "recognise", "-help", "shot.png"} {
+		if IsCommand(arg) {
+			t.Errorf("%q 不应当是子命令", arg)
+		}
+	}
+}
+
+func TestRecognizeFormat(t *testing.T) {
+	cfg := testConfig(t)
+	img := writePNG(t, t.TempDir(), "shot.png")
+
+	cases := []struct {
+		format string
+		want   []string // 输出中应当包含的内容
+	}{
+		{"text", []string{"Hello 世界\n"}},
+		{"json", []string{`"text": "Hello 世界"`, `"x": 2`}},
+		{"tsv", []string{"text\tx\ty\twidth\theight\n", "Hello 世界\t2\t1\t16\t8\n"}},
+		// 页面的图片名称取文件名，不含目录
+		{"hocr", []string{`class="ocr_page"`, `image &#34;shot.png&#34;; bbox 0 0 20 10`}},
+		{"alto", []string{"<alto", `<sourceImageInformation>`, "shot.png"}},
+		{"page", []string{"<PcGts", `imageFilename="shot.png"`, `imageWidth="20"`}},
+		{"pdf", []string{"%PDF-"}},
+	}
+	for _, c := range cases {
+		t.Run(c.format, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := cmdRecognize(cfg, []string{"-format", c.format, img}, &out); err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range c.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("输出中没有 %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+
+	// 默认输出文本
+	var out bytes.Buffer
+	if err := cmdRecognize(cfg, []string{img}, &out); err != nil || out.String() != "Hello 世界\n" {
+		t.Errorf("默认格式输出 %q, %v", out.String(), err)
+	}
+	if err := cmdRecognize(cfg, []string{"-format", "docx", img}, &out); err == nil || !strings.Contains(err.Error(), "不支持的输出格式") {
+		t.Errorf("未知格式: %v", err)
+	}
+}
+
+func TestRecognizeFlags(t *testing.T) {
+	cfg := testConfig(t)
+	img := writePNG(t, t.TempDir(), "shot.png")
+
+	// 预处理默认取配置中的设置，命令行参数优先
+	for _, c := range []struct {
+		args []string
+		want bool
+	}{
+		{[]string{img}, true},
+		{[]string{"-preprocess=false", img}, false},
+	} {
+		if err := cmdRecognize(cfg, c.args, &bytes.Buffer{}); err != nil {
+			t.Fatal(err)
+		}
+		if calls := fakeEngine.Calls(); len(calls) != 1 || calls[0].Preprocess != c.want || calls[0].Bounds != image.Rect(0, 0, 20, 10) {
+			t.Errorf("%v: 识别调用 %+v", c.args, calls)
+		}
+		if !fakeEngine.Closed() {
+			t.Errorf("%v: 识别后没有关闭引擎", c.args)
+		}
+	}
+
+	// -split 把文本块拆分为单词和单字
+	var out bytes.Buffer
+	if err := cmdRecognize(cfg, []string{"-split", "-format", "tsv", img}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(out.String()), "\n"); len(lines) != 4 || !strings.HasPrefix(lines[1], "Hello\t") || !strings.HasPrefix(lines[2], "世\t") {
+		t.Errorf("-split 输出:\n%s", out.String())
+	}
+
+	// -redact 打码结果中的敏感内容
+	cfg.Redaction.Enabled = false
+	ocr.Register("cli-fake-text", func() ocr.Engine {
+		return ocrtest.NewFakeEngine(ocr.TextBlock{Text: "mail alice@example.com", Width: 16, Height: 8})
+	})
+	out.Reset()
+	if err := cmdRecognize(cfg, []string{"-engine", "cli-fake-text", "-redact", img}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "alice@example.com") || !strings.HasPrefix(out.String(), "mail ") {
+		t.Errorf("-redact 输出 %q", out.String())
+	}
+
+	errCases := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"unknown-flag", []string{"-lang", "en", img}, "flag provided but not defined"},
+		{"unknown-engine", []string{"-engine", "nope", img}, "未知的 OCR 引擎: nope"},
+		{"missing-image", []string{filepath.Join(t.TempDir(), "missing.png")}, "打开图片失败"},
+		{"window-and-image", []string{"-window", "foreground", img}, "-window 不能与图片参数同时使用"},
+		{"window-mode", []string{"-window", "desktop"}, "未知的窗口选择方式"},
+	}
+	for _, c := range errCases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := cmdRecognize(cfg, c.args, &bytes.Buffer{}); err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Errorf("错误 %v，期望包含 %q", err, c.want)
+			}
+		})
+	}
+}
+
+func TestBatchFlags(t *testing.T) {
+	cfg := testConfig(t)
+	dir, out := t.TempDir(), t.TempDir()
+	writePNG(t, dir, "a.png")
+	writePNG(t, dir, "b.png")
+
+	var stdout bytes.Buffer
+	if err := cmdBatch(cfg, []string{"-format", "hocr", "-out", out, "-workers", "1", dir}, &stdout); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(stdout.String(), "共 2 张图片: 成功 2，跳过 0，失败 0，文本块 2，并发 1，") {
+		t.Errorf("输出 %q", stdout.String())
+	}
+	for _, name := range []string{"a.hocr", "b.hocr"} {
+		if data, err := os.ReadFile(filepath.Join(out, name)); err != nil || !strings.Contains(string(data), "Hello 世界") {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+
+	// 默认续跑：第二次全部跳过
+	stdout.Reset()
+	if err := cmdBatch(cfg, []string{"-format", "hocr", "-out", out, dir}, &stdout); err != nil || !strings.Contains(stdout.String(), "跳过 2") {
+		t.Errorf("续跑输出 %q, %v", stdout.String(), err)
+	}
+
+	for _, args := range [][]string{{}, {dir, out}} {
+		if err := cmdBatch(cfg, args, &stdout); err == nil || !strings.Contains(err.Error(), "用法") {
+			t.Errorf("%v: 错误 %v", args, err)
+		}
+	}
+}
+
+func TestWatchFlags(t *testing.T) {
+	cfg := testConfig(t)
+	if err := cmdWatch(cfg, nil, &bytes.Buffer{}); err == nil || !strings.Contains(err.Error(), "watch_folders") {
+		t.Errorf("没有目录: %v", err)
+	}
+
+	// 剪贴板由桌面程序提供
+	saved := CopyToClipboard
+	CopyToClipboard = nil
+	t.Cleanup(func() { CopyToClipboard = saved })
+	if err := cmdWatch(cfg, []string{"-copy", t.TempDir()}, &bytes.Buffer{}); err == nil || !strings.Contains(err.Error(), "剪贴板") {
+		t.Errorf("-copy: %v", err)
+	}
+}
+
+func TestTranslateFlags(t *testing.T) {
+	cfg := testConfig(t)
+	temperature := 0.7
+	cfg.TranslationProvider = "zz-cli"
+	cfg.TranslationSource = "auto"
+	cfg.TranslationTarget = "zh"
+	cfg.Translators = map[string]translator.Settings{
+		"zz-cli": {APIKey: "cfg-key", Model: "cfg-model", Temperature: &temperature, Stream: true},
+	}
+
+	// 没有指定的选项取配置文件中的设置
+	var out bytes.Buffer
+	if err := cmdTranslate(cfg, []string{"hello", "world"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	s := lastTranslator.settings
+	if out.String() != "[zh] hello world\n" || s.APIKey != "cfg-key" || s.Model != "cfg-model" || s.Temperature == nil || *s.Temperature != 0.7 || !s.Stream {
+		t.Errorf("输出 %q，设置 %+v", out.String(), s)
+	}
+
+	// 命令行参数覆盖配置，明确指定的 0 和 false 同样生效
+	out.Reset()
+	args := []string{"-from", "en", "-to", "ja", "-api-key", "flag-key", "-temperature", "0", "-stream=false", "text"}
+	if err := cmdTranslate(cfg, args, &out); err != nil {
+		t.Fatal(err)
+	}
+	s = lastTranslator.settings
+	if out.String() != "[ja] text\n" || s.APIKey != "flag-key" || s.Model != "cfg-model" || s.Temperature == nil || *s.Temperature != 0 || s.Stream {
+		t.Errorf("输出 %q，设置 %+v", out.String(), s)
+	}
+	if calls := lastTranslator.calls; len(calls) != 1 || calls[0] != "en→ja: text" {
+		t.Errorf("翻译调用 %v", calls)
+	}
+	if cfg.Translators["zz-cli"].APIKey != "cfg-key" {
+		t.Error("命令行参数不应修改配置")
+	}
+
+	out.Reset()
+	if err := cmdTranslate(cfg, []string{"-detect", "bonjour"}, &out); err != nil || out.String() != "xx\n" {
+		t.Errorf("-detect 输出 %q, %v", out.String(), err)
+	}
+	if err := cmdTranslate(cfg, []string{"-provider", "bing", "text"}, &out); err == nil || !strings.Contains(err.Error(), "未知的翻译服务") {
+		t.Errorf("未知服务: %v", err)
+	}
+	if err := cmdTranslate(cfg, []string{"  "}, &out); err == nil || !strings.Contains(err.Error(), "没有需要翻译的文本") {
+		t.Errorf("空文本: %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+// Package config 应用配置：默认值、配置文件位置和旧版配置的迁移，桌面程序与命令行共用
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"screenocr-wails/internal/archive"
+	"screenocr-wails/internal/ocr"
+	"screenocr-wails/internal/redact"
+	"screenocr-wails/internal/translator"
+)
+
+// FileName 配置文件名，保存在 Dir() 中
+const FileName = "config.json"
+
+// Config 应用配置
+type Config struct {
+	TriggerDelayMs      int                            `json:"trigger_delay_ms"`
+	Hotkey              string                         `json:"hotkey"`
+	RegionHotkey        string                         `json:"region_hotkey"`       // 框选区域识别的热键，为空时禁用
+	WindowHotkey        string                         `json:"window_hotkey"`       // 只识别一个窗口的热键，为空时禁用
+	WindowUnderCursor   bool                           `json:"window_under_cursor"` // 窗口识别热键识别鼠标下的窗口，否则识别前台窗口
+	ScrollHotkey        string                         `json:"scroll_hotkey"`       // 开始/结束滚动截图的热键，为空时禁用
+	AutoCopy            bool                           `json:"auto_copy"`
+	ShowDebug           bool                           `json:"show_debug"`
+	ImagePreprocess     bool                           `json:"image_preprocess"`
+	OcrEngine           string                         `json:"ocr_engine"`
+	EnableTranslation   bool                           `json:"enable_translation"`
+	TranslationSource   string                         `json:"translation_source"`
+	TranslationTarget   string                         `json:"translation_target"`
+	TranslationProvider string                         `json:"translation_provider"`         // 翻译服务（见 translator.Providers）
+	Translators         map[string]translator.Settings `json:"translators"`                  // 各翻译服务的设置，按服务名称
+	TencentSecretId     string                         `json:"tencent_secret_id,omitempty"`  // 旧版配置的腾讯云凭证，加载时迁移到 translators
+	TencentSecretKey    string                         `json:"tencent_secret_key,omitempty"` // 同上
+	OcrRouting          bool                           `json:"ocr_routing"`
+	OcrRouteRules       []ocr.RouteRule                `json:"ocr_route_rules"`
+	WatchFolders        []string                       `json:"watch_folders"`       // 自动识别新截图的目录
+	WatchCopy           bool                           `json:"watch_copy"`          // 识别后把文字复制到剪贴板
+	WatchTranslate      bool                           `json:"watch_translate"`     // 识别后翻译，译文写入 name.<目标语言>.txt
+	Redaction           redact.Policy                  `json:"redaction"`           // 发送给云端翻译和写入文件前打码敏感内容
+	HistoryEnabled      bool                           `json:"history_enabled"`     // 在内存中保留最近的屏幕截图（时光回溯），默认关闭
+	HistoryHotkey       string                         `json:"history_hotkey"`      // 回看最近截图的热键，为空时禁用
+	HistoryIntervalMs   int                            `json:"history_interval_ms"` // 时光回溯的截图间隔
+	HistoryFrames       int                            `json:"history_frames"`      // 时光回溯最多保留的帧数
+	HistoryMaxMB        int                            `json:"history_max_mb"`      // 时光回溯压缩后最多占用的内存
+	ArchiveHotkey       string                         `json:"archive_hotkey"`      // 把覆盖层中的截图和识别结果保存到存档的热键，为空时禁用
+	Archive             archive.Options                `json:"archive"`             // 截图存档的目录、文件名模板、格式和保留设置
+	FirstRun            bool                           `json:"first_run"`
+	ShowWelcome         bool                           `json:"show_welcome"`
+	ShowStartupNotify   bool                           `json:"show_startup_notification"`
+}
+
+// Dir 配置文件目录：%APPDATA%\ScreenOCR，没有 APPDATA 时为 ~/.screenocr
+func Dir() string {
+	// 优先使用 APPDATA 环境变量
+	appData := os.Getenv("APPDATA")
+	if appData != "" {
+		return filepath.Join(appData, "ScreenOCR")
+	}
+	// 回退到用户主目录
+	home, err := os.UserHomeDir()
+	if err == nil {
+		return filepath.Join(home, ".screenocr")
+	}
+	// 最后回退到可执行文件目录
+	exe, _ := os.Executable()
+	return filepath.Dir(exe)
+}
+
+// Default 默认配置
+func Default() Config {
+	return Config{
+		TriggerDelayMs:      300,
+		Hotkey:              "alt",
+		RegionHotkey:        "ctrl+shift+2",
+		WindowHotkey:        "",
+		WindowUnderCursor:   false,
+		ScrollHotkey:        "",
+		AutoCopy:            true,
+		ShowDebug:           false,
+		ImagePreprocess:     false,
+		OcrEngine:           "windows",
+		EnableTranslation:   true,
+		TranslationSource:   "auto",
+		TranslationTarget:   "zh",
+		TranslationProvider: translator.DefaultProvider,
+		Translators:         map[string]translator.Settings{},
+		OcrRouting:          false,
+		OcrRouteRules:       ocr.DefaultRouteRules(),
+		WatchFolders:        []string{},
+		WatchCopy:           false,
+		WatchTranslate:      false,
+		Redaction:           redact.DefaultPolicy(),
+		HistoryEnabled:      false,
+		HistoryHotkey:       "",
+		HistoryIntervalMs:   1000,
+		HistoryFrames:       30,
+		HistoryMaxMB:        100,
+		ArchiveHotkey:       "",
+		Archive:             archive.DefaultOptions(),
+		FirstRun:            true,
+		ShowWelcome:         true,
+		ShowStartupNotify:   true,
+	}
+}
+
+// Parse 在默认配置上解析配置文件内容并迁移旧版配置，migrated 为 true 时应把配置写回文件
+func Parse(data []byte) (cfg Config, migrated bool, err error) {
+	cfg = Default()
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Default(), false, fmt.Errorf("解析配置文件失败: %w", err)
+	}
+	keepRegionHotkeyOff(&cfg, data)
+	return cfg, Migrate(&cfg), nil
+}
+
+// Migrate 迁移旧版配置：tencent_secret_id/key 移到 translators.tencent（已有设置时不覆盖），返回是否有改动
+func Migrate(cfg *Config) bool {
+	if cfg.TencentSecretId == "" && cfg.TencentSecretKey == "" {
+		return false
+	}
+	if cfg.Translators == nil {
+		cfg.Translators = map[string]translator.Settings{}
+	}
+	settings := cfg.Translators["tencent"]
+	if settings.SecretID == "" && settings.SecretKey == "" {
+		settings.SecretID, settings.SecretKey = cfg.TencentSecretId, cfg.TencentSecretKey
+		cfg.Translators["tencent"] = settings
+	}
+	cfg.TencentSecretId, cfg.TencentSecretKey = "", ""
+	return true
+}
+
+// keepRegionHotkeyOff 旧版配置文件里没有 region_hotkey 时保持禁用，避免升级后悄悄注册新的全局热键
+func keepRegionHotkeyOff(cfg *Config, data []byte) {
+	var keys map[string]json.RawMessage
+	if json.Unmarshal(data, &keys) != nil {
+		return
+	}
+	if _, ok := keys["region_hotkey"]; !ok {
+		cfg.RegionHotkey = ""
+	}
+}
+
+// NewTranslator 按配置创建翻译服务；服务未知时使用默认服务，并返回原因
+func (c Config) NewTranslator() (translator.Provider, error) {
+	t, err := translator.New(c.TranslationProvider, c.Translators[c.TranslationProvider])
+	if err != nil {
+		t, _ = translator.New(translator.DefaultProvider, c.Translators[translator.DefaultProvider])
+	}
+	return t, err
+}
@@ -0,0 +1,190 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"screenocr-wails/internal/translator"
+)
+
+// legacyConfig 旧版配置文件：腾讯云凭证写在顶层，没有 translators、region_hotkey 和之后新增的设置
+const legacyConfig = `{
+  "trigger_delay_ms": 500,
+  "hotkey": "ctrl",
+  "auto_copy": false,
+  "show_debug": false,
+  "image_preprocess": true,
+  "ocr_engine": "wechat",
+  "enable_translation": true,
+  "translation_source": "auto",
+  "translation_target": "en",
+  "tencent_secret_id": "AKIDlegacy",
+  "tencent_secret_key": "legacy-key",
+  "first_run": false,
+  "show_welcome": false,
+  "show_startup_notification": true
+}`
+
+func TestParseLegacy(t *testing.T) {
+	cfg, migrated, err := Parse([]byte(legacyConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !migrated {
+		t.Error("旧版配置应当迁移")
+	}
+
+	want := Default()
+	want.TriggerDelayMs = 500
+	want.Hotkey = "ctrl"
+	want.AutoCopy = false
+	want.ImagePreprocess = true
+	want.OcrEngine = "wechat"
+	want.TranslationTarget = "en"
+	want.FirstRun = false
+	want.ShowWelcome = false
+	want.RegionHotkey = "" // 旧版没有区域识别热键，升级后保持禁用
+	want.Translators = map[string]translator.Settings{
+		"tencent": {SecretID: "AKIDlegacy", SecretKey: "legacy-key"},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("迁移后的配置\n得到 %+v\n期望 %+v", cfg, want)
+	}
+
+	// 写回后不再包含旧字段，再次读取不需要迁移
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "tencent_secret") {
+		t.Errorf("写回的配置仍包含旧字段: %s", data)
+	}
+	again, migrated, err := Parse(data)
+	if err != nil || migrated || !reflect.DeepEqual(again, cfg) {
+		t.Errorf("再次读取: 迁移 %v, %v\n得到 %+v", migrated, err, again)
+	}
+}
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		name         string
+		data         string
+		wantMigrated bool
+		check        func(t *testing.T, cfg Config)
+	}{
+		{
+			// translators.tencent 已有凭证时不覆盖，旧字段仍然清除
+			name:         "keep-existing",
+			data:         `{"tencent_secret_id": "old", "tencent_secret_key": "old-key", "translators": {"tencent": {"secret_id": "new", "secret_key": "new-key"}}}`,
+			wantMigrated: true,
+			check: func(t *testing.T, cfg Config) {
+				if s := cfg.Translators["tencent"]; s.SecretID != "new" || s.SecretKey != "new-key" {
+					t.Errorf("translators.tencent = %+v", s)
+				}
+				if cfg.TencentSecretId != "" || cfg.TencentSecretKey != "" {
+					t.Error("旧字段应当清除")
+				}
+			},
+		},
+		{
+			// 只有一半旧凭证时同样迁移，不影响其他服务的设置
+			name:         "partial",
+			data:         `{"tencent_secret_id": "only-id", "translators": {"deepl": {"api_key": "k:fx"}}}`,
+			wantMigrated: true,
+			check: func(t *testing.T, cfg Config) {
+				if s := cfg.Translators["tencent"]; s.SecretID != "only-id" || s.SecretKey != "" {
+					t.Errorf("translators.tencent = %+v", s)
+				}
+				if cfg.Translators["deepl"].APIKey != "k:fx" {
+					t.Errorf("translators = %+v", cfg.Translators)
+				}
+			},
+		},
+		{
+			name: "current",
+			data: `{"region_hotkey": "ctrl+alt+r", "translation_provider": "deepl", "history_frames": 10}`,
+			check: func(t *testing.T, cfg Config) {
+				if cfg.RegionHotkey != "ctrl+alt+r" || cfg.TranslationProvider != "deepl" || cfg.HistoryFrames != 10 {
+					t.Errorf("配置 %+v", cfg)
+				}
+				// 未出现的字段使用默认值
+				if cfg.HistoryMaxMB != 100 || cfg.Hotkey != "alt" || !reflect.DeepEqual(cfg.OcrRouteRules, Default().OcrRouteRules) {
+					t.Errorf("默认值 %+v", cfg)
+				}
+			},
+		},
+		{
+			// 明确设为空的区域识别热键保持为空
+			name: "region-disabled",
+			data: `{"region_hotkey": ""}`,
+			check: func(t *testing.T, cfg Config) {
+				if cfg.RegionHotkey != "" {
+					t.Errorf("region_hotkey = %q", cfg.RegionHotkey)
+				}
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg, migrated, err := Parse([]byte(c.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if migrated != c.wantMigrated {
+				t.Errorf("迁移 %v，期望 %v", migrated, c.wantMigrated)
+			}
+			c.check(t, cfg)
+		})
+	}
+
+	cfg, migrated, err := Parse([]byte(`{"hotkey": `))
+	if err == nil || !strings.Contains(err.Error(), "解析配置文件失败") || migrated {
+		t.Errorf("无效的 JSON: %v, %v", err, migrated)
+	}
+	if !reflect.DeepEqual(cfg, Default()) {
+		t.Errorf("解析失败时应返回默认配置，得到 %+v", cfg)
+	}
+}
+
+func TestNewTranslator(t *testing.T) {
+	cases := []struct {
+		name           string
+		provider       string
+		wantErr        string
+		wantConfigured bool
+	}{
+		{"deepl", "deepl", "", true},
+		// 未知或为空的服务回退到默认服务（腾讯云），使用其设置
+		{"unknown", "bing", "未知的翻译服务: bing", true},
+		{"empty", "", "未知的翻译服务", true},
+		// 已注册但没有设置
+		{"unconfigured", "baidu", "", false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.TranslationProvider = c.provider
+			cfg.Translators = map[string]translator.Settings{
+				"tencent": {SecretID: "AKIDtest", SecretKey: "key"},
+				"deepl":   {APIKey: "key:fx"},
+			}
+			p, err := cfg.NewTranslator()
+			if c.wantErr == "" && err != nil || c.wantErr != "" && (err == nil || !strings.Contains(err.Error(), c.wantErr)) {
+				t.Errorf("错误 %v，期望 %q", err, c.wantErr)
+			}
+			if p == nil {
+				t.Fatal("应当返回翻译服务")
+			}
+			if p.IsConfigured() != c.wantConfigured {
+				t.Errorf("IsConfigured = %v", p.IsConfigured())
+			}
+			if c.wantErr != "" {
+				if _, ok := p.(*translator.TencentTranslator); !ok {
+					t.Errorf("回退的服务 %T，期望腾讯云翻译", p)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+// Package history 最近屏幕截图的环形缓冲（时光回溯）
+//
+// 提示框、通知和视频字幕往往在按下热键之前就消失了。Buffer 以较低的频率在后台截图，
+// 压缩后保存在内存中（同时受帧数和字节数限制，超出时丢弃最旧的帧），用户可以在覆盖层中回看几秒前的画面并识别。
+// 画面没有变化时不保存新帧，只更新最新一帧的时间。截图只保存在内存中，停止后即释放。
+package history
+
+import (
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/draw"
+	"io"
+	"sync"
+	"time"
+
+	"screenocr-wails/internal/screenshot"
+)
+
+// Options 缓冲参数
+type Options struct {
+	Interval  time.Duration // 截图间隔，默认 1s
+	MaxFrames int           // 最多保留的帧数，默认 30
+	MaxBytes  int64         // 压缩后最多占用的内存，默认 100MB
+}
+
+// withDefaults 补全默认参数
+func (o Options) withDefaults() Options {
+	if o.Interval <= 0 {
+		o.Interval = time.Second
+	}
+	if o.MaxFrames <= 0 {
+		o.MaxFrames = 30
+	}
+	if o.MaxBytes <= 0 {
+		o.MaxBytes = 100 << 20
+	}
+	return o
+}
+
+// Frame 解压后的一帧
+type Frame struct {
+	Time   time.Time          // 最后一次截到这个画面的时间
+	Image  *image.RGBA        // 截图（原点为 (0, 0)）
+	Layout *screenshot.Layout // 截图对应的显示器布局
+}
+
+// Stats 缓冲统计
+type Stats struct {
+	Frames   int   `json:"frames"`    // 保存的帧数
+	Bytes    int64 `json:"bytes"`     // 压缩后占用的字节数
+	RawBytes int64 `json:"raw_bytes"` // 未压缩时需要的字节数
+	Captured int   `json:"captured"`  // 截图次数（含画面没有变化的）
+	Failed   int   `json:"failed"`    // 截图失败次数
+}
+
+// entry 压缩保存的一帧
+type entry struct {
+	time   time.Time
+	layout *screenshot.Layout
+	size   image.Point
+	sum    uint64 // 像素的哈希，用于跳过没有变化的画面
+	data   []byte // flate 压缩的 RGBA 像素
+}
+
+// Buffer 截图环形缓冲，可被多个 goroutine 同时使用
+type Buffer struct {
+	capturer screenshot.Capturer
+	opts     Options
+
+	mu       sync.Mutex
+	frames   []entry // 按时间排列，最旧的在前
+	bytes    int64
+	captured int
+	failed   int
+	paused   bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewBuffer 创建从 capturer 截图的缓冲（调用 Start 后才开始截图）
+func NewBuffer(capturer screenshot.Capturer, opts Options) *Buffer {
+	return &Buffer{capturer: capturer, opts: opts.withDefaults()}
+}
+
+// Start 开始定时截图
+func (b *Buffer) Start() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.stop != nil {
+		return
+	}
+	b.stop = make(chan struct{})
+	b.done = make(chan struct{})
+	go b.loop(b.stop, b.done)
+}
+
+// Stop 停止截图并释放所有帧
+func (b *Buffer) Stop() {
+	b.mu.Lock()
+	stop, done := b.stop, b.done
+	b.stop, b.done = nil, nil
+	b.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+	b.Clear()
+}
+
+// SetPaused 暂停或恢复截图（回看历史帧时暂停，避免截到覆盖层本身）
+func (b *Buffer) SetPaused(paused bool) {
+	b.mu.Lock()
+	b.paused = paused
+	b.mu.Unlock()
+}
+
+// loop 定时截图直到 Stop
+func (b *Buffer) loop(stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(b.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		b.mu.Lock()
+		paused := b.paused
+		b.mu.Unlock()
+		if paused {
+			continue
+		}
+
+		img, layout, err := screenshot.CaptureLayout(b.capturer)
+		if err != nil {
+			b.mu.Lock()
+			b.failed++
+			b.mu.Unlock()
+			continue
+		}
+		if err := b.Add(img, layout, time.Now()); err != nil {
+			fmt.Println("[时光回溯] ⚠", err)
+		}
+	}
+}
+
+// Add 加入一帧：与最新一帧相同时只更新时间，否则压缩保存，超出限制时丢弃最旧的帧
+func (b *Buffer) Add(img image.Image, layout *screenshot.Layout, t time.Time) error {
+	rgba := toRGBA(img)
+	size := rgba.Rect.Size()
+	pix := rgba.Pix[:size.Y*rgba.Stride]
+	h := fnv.New64a()
+	h.Write(pix)
+	sum := h.Sum64()
+
+	b.mu.Lock()
+	b.captured++
+	if n := len(b.frames); n > 0 && b.frames[n-1].sum == sum && b.frames[n-1].size == size {
+		b.frames[n-1].time = t
+		b.frames[n-1].layout = layout
+		b.mu.Unlock()
+		return nil
+	}
+	b.mu.Unlock()
+
+	// 压缩在锁外进行，避免阻塞回看
+	var buf bytes.Buffer
+	w, _ := flate.NewWriter(&buf, flate.BestSpeed)
+	if _, err := w.Write(pix); err != nil {
+		return fmt.Errorf("压缩截图失败: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("压缩截图失败: %w", err)
+	}
+	data := bytes.Clone(buf.Bytes())
+	if int64(len(data)) > b.opts.MaxBytes {
+		return fmt.Errorf("压缩后的截图 (%d 字节) 超过缓冲上限", len(data))
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.frames = append(b.frames, entry{time: t, layout: layout, size: size, sum: sum, data: data})
+	b.bytes += int64(len(data))
+	for len(b.frames) > b.opts.MaxFrames || b.bytes > b.opts.MaxBytes {
+		b.bytes -= int64(len(b.frames[0].data))
+		b.frames[0] = entry{}
+		b.frames = b.frames[1:]
+	}
+	return nil
+}
+
+// Len 保存的帧数
+func (b *Buffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.frames)
+}
+
+// Times 各帧的时间，最新的在前
+func (b *Buffer) Times() []time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	times := make([]time.Time, len(b.frames))
+	for i, e := range b.frames {
+		times[len(b.frames)-1-i] = e.time
+	}
+	return times
+}
+
+// Frame 解压第 back 帧（0 为最新的一帧，Len()-1 为最旧的一帧）
+func (b *Buffer) Frame(back int) (Frame, error) {
+	b.mu.Lock()
+	if back < 0 || back >= len(b.frames) {
+		n := len(b.frames)
+		b.mu.Unlock()
+		return Frame{}, fmt.Errorf("历史帧 %d 不存在（共 %d 帧）", back, n)
+	}
+	e := b.frames[len(b.frames)-1-back]
+	b.mu.Unlock()
+
+	img := image.NewRGBA(image.Rectangle{Max: e.size})
+	r := flate.NewReader(bytes.NewReader(e.data))
+	defer r.Close()
+	if _, err := io.ReadFull(r, img.Pix); err != nil {
+		return Frame{}, fmt.Errorf("解压截图失败: %w", err)
+	}
+	return Frame{Time: e.time, Image: img, Layout: e.layout}, nil
+}
+
+// Stats 缓冲统计
+func (b *Buffer) Stats() Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := Stats{Frames: len(b.frames), Bytes: b.bytes, Captured: b.captured, Failed: b.failed}
+	for _, e := range b.frames {
+		s.RawBytes += int64(e.size.X * e.size.Y * 4)
+	}
+	return s
+}
+
+// Clear 丢弃所有帧
+func (b *Buffer) Clear() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.frames = nil
+	b.bytes = 0
+}
+
+// toRGBA 转为原点 (0, 0)、各行紧密排列的 *image.RGBA，已经是时直接返回
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) && rgba.Stride == rgba.Rect.Dx()*4 {
+		return rgba
+	}
+	bounds := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(out, out.Rect, img, bounds.Min, draw.Src)
+	return out
+}
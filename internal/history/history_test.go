@@ -0,0 +1,280 @@
+package history
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math/rand"
+	"testing"
+	"time"
+
+	"screenocr-wails/internal/screenshot"
+)
+
+// timeline 合成的截图序列：通知弹出 2 帧后消失，字幕每 3 帧换一次，其余时间画面不变
+// 返回各帧和与上一帧不同的截图下标
+func timeline(width, height int) ([]*image.RGBA, []int) {
+	desktop := syntheticDesktop(width, height)
+	var frames []*image.RGBA
+	var distinct []int
+	for i := 0; i < 24; i++ {
+		frame := cloneRGBA(desktop)
+		if i >= 5 && i < 7 {
+			drawToast(frame, i)
+		}
+		drawSubtitle(frame, i/3)
+		if len(frames) == 0 || !sameRGBA(frame, frames[len(frames)-1]) {
+			distinct = append(distinct, i)
+		}
+		frames = append(frames, frame)
+	}
+	return frames, distinct
+}
+
+// fill 通过 ImageCapturer 依次截图加入缓冲（第 i 帧的时间为第 i 秒），返回每次新保存一帧后增加的字节数
+func fill(t testing.TB, buf *Buffer, frames []*image.RGBA) []int64 {
+	t.Helper()
+	capturer := screenshot.NewImageCapturer(frames[0])
+	var sizes []int64
+	var prev int64
+	for i, frame := range frames {
+		capturer.SetImage(frame)
+		img, layout, err := screenshot.CaptureLayout(capturer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := buf.Add(img, layout, time.Unix(int64(i), 0)); err != nil {
+			t.Fatalf("第 %d 帧: %v", i, err)
+		}
+		if bytes := buf.Stats().Bytes; bytes != prev {
+			sizes = append(sizes, bytes-prev)
+			prev = bytes
+		}
+	}
+	return sizes
+}
+
+// checkFrames 最新的一帧在前：第 back 帧应为 want 中倒数第 back+1 个截图，时间为该画面最后一次截到的时间
+func checkFrames(t *testing.T, buf *Buffer, frames []*image.RGBA, want []int) {
+	t.Helper()
+	if buf.Len() != len(want) {
+		t.Fatalf("保存了 %d 帧，期望 %d 帧", buf.Len(), len(want))
+	}
+	times := buf.Times()
+	for back := range want {
+		i := want[len(want)-1-back]
+		f, err := buf.Frame(back)
+		if err != nil {
+			t.Fatalf("第 %d 帧: %v", back, err)
+		}
+		if !sameRGBA(f.Image, frames[i]) {
+			t.Errorf("第 %d 帧与截图 %d 不同", back, i)
+		}
+		if f.Layout == nil {
+			t.Errorf("第 %d 帧没有显示器布局", back)
+		}
+		last := i
+		for last+1 < len(frames) && sameRGBA(frames[last+1], frames[i]) {
+			last++
+		}
+		if !f.Time.Equal(time.Unix(int64(last), 0)) || !times[back].Equal(f.Time) {
+			t.Errorf("第 %d 帧的时间 %v（Times %v），期望第 %d 秒", back, f.Time.Unix(), times[back].Unix(), last)
+		}
+	}
+}
+
+func TestBufferKeepsChangedFrames(t *testing.T) {
+	frames, distinct := timeline(640, 400)
+	buf := NewBuffer(nil, Options{MaxFrames: 64, MaxBytes: 64 << 20})
+	fill(t, buf, frames)
+	checkFrames(t, buf, frames, distinct)
+
+	stats := buf.Stats()
+	if stats.Captured != len(frames) || stats.Frames != len(distinct) {
+		t.Errorf("统计 %+v", stats)
+	}
+	if stats.RawBytes != int64(len(distinct)*640*400*4) || stats.Bytes >= stats.RawBytes {
+		t.Errorf("压缩后 %d 字节，未压缩 %d 字节", stats.Bytes, stats.RawBytes)
+	}
+}
+
+func TestBufferMaxFrames(t *testing.T) {
+	frames, distinct := timeline(640, 400)
+	buf := NewBuffer(nil, Options{MaxFrames: 4, MaxBytes: 64 << 20})
+	fill(t, buf, frames)
+	checkFrames(t, buf, frames, distinct[len(distinct)-4:])
+}
+
+func TestBufferMaxBytes(t *testing.T) {
+	frames, distinct := timeline(640, 400)
+
+	// 先不限制压缩一遍，取最后 3 帧的大小作为上限，期望正好保留这 3 帧
+	sizes := fill(t, NewBuffer(nil, Options{MaxFrames: 64, MaxBytes: 64 << 20}), frames)
+	var limit int64
+	for _, s := range sizes[len(sizes)-3:] {
+		limit += s
+	}
+
+	buf := NewBuffer(nil, Options{MaxFrames: 64, MaxBytes: limit})
+	fill(t, buf, frames)
+	checkFrames(t, buf, frames, distinct[len(distinct)-3:])
+	if bytes := buf.Stats().Bytes; bytes > limit {
+		t.Errorf("占用 %d 字节，超过上限 %d", bytes, limit)
+	}
+}
+
+func TestBufferRejectsOversizedFrame(t *testing.T) {
+	buf := NewBuffer(nil, Options{MaxBytes: 16})
+	noise := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	rand.New(rand.NewSource(1)).Read(noise.Pix)
+	if err := buf.Add(noise, nil, time.Now()); err == nil {
+		t.Error("压缩后超过上限的截图应返回错误")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("保存了 %d 帧", buf.Len())
+	}
+}
+
+func TestBufferFrameOutOfRange(t *testing.T) {
+	buf := NewBuffer(nil, Options{})
+	buf.Add(image.NewRGBA(image.Rect(0, 0, 8, 8)), nil, time.Now())
+	for _, back := range []int{-1, 1} {
+		if _, err := buf.Frame(back); err == nil {
+			t.Errorf("Frame(%d) 应返回错误", back)
+		}
+	}
+	buf.Clear()
+	if buf.Len() != 0 || buf.Stats().Bytes != 0 {
+		t.Errorf("Clear 后统计 %+v", buf.Stats())
+	}
+}
+
+func TestBufferStartStop(t *testing.T) {
+	frames, _ := timeline(320, 200)
+	capturer := screenshot.NewImageCapturer(frames[0])
+	buf := NewBuffer(capturer, Options{Interval: 5 * time.Millisecond})
+	buf.Start()
+	buf.Start() // 重复 Start 不启动第二个 goroutine
+	defer buf.Stop()
+
+	waitFor(t, func() bool { return buf.Len() == 1 })
+	capturer.SetImage(frames[6])
+	waitFor(t, func() bool { return buf.Len() == 2 })
+
+	// 暂停时不截图
+	buf.SetPaused(true)
+	time.Sleep(20 * time.Millisecond)
+	captured := buf.Stats().Captured
+	capturer.SetImage(frames[12])
+	time.Sleep(50 * time.Millisecond)
+	if buf.Stats().Captured != captured || buf.Len() != 2 {
+		t.Errorf("暂停后仍在截图: %+v", buf.Stats())
+	}
+	buf.SetPaused(false)
+	waitFor(t, func() bool { return buf.Len() == 3 })
+
+	buf.Stop()
+	if buf.Len() != 0 {
+		t.Errorf("Stop 后仍保存 %d 帧", buf.Len())
+	}
+}
+
+// waitFor 等待条件成立，超时失败
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("等待超时")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func BenchmarkBufferAdd(b *testing.B) {
+	frames, _ := timeline(1920, 1080)
+	buf := NewBuffer(nil, Options{MaxFrames: 8, MaxBytes: 1 << 30})
+	b.SetBytes(1920 * 1080 * 4)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// 相邻两帧总是不同，每次都要压缩
+		if err := buf.Add(frames[5+i%2], nil, time.Now()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBufferFrame(b *testing.B) {
+	frames, _ := timeline(1920, 1080)
+	buf := NewBuffer(nil, Options{MaxFrames: 8, MaxBytes: 1 << 30})
+	buf.Add(frames[0], nil, time.Now())
+	b.SetBytes(1920 * 1080 * 4)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := buf.Frame(0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// syntheticDesktop 合成的桌面：任务栏、两个窗口（标题栏 + 文字行）
+func syntheticDesktop(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Rect, &image.Uniform{color.RGBA{30, 80, 130, 255}}, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(0, height-40, width, height), &image.Uniform{color.RGBA{32, 32, 36, 255}}, image.Point{}, draw.Src)
+
+	rng := rand.New(rand.NewSource(4))
+	for _, win := range []image.Rectangle{
+		image.Rect(width/20, height/12, width*11/20, height*2/3),
+		image.Rect(width*2/5, height/4, width*19/20, height*5/6),
+	} {
+		win = win.Intersect(img.Rect)
+		draw.Draw(img, win, image.White, image.Point{}, draw.Src)
+		draw.Draw(img, image.Rect(win.Min.X, win.Min.Y, win.Max.X, win.Min.Y+30), &image.Uniform{color.RGBA{60, 60, 70, 255}}, image.Point{}, draw.Src)
+		// 文字行
+		for y := win.Min.Y + 44; y+24 <= win.Max.Y-4; y += 24 {
+			end := win.Min.X + 26 + rng.Intn(max(win.Dx()-52, 1))
+			for x := win.Min.X + 26; x < end; x += 3 {
+				if rng.Intn(3) == 0 {
+					top := y + 4 + rng.Intn(5)
+					draw.Draw(img, image.Rect(x, top, x+2, top+8+rng.Intn(5)), image.Black, image.Point{}, draw.Src)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// drawToast 在右下角绘制一条通知
+func drawToast(img *image.RGBA, seed int) {
+	b := img.Rect
+	toast := image.Rect(b.Max.X-340, b.Max.Y-140, b.Max.X-20, b.Max.Y-60).Intersect(b)
+	draw.Draw(img, toast, &image.Uniform{color.RGBA{46, 30, 30, 255}}, image.Point{}, draw.Src)
+	for x := toast.Min.X + 20; x < toast.Max.X-20; x += 9 + seed%3 {
+		draw.Draw(img, image.Rect(x, toast.Min.Y+20, x+6, toast.Min.Y+34), image.White, image.Point{}, draw.Src)
+	}
+}
+
+// drawSubtitle 在底部中央绘制第 n 条字幕
+func drawSubtitle(img *image.RGBA, n int) {
+	b := img.Rect
+	bar := image.Rect(b.Dx()/4, b.Max.Y-110, b.Dx()*3/4, b.Max.Y-70)
+	draw.Draw(img, bar, &image.Uniform{color.RGBA{0, 0, 0, 255}}, image.Point{}, draw.Src)
+	for x, i := bar.Min.X+12, 0; x < bar.Max.X-12; x, i = x+8, i+1 {
+		if (i*7+n*13)%5 != 0 {
+			draw.Draw(img, image.Rect(x, bar.Min.Y+12, x+5, bar.Min.Y+28), image.White, image.Point{}, draw.Src)
+		}
+	}
+}
+
+// cloneRGBA 复制图片
+func cloneRGBA(img *image.RGBA) *image.RGBA {
+	out := image.NewRGBA(img.Rect)
+	copy(out.Pix, img.Pix)
+	return out
+}
+
+// sameRGBA 两张图片的尺寸和像素是否完全相同
+func sameRGBA(a, b *image.RGBA) bool {
+	return a.Rect.Size() == b.Rect.Size() && string(a.Pix) == string(b.Pix)
+}
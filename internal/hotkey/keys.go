@@ -0,0 +1,142 @@
+package hotkey
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 键盘钩子收到的按键消息
+const (
+	WM_KEYDOWN    = 0x0100
+	WM_KEYUP      = 0x0101
+	WM_SYSKEYDOWN = 0x0104
+	WM_SYSKEYUP   = 0x0105
+)
+
+// 虚拟键码映射
+var keyCodeMap = map[string][]uint32{
+	"ctrl":  {162, 163}, // 左右 CTRL
+	"alt":   {164, 165}, // 左右 ALT
+	"shift": {160, 161}, // 左右 SHIFT
+	"win":   {91, 92},   // 左右 WIN
+	"f1":    {112}, "f2": {113}, "f3": {114}, "f4": {115},
+	"f5": {116}, "f6": {117}, "f7": {118}, "f8": {119},
+	"f9": {120}, "f10": {121}, "f11": {122}, "f12": {123},
+	"space": {32}, "tab": {9}, "enter": {13}, "esc": {27},
+	// 数字键（主键盘与小键盘）
+	"0": {48, 96}, "1": {49, 97}, "2": {50, 98}, "3": {51, 99}, "4": {52, 100},
+	"5": {53, 101}, "6": {54, 102}, "7": {55, 103}, "8": {56, 104}, "9": {57, 105},
+	// 字母键
+	"a": {65}, "b": {66}, "c": {67}, "d": {68}, "e": {69},
+	"f": {70}, "g": {71}, "h": {72}, "i": {73}, "j": {74},
+	"k": {75}, "l": {76}, "m": {77}, "n": {78}, "o": {79},
+	"p": {80}, "q": {81}, "r": {82}, "s": {83}, "t": {84},
+	"u": {85}, "v": {86}, "w": {87}, "x": {88}, "y": {89}, "z": {90},
+}
+
+// combo 解析后的组合键，每个部分对应一组键码（左右修饰键、主键盘与小键盘数字任意一个即可）
+type combo [][]uint32
+
+// Validate 检查组合键（如 "ctrl+shift+2"）中的每个键是否都支持，空字符串表示禁用
+func Validate(hotkey string) error {
+	_, err := parseCombo(hotkey)
+	return err
+}
+
+// parseCombo 解析组合键；不支持的键直接报错，否则按下其余的键就会触发
+func parseCombo(hotkey string) (combo, error) {
+	hotkey = strings.TrimSpace(strings.ToLower(hotkey))
+	if hotkey == "" {
+		return nil, nil
+	}
+	var c combo
+	for _, part := range strings.Split(hotkey, "+") {
+		part = strings.TrimSpace(part)
+		codes, ok := keyCodeMap[part]
+		if !ok {
+			return nil, fmt.Errorf("热键 %s 包含不支持的按键 %q", hotkey, part)
+		}
+		c = append(c, codes)
+	}
+	return c, nil
+}
+
+// codes 组合键包含的所有键码
+func (c combo) codes() []uint32 {
+	var codes []uint32
+	for _, part := range c {
+		codes = append(codes, part...)
+	}
+	return codes
+}
+
+// contains 检查键码是否属于组合键
+func (c combo) contains(code uint32) bool {
+	for _, part := range c {
+		for _, k := range part {
+			if k == code {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pressed 检查组合键的每个部分是否都有键按下，空组合键（禁用）永远不算按下
+func (c combo) pressed(pressed map[uint32]bool) bool {
+	if len(c) == 0 {
+		return false
+	}
+	for _, part := range c {
+		found := false
+		for _, code := range part {
+			if pressed[code] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// pressHotkey 按下即触发一次的组合键，松开任意一个键后才能再次触发
+type pressHotkey struct {
+	hotkey  string
+	keys    combo // 设置时解析好的键码，钩子中不再拆分字符串
+	pressed map[uint32]bool
+	fired   bool
+}
+
+// set 更换组合键并清空按键状态，包含不支持的按键时禁用并返回错误
+func (h *pressHotkey) set(hotkey string) error {
+	keys, err := parseCombo(hotkey)
+	h.hotkey, h.keys = strings.ToLower(hotkey), keys
+	if err != nil {
+		h.hotkey = ""
+	}
+	h.pressed = make(map[uint32]bool)
+	h.fired = false
+	return err
+}
+
+// handle 处理一次按键事件，组合键刚全部按下时返回 true（调用方持有锁）
+func (h *pressHotkey) handle(vkCode uint32, wParam uintptr) bool {
+	if !h.keys.contains(vkCode) {
+		return false
+	}
+	switch wParam {
+	case WM_KEYDOWN, WM_SYSKEYDOWN:
+		h.pressed[vkCode] = true
+		if !h.fired && h.keys.pressed(h.pressed) {
+			h.fired = true
+			return true
+		}
+	case WM_KEYUP, WM_SYSKEYUP:
+		delete(h.pressed, vkCode)
+		h.fired = false
+	}
+	return false
+}
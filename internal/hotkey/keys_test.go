@@ -0,0 +1,126 @@
+package hotkey
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseCombo(t *testing.T) {
+	cases := []struct {
+		name    string
+		hotkey  string
+		want    combo
+		wantErr string
+	}{
+		{"disabled", "", nil, ""},
+		{"blank", "  ", nil, ""},
+		{"modifier", "alt", combo{{164, 165}}, ""},
+		{"mixed-case", " Ctrl + SHIFT+o ", combo{{162, 163}, {160, 161}, {79}}, ""},
+		// 数字键同时对应主键盘和小键盘
+		{"digit", "ctrl+alt+1", combo{{162, 163}, {164, 165}, {49, 97}}, ""},
+		{"function", "win+f12", combo{{91, 92}, {123}}, ""},
+		// 不支持的按键不能被忽略，否则 ctrl+alt+/ 按下 ctrl+alt 就会触发
+		{"unknown", "ctrl+alt+/", nil, `"/"`},
+		{"trailing-plus", "ctrl+", nil, `""`},
+		{"named", "ctrl+printscreen", nil, `"printscreen"`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := parseCombo(c.hotkey)
+			if c.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+					t.Errorf("parseCombo(%q) 错误 %v，期望包含 %s", c.hotkey, err, c.wantErr)
+				}
+				if Validate(c.hotkey) == nil {
+					t.Errorf("Validate(%q) 应当报错", c.hotkey)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.EqualFunc(got, c.want, slices.Equal[[]uint32]) {
+				t.Errorf("parseCombo(%q) = %v，期望 %v", c.hotkey, got, c.want)
+			}
+		})
+	}
+}
+
+func TestComboPressed(t *testing.T) {
+	keys, err := parseCombo("ctrl+alt+1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name    string
+		pressed []uint32
+		want    bool
+	}{
+		{"modifiers-only", []uint32{162, 164}, false},
+		{"all", []uint32{162, 164, 49}, true},
+		// 左右修饰键、主键盘和小键盘数字任意一个即可
+		{"right-ctrl-numpad", []uint32{163, 165, 97}, true},
+		{"missing-alt", []uint32{162, 163, 49}, false},
+		{"none", nil, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			pressed := make(map[uint32]bool)
+			for _, code := range c.pressed {
+				pressed[code] = true
+			}
+			if got := keys.pressed(pressed); got != c.want {
+				t.Errorf("按下 %v 时 pressed = %v，期望 %v", c.pressed, got, c.want)
+			}
+		})
+	}
+
+	// 禁用（空）的组合键永远不触发，也不包含任何键
+	var disabled combo
+	if disabled.pressed(map[uint32]bool{162: true}) || disabled.contains(162) {
+		t.Error("空组合键不应触发")
+	}
+	if !keys.contains(97) || keys.contains(65) {
+		t.Error("contains 结果不正确")
+	}
+	if got := keys.codes(); !slices.Equal(got, []uint32{162, 163, 164, 165, 49, 97}) {
+		t.Errorf("codes = %v", got)
+	}
+}
+
+// TestPressHotkey 组合键全部按下时触发一次，松开任意一个键后才能再次触发
+func TestPressHotkey(t *testing.T) {
+	var h pressHotkey
+	if err := h.set("CTRL+SHIFT+2"); err != nil {
+		t.Fatal(err)
+	}
+	steps := []struct {
+		code  uint32
+		event uintptr
+		want  bool
+	}{
+		{162, WM_KEYDOWN, false},
+		{160, WM_KEYDOWN, false},
+		{65, WM_KEYDOWN, false}, // 不属于组合键的键被忽略
+		{50, WM_KEYDOWN, true},
+		{50, WM_KEYDOWN, false}, // 按住时的自动重复不再触发
+		{50, WM_KEYUP, false},
+		{98, WM_SYSKEYDOWN, true}, // 小键盘的 2
+	}
+	for i, s := range steps {
+		if got := h.handle(s.code, s.event); got != s.want {
+			t.Errorf("第 %d 步（vk=%d）触发 %v，期望 %v", i+1, s.code, got, s.want)
+		}
+	}
+
+	// 不支持的按键：禁用并返回错误
+	if err := h.set("ctrl+alt+/"); err == nil || h.hotkey != "" {
+		t.Fatalf("set 错误 %v，hotkey = %q", err, h.hotkey)
+	}
+	for _, code := range []uint32{162, 164} {
+		if h.handle(code, WM_KEYDOWN) {
+			t.Error("无效的热键被禁用后不应触发")
+		}
+	}
+}
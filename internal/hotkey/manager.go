@@ -7,6 +7,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 	"unsafe"
@@ -25,31 +26,9 @@ var (
 
 const (
 	WH_KEYBOARD_LL = 13
-	WM_KEYDOWN     = 0x0100
-	WM_KEYUP       = 0x0101
-	WM_SYSKEYDOWN  = 0x0104
-	WM_SYSKEYUP    = 0x0105
 	PM_REMOVE      = 0x0001
 )
 
-// 虚拟键码映射
-var keyCodeMap = map[string][]uint32{
-	"ctrl":  {162, 163}, // 左右 CTRL
-	"alt":   {164, 165}, // 左右 ALT
-	"shift": {160, 161}, // 左右 SHIFT
-	"win":   {91, 92},   // 左右 WIN
-	"f1":    {112}, "f2": {113}, "f3": {114}, "f4": {115},
-	"f5": {116}, "f6": {117}, "f7": {118}, "f8": {119},
-	"f9": {120}, "f10": {121}, "f11": {122}, "f12": {123},
-	"space": {32}, "tab": {9}, "enter": {13}, "esc": {27},
-	// 字母键
-	"a": {65}, "b": {66}, "c": {67}, "d": {68}, "e": {69},
-	"f": {70}, "g": {71}, "h": {72}, "i": {73}, "j": {74},
-	"k": {75}, "l": {76}, "m": {77}, "n": {78}, "o": {79},
-	"p": {80}, "q": {81}, "r": {82}, "s": {83}, "t": {84},
-	"u": {85}, "v": {86}, "w": {87}, "x": {88}, "y": {89}, "z": {90},
-}
-
 // 键码到名称的反向映射
 var keyNameMap = map[uint32]string{
 	162: "LCTRL", 163: "RCTRL",
@@ -80,6 +59,7 @@ type MSG struct {
 // Manager 热键管理器
 type Manager struct {
 	hotkey       string
+	keys         combo // 主热键解析后的键码
 	delayMs      int
 	hookID       uintptr
 	running      bool
@@ -88,19 +68,40 @@ type Manager struct {
 	pressTime    time.Time
 	triggered    bool
 
+	// 区域识别、窗口识别、滚动截图、时光回溯和保存截图热键：按下即触发，不需要按住
+	region  pressHotkey
+	window  pressHotkey
+	scroll  pressHotkey
+	history pressHotkey
+	archive pressHotkey
+
 	OnTrigger    func() // 触发回调
 	OnKeyRelease func() // 按键松开回调（用于关闭覆盖层）
 	OnEscape     func() // ESC 键回调（全局，与 Python 一致）
+	OnRegion     func() // 区域识别热键回调
+	OnWindow     func() // 窗口识别热键回调
+	OnScroll     func() // 滚动截图热键回调（开始/结束）
+	OnHistory    func() // 时光回溯热键回调
+	OnArchive    func() // 保存截图热键回调
+	OnArrow      func(step int) // 左/右方向键回调（仅在 SetArrowKeys(true) 后触发），左为 -1，右为 +1
+
+	arrows atomic.Bool // 是否把左/右方向键交给 OnArrow（回看历史帧时），钩子里只做一次原子读取
 }
 
 // NewManager 创建热键管理器
 func NewManager(hotkey string, delayMs int) *Manager {
 	fmt.Printf("[热键] 创建管理器: hotkey=%s, delay=%dms\n", hotkey, delayMs)
-	return &Manager{
-		hotkey:      strings.ToLower(hotkey),
+	m := &Manager{
 		delayMs:     delayMs,
 		pressedKeys: make(map[uint32]bool),
 	}
+	m.setHotkey(hotkey)
+	m.region.set("")
+	m.window.set("")
+	m.scroll.set("")
+	m.history.set("")
+	m.archive.set("")
+	return m
 }
 
 // Start 启动热键监听
@@ -192,13 +193,86 @@ func (m *Manager) UpdateHotkey(hotkey string, delayMs int) {
 	defer m.mu.Unlock()
 
 	fmt.Printf("[热键] 更新配置: %s -> %s, delay=%dms\n", m.hotkey, hotkey, delayMs)
-	m.hotkey = strings.ToLower(hotkey)
+	m.setHotkey(hotkey)
 	m.delayMs = delayMs
 	m.pressedKeys = make(map[uint32]bool)
 	m.triggered = false
 }
 
-const VK_ESCAPE = 0x1B // ESC 键码
+// SetRegionHotkey 设置区域识别热键（如 "ctrl+shift+2"），为空时禁用
+func (m *Manager) SetRegionHotkey(hotkey string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Printf("[热键] 区域识别热键: %q -> %q\n", m.region.hotkey, hotkey)
+	if err := m.region.set(hotkey); err != nil {
+		fmt.Printf("[热键] ❌ 区域识别热键已禁用: %v\n", err)
+	}
+}
+
+// SetWindowHotkey 设置窗口识别热键（如 "ctrl+alt+w"），为空时禁用
+func (m *Manager) SetWindowHotkey(hotkey string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Printf("[热键] 窗口识别热键: %q -> %q\n", m.window.hotkey, hotkey)
+	if err := m.window.set(hotkey); err != nil {
+		fmt.Printf("[热键] ❌ 窗口识别热键已禁用: %v\n", err)
+	}
+}
+
+// SetScrollHotkey 设置滚动截图热键（如 "ctrl+alt+s"），为空时禁用
+func (m *Manager) SetScrollHotkey(hotkey string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Printf("[热键] 滚动截图热键: %q -> %q\n", m.scroll.hotkey, hotkey)
+	if err := m.scroll.set(hotkey); err != nil {
+		fmt.Printf("[热键] ❌ 滚动截图热键已禁用: %v\n", err)
+	}
+}
+
+// SetHistoryHotkey 设置时光回溯热键（如 "ctrl+alt+h"），为空时禁用
+func (m *Manager) SetHistoryHotkey(hotkey string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Printf("[热键] 时光回溯热键: %q -> %q\n", m.history.hotkey, hotkey)
+	if err := m.history.set(hotkey); err != nil {
+		fmt.Printf("[热键] ❌ 时光回溯热键已禁用: %v\n", err)
+	}
+}
+
+// SetArchiveHotkey 设置保存截图热键（如 "ctrl+alt+p"），为空时禁用
+func (m *Manager) SetArchiveHotkey(hotkey string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Printf("[热键] 保存截图热键: %q -> %q\n", m.archive.hotkey, hotkey)
+	if err := m.archive.set(hotkey); err != nil {
+		fmt.Printf("[热键] ❌ 保存截图热键已禁用: %v\n", err)
+	}
+}
+
+// setHotkey 更换主热键并解析键码，包含不支持的按键时禁用（调用方持有锁）
+func (m *Manager) setHotkey(hotkey string) {
+	keys, err := parseCombo(hotkey)
+	if err != nil {
+		fmt.Printf("[热键] ❌ 主热键已禁用: %v\n", err)
+	}
+	m.hotkey, m.keys = strings.ToLower(hotkey), keys
+}
+
+// SetArrowKeys 设置是否把左/右方向键交给 OnArrow（回看历史帧时开启，关闭回看后关闭）
+func (m *Manager) SetArrowKeys(enabled bool) {
+	m.arrows.Store(enabled)
+}
+
+const (
+	VK_ESCAPE = 0x1B // ESC 键码
+	VK_LEFT   = 0x25 // 左方向键
+	VK_RIGHT  = 0x27 // 右方向键
+)
 
 // keyboardProc 键盘钩子回调
 func (m *Manager) keyboardProc(nCode int, wParam uintptr, lParam uintptr) uintptr {
@@ -224,18 +298,23 @@ func (m *Manager) keyboardProc(nCode int, wParam uintptr, lParam uintptr) uintpt
 			}
 		}
 
-		// 获取配置的热键键码
-		targetCodes := m.getHotkeyCodes()
-
-		// 检查是否是目标键
-		isTargetKey := false
-		for _, code := range targetCodes {
-			if vkCode == code {
-				isTargetKey = true
-				break
+		// 左/右方向键（回看历史帧），按住时随自动重复连续触发；平时不启动 goroutine
+		if (vkCode == VK_LEFT || vkCode == VK_RIGHT) && wParam == WM_KEYDOWN && m.arrows.Load() && m.OnArrow != nil {
+			step := 1
+			if vkCode == VK_LEFT {
+				step = -1
 			}
+			go m.OnArrow(step)
 		}
 
+		// 区域识别、窗口识别、滚动截图、时光回溯、保存截图热键
+		m.handlePressKeys(vkCode, wParam)
+
+		// 检查是否是主热键的键（键码在设置热键时已解析）
+		m.mu.RLock()
+		isTargetKey := m.keys.contains(vkCode)
+		m.mu.RUnlock()
+
 		switch wParam {
 		case WM_KEYDOWN, WM_SYSKEYDOWN:
 			if isTargetKey {
@@ -246,10 +325,11 @@ func (m *Manager) keyboardProc(nCode int, wParam uintptr, lParam uintptr) uintpt
 					fmt.Printf("[热键] 按下: %s (vk=%d) [目标键]\n", keyName, vkCode)
 					
 					// 检查是否所有键都按下
-					allPressed := m.allKeysPressed(targetCodes)
+					allPressed := m.allKeysPressed()
 					fmt.Printf("[热键] 已按下的键: %v, 全部按下: %v\n", m.pressedKeys, allPressed)
 					
-					if allPressed && m.pressTime.IsZero() {
+					// 区域/窗口识别、滚动截图、时光回溯、保存截图热键刚触发时不再开始计时（热键共用按键的情况）
+					if allPressed && m.pressTime.IsZero() && !m.region.fired && !m.window.fired && !m.scroll.fired && !m.history.fired && !m.archive.fired {
 						m.pressTime = time.Now()
 						m.triggered = false
 						fmt.Printf("[热键] ⏱ 开始计时，延迟 %dms 后触发\n", m.delayMs)
@@ -290,38 +370,50 @@ func (m *Manager) keyboardProc(nCode int, wParam uintptr, lParam uintptr) uintpt
 	return ret
 }
 
-// getHotkeyCodes 获取热键对应的键码
-func (m *Manager) getHotkeyCodes() []uint32 {
-	var codes []uint32
-	parts := strings.Split(m.hotkey, "+")
-	for _, part := range parts {
-		part = strings.TrimSpace(strings.ToLower(part))
-		if keyCodes, ok := keyCodeMap[part]; ok {
-			codes = append(codes, keyCodes...)
+// handlePressKeys 处理区域识别、窗口识别、滚动截图、时光回溯和保存截图热键，组合键全部按下时立即触发
+func (m *Manager) handlePressKeys(vkCode uint32, wParam uintptr) {
+	keys := []struct {
+		h        *pressHotkey
+		name     string
+		callback func()
+	}{
+		{&m.region, "区域识别", m.OnRegion},
+		{&m.window, "窗口识别", m.OnWindow},
+		{&m.scroll, "滚动截图", m.OnScroll},
+		{&m.history, "时光回溯", m.OnHistory},
+		{&m.archive, "保存截图", m.OnArchive},
+	}
+
+	m.mu.Lock()
+	fired := make([]string, len(keys)) // 触发的热键，未触发为空
+	for i, k := range keys {
+		if k.h.handle(vkCode, wParam) {
+			fired[i] = k.h.hotkey
+			// 与主热键共用按键（如 ALT）时取消主热键的计时，避免紧接着触发全屏识别
+			m.pressTime = time.Time{}
 		}
 	}
-	return codes
-}
+	m.mu.Unlock()
 
-// allKeysPressed 检查是否所有键都按下
-func (m *Manager) allKeysPressed(targetCodes []uint32) bool {
-	parts := strings.Split(m.hotkey, "+")
-	for _, part := range parts {
-		part = strings.TrimSpace(strings.ToLower(part))
-		if keyCodes, ok := keyCodeMap[part]; ok {
-			pressed := false
-			for _, code := range keyCodes {
-				if m.pressedKeys[code] {
-					pressed = true
-					break
-				}
-			}
-			if !pressed {
-				return false
-			}
+	for i, hotkey := range fired {
+		if hotkey == "" {
+			continue
+		}
+		fmt.Printf("[热键] 🎯 %s热键触发: %s\n", keys[i].name, hotkey)
+		if keys[i].callback != nil {
+			go keys[i].callback()
 		}
 	}
-	return true
+}
+
+// getHotkeyCodes 获取热键对应的键码
+func (m *Manager) getHotkeyCodes() []uint32 {
+	return m.keys.codes()
+}
+
+// allKeysPressed 检查是否所有键都按下
+func (m *Manager) allKeysPressed() bool {
+	return m.keys.pressed(m.pressedKeys)
 }
 
 // checkTrigger 检查是否触发
@@ -0,0 +1,238 @@
+package ocr
+
+import (
+	"image"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// syntheticBlocks 生成类似拆分为单字后的密集文本块：n 个字符按行排满 width x height 的屏幕
+func syntheticBlocks(n, width, height int) []TextBlock {
+	rng := rand.New(rand.NewSource(1))
+	blocks := make([]TextBlock, 0, n)
+	charW, lineH := 14, 20
+	x, y := 10, 10
+	for len(blocks) < n {
+		w := charW + rng.Intn(5) - 2
+		blocks = append(blocks, TextBlock{Text: "字", X: x, Y: y + rng.Intn(3), Width: w, Height: lineH - 4})
+		x += w + 2
+		if rng.Intn(40) == 0 {
+			x += 30 // 词间空白
+		}
+		if x+charW > width {
+			x = 10
+			y += lineH
+			if y+lineH > height {
+				y = 10 + rng.Intn(lineH) // 写满后错位叠加，保持密度
+			}
+		}
+	}
+	return blocks
+}
+
+// randomBlocks 大小悬殊、可能重叠或宽高为 0 的文本块，坐标可为负
+func randomBlocks(rng *rand.Rand, n int) []TextBlock {
+	blocks := make([]TextBlock, n)
+	for i := range blocks {
+		blocks[i] = TextBlock{
+			Text:   "x",
+			X:      rng.Intn(2000) - 1000,
+			Y:      rng.Intn(1200) - 600,
+			Width:  rng.Intn(40),
+			Height: rng.Intn(30),
+		}
+		if rng.Intn(50) == 0 {
+			blocks[i].Width, blocks[i].Height = rng.Intn(1500), rng.Intn(800) // 偶尔出现的大文本块
+		}
+	}
+	return blocks
+}
+
+// linearAt 线性扫描的点查询
+func linearAt(blocks []TextBlock, x, y int) int {
+	for i, b := range blocks {
+		if x >= b.X && x <= b.X+b.Width && y >= b.Y && y <= b.Y+b.Height {
+			return i
+		}
+	}
+	return -1
+}
+
+// linearIntersect 线性扫描的框选（边界包含在内）
+func linearIntersect(blocks []TextBlock, rect image.Rectangle) []int {
+	rect = rect.Canon()
+	var result []int
+	for i, b := range blocks {
+		if rect.Max.X < b.X || rect.Min.X > b.X+b.Width || rect.Max.Y < b.Y || rect.Min.Y > b.Y+b.Height {
+			continue
+		}
+		result = append(result, i)
+	}
+	return result
+}
+
+// linearNearest 线性扫描的最近邻，距离相同时取下标最小的
+func linearNearest(blocks []TextBlock, x, y int) (int, int) {
+	best, bestDist := -1, 0
+	for i, b := range blocks {
+		dx := max(max(b.X-x, 0), x-b.X-b.Width)
+		dy := max(max(b.Y-y, 0), y-b.Y-b.Height)
+		if d := dx*dx + dy*dy; best < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best, bestDist
+}
+
+func TestResultMatchesLinearScan(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	datasets := map[string][]TextBlock{
+		"密集单字":  syntheticBlocks(5000, 1920, 1080),
+		"随机大小":  randomBlocks(rng, 800),
+		"单个文本块": {{Text: "a", X: 5, Y: 5, Width: 10, Height: 10}},
+		"宽高为 0": {{Text: "a", X: 5, Y: 5}, {Text: "b", X: 5, Y: 5, Width: 0, Height: 7}, {Text: "c", X: 30, Y: 5, Width: 4}},
+	}
+
+	for name, blocks := range datasets {
+		t.Run(name, func(t *testing.T) {
+			index := NewResult(blocks)
+			if index.Len() != len(blocks) {
+				t.Fatalf("Len() = %d，期望 %d", index.Len(), len(blocks))
+			}
+
+			for q := 0; q < 3000; q++ {
+				x, y := rng.Intn(2400)-1200, rng.Intn(1600)-700
+				if q%3 == 0 {
+					// 落在文本块边界上的点
+					b := blocks[rng.Intn(len(blocks))]
+					x, y = b.X+b.Width*rng.Intn(2), b.Y+b.Height*rng.Intn(2)
+				}
+
+				if got, want := index.At(x, y), linearAt(blocks, x, y); got != want {
+					t.Fatalf("At(%d, %d) = %d，线性扫描为 %d", x, y, got, want)
+				}
+
+				gotIdx, gotDist := index.Nearest(x, y)
+				wantIdx, wantDist := linearNearest(blocks, x, y)
+				if gotIdx != wantIdx || gotDist != wantDist {
+					t.Fatalf("Nearest(%d, %d) = (%d, %d)，线性扫描为 (%d, %d)", x, y, gotIdx, gotDist, wantIdx, wantDist)
+				}
+
+				rect := image.Rect(x, y, x+rng.Intn(600)-100, y+rng.Intn(300)-50)
+				if got, want := index.Intersect(rect), linearIntersect(blocks, rect); !slices.Equal(got, want) {
+					t.Fatalf("Intersect(%v) 返回 %d 个，线性扫描为 %d 个", rect, len(got), len(want))
+				}
+			}
+		})
+	}
+}
+
+func TestResultEmpty(t *testing.T) {
+	for _, r := range []*Result{nil, NewResult(nil)} {
+		if r.Len() != 0 || len(r.ReadingOrder()) != 0 {
+			t.Errorf("空结果的 Len / ReadingOrder 应为空")
+		}
+	}
+	r := NewResult(nil)
+	if r.At(0, 0) != -1 || r.Intersect(image.Rect(0, 0, 10, 10)) != nil || r.Next(0) != -1 || r.Prev(0) != -1 {
+		t.Errorf("空结果的查询应返回 -1 / nil")
+	}
+	if idx, _ := r.Nearest(0, 0); idx != -1 {
+		t.Errorf("空结果的 Nearest 应返回 -1，实际 %d", idx)
+	}
+}
+
+func TestResultReadingOrder(t *testing.T) {
+	blocks := []TextBlock{
+		{Text: "world", X: 80, Y: 11, Width: 50, Height: 20},
+		{Text: "second", X: 10, Y: 50, Width: 60, Height: 20},
+		{Text: "hello", X: 10, Y: 10, Width: 60, Height: 20},
+	}
+	r := NewResult(blocks)
+	if got, want := r.ReadingOrder(), []int{2, 0, 1}; !slices.Equal(got, want) {
+		t.Fatalf("阅读顺序 %v，期望 %v", got, want)
+	}
+	if r.Next(2) != 0 || r.Next(0) != 1 || r.Next(1) != -1 {
+		t.Errorf("Next 与阅读顺序不一致")
+	}
+	if r.Prev(1) != 0 || r.Prev(0) != 2 || r.Prev(2) != -1 {
+		t.Errorf("Prev 与阅读顺序不一致")
+	}
+
+	indices := []int{1, 0, 2}
+	r.SortReading(indices)
+	if !slices.Equal(indices, []int{2, 0, 1}) {
+		t.Errorf("SortReading 结果 %v", indices)
+	}
+}
+
+// benchQueries 基准测试使用的文本块和查询点（10000 个单字铺满 4K 屏幕）
+func benchQueries() ([]TextBlock, []image.Point) {
+	const width, height = 3840, 2160
+	rng := rand.New(rand.NewSource(2))
+	points := make([]image.Point, 1024)
+	for i := range points {
+		points[i] = image.Pt(rng.Intn(width), rng.Intn(height))
+	}
+	return syntheticBlocks(10000, width, height), points
+}
+
+func BenchmarkNewResult(b *testing.B) {
+	blocks, _ := benchQueries()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		NewResult(blocks)
+	}
+}
+
+func BenchmarkResultAt(b *testing.B) {
+	blocks, points := benchQueries()
+	index := NewResult(blocks)
+	b.Run("index", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			p := points[i%len(points)]
+			index.At(p.X, p.Y)
+		}
+	})
+	b.Run("linear", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			p := points[i%len(points)]
+			linearAt(blocks, p.X, p.Y)
+		}
+	})
+}
+
+func BenchmarkResultIntersect(b *testing.B) {
+	blocks, points := benchQueries()
+	index := NewResult(blocks)
+	b.Run("index", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			p := points[i%len(points)]
+			index.Intersect(image.Rect(p.X, p.Y, p.X+400, p.Y+200))
+		}
+	})
+	b.Run("linear", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			p := points[i%len(points)]
+			linearIntersect(blocks, image.Rect(p.X, p.Y, p.X+400, p.Y+200))
+		}
+	})
+}
+
+func BenchmarkResultNearest(b *testing.B) {
+	blocks, points := benchQueries()
+	index := NewResult(blocks)
+	b.Run("index", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			p := points[i%len(points)]
+			index.Nearest(p.X, p.Y)
+		}
+	})
+	b.Run("linear", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			p := points[i%len(points)]
+			linearNearest(blocks, p.X, p.Y)
+		}
+	})
+}
@@ -0,0 +1,207 @@
+package ocr
+
+import (
+	"image"
+	"sort"
+	"strings"
+)
+
+// Line 文本行（按阅读顺序排列的一组文本块）
+type Line struct {
+	Blocks []TextBlock
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
+// GroupLines 按阅读顺序把文本块分组为行：行从上到下，行内从左到右
+// 垂直方向重叠超过较矮文本块一半高度的文本块视为同一行
+func GroupLines(blocks []TextBlock) []Line {
+	var lines []Line
+	for _, group := range groupLineIndices(blocks) {
+		line := Line{
+			Blocks: make([]TextBlock, len(group.indices)),
+			X:      group.X,
+			Y:      group.Y,
+			Width:  group.Width,
+			Height: group.Height,
+		}
+		for j, idx := range group.indices {
+			line.Blocks[j] = blocks[idx]
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// lineIndices 按下标记录的行，用于在不复制文本块的情况下计算阅读顺序
+type lineIndices struct {
+	indices []int
+	X       int
+	Y       int
+	Width   int
+	Height  int
+}
+
+// groupLineIndices 与 GroupLines 相同的分行算法，返回每行文本块在 blocks 中的下标
+func groupLineIndices(blocks []TextBlock) []lineIndices {
+	sorted := make([]int, len(blocks))
+	for i := range sorted {
+		sorted[i] = i
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := blocks[sorted[i]], blocks[sorted[j]]
+		return a.Y*2+a.Height < b.Y*2+b.Height
+	})
+
+	var lines []lineIndices
+	for _, idx := range sorted {
+		block := blocks[idx]
+		placed := false
+		for i := range lines {
+			line := &lines[i]
+			overlap := min(line.Y+line.Height, block.Y+block.Height) - max(line.Y, block.Y)
+			if overlap*2 >= min(line.Height, block.Height) && overlap > 0 {
+				x1 := min(line.X, block.X)
+				y1 := min(line.Y, block.Y)
+				x2 := max(line.X+line.Width, block.X+block.Width)
+				y2 := max(line.Y+line.Height, block.Y+block.Height)
+				line.indices = append(line.indices, idx)
+				line.X, line.Y, line.Width, line.Height = x1, y1, x2-x1, y2-y1
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			lines = append(lines, lineIndices{
+				indices: []int{idx},
+				X:       block.X,
+				Y:       block.Y,
+				Width:   block.Width,
+				Height:  block.Height,
+			})
+		}
+	}
+
+	for i := range lines {
+		indices := lines[i].indices
+		sort.SliceStable(indices, func(a, b int) bool {
+			return blocks[indices[a]].X < blocks[indices[b]].X
+		})
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].Y < lines[j].Y
+	})
+	return lines
+}
+
+// Text 拼接行内文本：中日韩文字之间直接连接，其余用空格分隔
+func (l Line) Text() string {
+	var sb strings.Builder
+	var prev rune
+	for _, block := range l.Blocks {
+		runes := []rune(block.Text)
+		if len(runes) == 0 {
+			continue
+		}
+		if sb.Len() > 0 && !(isCJK(prev) && isCJK(runes[0])) {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(block.Text)
+		prev = runes[len(runes)-1]
+	}
+	return sb.String()
+}
+
+// JoinText 按阅读顺序拼接所有文本块，行之间用换行分隔
+func JoinText(blocks []TextBlock) string {
+	lines := GroupLines(blocks)
+	texts := make([]string, 0, len(lines))
+	for _, line := range lines {
+		texts = append(texts, line.Text())
+	}
+	return strings.Join(texts, "\n")
+}
+
+// NearbyText 截图中 rect 附近的文字（不含中心在 rect 内的文本块），按阅读顺序拼接，最多 maxRunes 个字符
+// 用于翻译按钮、菜单等短文本时提供上下文：范围从上下三行、左右较宽的区域逐步缩小到一行，直到文字不超过 maxRunes
+func NearbyText(blocks []TextBlock, rect image.Rectangle, maxRunes int) string {
+	if rect.Empty() || maxRunes <= 0 {
+		return ""
+	}
+
+	// 行高取 rect 内文本块高度的中位数
+	var outside []TextBlock
+	var heights []int
+	for _, b := range blocks {
+		if image.Pt(b.X+b.Width/2, b.Y+b.Height/2).In(rect) {
+			heights = append(heights, b.Height)
+		} else {
+			outside = append(outside, b)
+		}
+	}
+	lineHeight := rect.Dy()
+	if len(heights) > 0 {
+		sort.Ints(heights)
+		lineHeight = heights[len(heights)/2]
+	}
+	lineHeight = max(lineHeight, 8)
+
+	var text string
+	for n := 3; n >= 1; n-- {
+		dx, dy := 8*n*lineHeight, n*lineHeight*3/2
+		area := image.Rect(rect.Min.X-dx, rect.Min.Y-dy, rect.Max.X+dx, rect.Max.Y+dy)
+		var near []TextBlock
+		for _, b := range outside {
+			if image.Rect(b.X, b.Y, b.X+b.Width, b.Y+b.Height).Overlaps(area) {
+				near = append(near, b)
+			}
+		}
+		text = JoinText(near)
+		if len([]rune(text)) <= maxRunes {
+			return text
+		}
+	}
+	return string([]rune(text)[:maxRunes])
+}
+
+// Paragraph 段落（垂直方向相邻、水平方向重叠的若干行）
+type Paragraph struct {
+	Lines  []Line
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
+// GroupParagraphs 把按阅读顺序排列的行合并为段落
+// 行间距不超过行高且水平方向有重叠的相邻行视为同一段落
+func GroupParagraphs(lines []Line) []Paragraph {
+	var paragraphs []Paragraph
+	for _, line := range lines {
+		if n := len(paragraphs); n > 0 {
+			p := &paragraphs[n-1]
+			last := p.Lines[len(p.Lines)-1]
+			gap := line.Y - (last.Y + last.Height)
+			overlapX := min(p.X+p.Width, line.X+line.Width) - max(p.X, line.X)
+			if gap <= max(last.Height, line.Height) && overlapX > 0 {
+				x1 := min(p.X, line.X)
+				y1 := min(p.Y, line.Y)
+				x2 := max(p.X+p.Width, line.X+line.Width)
+				y2 := max(p.Y+p.Height, line.Y+line.Height)
+				p.Lines = append(p.Lines, line)
+				p.X, p.Y, p.Width, p.Height = x1, y1, x2-x1, y2-y1
+				continue
+			}
+		}
+		paragraphs = append(paragraphs, Paragraph{
+			Lines:  []Line{line},
+			X:      line.X,
+			Y:      line.Y,
+			Width:  line.Width,
+			Height: line.Height,
+		})
+	}
+	return paragraphs
+}
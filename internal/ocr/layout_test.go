@@ -0,0 +1,58 @@
+package ocr
+
+import (
+	"image"
+	"strings"
+	"testing"
+)
+
+// dialogBlocks 确认对话框：上方的提示、三个按钮和远处的状态栏
+var dialogBlocks = []TextBlock{
+	{Text: "Do you want to save changes to report.docx?", X: 100, Y: 100, Width: 360, Height: 20},
+	{Text: "Save", X: 180, Y: 150, Width: 50, Height: 20},
+	{Text: "Don't Save", X: 250, Y: 150, Width: 90, Height: 20},
+	{Text: "Cancel", X: 360, Y: 150, Width: 60, Height: 20},
+	{Text: "状态栏 第 3 页", X: 10, Y: 1000, Width: 120, Height: 20},
+}
+
+func TestNearbyText(t *testing.T) {
+	save := image.Rect(180, 150, 230, 170)
+	cases := []struct {
+		name     string
+		rect     image.Rectangle
+		maxRunes int
+		want     string
+	}{
+		// 上方的提示和旁边的按钮作为上下文，选中的文字本身和远处的状态栏不带上
+		{"button", save, 600, "Do you want to save changes to report.docx?\nDon't Save Cancel"},
+		// 文字太多时缩小到同一行
+		{"shrink-to-line", save, 20, "Don't Save Cancel"},
+		// 缩小到一行仍然太长时截断
+		{"truncate", save, 5, "Don't"},
+		{"empty-rect", image.Rectangle{}, 600, ""},
+		{"no-runes", save, 0, ""},
+		{"far-away", image.Rect(1000, 600, 1100, 620), 600, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := NearbyText(dialogBlocks, c.rect, c.maxRunes); got != c.want {
+				t.Errorf("NearbyText = %q，期望 %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestNearbyTextUsesLineHeight(t *testing.T) {
+	// 选中较高的标题时范围按标题的行高放大，能带上下方较远的正文
+	blocks := []TextBlock{
+		{Text: "Settings", X: 100, Y: 100, Width: 200, Height: 60},
+		{Text: "Choose how screenshots are recognized", X: 100, Y: 240, Width: 400, Height: 20},
+	}
+	got := NearbyText(blocks, image.Rect(100, 100, 300, 160), 600)
+	if !strings.Contains(got, "Choose how") {
+		t.Errorf("NearbyText = %q，应包含下方的正文", got)
+	}
+	if got := NearbyText(blocks[1:], image.Rect(100, 100, 300, 120), 600); got != "" {
+		t.Errorf("普通行高时 NearbyText = %q，不应包含远处的正文", got)
+	}
+}
@@ -0,0 +1,297 @@
+package ocr
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrSuperseded 排队中的识别请求被更新的请求取代（Coalesce 模式）
+	ErrSuperseded = errors.New("识别请求已被更新的请求取代")
+	// ErrTimeout 识别请求在 Timeout 内没有完成
+	ErrTimeout = errors.New("识别超时")
+	// ErrQueueFull 排队的请求数达到 MaxQueue
+	ErrQueueFull = errors.New("识别队列已满")
+	// ErrEngineClosed 引擎已关闭
+	ErrEngineClosed = errors.New("OCR 引擎已关闭")
+)
+
+// LimitOptions 并发限制选项
+type LimitOptions struct {
+	MaxConcurrent int           // 同时进行的识别数；0 时使用引擎声明的 MaxConcurrency，仍为 0 则不限制
+	Coalesce      bool          // 排队的请求只保留最新一个（latest-wins），被取代的请求返回 ErrSuperseded
+	MaxQueue      int           // 最大排队数，0 表示不限制
+	Timeout       time.Duration // 单次调用（排队 + 识别）的最长时间，0 表示不限制
+}
+
+// LimitStats 队列统计
+type LimitStats struct {
+	Limit      int     `json:"limit"`       // 并发上限（0 表示不限制）
+	Active     int     `json:"active"`      // 正在识别的请求数（包括调用方已超时但引擎仍在执行的请求）
+	Queued     int     `json:"queued"`      // 排队中的请求数
+	MaxQueued  int     `json:"max_queued"`  // 观察到的最大排队数
+	Completed  uint64  `json:"completed"`   // 成功完成的请求数
+	Failed     uint64  `json:"failed"`      // 引擎返回错误的请求数
+	Superseded uint64  `json:"superseded"`  // 被取代的请求数
+	TimedOut   uint64  `json:"timed_out"`   // 超时的请求数
+	Rejected   uint64  `json:"rejected"`    // 因队列已满或引擎关闭被拒绝的请求数
+	AvgWaitMs  float64 `json:"avg_wait_ms"` // 平均排队时间
+	AvgRunMs   float64 `json:"avg_run_ms"`  // 平均识别时间
+}
+
+// waiter 排队中的请求，获得执行权时收到 nil，被取代或拒绝时收到对应错误
+type waiter struct {
+	ch chan error
+}
+
+// LimitedEngine 为任意引擎加上并发限制、排队 / 合并请求和调用超时
+// 典型用法: 微信 OCR 通过全局 C 变量返回结果，必须单路执行；
+// 覆盖层连续触发识别时只需要最新一次的结果，开启 Coalesce 后旧请求直接放弃
+type LimitedEngine struct {
+	engine    Engine
+	limit     int
+	limitOnce sync.Once
+	opts      LimitOptions
+
+	mu       sync.Mutex
+	active   int
+	queue    []*waiter
+	closed   bool
+	inflight sync.WaitGroup
+
+	stats     LimitStats
+	waitTotal time.Duration
+	waitCount uint64
+	runTotal  time.Duration
+	runCount  uint64
+}
+
+// NewLimitedEngine 包装引擎
+// 未指定 MaxConcurrent 时，首次识别时才向引擎查询并发上限（路由引擎查询时会创建子引擎）
+func NewLimitedEngine(engine Engine, opts LimitOptions) *LimitedEngine {
+	return &LimitedEngine{engine: engine, limit: max(opts.MaxConcurrent, 0), opts: opts}
+}
+
+// resolveLimit 未指定 MaxConcurrent 时向被包装引擎查询并发上限，只查询一次
+func (l *LimitedEngine) resolveLimit() {
+	l.limitOnce.Do(func() {
+		if l.opts.MaxConcurrent > 0 {
+			return
+		}
+		limit := MaxConcurrency(l.engine)
+		l.mu.Lock()
+		l.limit = limit
+		l.mu.Unlock()
+	})
+}
+
+// Unwrap 返回被包装的引擎
+func (l *LimitedEngine) Unwrap() Engine {
+	return l.engine
+}
+
+// IsAvailable 检查是否可用
+func (l *LimitedEngine) IsAvailable() bool {
+	l.mu.Lock()
+	closed := l.closed
+	l.mu.Unlock()
+	return !closed && l.engine.IsAvailable()
+}
+
+// GetError 获取错误信息
+func (l *LimitedEngine) GetError() string {
+	return l.engine.GetError()
+}
+
+// MaxConcurrency 返回并发上限，超出的调用会排队而不是并发执行
+func (l *LimitedEngine) MaxConcurrency() int {
+	l.resolveLimit()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// Recognize 排队获得执行权后调用被包装引擎
+// 超时后调用方立即返回 ErrTimeout，但引擎内部的调用无法中断，执行权在其真正结束后才释放
+func (l *LimitedEngine) Recognize(img image.Image, preprocess bool) ([]TextBlock, error) {
+	l.resolveLimit()
+
+	var deadline <-chan time.Time
+	if l.opts.Timeout > 0 {
+		timer := time.NewTimer(l.opts.Timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	queuedAt := time.Now()
+	if err := l.acquire(deadline); err != nil {
+		return nil, err
+	}
+
+	startedAt := time.Now()
+	type result struct {
+		blocks []TextBlock
+		err    error
+	}
+	done := make(chan result, 1)
+
+	go func() {
+		defer l.inflight.Done()
+		blocks, err := l.engine.Recognize(img, preprocess)
+		l.record(startedAt.Sub(queuedAt), time.Since(startedAt), err)
+		l.release()
+		done <- result{blocks, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.blocks, r.err
+	case <-deadline:
+		l.count(&l.stats.TimedOut)
+		fmt.Printf("[Limit] 识别超过 %v 仍未完成，放弃等待\n", l.opts.Timeout)
+		return nil, ErrTimeout
+	}
+}
+
+// acquire 获取执行权
+func (l *LimitedEngine) acquire(deadline <-chan time.Time) error {
+	l.mu.Lock()
+	if l.closed {
+		l.stats.Rejected++
+		l.mu.Unlock()
+		return ErrEngineClosed
+	}
+	if l.limit <= 0 || (l.active < l.limit && len(l.queue) == 0) {
+		l.active++
+		l.inflight.Add(1)
+		l.mu.Unlock()
+		return nil
+	}
+
+	if l.opts.Coalesce {
+		for _, w := range l.queue {
+			w.ch <- ErrSuperseded
+			l.stats.Superseded++
+		}
+		l.queue = l.queue[:0]
+	}
+	if l.opts.MaxQueue > 0 && len(l.queue) >= l.opts.MaxQueue {
+		l.stats.Rejected++
+		l.mu.Unlock()
+		return ErrQueueFull
+	}
+
+	w := &waiter{ch: make(chan error, 1)}
+	l.queue = append(l.queue, w)
+	l.stats.MaxQueued = max(l.stats.MaxQueued, len(l.queue))
+	l.mu.Unlock()
+
+	select {
+	case err := <-w.ch:
+		return err
+	case <-deadline:
+		l.mu.Lock()
+		for i, q := range l.queue {
+			if q == w {
+				l.queue = append(l.queue[:i], l.queue[i+1:]...)
+				l.stats.TimedOut++
+				l.mu.Unlock()
+				return ErrTimeout
+			}
+		}
+		l.mu.Unlock()
+
+		// 超时的同时已被唤醒：获得了执行权就交还，否则返回收到的错误
+		if err := <-w.ch; err != nil {
+			return err
+		}
+		l.mu.Lock()
+		l.stats.TimedOut++
+		l.mu.Unlock()
+		l.release()
+		l.inflight.Done()
+		return ErrTimeout
+	}
+}
+
+// record 记录一次识别的排队时间、执行时间和结果
+func (l *LimitedEngine) record(wait, run time.Duration, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err != nil {
+		l.stats.Failed++
+	} else {
+		l.stats.Completed++
+	}
+	l.waitTotal += wait
+	l.waitCount++
+	l.runTotal += run
+	l.runCount++
+}
+
+// release 交还执行权：有排队请求时直接转交给队首，否则减少计数
+func (l *LimitedEngine) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limit <= 0 {
+		l.active--
+		return
+	}
+	if len(l.queue) > 0 && !l.closed {
+		w := l.queue[0]
+		l.queue = l.queue[1:]
+		l.inflight.Add(1)
+		w.ch <- nil
+		return
+	}
+	l.active--
+}
+
+// count 在锁内递增计数器
+func (l *LimitedEngine) count(counter *uint64) {
+	l.mu.Lock()
+	*counter++
+	l.mu.Unlock()
+}
+
+// Stats 返回队列统计
+func (l *LimitedEngine) Stats() LimitStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := l.stats
+	stats.Limit = l.limit
+	stats.Active = l.active
+	stats.Queued = len(l.queue)
+	if l.waitCount > 0 {
+		stats.AvgWaitMs = float64(l.waitTotal.Microseconds()) / 1000 / float64(l.waitCount)
+	}
+	if l.runCount > 0 {
+		stats.AvgRunMs = float64(l.runTotal.Microseconds()) / 1000 / float64(l.runCount)
+	}
+	return stats
+}
+
+// Close 拒绝排队中的请求，等待正在执行的识别结束后关闭被包装引擎
+func (l *LimitedEngine) Close() {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return
+	}
+	l.closed = true
+	for _, w := range l.queue {
+		w.ch <- ErrEngineClosed
+		l.stats.Rejected++
+	}
+	l.queue = nil
+	l.mu.Unlock()
+
+	l.inflight.Wait()
+	l.engine.Close()
+}
@@ -0,0 +1,53 @@
+package ocr
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestAcquireWokenAtDeadline 超时的同时被唤醒：交还执行权并返回 ErrTimeout，计数保持一致
+func TestAcquireWokenAtDeadline(t *testing.T) {
+	l := NewLimitedEngine(nil, LimitOptions{MaxConcurrent: 1})
+	l.limitOnce.Do(func() {})
+
+	// 模拟一个正在执行的请求
+	l.active = 1
+	l.inflight.Add(1)
+
+	deadline := make(chan time.Time)
+	done := make(chan error, 1)
+	go func() { done <- l.acquire(deadline) }()
+	for l.Stats().Queued != 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// 持有锁时触发超时，acquire 在查找队列前等待锁；此时正在执行的请求结束，把执行权转交给它
+	l.mu.Lock()
+	deadline <- time.Now()
+	w := l.queue[0]
+	l.queue = l.queue[1:]
+	l.inflight.Add(1)
+	w.ch <- nil
+	l.inflight.Done()
+	l.mu.Unlock()
+
+	if err := <-done; !errors.Is(err, ErrTimeout) {
+		t.Fatalf("acquire 返回 %v，期望 ErrTimeout", err)
+	}
+	if s := l.Stats(); s.Active != 0 || s.Queued != 0 || s.TimedOut != 1 {
+		t.Errorf("统计 %+v", s)
+	}
+
+	// 转交的执行权已归还，inflight 计数归零
+	waited := make(chan struct{})
+	go func() {
+		l.inflight.Wait()
+		close(waited)
+	}()
+	select {
+	case <-waited:
+	case <-time.After(time.Second):
+		t.Error("inflight 计数没有归零")
+	}
+}
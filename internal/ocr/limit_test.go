@@ -0,0 +1,229 @@
+package ocr_test
+
+import (
+	"errors"
+	"image"
+	"testing"
+	"time"
+
+	"screenocr-wails/internal/ocr"
+	"screenocr-wails/internal/ocr/ocrtest"
+)
+
+func TestLimitedEngineConformance(t *testing.T) {
+	ocrtest.Run(t, func() ocr.Engine {
+		return ocr.NewLimitedEngine(ocrtest.NewFakeEngine(), ocr.LimitOptions{MaxConcurrent: 2})
+	}, ocrtest.Options{})
+}
+
+// gatedEngine 识别开始时通知 started，关闭 release 后才返回
+type gatedEngine struct {
+	*ocrtest.FakeEngine
+	started chan struct{}
+	release chan struct{}
+}
+
+func newGatedEngine() *gatedEngine {
+	g := &gatedEngine{
+		FakeEngine: ocrtest.NewFakeEngine(),
+		started:    make(chan struct{}, 16),
+		release:    make(chan struct{}),
+	}
+	g.RecognizeFunc = func(img image.Image, preprocess bool) ([]ocr.TextBlock, error) {
+		g.started <- struct{}{}
+		<-g.release
+		return []ocr.TextBlock{{Text: "ok", Width: 10, Height: 10}}, nil
+	}
+	return g
+}
+
+// waitStarted 等待一次识别开始
+func (g *gatedEngine) waitStarted(t *testing.T) {
+	t.Helper()
+	select {
+	case <-g.started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("识别没有开始")
+	}
+}
+
+// recognizeAsync 在后台识别，结果的错误发送到返回的通道
+func recognizeAsync(l *ocr.LimitedEngine) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		_, err := l.Recognize(image.NewRGBA(image.Rect(0, 0, 10, 10)), false)
+		done <- err
+	}()
+	return done
+}
+
+// waitStats 等待统计满足条件
+func waitStats(t *testing.T, l *ocr.LimitedEngine, what string, ok func(s ocr.LimitStats) bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !ok(l.Stats()) {
+		if time.Now().After(deadline) {
+			t.Fatalf("等待%s超时，统计 %+v", what, l.Stats())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// result 取出后台识别的错误
+func result(t *testing.T, done <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(2 * time.Second):
+		t.Fatal("识别没有返回")
+		return nil
+	}
+}
+
+func TestLimitedEngineCoalesce(t *testing.T) {
+	g := newGatedEngine()
+	l := ocr.NewLimitedEngine(g, ocr.LimitOptions{MaxConcurrent: 1, Coalesce: true})
+
+	first := recognizeAsync(l)
+	g.waitStarted(t)
+	older := recognizeAsync(l)
+	waitStats(t, l, "排队", func(s ocr.LimitStats) bool { return s.Queued == 1 })
+
+	// 新的请求取代排队中的旧请求
+	latest := recognizeAsync(l)
+	if err := result(t, older); !errors.Is(err, ocr.ErrSuperseded) {
+		t.Errorf("被取代的请求返回 %v，期望 ErrSuperseded", err)
+	}
+	waitStats(t, l, "新请求排队", func(s ocr.LimitStats) bool { return s.Queued == 1 })
+
+	close(g.release)
+	for _, done := range []<-chan error{first, latest} {
+		if err := result(t, done); err != nil {
+			t.Errorf("识别失败: %v", err)
+		}
+	}
+	s := l.Stats()
+	if s.Superseded != 1 || s.Completed != 2 || s.MaxQueued != 1 || s.Queued != 0 || s.Active != 0 {
+		t.Errorf("统计 %+v", s)
+	}
+}
+
+func TestLimitedEngineQueueFull(t *testing.T) {
+	g := newGatedEngine()
+	l := ocr.NewLimitedEngine(g, ocr.LimitOptions{MaxConcurrent: 1, MaxQueue: 1})
+
+	first := recognizeAsync(l)
+	g.waitStarted(t)
+	queued := recognizeAsync(l)
+	waitStats(t, l, "排队", func(s ocr.LimitStats) bool { return s.Queued == 1 })
+
+	if err := result(t, recognizeAsync(l)); !errors.Is(err, ocr.ErrQueueFull) {
+		t.Errorf("队列已满时返回 %v，期望 ErrQueueFull", err)
+	}
+
+	close(g.release)
+	for _, done := range []<-chan error{first, queued} {
+		if err := result(t, done); err != nil {
+			t.Errorf("识别失败: %v", err)
+		}
+	}
+	if s := l.Stats(); s.Rejected != 1 || s.Completed != 2 {
+		t.Errorf("统计 %+v", s)
+	}
+}
+
+func TestLimitedEngineTimeout(t *testing.T) {
+	g := newGatedEngine()
+	l := ocr.NewLimitedEngine(g, ocr.LimitOptions{MaxConcurrent: 1, Timeout: 50 * time.Millisecond})
+
+	// 第一个请求在识别中超时，第二个在排队中超时
+	running := recognizeAsync(l)
+	g.waitStarted(t)
+	queued := recognizeAsync(l)
+	for name, done := range map[string]<-chan error{"running": running, "queued": queued} {
+		if err := result(t, done); !errors.Is(err, ocr.ErrTimeout) {
+			t.Errorf("%s: 返回 %v，期望 ErrTimeout", name, err)
+		}
+	}
+	// 引擎内部的调用无法中断，执行权在其结束前不释放
+	if s := l.Stats(); s.TimedOut != 2 || s.Queued != 0 || s.Active != 1 {
+		t.Errorf("超时后的统计 %+v", s)
+	}
+
+	close(g.release)
+	waitStats(t, l, "识别结束", func(s ocr.LimitStats) bool { return s.Active == 0 })
+	if err := result(t, recognizeAsync(l)); err != nil {
+		t.Errorf("执行权释放后识别失败: %v", err)
+	}
+	if s := l.Stats(); s.Completed != 2 || s.TimedOut != 2 {
+		t.Errorf("统计 %+v", s)
+	}
+}
+
+func TestLimitedEngineCloseRejectsQueued(t *testing.T) {
+	g := newGatedEngine()
+	l := ocr.NewLimitedEngine(g, ocr.LimitOptions{MaxConcurrent: 1})
+
+	running := recognizeAsync(l)
+	g.waitStarted(t)
+	queued := recognizeAsync(l)
+	waitStats(t, l, "排队", func(s ocr.LimitStats) bool { return s.Queued == 1 })
+
+	closed := make(chan struct{})
+	go func() {
+		l.Close()
+		close(closed)
+	}()
+	if err := result(t, queued); !errors.Is(err, ocr.ErrEngineClosed) {
+		t.Errorf("排队中的请求返回 %v，期望 ErrEngineClosed", err)
+	}
+	if err := result(t, recognizeAsync(l)); !errors.Is(err, ocr.ErrEngineClosed) {
+		t.Errorf("关闭后的请求返回 %v，期望 ErrEngineClosed", err)
+	}
+
+	// 正在执行的识别结束后才关闭被包装引擎
+	select {
+	case <-closed:
+		t.Fatal("Close 没有等待正在执行的识别")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(g.release)
+	if err := result(t, running); err != nil {
+		t.Errorf("正在执行的识别失败: %v", err)
+	}
+	<-closed
+	if g.IsAvailable() || l.IsAvailable() {
+		t.Error("关闭后引擎仍可用")
+	}
+	if s := l.Stats(); s.Rejected != 2 || s.Completed != 1 {
+		t.Errorf("统计 %+v", s)
+	}
+}
+
+func TestLimitedEngineStats(t *testing.T) {
+	g := newGatedEngine()
+	l := ocr.NewLimitedEngine(g, ocr.LimitOptions{MaxConcurrent: 1})
+
+	calls := []<-chan error{recognizeAsync(l)}
+	g.waitStarted(t)
+	for i := 0; i < 3; i++ {
+		calls = append(calls, recognizeAsync(l))
+	}
+	waitStats(t, l, "排队", func(s ocr.LimitStats) bool { return s.Queued == 3 })
+	if s := l.Stats(); s.Limit != 1 || s.Active != 1 || s.MaxQueued != 3 {
+		t.Errorf("排队时的统计 %+v", s)
+	}
+
+	close(g.release)
+	for _, done := range calls {
+		if err := result(t, done); err != nil {
+			t.Errorf("识别失败: %v", err)
+		}
+	}
+	s := l.Stats()
+	if s.Queued != 0 || s.Active != 0 || s.MaxQueued != 3 || s.Completed != 4 ||
+		s.Superseded != 0 || s.TimedOut != 0 || s.Rejected != 0 || s.AvgWaitMs <= 0 {
+		t.Errorf("统计 %+v", s)
+	}
+}
@@ -4,11 +4,12 @@ import "image"
 
 // TextBlock 文字块
 type TextBlock struct {
-	Text   string `json:"text"`
-	X      int    `json:"x"`
-	Y      int    `json:"y"`
-	Width  int    `json:"width"`
-	Height int    `json:"height"`
+	Text       string  `json:"text"`
+	X          int     `json:"x"`
+	Y          int     `json:"y"`
+	Width      int     `json:"width"`
+	Height     int     `json:"height"`
+	Confidence float64 `json:"confidence,omitempty"` // 置信度 0-1，引擎未提供时为 0
 }
 
 // Engine OCR 引擎接口
@@ -26,3 +27,17 @@ type Engine interface {
 	Close()
 }
 
+// ConcurrencyLimiter 可选接口：引擎声明自身允许同时进行的识别数
+// 未实现该接口的引擎视为可以并发调用
+type ConcurrencyLimiter interface {
+	// MaxConcurrency 最大并发识别数，0 表示不限制
+	MaxConcurrency() int
+}
+
+// MaxConcurrency 返回引擎允许的最大并发识别数，0 表示不限制
+func MaxConcurrency(engine Engine) int {
+	if limiter, ok := engine.(ConcurrencyLimiter); ok {
+		return max(limiter.MaxConcurrency(), 0)
+	}
+	return 0
+}
@@ -0,0 +1,363 @@
+// Package ocrtest 提供 ocr.Engine 实现的一致性测试套件、假引擎和解析器样例数据
+//
+// 在引擎实现的测试中调用：
+//
+//	func TestConformance(t *testing.T) {
+//		ocrtest.Run(t, func() ocr.Engine { return ocr.NewWindowsOCRNative() }, ocrtest.Options{})
+//	}
+package ocrtest
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"screenocr-wails/internal/ocr"
+)
+
+// Options 一致性测试选项
+type Options struct {
+	Sample         image.Image   // 含文字的样例图片（默认渲染 DefaultSampleLines）
+	HugeWidth      int           // 超大图片宽度（默认 7680，相当于两台 4K 显示器并排）
+	HugeHeight     int           // 超大图片高度（默认 2160）
+	SkipHuge       bool          // 跳过超大图片测试
+	SkipConcurrent bool          // 跳过并发调用测试（引擎不支持并发时设置）
+	Concurrency    int           // 并发调用数（默认 4）
+	Timeout        time.Duration // 单次调用超时（默认 60 秒）
+}
+
+// DefaultSampleLines 默认样例图片中的文字
+var DefaultSampleLines = []string{"Screen OCR 2024", "Hello World"}
+
+// Run 对引擎运行一致性测试
+// newEngine 每个子测试调用一次，引擎不可用时只检查错误信息行为
+func Run(t *testing.T, newEngine func() ocr.Engine, opts Options) {
+	t.Helper()
+
+	if opts.Sample == nil {
+		opts.Sample = RenderText(DefaultSampleLines, 4)
+	}
+	if opts.HugeWidth <= 0 || opts.HugeHeight <= 0 {
+		opts.HugeWidth, opts.HugeHeight = 7680, 2160
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 60 * time.Second
+	}
+
+	probe := newEngine()
+	available := probe.IsAvailable()
+	probe.Close()
+
+	t.Run("ErrorMessages", func(t *testing.T) {
+		testErrorMessages(t, newEngine, opts)
+	})
+	if !available {
+		t.Log("引擎不可用，跳过其余一致性测试")
+		return
+	}
+
+	subtests := []struct {
+		name string
+		fn   func(*testing.T, ocr.Engine, Options)
+	}{
+		{"EmptyImage", testEmptyImage},
+		{"BlankImage", testBlankImage},
+		{"HugeImage", testHugeImage},
+		{"SampleInBounds", testSampleInBounds},
+		{"NegativeOrigin", testNegativeOrigin},
+		{"ImageTypes", testImageTypes},
+		{"Concurrent", testConcurrent},
+	}
+	for _, st := range subtests {
+		st := st
+		t.Run(st.name, func(t *testing.T) {
+			engine := newEngine()
+			defer engine.Close()
+			st.fn(t, engine, opts)
+		})
+	}
+
+	t.Run("Close", func(t *testing.T) {
+		testClose(t, newEngine(), opts)
+	})
+}
+
+// recognize 带超时和 panic 保护的识别调用
+func recognize(t *testing.T, engine ocr.Engine, img image.Image, opts Options) ([]ocr.TextBlock, error) {
+	t.Helper()
+
+	type result struct {
+		blocks []ocr.TextBlock
+		err    error
+		panic  interface{}
+	}
+	done := make(chan result, 1)
+	go func() {
+		var r result
+		defer func() {
+			r.panic = recover()
+			done <- r
+		}()
+		r.blocks, r.err = engine.Recognize(img, false)
+	}()
+
+	select {
+	case r := <-done:
+		if r.panic != nil {
+			t.Fatalf("Recognize panic: %v", r.panic)
+		}
+		if r.err != nil && strings.TrimSpace(r.err.Error()) == "" {
+			t.Errorf("Recognize 返回了空的错误信息")
+		}
+		return r.blocks, r.err
+	case <-time.After(opts.Timeout):
+		t.Fatalf("Recognize 超时 (%v)", opts.Timeout)
+		return nil, nil
+	}
+}
+
+// checkBlocks 检查文本块坐标在图像范围内（坐标相对图像左上角）且内容有效
+func checkBlocks(t *testing.T, blocks []ocr.TextBlock, bounds image.Rectangle) {
+	t.Helper()
+	w, h := bounds.Dx(), bounds.Dy()
+	for i, b := range blocks {
+		if strings.TrimSpace(b.Text) == "" {
+			t.Errorf("文本块 %d 文字为空", i)
+		}
+		if b.Width <= 0 || b.Height <= 0 {
+			t.Errorf("文本块 %d 尺寸无效: %dx%d", i, b.Width, b.Height)
+		}
+		// 允许 2 像素误差（部分引擎坐标为浮点数取整）
+		if b.X < -2 || b.Y < -2 || b.X+b.Width > w+2 || b.Y+b.Height > h+2 {
+			t.Errorf("文本块 %d 超出图像范围 %dx%d: %+v", i, w, h, b)
+		}
+	}
+}
+
+// testErrorMessages 不可用的引擎必须提供错误信息，且识别时返回错误而不是 panic
+func testErrorMessages(t *testing.T, newEngine func() ocr.Engine, opts Options) {
+	engine := newEngine()
+	defer engine.Close()
+
+	if engine.IsAvailable() {
+		return
+	}
+	msg := engine.GetError()
+	if strings.TrimSpace(msg) == "" {
+		t.Errorf("引擎不可用但 GetError 为空")
+	}
+	_, err := recognize(t, engine, opts.Sample, opts)
+	if err == nil {
+		t.Fatalf("不可用的引擎 Recognize 应返回错误")
+	}
+	if msg != "" && !strings.Contains(err.Error(), msg) {
+		t.Errorf("错误信息应包含 GetError 的内容 %q，实际: %q", msg, err.Error())
+	}
+}
+
+// testEmptyImage 0x0 图片不应 panic，可以返回错误或空结果
+func testEmptyImage(t *testing.T, engine ocr.Engine, opts Options) {
+	blocks, err := recognize(t, engine, image.NewRGBA(image.Rect(0, 0, 0, 0)), opts)
+	if err == nil && len(blocks) != 0 {
+		t.Errorf("空图片不应识别出文字，实际 %d 个文本块", len(blocks))
+	}
+}
+
+// testBlankImage 纯白图片不应识别出文字
+func testBlankImage(t *testing.T, engine ocr.Engine, opts Options) {
+	img := image.NewRGBA(image.Rect(0, 0, 200, 100))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	blocks, err := recognize(t, engine, img, opts)
+	if err != nil {
+		t.Logf("纯白图片返回错误: %v", err)
+		return
+	}
+	if len(blocks) != 0 {
+		t.Errorf("纯白图片不应识别出文字，实际: %+v", blocks)
+	}
+}
+
+// testHugeImage 多显示器尺寸的图片应在超时内完成
+func testHugeImage(t *testing.T, engine ocr.Engine, opts Options) {
+	if opts.SkipHuge {
+		t.Skip("已配置跳过超大图片测试")
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, opts.HugeWidth, opts.HugeHeight))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	sample := opts.Sample.Bounds()
+	draw.Draw(img, sample.Add(image.Pt(opts.HugeWidth/2, opts.HugeHeight/2)), opts.Sample, sample.Min, draw.Src)
+
+	blocks, err := recognize(t, engine, img, opts)
+	if err != nil {
+		t.Logf("超大图片返回错误: %v", err)
+		return
+	}
+	checkBlocks(t, blocks, img.Bounds())
+}
+
+// testSampleInBounds 样例图片的识别结果应在图像范围内
+func testSampleInBounds(t *testing.T, engine ocr.Engine, opts Options) {
+	blocks, err := recognize(t, engine, opts.Sample, opts)
+	if err != nil {
+		t.Fatalf("识别样例图片失败: %v", err)
+	}
+	if len(blocks) == 0 {
+		t.Errorf("样例图片未识别出文字")
+	}
+	checkBlocks(t, blocks, opts.Sample.Bounds())
+}
+
+// testNegativeOrigin 多显示器下截图原点可能为负，坐标应始终相对图像左上角
+func testNegativeOrigin(t *testing.T, engine ocr.Engine, opts Options) {
+	want, err := recognize(t, engine, toRGBA(opts.Sample), opts)
+	if err != nil {
+		t.Fatalf("识别样例图片失败: %v", err)
+	}
+
+	shifted := toRGBA(opts.Sample)
+	shifted.Rect = shifted.Rect.Sub(shifted.Rect.Min).Add(image.Pt(-1920, -1080))
+
+	got, err := recognize(t, engine, shifted, opts)
+	if err != nil {
+		t.Fatalf("识别负原点图片失败: %v", err)
+	}
+	if diff := diffBlocks(want, got, 2); diff != "" {
+		t.Errorf("负原点图片的结果与原图不一致: %s", diff)
+	}
+}
+
+// testImageTypes 非 RGBA 类型的图片也应能识别，像素相同的类型结果应一致
+func testImageTypes(t *testing.T, engine ocr.Engine, opts Options) {
+	base := toRGBA(opts.Sample)
+	want, err := recognize(t, engine, base, opts)
+	if err != nil {
+		t.Fatalf("识别样例图片失败: %v", err)
+	}
+
+	bounds := base.Bounds()
+	convert := func(dst draw.Image) image.Image {
+		draw.Draw(dst, bounds, base, bounds.Min, draw.Src)
+		return dst
+	}
+
+	exact := map[string]image.Image{
+		"NRGBA":   convert(image.NewNRGBA(bounds)),
+		"RGBA64":  convert(image.NewRGBA64(bounds)),
+		"NRGBA64": convert(image.NewNRGBA64(bounds)),
+	}
+	for name, img := range exact {
+		got, err := recognize(t, engine, img, opts)
+		if err != nil {
+			t.Errorf("%s: 识别失败: %v", name, err)
+			continue
+		}
+		if diff := diffBlocks(want, got, 0); diff != "" {
+			t.Errorf("%s: 结果与 RGBA 不一致: %s", name, diff)
+		}
+	}
+
+	// 灰度和调色板会改变像素值，只检查结果有效
+	lossy := map[string]image.Image{
+		"Gray":     convert(image.NewGray(bounds)),
+		"Paletted": convert(image.NewPaletted(bounds, color.Palette{color.White, color.Black})),
+	}
+	for name, img := range lossy {
+		got, err := recognize(t, engine, img, opts)
+		if err != nil {
+			t.Errorf("%s: 识别失败: %v", name, err)
+			continue
+		}
+		checkBlocks(t, got, bounds)
+	}
+}
+
+// testConcurrent 并发调用的结果应与顺序调用一致
+func testConcurrent(t *testing.T, engine ocr.Engine, opts Options) {
+	if opts.SkipConcurrent {
+		t.Skip("已配置跳过并发测试")
+	}
+
+	want, err := recognize(t, engine, opts.Sample, opts)
+	if err != nil {
+		t.Fatalf("识别样例图片失败: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan string, opts.Concurrency)
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			got, err := engine.Recognize(opts.Sample, false)
+			if err != nil {
+				errs <- fmt.Sprintf("调用 %d 失败: %v", i, err)
+				return
+			}
+			if diff := diffBlocks(want, got, 0); diff != "" {
+				errs <- fmt.Sprintf("调用 %d 结果不一致: %s", i, diff)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for msg := range errs {
+		t.Error(msg)
+	}
+}
+
+// testClose Close 可重复调用；关闭后识别不应 panic，引擎报告不可用时必须返回错误
+func testClose(t *testing.T, engine ocr.Engine, opts Options) {
+	engine.Close()
+	engine.Close()
+
+	_, err := recognize(t, engine, opts.Sample, opts)
+	if !engine.IsAvailable() && err == nil {
+		t.Errorf("引擎关闭后报告不可用，但 Recognize 没有返回错误")
+	}
+}
+
+// toRGBA 复制为左上角在 (0, 0) 的 RGBA 图片
+func toRGBA(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+	return dst
+}
+
+// diffBlocks 比较两组文本块，坐标允许 tolerance 像素误差；一致时返回空字符串
+func diffBlocks(want, got []ocr.TextBlock, tolerance int) string {
+	if len(want) != len(got) {
+		return fmt.Sprintf("文本块数量不同: 期望 %d，实际 %d", len(want), len(got))
+	}
+	abs := func(v int) int {
+		if v < 0 {
+			return -v
+		}
+		return v
+	}
+	for i := range want {
+		w, g := want[i], got[i]
+		if w.Text != g.Text {
+			return fmt.Sprintf("文本块 %d 文字不同: 期望 %q，实际 %q", i, w.Text, g.Text)
+		}
+		if abs(w.X-g.X) > tolerance || abs(w.Y-g.Y) > tolerance ||
+			abs(w.Width-g.Width) > tolerance || abs(w.Height-g.Height) > tolerance {
+			return fmt.Sprintf("文本块 %d 坐标不同: 期望 %+v，实际 %+v", i, w, g)
+		}
+		if tolerance == 0 && w.Confidence != g.Confidence {
+			return fmt.Sprintf("文本块 %d 置信度不同: 期望 %v，实际 %v", i, w.Confidence, g.Confidence)
+		}
+	}
+	return ""
+}
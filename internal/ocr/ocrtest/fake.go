@@ -0,0 +1,204 @@
+package ocrtest
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"sync"
+	"time"
+
+	"screenocr-wails/internal/ocr"
+)
+
+// Call 一次 Recognize 调用的记录
+type Call struct {
+	Bounds     image.Rectangle
+	Preprocess bool
+}
+
+// FakeEngine 可配置的假引擎，用于在任意平台上测试依赖 ocr.Engine 的代码
+type FakeEngine struct {
+	Blocks        []ocr.TextBlock                                                 // 固定返回的文本块（nil 时按图片内容生成，见 InkBlocks）
+	Err           error                                                           // 固定返回的错误
+	Delay         time.Duration                                                   // 每次识别的耗时
+	ErrorMsg      string                                                          // GetError 返回值
+	Concurrency   int                                                             // MaxConcurrency 返回值（0 表示不限制）
+	RecognizeFunc func(img image.Image, preprocess bool) ([]ocr.TextBlock, error) // 自定义识别逻辑（优先于 Blocks/Err）
+
+	mu        sync.Mutex
+	available bool
+	closed    bool
+	calls     []Call
+	active    int
+	maxActive int
+}
+
+// NewFakeEngine 创建可用的假引擎，每次识别返回 blocks 的副本
+// 不传 blocks 时按图片中的深色文字行生成文本块，可以直接通过一致性测试
+func NewFakeEngine(blocks ...ocr.TextBlock) *FakeEngine {
+	return &FakeEngine{Blocks: blocks, available: true}
+}
+
+// NewUnavailableEngine 创建不可用的假引擎
+func NewUnavailableEngine(errMsg string) *FakeEngine {
+	return &FakeEngine{ErrorMsg: errMsg}
+}
+
+// IsAvailable 检查是否可用
+func (f *FakeEngine) IsAvailable() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.available && !f.closed
+}
+
+// Recognize 记录调用并返回预设结果
+func (f *FakeEngine) Recognize(img image.Image, preprocess bool) ([]ocr.TextBlock, error) {
+	f.mu.Lock()
+	if !f.available || f.closed {
+		msg := f.errorLocked()
+		f.mu.Unlock()
+		return nil, errors.New("FakeEngine 不可用: " + msg)
+	}
+	f.calls = append(f.calls, Call{Bounds: img.Bounds(), Preprocess: preprocess})
+	f.active++
+	if f.active > f.maxActive {
+		f.maxActive = f.active
+	}
+	f.mu.Unlock()
+
+	defer func() {
+		f.mu.Lock()
+		f.active--
+		f.mu.Unlock()
+	}()
+
+	if f.Delay > 0 {
+		time.Sleep(f.Delay)
+	}
+	if f.RecognizeFunc != nil {
+		return f.RecognizeFunc(img, preprocess)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if f.Blocks == nil {
+		return InkBlocks(img), nil
+	}
+
+	blocks := make([]ocr.TextBlock, len(f.Blocks))
+	copy(blocks, f.Blocks)
+	return blocks, nil
+}
+
+// GetError 获取错误信息
+func (f *FakeEngine) GetError() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.errorLocked()
+}
+
+// errorLocked 获取错误信息（调用方需持有锁）
+func (f *FakeEngine) errorLocked() string {
+	if f.closed {
+		return "引擎已关闭"
+	}
+	return f.ErrorMsg
+}
+
+// MaxConcurrency 返回声明的最大并发识别数
+func (f *FakeEngine) MaxConcurrency() int {
+	return f.Concurrency
+}
+
+// Close 关闭引擎，之后的识别调用返回错误
+func (f *FakeEngine) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+}
+
+// Calls 返回所有识别调用记录
+func (f *FakeEngine) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	calls := make([]Call, len(f.calls))
+	copy(calls, f.calls)
+	return calls
+}
+
+// MaxActive 返回观察到的最大并发调用数
+func (f *FakeEngine) MaxActive() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.maxActive
+}
+
+// Closed 返回是否已关闭
+func (f *FakeEngine) Closed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closed
+}
+
+// InkBlocks 把图片中含深色像素的行段作为文本行，返回每行深色像素的外接矩形（相对图像左上角）
+// 文字依次取 DefaultSampleLines，超出时为 "line N"；纯色图片返回空结果
+func InkBlocks(img image.Image) []ocr.TextBlock {
+	b := img.Bounds()
+	type band struct{ top, bottom, left, right int }
+	var bands []band
+	open := false
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		left, right := b.Max.X, b.Min.X
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y < 128 {
+				left = min(left, x)
+				right = x + 1
+			}
+		}
+		if right <= left {
+			open = false
+			continue
+		}
+		if !open {
+			bands = append(bands, band{top: y, left: left, right: right})
+			open = true
+		}
+		last := &bands[len(bands)-1]
+		last.bottom = y + 1
+		last.left = min(last.left, left)
+		last.right = max(last.right, right)
+	}
+
+	// 间隔小于相邻行段较大高度一半的行段属于同一行（如 i 上的点）
+	merged := bands[:0]
+	for _, bd := range bands {
+		if n := len(merged); n > 0 {
+			prev := &merged[n-1]
+			if (bd.top-prev.bottom)*2 < max(prev.bottom-prev.top, bd.bottom-bd.top) {
+				prev.bottom = bd.bottom
+				prev.left = min(prev.left, bd.left)
+				prev.right = max(prev.right, bd.right)
+				continue
+			}
+		}
+		merged = append(merged, bd)
+	}
+
+	blocks := make([]ocr.TextBlock, len(merged))
+	for i, bd := range merged {
+		text := fmt.Sprintf("line %d", i+1)
+		if i < len(DefaultSampleLines) {
+			text = DefaultSampleLines[i]
+		}
+		blocks[i] = ocr.TextBlock{
+			Text:       text,
+			X:          bd.left - b.Min.X,
+			Y:          bd.top - b.Min.Y,
+			Width:      bd.right - bd.left,
+			Height:     bd.bottom - bd.top,
+			Confidence: 1,
+		}
+	}
+	return blocks
+}
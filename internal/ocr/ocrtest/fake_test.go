@@ -0,0 +1,31 @@
+package ocrtest
+
+import (
+	"testing"
+
+	"screenocr-wails/internal/ocr"
+)
+
+func TestFakeEngineConformance(t *testing.T) {
+	Run(t, func() ocr.Engine { return NewFakeEngine() }, Options{})
+}
+
+func TestUnavailableEngineConformance(t *testing.T) {
+	Run(t, func() ocr.Engine { return NewUnavailableEngine("测试引擎未安装") }, Options{})
+}
+
+func TestInkBlocks(t *testing.T) {
+	img := RenderText(DefaultSampleLines, 4)
+	blocks := InkBlocks(img)
+	if len(blocks) != len(DefaultSampleLines) {
+		t.Fatalf("期望 %d 行，实际 %+v", len(DefaultSampleLines), blocks)
+	}
+	for i, b := range blocks {
+		if b.Text != DefaultSampleLines[i] {
+			t.Errorf("第 %d 行文字为 %q", i, b.Text)
+		}
+		if i > 0 && b.Y < blocks[i-1].Y+blocks[i-1].Height {
+			t.Errorf("第 %d 行与上一行重叠: %+v", i, blocks)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package ocrtest
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+
+	"screenocr-wails/internal/ocr"
+)
+
+// fixtureFS 录制的引擎原始输出
+// fixtures/<解析器>/<名称>.out 为原始输出，<名称>.want.json 为期望结果：
+// {"blocks": [...]} 表示期望的文本块，{"error": "..."} 表示期望错误信息包含的内容
+//
+//go:embed fixtures
+var fixtureFS embed.FS
+
+// Fixture 解析器样例
+type Fixture struct {
+	Parser string
+	Name   string
+	Output string          // 引擎原始输出
+	Blocks []ocr.TextBlock // 期望的文本块
+	Error  string          // 期望错误信息包含的内容（为空表示期望成功）
+}
+
+// Parsers 现有引擎输出解析器，键为 fixtures 下的目录名
+var Parsers = map[string]func(string) ([]ocr.TextBlock, error){
+	"blocks": ocr.ParseBlocksJSON, // PowerShell / Python 脚本输出
+	"wechat": ocr.ParseWeChatJSON, // wcocr 回调输出
+}
+
+// Fixtures 加载指定解析器的全部样例
+func Fixtures(parser string) ([]Fixture, error) {
+	dir := path.Join("fixtures", parser)
+	entries, err := fixtureFS.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取样例目录 %s 失败: %w", dir, err)
+	}
+
+	var fixtures []Fixture
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".out") {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ".out")
+
+		output, err := fixtureFS.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		wantData, err := fixtureFS.ReadFile(path.Join(dir, name+".want.json"))
+		if err != nil {
+			return nil, fmt.Errorf("样例 %s/%s 缺少期望结果: %w", parser, name, err)
+		}
+
+		var want struct {
+			Blocks []ocr.TextBlock `json:"blocks"`
+			Error  string          `json:"error"`
+		}
+		if err := json.Unmarshal(wantData, &want); err != nil {
+			return nil, fmt.Errorf("解析样例 %s/%s 期望结果失败: %w", parser, name, err)
+		}
+
+		fixtures = append(fixtures, Fixture{
+			Parser: parser,
+			Name:   name,
+			Output: string(output),
+			Blocks: want.Blocks,
+			Error:  want.Error,
+		})
+	}
+
+	sort.Slice(fixtures, func(i, j int) bool {
+		return fixtures[i].Name < fixtures[j].Name
+	})
+	return fixtures, nil
+}
+
+// RunParserFixtures 用录制的样例测试所有已知解析器
+func RunParserFixtures(t *testing.T) {
+	t.Helper()
+
+	names := make([]string, 0, len(Parsers))
+	for name := range Parsers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		parse := Parsers[name]
+		fixtures, err := Fixtures(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, fx := range fixtures {
+			fx := fx
+			t.Run(name+"/"+fx.Name, func(t *testing.T) {
+				CheckFixture(t, fx, parse)
+			})
+		}
+	}
+}
+
+// CheckFixture 用单个样例检查解析器
+func CheckFixture(t *testing.T, fx Fixture, parse func(string) ([]ocr.TextBlock, error)) {
+	t.Helper()
+
+	blocks, err := parse(fx.Output)
+	if fx.Error != "" {
+		if err == nil {
+			t.Fatalf("期望错误包含 %q，实际成功: %+v", fx.Error, blocks)
+		}
+		if !strings.Contains(err.Error(), fx.Error) {
+			t.Fatalf("错误信息应包含 %q，实际: %q", fx.Error, err.Error())
+		}
+		return
+	}
+
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if diff := diffBlocks(fx.Blocks, blocks, 0); diff != "" {
+		t.Fatal(diff)
+	}
+}
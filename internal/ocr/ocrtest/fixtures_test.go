@@ -0,0 +1,7 @@
+package ocrtest
+
+import "testing"
+
+func TestParserFixtures(t *testing.T) {
+	RunParserFixtures(t)
+}
@@ -0,0 +1,56 @@
+package ocrtest
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// RenderText 渲染白底黑字的样例图片（ASCII 文本，每行一个字符串）
+// scale 为放大倍数，内置点阵字体只有 13 像素高，OCR 引擎通常需要 3 倍以上才能稳定识别
+func RenderText(lines []string, scale int) *image.RGBA {
+	if scale < 1 {
+		scale = 1
+	}
+
+	face := basicfont.Face7x13
+	lineHeight := face.Metrics().Height.Ceil() + 4
+	margin := 8
+
+	width := 0
+	for _, line := range lines {
+		width = max(width, font.MeasureString(face, line).Ceil())
+	}
+	small := image.NewRGBA(image.Rect(0, 0, width+margin*2, lineHeight*len(lines)+margin*2))
+	draw.Draw(small, small.Bounds(), image.White, image.Point{}, draw.Src)
+
+	drawer := &font.Drawer{
+		Dst:  small,
+		Src:  image.NewUniform(color.Black),
+		Face: face,
+	}
+	for i, line := range lines {
+		drawer.Dot = fixed.P(margin, margin+lineHeight*i+face.Metrics().Ascent.Ceil())
+		drawer.DrawString(line)
+	}
+
+	if scale == 1 {
+		return small
+	}
+
+	// 最近邻放大，保持笔画边缘清晰
+	sb := small.Bounds()
+	big := image.NewRGBA(image.Rect(0, 0, sb.Dx()*scale, sb.Dy()*scale))
+	for y := 0; y < big.Bounds().Dy(); y++ {
+		for x := 0; x < big.Bounds().Dx(); x++ {
+			si := small.PixOffset(x/scale, y/scale)
+			di := big.PixOffset(x, y)
+			copy(big.Pix[di:di+4], small.Pix[si:si+4])
+		}
+	}
+	return big
+}
@@ -0,0 +1,143 @@
+package ocr
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ParseBlocksJSON 解析 PowerShell / Python 脚本输出的 JSON 结果
+// 格式: [{"text": ..., "x": ..., "y": ..., "width": ..., "height": ...}, ...]
+// 只有一个文本块时 PowerShell 的 ConvertTo-Json 会输出单个对象；出错时输出 {"error": "..."}
+func ParseBlocksJSON(jsonStr string) ([]TextBlock, error) {
+	jsonStr = strings.TrimSpace(jsonStr)
+
+	if jsonStr == "" || jsonStr == "null" || jsonStr == "[]" {
+		fmt.Println("[OCR] 未识别到文字")
+		return []TextBlock{}, nil
+	}
+
+	// 检查错误
+	if strings.HasPrefix(jsonStr, `{"error"`) {
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(jsonStr), &errResp); err == nil && errResp.Error != "" {
+			return nil, fmt.Errorf("OCR 错误: %s", errResp.Error)
+		}
+	}
+
+	var blocks []TextBlock
+	if err := json.Unmarshal([]byte(jsonStr), &blocks); err != nil {
+		var block TextBlock
+		if err2 := json.Unmarshal([]byte(jsonStr), &block); err2 == nil {
+			return []TextBlock{block}, nil
+		}
+		return nil, fmt.Errorf("解析失败: %w, 原始: %s", err, jsonStr)
+	}
+
+	fmt.Printf("[OCR] 识别成功，共 %d 个文本块\n", len(blocks))
+	return blocks, nil
+}
+
+// ParseWeChatJSON 解析 wcocr 返回的结果 - 格式可能是 dict 或 list
+func ParseWeChatJSON(resultStr string) ([]TextBlock, error) {
+	var textBlocks []TextBlock
+
+	// wcocr 返回格式: {"errcode":0,"imgpath":"...","width":1920,"height":1080,"ocr_response":[...]}
+	// ocr_response 中的坐标是浮点数
+	var dictResult struct {
+		Errcode     int `json:"errcode"`
+		OcrResponse []struct {
+			Text   string  `json:"text"`
+			Left   float64 `json:"left"`
+			Top    float64 `json:"top"`
+			Right  float64 `json:"right"`
+			Bottom float64 `json:"bottom"`
+			Rate   float64 `json:"rate"`
+		} `json:"ocr_response"`
+	}
+
+	if err := json.Unmarshal([]byte(resultStr), &dictResult); err == nil {
+		if dictResult.Errcode != 0 {
+			return nil, fmt.Errorf("OCR 错误码: %d", dictResult.Errcode)
+		}
+
+		for _, item := range dictResult.OcrResponse {
+			if item.Text == "" {
+				continue
+			}
+
+			x := int(item.Left)
+			y := int(item.Top)
+			width := int(item.Right - item.Left)
+			height := int(item.Bottom - item.Top)
+
+			if width > 0 && height > 0 {
+				textBlocks = append(textBlocks, TextBlock{
+					Text:       item.Text,
+					X:          x,
+					Y:          y,
+					Width:      width,
+					Height:     height,
+					Confidence: item.Rate,
+				})
+			}
+		}
+
+		fmt.Printf("✓ OCR 识别到 %d 个文本块\n", len(textBlocks))
+		return textBlocks, nil
+	}
+
+	// 尝试解析为 list 格式 [{"text": ..., ...}, ...]
+	var listResult []struct {
+		Text   string `json:"text"`
+		Word   string `json:"word"`
+		Left   int    `json:"left"`
+		Top    int    `json:"top"`
+		Right  int    `json:"right"`
+		Bottom int    `json:"bottom"`
+		X      int    `json:"x"`
+		Y      int    `json:"y"`
+		Width  int    `json:"width"`
+		Height int    `json:"height"`
+	}
+
+	if err := json.Unmarshal([]byte(resultStr), &listResult); err == nil {
+		for _, item := range listResult {
+			text := item.Text
+			if text == "" {
+				text = item.Word
+			}
+			if text == "" {
+				continue
+			}
+
+			var x, y, width, height int
+			if item.Right > 0 || item.Bottom > 0 {
+				x = item.Left
+				y = item.Top
+				width = item.Right - item.Left
+				height = item.Bottom - item.Top
+			} else {
+				x = item.X
+				y = item.Y
+				width = item.Width
+				height = item.Height
+			}
+
+			if width > 0 && height > 0 {
+				textBlocks = append(textBlocks, TextBlock{
+					Text:   text,
+					X:      x,
+					Y:      y,
+					Width:  width,
+					Height: height,
+				})
+			}
+		}
+		return textBlocks, nil
+	}
+
+	return nil, fmt.Errorf("无法解析 OCR 结果: %s", resultStr)
+}
@@ -2,41 +2,26 @@ package ocr
 
 import (
 	"image"
-	"image/color"
-)
-
-// preprocessImage 图像预处理（增强对比度）
-func preprocessImage(img image.Image) image.Image {
-	bounds := img.Bounds()
-	result := image.NewRGBA(bounds)
-
-	// 增强对比度 1.5 倍
-	contrastFactor := 1.5
 
-	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
-		for x := bounds.Min.X; x < bounds.Max.X; x++ {
-			originalColor := img.At(x, y)
-			r, g, b, a := originalColor.RGBA()
-
-			// 转换为 0-255 范围
-			r8 := float64(r >> 8)
-			g8 := float64(g >> 8)
-			b8 := float64(b >> 8)
+	"screenocr-wails/internal/raster"
+)
 
-			// 增强对比度
-			r8 = clamp((r8-128)*contrastFactor+128, 0, 255)
-			g8 = clamp((g8-128)*contrastFactor+128, 0, 255)
-			b8 = clamp((b8-128)*contrastFactor+128, 0, 255)
+// contrastFactor 预处理时增强对比度的倍数
+const contrastFactor = 1.5
 
-			result.Set(x, y, color.RGBA{
-				R: uint8(r8),
-				G: uint8(g8),
-				B: uint8(b8),
-				A: uint8(a >> 8),
-			})
-		}
+// contrastLUT 对比度增强查找表
+var contrastLUT = func() (table [256]uint8) {
+	for v := range table {
+		table[v] = uint8(clamp((float64(v)-128)*contrastFactor+128, 0, 255))
 	}
+	return table
+}()
 
+// preprocessImage 图像预处理（增强对比度 1.5 倍）
+func preprocessImage(img image.Image) image.Image {
+	src := raster.ToRGBA(img)
+	result := image.NewRGBA(src.Rect)
+	raster.LUT(result, src, &contrastLUT, raster.Parallel)
 	return result
 }
 
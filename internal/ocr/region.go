@@ -0,0 +1,31 @@
+package ocr
+
+import (
+	"fmt"
+	"image"
+)
+
+// RecognizeRegion 只识别图片中的矩形区域（相对图片左上角的坐标），结果坐标映射回整张图片
+// 小区域比整屏识别更快，也不会受区域外文字的干扰
+func RecognizeRegion(engine Engine, img image.Image, rect image.Rectangle, preprocess bool) ([]TextBlock, error) {
+	size := img.Bounds().Size()
+	rect = rect.Canon().Intersect(image.Rectangle{Max: size})
+	if rect.Empty() {
+		return nil, fmt.Errorf("识别区域不在图片范围内")
+	}
+
+	blocks, err := engine.Recognize(cropImage(img, rect), preprocess)
+	if err != nil {
+		return nil, err
+	}
+	OffsetBlocks(blocks, rect.Min.X, rect.Min.Y)
+	return blocks, nil
+}
+
+// OffsetBlocks 把文本块坐标整体平移（原地修改）
+func OffsetBlocks(blocks []TextBlock, dx, dy int) {
+	for i := range blocks {
+		blocks[i].X += dx
+		blocks[i].Y += dy
+	}
+}
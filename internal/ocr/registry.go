@@ -0,0 +1,47 @@
+package ocr
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Factory OCR 引擎构造函数
+type Factory func() Engine
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]Factory)
+)
+
+// Register 注册 OCR 引擎（各平台实现在 init 中调用）
+func Register(name string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	factories[name] = factory
+}
+
+// New 按名称创建 OCR 引擎
+func New(name string) (Engine, error) {
+	factoriesMu.RLock()
+	factory, ok := factories[name]
+	factoriesMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("未知的 OCR 引擎: %s", name)
+	}
+	return factory(), nil
+}
+
+// Names 返回已注册的引擎名称（按字母排序）
+func Names() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
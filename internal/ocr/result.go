@@ -0,0 +1,271 @@
+package ocr
+
+import (
+	"image"
+	"slices"
+	"sort"
+)
+
+// Result 识别结果及其空间索引
+// 文本块按均匀网格建立索引，点查询、框选和最近邻查询只检查附近的网格，
+// 适合覆盖层在鼠标移动时对拆分成单字的大量文本块做命中测试
+// 建立后只读，可被多个 goroutine 同时查询
+type Result struct {
+	Blocks []TextBlock
+
+	// 网格索引
+	originX, originY int
+	cellSize         int
+	cols, rows       int
+	cells            [][]int32     // 每个网格内的文本块下标（按下标升序）
+	firstCell        []image.Point // 每个文本块所在的左上角网格
+
+	// 阅读顺序
+	order []int // 按阅读顺序排列的文本块下标
+	rank  []int // rank[i] 为文本块 i 在阅读顺序中的位置
+}
+
+// maxCellsPerBlock 网格总数上限相对文本块数量的倍数，避免稀疏的大文本块占用过多内存
+const maxCellsPerBlock = 4
+
+// NewResult 为文本块建立索引（blocks 不会被复制，建立后不应再修改）
+func NewResult(blocks []TextBlock) *Result {
+	r := &Result{Blocks: blocks}
+	r.buildGrid()
+	r.buildOrder()
+	return r
+}
+
+// Len 文本块数量
+func (r *Result) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.Blocks)
+}
+
+// buildGrid 建立网格索引：网格边长取文本块高度的中位数，使每个文本块只落在少数几个网格中
+func (r *Result) buildGrid() {
+	if len(r.Blocks) == 0 {
+		return
+	}
+
+	// 不能用 Rectangle.Union：宽或高为 0 的文本块是空矩形，会被忽略
+	bounds := blockRect(r.Blocks[0])
+	heights := make([]int, 0, len(r.Blocks))
+	for _, block := range r.Blocks {
+		rect := blockRect(block)
+		bounds.Min.X = min(bounds.Min.X, rect.Min.X)
+		bounds.Min.Y = min(bounds.Min.Y, rect.Min.Y)
+		bounds.Max.X = max(bounds.Max.X, rect.Max.X)
+		bounds.Max.Y = max(bounds.Max.Y, rect.Max.Y)
+		heights = append(heights, max(block.Height, 1))
+	}
+	sort.Ints(heights)
+
+	cellSize := max(heights[len(heights)/2]*2, 8)
+	// 文本块很少但分布很散时放大网格，限制网格总数
+	for (bounds.Dx()/cellSize+1)*(bounds.Dy()/cellSize+1) > maxCellsPerBlock*len(r.Blocks)+64 {
+		cellSize *= 2
+	}
+
+	r.originX, r.originY = bounds.Min.X, bounds.Min.Y
+	r.cellSize = cellSize
+	// 边界包含在内（与覆盖层命中测试一致），因此右下边缘也要有网格
+	r.cols = bounds.Dx()/cellSize + 1
+	r.rows = bounds.Dy()/cellSize + 1
+	r.cells = make([][]int32, r.cols*r.rows)
+
+	r.firstCell = make([]image.Point, len(r.Blocks))
+	for i, block := range r.Blocks {
+		cx1, cy1, cx2, cy2 := r.cellRange(blockRect(block))
+		r.firstCell[i] = image.Pt(cx1, cy1)
+		for cy := cy1; cy <= cy2; cy++ {
+			for cx := cx1; cx <= cx2; cx++ {
+				cell := cy*r.cols + cx
+				r.cells[cell] = append(r.cells[cell], int32(i))
+			}
+		}
+	}
+}
+
+// buildOrder 计算阅读顺序（与 GroupLines 相同的分行规则）
+func (r *Result) buildOrder() {
+	r.order = make([]int, 0, len(r.Blocks))
+	for _, line := range groupLineIndices(r.Blocks) {
+		r.order = append(r.order, line.indices...)
+	}
+	r.rank = make([]int, len(r.Blocks))
+	for pos, idx := range r.order {
+		r.rank[idx] = pos
+	}
+}
+
+// blockRect 文本块的外接矩形（Max 为右下角坐标本身，边界包含在内）
+func blockRect(block TextBlock) image.Rectangle {
+	return image.Rect(block.X, block.Y, block.X+max(block.Width, 0), block.Y+max(block.Height, 0))
+}
+
+// cellRange 矩形覆盖的网格范围（已裁剪到网格内）
+func (r *Result) cellRange(rect image.Rectangle) (cx1, cy1, cx2, cy2 int) {
+	cx1 = clampInt(floorDiv(rect.Min.X-r.originX, r.cellSize), 0, r.cols-1)
+	cy1 = clampInt(floorDiv(rect.Min.Y-r.originY, r.cellSize), 0, r.rows-1)
+	cx2 = clampInt(floorDiv(rect.Max.X-r.originX, r.cellSize), 0, r.cols-1)
+	cy2 = clampInt(floorDiv(rect.Max.Y-r.originY, r.cellSize), 0, r.rows-1)
+	return
+}
+
+// At 返回包含点 (x, y) 的文本块下标（边界包含在内），多个文本块重叠时返回下标最小的，没有则返回 -1
+func (r *Result) At(x, y int) int {
+	if r.Len() == 0 {
+		return -1
+	}
+	cx, cy, _, _ := r.cellRange(image.Rect(x, y, x, y))
+	for _, idx := range r.cells[cy*r.cols+cx] {
+		block := r.Blocks[idx]
+		if x >= block.X && x <= block.X+block.Width &&
+			y >= block.Y && y <= block.Y+block.Height {
+			return int(idx)
+		}
+	}
+	return -1
+}
+
+// Intersect 返回与矩形相交的文本块下标（升序），边界相接也算相交
+// rect 的 Min、Max 均为包含在内的角点坐标
+func (r *Result) Intersect(rect image.Rectangle) []int {
+	if r.Len() == 0 {
+		return nil
+	}
+	rect = rect.Canon()
+
+	var result []int
+	qx1, qy1, qx2, qy2 := r.cellRange(rect)
+	for cy := qy1; cy <= qy2; cy++ {
+		for cx := qx1; cx <= qx2; cx++ {
+			for _, idx := range r.cells[cy*r.cols+cx] {
+				block := r.Blocks[idx]
+				if rect.Max.X < block.X || rect.Min.X > block.X+block.Width ||
+					rect.Max.Y < block.Y || rect.Min.Y > block.Y+block.Height {
+					continue
+				}
+				// 跨多个网格的文本块只在它与查询范围重叠的第一个网格中报告，避免重复
+				first := r.firstCell[idx]
+				if cx != max(first.X, qx1) || cy != max(first.Y, qy1) {
+					continue
+				}
+				result = append(result, int(idx))
+			}
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+// Nearest 返回离点 (x, y) 最近的文本块下标及距离的平方（点在文本块内时距离为 0），没有文本块时返回 -1
+// 距离相同时返回下标最小的
+func (r *Result) Nearest(x, y int) (int, int) {
+	if r.Len() == 0 {
+		return -1, 0
+	}
+
+	best, bestDist := -1, 0
+	cx, cy, _, _ := r.cellRange(image.Rect(x, y, x, y))
+	for ring := 0; ring <= max(r.cols, r.rows); ring++ {
+		// 从起始网格向外逐圈搜索，整圈网格都比当前最近的文本块远时结束
+		ringDist := -1
+		for gy := cy - ring; gy <= cy+ring; gy++ {
+			if gy < 0 || gy >= r.rows {
+				continue
+			}
+			step := 1
+			if gy != cy-ring && gy != cy+ring {
+				step = 2 * ring // 中间的行只检查圈的左右两端
+			}
+			for gx := cx - ring; gx <= cx+ring; gx += step {
+				if gx < 0 || gx >= r.cols {
+					continue
+				}
+				d := r.cellDist2(gx, gy, x, y)
+				if ringDist < 0 || d < ringDist {
+					ringDist = d
+				}
+				if best >= 0 && d > bestDist {
+					continue
+				}
+				for _, idx := range r.cells[gy*r.cols+gx] {
+					d := pointRectDist2(x, y, r.Blocks[idx])
+					if best < 0 || d < bestDist || (d == bestDist && int(idx) < best) {
+						best, bestDist = int(idx), d
+					}
+				}
+			}
+		}
+		if ringDist < 0 || (best >= 0 && ringDist > bestDist) {
+			break
+		}
+	}
+	return best, bestDist
+}
+
+// cellDist2 点到网格 (gx, gy) 的距离的平方
+func (r *Result) cellDist2(gx, gy, x, y int) int {
+	return pointRectDist2(x, y, TextBlock{
+		X:      r.originX + gx*r.cellSize,
+		Y:      r.originY + gy*r.cellSize,
+		Width:  r.cellSize,
+		Height: r.cellSize,
+	})
+}
+
+// pointRectDist2 点到文本块的距离的平方
+func pointRectDist2(x, y int, block TextBlock) int {
+	dx := max(max(block.X-x, 0), x-(block.X+block.Width))
+	dy := max(max(block.Y-y, 0), y-(block.Y+block.Height))
+	return dx*dx + dy*dy
+}
+
+// ReadingOrder 按阅读顺序（行从上到下，行内从左到右）排列的文本块下标
+func (r *Result) ReadingOrder() []int {
+	if r == nil {
+		return nil
+	}
+	return r.order
+}
+
+// Next 阅读顺序中 i 之后的文本块下标，已是最后一个时返回 -1
+func (r *Result) Next(i int) int {
+	if i < 0 || i >= r.Len() || r.rank[i]+1 >= len(r.order) {
+		return -1
+	}
+	return r.order[r.rank[i]+1]
+}
+
+// Prev 阅读顺序中 i 之前的文本块下标，已是第一个时返回 -1
+func (r *Result) Prev(i int) int {
+	if i < 0 || i >= r.Len() || r.rank[i] == 0 {
+		return -1
+	}
+	return r.order[r.rank[i]-1]
+}
+
+// SortReading 把文本块下标按阅读顺序排序（原地）
+func (r *Result) SortReading(indices []int) {
+	sort.Slice(indices, func(a, b int) bool {
+		return r.rank[indices[a]] < r.rank[indices[b]]
+	})
+}
+
+// floorDiv 向下取整的整数除法（b > 0）
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
+}
+
+// clampInt 把 v 限制在 [lo, hi]
+func clampInt(v, lo, hi int) int {
+	return min(max(v, lo), hi)
+}
@@ -0,0 +1,259 @@
+package ocr
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"sync"
+)
+
+// RouteRule 路由规则：将指定书写系统的区域交给指定引擎重新识别
+type RouteRule struct {
+	Script Script `json:"script"` // cjk / latin
+	Engine string `json:"engine"` // 已注册的引擎名称
+}
+
+// DefaultRouteRules 默认路由规则：中日韩文字交给微信 OCR，拉丁字母交给 Windows OCR
+func DefaultRouteRules() []RouteRule {
+	return []RouteRule{
+		{Script: ScriptCJK, Engine: "wechat"},
+		{Script: ScriptLatin, Engine: "windows"},
+	}
+}
+
+// RouterEngine 路由引擎：先用快速引擎识别全图，再按书写系统把区域裁剪出来交给更合适的引擎
+type RouterEngine struct {
+	first     Engine
+	firstName string
+	rules     []RouteRule
+	resolve   func(name string) (Engine, error)
+	padding   int // 裁剪区域的外扩像素
+
+	mu      sync.Mutex
+	engines map[string]Engine // 已创建的子引擎缓存
+}
+
+// routeRegion 需要重新识别的区域
+type routeRegion struct {
+	engine string
+	rect   image.Rectangle // 相对图像左上角的坐标
+	blocks []int           // 区域覆盖的首轮文本块索引
+}
+
+// NewRouterEngine 创建路由引擎
+// resolve 用于按名称获取子引擎（通常为 ocr.New），首次使用时才会创建
+func NewRouterEngine(first Engine, firstName string, rules []RouteRule, resolve func(name string) (Engine, error)) *RouterEngine {
+	return &RouterEngine{
+		first:     first,
+		firstName: firstName,
+		rules:     rules,
+		resolve:   resolve,
+		padding:   4,
+		engines:   make(map[string]Engine),
+	}
+}
+
+// IsAvailable 检查是否可用（取决于首轮引擎）
+func (r *RouterEngine) IsAvailable() bool {
+	return r.first != nil && r.first.IsAvailable()
+}
+
+// Recognize 识别图片：首轮识别后按规则对区域重新识别
+func (r *RouterEngine) Recognize(img image.Image, preprocess bool) ([]TextBlock, error) {
+	if !r.IsAvailable() {
+		return nil, fmt.Errorf("路由引擎不可用: %s", r.GetError())
+	}
+
+	blocks, err := r.first.Recognize(img, preprocess)
+	if err != nil {
+		return nil, err
+	}
+
+	regions := r.planRegions(blocks, img.Bounds())
+	if len(regions) == 0 {
+		return blocks, nil
+	}
+
+	// 每个区域的替换结果，按区域首个文本块索引存放
+	replacements := make(map[int][]TextBlock)
+	routed := make(map[int]bool)
+
+	for _, region := range regions {
+		engine := r.engine(region.engine)
+		if engine == nil {
+			continue
+		}
+
+		subBlocks, err := engine.Recognize(cropImage(img, region.rect), preprocess)
+		if err != nil || len(subBlocks) == 0 {
+			// 重新识别失败时保留首轮结果
+			fmt.Printf("[Router] 区域 %v 使用 %s 识别失败，保留首轮结果: %v\n", region.rect, region.engine, err)
+			continue
+		}
+
+		OffsetBlocks(subBlocks, region.rect.Min.X, region.rect.Min.Y)
+		replacements[region.blocks[0]] = subBlocks
+		for _, idx := range region.blocks {
+			routed[idx] = true
+		}
+	}
+
+	// 按首轮顺序输出，被替换的区域在其首个文本块位置插入新结果
+	result := make([]TextBlock, 0, len(blocks))
+	for i, block := range blocks {
+		if sub, ok := replacements[i]; ok {
+			result = append(result, sub...)
+			continue
+		}
+		if routed[i] {
+			continue
+		}
+		result = append(result, block)
+	}
+
+	fmt.Printf("[Router] 重新识别 %d 个区域，共 %d 个文本块\n", len(replacements), len(result))
+	return result, nil
+}
+
+// MaxConcurrency 返回首轮引擎和各规则目标引擎中最严格的并发限制
+// 会创建规则中的子引擎；LimitedEngine 在首次识别时才调用，不会在包装时提前创建
+func (r *RouterEngine) MaxConcurrency() int {
+	limit := MaxConcurrency(r.first)
+	for _, rule := range r.rules {
+		if rule.Engine == r.firstName {
+			continue
+		}
+		engine := r.engine(rule.Engine)
+		if engine == nil {
+			continue
+		}
+		if n := MaxConcurrency(engine); n > 0 && (limit == 0 || n < limit) {
+			limit = n
+		}
+	}
+	return limit
+}
+
+// planRegions 按规则把首轮文本块分组为待重新识别的区域
+// 同一行上相邻且目标引擎相同的文本块合并为一个区域，减少子引擎调用次数
+func (r *RouterEngine) planRegions(blocks []TextBlock, bounds image.Rectangle) []routeRegion {
+	var regions []routeRegion
+	imgRect := image.Rect(0, 0, bounds.Dx(), bounds.Dy())
+
+	for i, block := range blocks {
+		target := r.targetFor(block.Text)
+		if target == "" || target == r.firstName || block.Width <= 0 || block.Height <= 0 {
+			continue
+		}
+
+		rect := image.Rect(block.X, block.Y, block.X+block.Width, block.Y+block.Height)
+
+		merged := false
+		for j := range regions {
+			if regions[j].engine == target && sameLine(regions[j].rect, rect) {
+				regions[j].rect = regions[j].rect.Union(rect)
+				regions[j].blocks = append(regions[j].blocks, i)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			regions = append(regions, routeRegion{engine: target, rect: rect, blocks: []int{i}})
+		}
+	}
+
+	// 外扩边距并裁剪到图像范围
+	valid := regions[:0]
+	for _, region := range regions {
+		region.rect = region.rect.Inset(-r.padding).Intersect(imgRect)
+		if !region.rect.Empty() {
+			valid = append(valid, region)
+		}
+	}
+	return valid
+}
+
+// targetFor 查找文本对应的目标引擎
+func (r *RouterEngine) targetFor(text string) string {
+	script := DetectScript(text)
+	if script == ScriptUnknown {
+		return ""
+	}
+	for _, rule := range r.rules {
+		if rule.Script == script {
+			return rule.Engine
+		}
+	}
+	return ""
+}
+
+// engine 获取（必要时创建）子引擎，不可用时返回 nil
+func (r *RouterEngine) engine(name string) Engine {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if engine, ok := r.engines[name]; ok {
+		return engine
+	}
+
+	var engine Engine
+	if r.resolve != nil {
+		created, err := r.resolve(name)
+		if err != nil {
+			fmt.Printf("[Router] 创建引擎 %s 失败: %v\n", name, err)
+		} else if !created.IsAvailable() {
+			fmt.Printf("[Router] 引擎 %s 不可用: %s\n", name, created.GetError())
+			created.Close()
+		} else {
+			engine = created
+		}
+	}
+
+	// 不可用的引擎也缓存为 nil，避免每次识别都重复初始化
+	r.engines[name] = engine
+	return engine
+}
+
+// GetError 获取错误信息
+func (r *RouterEngine) GetError() string {
+	if r.first == nil {
+		return "未配置首轮引擎"
+	}
+	return r.first.GetError()
+}
+
+// Close 关闭首轮引擎和所有子引擎
+func (r *RouterEngine) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for name, engine := range r.engines {
+		if engine != nil {
+			engine.Close()
+		}
+		delete(r.engines, name)
+	}
+	if r.first != nil {
+		r.first.Close()
+	}
+}
+
+// sameLine 判断两个矩形是否在同一行且水平距离足够近
+func sameLine(a, b image.Rectangle) bool {
+	overlap := min(a.Max.Y, b.Max.Y) - max(a.Min.Y, b.Min.Y)
+	minHeight := min(a.Dy(), b.Dy())
+	if overlap*2 < minHeight {
+		return false
+	}
+
+	gap := max(a.Min.X, b.Min.X) - min(a.Max.X, b.Max.X)
+	return gap <= max(a.Dy(), b.Dy())*2
+}
+
+// cropImage 裁剪图像，rect 为相对图像左上角的坐标，返回图像的左上角从 (0, 0) 开始
+func cropImage(img image.Image, rect image.Rectangle) image.Image {
+	src := rect.Add(img.Bounds().Min)
+	dst := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, src.Min, draw.Src)
+	return dst
+}
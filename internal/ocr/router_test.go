@@ -0,0 +1,196 @@
+package ocr_test
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"slices"
+	"testing"
+
+	"screenocr-wails/internal/ocr"
+	"screenocr-wails/internal/ocr/ocrtest"
+)
+
+func TestRouterEngineConformance(t *testing.T) {
+	ocrtest.Run(t, func() ocr.Engine {
+		rules := []ocr.RouteRule{{Script: ocr.ScriptLatin, Engine: "latin"}}
+		return ocr.NewRouterEngine(ocrtest.NewFakeEngine(), "fast", rules, func(name string) (ocr.Engine, error) {
+			return ocrtest.NewFakeEngine(), nil
+		})
+	}, ocrtest.Options{})
+}
+
+// routeFixture 首轮识别出一行中文和一行英文，子引擎按名称记录创建次数
+type routeFixture struct {
+	first   *ocrtest.FakeEngine
+	engines map[string]*ocrtest.FakeEngine
+	created map[string]int
+}
+
+func newRouteFixture() *routeFixture {
+	return &routeFixture{
+		first: ocrtest.NewFakeEngine(
+			ocr.TextBlock{Text: "你好世界", X: 10, Y: 10, Width: 80, Height: 20},
+			ocr.TextBlock{Text: "Hello", X: 10, Y: 50, Width: 60, Height: 20},
+			ocr.TextBlock{Text: "2024", X: 100, Y: 50, Width: 40, Height: 20},
+		),
+		engines: map[string]*ocrtest.FakeEngine{
+			"cjk":   ocrtest.NewFakeEngine(ocr.TextBlock{Text: "你好，世界", X: 2, Y: 3, Width: 70, Height: 16}),
+			"latin": ocrtest.NewFakeEngine(ocr.TextBlock{Text: "Hello!", X: 1, Y: 2, Width: 58, Height: 16}),
+		},
+		created: make(map[string]int),
+	}
+}
+
+func (f *routeFixture) resolve(name string) (ocr.Engine, error) {
+	f.created[name]++
+	engine, ok := f.engines[name]
+	if !ok {
+		return nil, fmt.Errorf("未知的引擎: %s", name)
+	}
+	return engine, nil
+}
+
+func (f *routeFixture) router(rules ...ocr.RouteRule) *ocr.RouterEngine {
+	return ocr.NewRouterEngine(f.first, "fast", rules, f.resolve)
+}
+
+func texts(blocks []ocr.TextBlock) []string {
+	out := make([]string, len(blocks))
+	for i, b := range blocks {
+		out[i] = b.Text
+	}
+	return out
+}
+
+func TestRouterRoutesByScript(t *testing.T) {
+	f := newRouteFixture()
+	router := f.router(
+		ocr.RouteRule{Script: ocr.ScriptCJK, Engine: "cjk"},
+		ocr.RouteRule{Script: ocr.ScriptLatin, Engine: "latin"},
+	)
+
+	blocks, err := router.Recognize(image.NewRGBA(image.Rect(0, 0, 200, 100)), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"你好，世界", "Hello!", "2024"}
+	if got := texts(blocks); !slices.Equal(got, want) {
+		t.Fatalf("文本块 %q，期望 %q", got, want)
+	}
+
+	// 区域外扩 4 像素后裁剪，子引擎的坐标加上区域左上角
+	if b := blocks[0]; b.X != 2+6 || b.Y != 3+6 {
+		t.Errorf("子引擎结果未换算到原图坐标: %+v", b)
+	}
+	calls := f.engines["cjk"].Calls()
+	if len(calls) != 1 || calls[0].Bounds != image.Rect(0, 0, 88, 28) {
+		t.Errorf("中文区域裁剪不正确: %+v", calls)
+	}
+}
+
+func TestRouterMergesSameLine(t *testing.T) {
+	f := newRouteFixture()
+	f.first.Blocks = []ocr.TextBlock{
+		{Text: "Hello", X: 10, Y: 10, Width: 50, Height: 20},
+		{Text: "World", X: 70, Y: 12, Width: 50, Height: 20},
+	}
+	router := f.router(ocr.RouteRule{Script: ocr.ScriptLatin, Engine: "latin"})
+
+	blocks, err := router.Recognize(image.NewRGBA(image.Rect(0, 0, 200, 100)), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := texts(blocks); !slices.Equal(got, []string{"Hello!"}) {
+		t.Errorf("同一行的文本块应合并为一个区域重新识别，实际 %q", got)
+	}
+	if n := len(f.engines["latin"].Calls()); n != 1 {
+		t.Errorf("子引擎调用 %d 次，期望 1 次", n)
+	}
+}
+
+func TestRouterFallsBackToFirstEngine(t *testing.T) {
+	cases := []struct {
+		name  string
+		setup func(f *routeFixture)
+		rules []ocr.RouteRule
+	}{
+		{
+			name:  "子引擎识别失败",
+			setup: func(f *routeFixture) { f.engines["cjk"].Err = errors.New("识别失败") },
+			rules: []ocr.RouteRule{{Script: ocr.ScriptCJK, Engine: "cjk"}},
+		},
+		{
+			name:  "子引擎没有结果",
+			setup: func(f *routeFixture) { f.engines["cjk"].Blocks = []ocr.TextBlock{} },
+			rules: []ocr.RouteRule{{Script: ocr.ScriptCJK, Engine: "cjk"}},
+		},
+		{
+			name:  "子引擎不可用",
+			setup: func(f *routeFixture) { f.engines["cjk"] = ocrtest.NewUnavailableEngine("未安装") },
+			rules: []ocr.RouteRule{{Script: ocr.ScriptCJK, Engine: "cjk"}},
+		},
+		{
+			name:  "未知的引擎",
+			rules: []ocr.RouteRule{{Script: ocr.ScriptCJK, Engine: "missing"}},
+		},
+		{
+			name:  "规则指向首轮引擎",
+			rules: []ocr.RouteRule{{Script: ocr.ScriptCJK, Engine: "fast"}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newRouteFixture()
+			if tc.setup != nil {
+				tc.setup(f)
+			}
+			blocks, err := f.router(tc.rules...).Recognize(image.NewRGBA(image.Rect(0, 0, 200, 100)), false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(blocks, f.first.Blocks) {
+				t.Errorf("应保留首轮结果，实际 %+v", blocks)
+			}
+		})
+	}
+}
+
+func TestRouterCreatesEnginesLazily(t *testing.T) {
+	f := newRouteFixture()
+	f.engines["cjk"].Concurrency = 1
+	f.first.Blocks = []ocr.TextBlock{{Text: "Hello", X: 10, Y: 10, Width: 50, Height: 20}}
+	rules := []ocr.RouteRule{
+		{Script: ocr.ScriptCJK, Engine: "cjk"},
+		{Script: ocr.ScriptLatin, Engine: "latin"},
+	}
+
+	router := f.router(rules...)
+	img := image.NewRGBA(image.Rect(0, 0, 200, 100))
+	for i := 0; i < 2; i++ {
+		if _, err := router.Recognize(img, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if f.created["cjk"] != 0 || f.created["latin"] != 1 {
+		t.Errorf("只应创建一次用到的子引擎，实际 %v", f.created)
+	}
+
+	// 包装为 LimitedEngine 时不创建子引擎，首次识别时才查询并发上限
+	f = newRouteFixture()
+	f.engines["cjk"].Concurrency = 1
+	limited := ocr.NewLimitedEngine(f.router(rules...), ocr.LimitOptions{})
+	if len(f.created) != 0 {
+		t.Fatalf("包装时创建了子引擎: %v", f.created)
+	}
+	if _, err := limited.Recognize(img, false); err != nil {
+		t.Fatal(err)
+	}
+	if n := limited.MaxConcurrency(); n != 1 {
+		t.Errorf("并发上限应取子引擎中最严格的 1，实际 %d", n)
+	}
+	if f.created["cjk"] != 1 || f.created["latin"] != 1 {
+		t.Errorf("子引擎应各创建一次，实际 %v", f.created)
+	}
+}
@@ -0,0 +1,42 @@
+package ocr
+
+import "unicode"
+
+// Script 文字所属的书写系统
+type Script string
+
+const (
+	ScriptUnknown Script = ""
+	ScriptCJK     Script = "cjk"   // 中日韩文字
+	ScriptLatin   Script = "latin" // 拉丁字母（英文等）
+)
+
+// DetectScript 检测文本的主要书写系统
+// 中日韩字符至少占拉丁字母的 1/3 即视为 CJK（中文引擎对中英混排的识别效果更好）
+func DetectScript(text string) Script {
+	cjk, latin := 0, 0
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			cjk++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+
+	if cjk == 0 && latin == 0 {
+		return ScriptUnknown
+	}
+	if cjk*3 >= latin {
+		return ScriptCJK
+	}
+	return ScriptLatin
+}
+
+// isCJK 检查是否是中日韩文字
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r)
+}
@@ -0,0 +1,157 @@
+package ocr
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+
+	"screenocr-wails/internal/raster"
+
+	"golang.org/x/image/bmp"
+)
+
+// Transport 把图片交给引擎的方式
+type Transport string
+
+const (
+	TransportStdin Transport = "stdin" // 原始 BGRA 像素通过标准输入传给子进程，不落盘
+	TransportFile  Transport = "file"  // 写入临时文件（引擎只接受文件路径时使用）
+)
+
+// TempFormat 临时文件格式
+type TempFormat int
+
+const (
+	TempPNG TempFormat = iota // PNG（最快压缩级别）
+	TempBMP                   // 未压缩 BMP，编码最快但文件最大
+)
+
+// RawHeaderSize 原始帧头部长度：宽、高各为 4 字节小端整数
+const RawHeaderSize = 8
+
+// AppendBGRA 把图片转换为紧密排列的 BGRA 像素（从上到下逐行）追加到 dst
+func AppendBGRA(dst []byte, img image.Image) []byte {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	start := len(dst)
+	if n := start + w*h*4; cap(dst) < n {
+		grown := make([]byte, start, n)
+		copy(grown, dst)
+		dst = grown
+	}
+	dst = dst[:start+w*h*4]
+	out := dst[start:]
+
+	switch src := img.(type) {
+	case *image.RGBA:
+		raster.Copy(raster.FromBGRA(out, w, h, w*4), src, raster.SwapRB)
+	default:
+		i := 0
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				r, g, b, a := img.At(x, y).RGBA()
+				out[i], out[i+1], out[i+2], out[i+3] = byte(b>>8), byte(g>>8), byte(r>>8), byte(a>>8)
+				i += 4
+			}
+		}
+	}
+	return dst
+}
+
+// WriteRawFrame 写入原始帧：8 字节头部（宽、高）+ BGRA 像素（预乘 alpha）
+func WriteRawFrame(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	var header [RawHeaderSize]byte
+	binary.LittleEndian.PutUint32(header[0:], uint32(bounds.Dx()))
+	binary.LittleEndian.PutUint32(header[4:], uint32(bounds.Dy()))
+	if _, err := w.Write(header[:]); err != nil {
+		return fmt.Errorf("写入图片数据失败: %w", err)
+	}
+
+	// 逐行转换写入，避免为大屏幕截图一次性分配完整的缓冲区
+	row := make([]byte, 0, bounds.Dx()*4)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		row = AppendBGRA(row[:0], subImage(img, image.Rect(bounds.Min.X, y, bounds.Max.X, y+1)))
+		if _, err := w.Write(row); err != nil {
+			return fmt.Errorf("写入图片数据失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReadRawFrame 读取 WriteRawFrame 写入的原始帧
+func ReadRawFrame(r io.Reader) (*image.RGBA, error) {
+	var header [RawHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, fmt.Errorf("读取图片头部失败: %w", err)
+	}
+	w := int(binary.LittleEndian.Uint32(header[0:]))
+	h := int(binary.LittleEndian.Uint32(header[4:]))
+	if w < 0 || h < 0 || w > 1<<16 || h > 1<<16 {
+		return nil, fmt.Errorf("图片尺寸无效: %dx%d", w, h)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	if _, err := io.ReadFull(r, img.Pix); err != nil {
+		return nil, fmt.Errorf("读取图片数据失败: %w", err)
+	}
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+2] = img.Pix[i+2], img.Pix[i]
+	}
+	return img, nil
+}
+
+// subImage 截取子图（支持 SubImage 的类型直接共享像素）
+func subImage(img image.Image, r image.Rectangle) image.Image {
+	if s, ok := img.(interface {
+		SubImage(image.Rectangle) image.Image
+	}); ok {
+		return s.SubImage(r)
+	}
+	return cropImage(img, r.Sub(img.Bounds().Min))
+}
+
+// EncodeImage 按临时文件格式编码图片
+func EncodeImage(w io.Writer, img image.Image, format TempFormat) error {
+	switch format {
+	case TempBMP:
+		return bmp.Encode(w, img)
+	default:
+		encoder := png.Encoder{CompressionLevel: png.BestSpeed}
+		return encoder.Encode(w, img)
+	}
+}
+
+// TempImage 把图片写入临时文件，返回路径和删除函数
+// 只在引擎必须读取文件时使用；文件仅当前用户可读，调用方应尽快调用删除函数
+func TempImage(img image.Image, format TempFormat) (string, func(), error) {
+	ext := ".png"
+	if format == TempBMP {
+		ext = ".bmp"
+	}
+
+	f, err := os.CreateTemp("", "screenocr_*"+ext)
+	if err != nil {
+		return "", nil, fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	path := f.Name()
+	cleanup := func() { os.Remove(path) }
+
+	bw := bufio.NewWriterSize(f, 1<<20)
+	err = EncodeImage(bw, img, format)
+	if err == nil {
+		err = bw.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("保存图片失败: %w", err)
+	}
+	return path, cleanup, nil
+}
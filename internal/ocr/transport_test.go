@@ -0,0 +1,228 @@
+package ocr
+
+import (
+	"bufio"
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math/rand"
+	"os"
+	"testing"
+
+	"golang.org/x/image/bmp"
+)
+
+// syntheticScreen 生成类似桌面截图的测试图片：纯色窗口、标题栏和文字状的细碎像素
+func syntheticScreen(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	rng := rand.New(rand.NewSource(1))
+	fill := func(r image.Rectangle, c color.RGBA) {
+		draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Src)
+	}
+
+	fill(img.Bounds(), color.RGBA{32, 64, 96, 255})
+	for i := 0; i < 12; i++ {
+		x, y := rng.Intn(width), rng.Intn(height)
+		win := image.Rect(x, y, x+width/3, y+height/3)
+		fill(win, color.RGBA{250, 250, 250, 255})
+		fill(image.Rect(win.Min.X, win.Min.Y, win.Max.X, win.Min.Y+30), color.RGBA{40, 40, 48, 255})
+
+		// 文字行：随机的深色短笔画
+		for ly := win.Min.Y + 40; ly+16 < win.Max.Y; ly += 24 {
+			for lx := win.Min.X + 8; lx < win.Max.X-8; lx += 3 {
+				if rng.Intn(3) == 0 {
+					fill(image.Rect(lx, ly+rng.Intn(6), lx+2, ly+10+rng.Intn(6)), color.RGBA{20, 20, 20, 255})
+				}
+			}
+		}
+	}
+	return img
+}
+
+// toRGBA 复制为左上角在 (0, 0) 的 RGBA 图片
+func toRGBA(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+	return dst
+}
+
+// transportImages 各种类型和原点的图片
+func transportImages() map[string]image.Image {
+	screen := syntheticScreen(320, 200)
+	shifted := *toRGBA(screen)
+	shifted.Rect = shifted.Rect.Add(image.Pt(-1920, -1080))
+
+	convert := func(dst draw.Image) image.Image {
+		draw.Draw(dst, dst.Bounds(), screen, image.Point{}, draw.Src)
+		return dst
+	}
+	translucent := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	translucent.Pix = []byte{
+		255, 0, 0, 128, 0, 255, 0, 64, 0, 0, 255, 0,
+		10, 20, 30, 255, 200, 100, 50, 1, 255, 255, 255, 200,
+	}
+
+	return map[string]image.Image{
+		"RGBA":       screen,
+		"SubImage":   screen.SubImage(image.Rect(17, 9, 251, 133)),
+		"负原点":        &shifted,
+		"NRGBA":      convert(image.NewNRGBA(screen.Bounds())),
+		"Gray":       convert(image.NewGray(screen.Bounds())),
+		"半透明 NRGBA":  translucent,
+		"空图片":        image.NewRGBA(image.Rect(0, 0, 0, 0)),
+		"单像素 RGBA64": convert(image.NewRGBA64(image.Rect(0, 0, 1, 1))),
+	}
+}
+
+func TestRawFrameRoundTrip(t *testing.T) {
+	for name, img := range transportImages() {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteRawFrame(&buf, img); err != nil {
+				t.Fatal(err)
+			}
+			b := img.Bounds()
+			if want := RawHeaderSize + b.Dx()*b.Dy()*4; buf.Len() != want {
+				t.Fatalf("原始帧 %d 字节，期望 %d", buf.Len(), want)
+			}
+
+			got, err := ReadRawFrame(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// 原始帧为预乘 alpha，与 draw 到 RGBA 的结果逐字节一致
+			if want := toRGBA(img); got.Rect != want.Rect || !bytes.Equal(got.Pix, want.Pix) {
+				t.Errorf("读回的像素与原图不一致")
+			}
+		})
+	}
+}
+
+func TestAppendBGRAKeepsPrefix(t *testing.T) {
+	img := syntheticScreen(8, 4)
+	prefix := []byte("head")
+	out := AppendBGRA(append([]byte(nil), prefix...), img)
+	if !bytes.HasPrefix(out, prefix) || len(out) != len(prefix)+8*4*4 {
+		t.Fatalf("追加结果不正确: %d 字节", len(out))
+	}
+	if b, g, r, a := out[4], out[5], out[6], out[7]; [4]byte{r, g, b, a} != [4]byte(img.Pix[:4]) {
+		t.Errorf("第一个像素应为 BGRA 顺序")
+	}
+}
+
+func TestReadRawFrameRejectsBadInput(t *testing.T) {
+	cases := map[string][]byte{
+		"头部不完整": {1, 0, 0},
+		"尺寸过大":  {0, 0, 0, 1, 1, 0, 0, 0},
+		"像素不完整": {2, 0, 0, 0, 1, 0, 0, 0, 1, 2, 3, 4},
+	}
+	for name, data := range cases {
+		if _, err := ReadRawFrame(bytes.NewReader(data)); err == nil {
+			t.Errorf("%s: 应返回错误", name)
+		}
+	}
+}
+
+func TestTempImageDecodes(t *testing.T) {
+	img := syntheticScreen(320, 200)
+	decoders := map[TempFormat]func(io.Reader) (image.Image, error){
+		TempPNG: png.Decode,
+		TempBMP: bmp.Decode,
+	}
+	for format, decode := range decoders {
+		path, cleanup, err := TempImage(img, format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := decode(f)
+		f.Close()
+		if err != nil {
+			t.Fatalf("格式 %d: 解码临时文件失败: %v", format, err)
+		}
+		if !bytes.Equal(toRGBA(decoded).Pix, img.Pix) {
+			t.Errorf("格式 %d: 临时文件的像素与原图不一致", format)
+		}
+
+		cleanup()
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("格式 %d: 删除函数没有删除临时文件", format)
+		}
+	}
+}
+
+// BenchmarkTransport 比较截图交给引擎的各种方式，只测量传递本身（编码、写文件或管道），不包括识别
+func BenchmarkTransport(b *testing.B) {
+	img := syntheticScreen(3840, 2160)
+	cases := []struct {
+		name string
+		run  func() error
+	}{
+		{"png-file-default", func() error {
+			f, err := os.CreateTemp("", "screenocr_bench_*.png")
+			if err != nil {
+				return err
+			}
+			defer os.Remove(f.Name())
+			defer f.Close()
+			return png.Encode(f, img)
+		}},
+		{"png-file-fast", func() error {
+			_, cleanup, err := TempImage(img, TempPNG)
+			if err == nil {
+				cleanup()
+			}
+			return err
+		}},
+		{"bmp-file", func() error {
+			_, cleanup, err := TempImage(img, TempBMP)
+			if err == nil {
+				cleanup()
+			}
+			return err
+		}},
+		{"raw-pipe", func() error {
+			pr, pw, err := os.Pipe()
+			if err != nil {
+				return err
+			}
+			done := make(chan struct{})
+			go func() {
+				io.Copy(io.Discard, pr)
+				pr.Close()
+				close(done)
+			}()
+			bw := bufio.NewWriterSize(pw, 1<<20)
+			err = WriteRawFrame(bw, img)
+			if err == nil {
+				err = bw.Flush()
+			}
+			pw.Close()
+			<-done
+			return err
+		}},
+		{"raw-memory", func() error {
+			AppendBGRA(nil, img)
+			return nil
+		}},
+	}
+
+	for _, c := range cases {
+		b.Run(c.name, func(b *testing.B) {
+			b.SetBytes(int64(len(img.Pix)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := c.run(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
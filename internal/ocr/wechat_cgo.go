@@ -109,10 +109,8 @@ static void wcocr_stop() {
 import "C"
 
 import (
-	"encoding/json"
 	"fmt"
 	"image"
-	"image/png"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -134,6 +132,10 @@ type WeChatOCRCGO struct {
 	isWeChat4     bool   // 是否是微信 4.0
 }
 
+func init() {
+	Register("wechat", func() Engine { return NewWeChatOCRCGO() })
+}
+
 // ocrCandidate OCR 组件候选
 type ocrCandidate struct {
 	path    string
@@ -507,19 +509,12 @@ func (w *WeChatOCRCGO) Recognize(img image.Image, preprocess bool) ([]TextBlock,
 		img = preprocessImage(img)
 	}
 
-	// 保存临时文件
-	tmpFile, err := os.CreateTemp("", "wechat_ocr_*.png")
+	// 保存临时文件（wcocr 只接受文件路径，使用最快压缩级别的 PNG）
+	tmpPath, cleanup, err := TempImage(img, TempPNG)
 	if err != nil {
-		return nil, fmt.Errorf("创建临时文件失败: %w", err)
-	}
-	tmpPath := tmpFile.Name()
-	defer os.Remove(tmpPath)
-
-	if err := png.Encode(tmpFile, img); err != nil {
-		tmpFile.Close()
-		return nil, fmt.Errorf("保存图片失败: %w", err)
+		return nil, err
 	}
-	tmpFile.Close()
+	defer cleanup()
 
 	// 调用 OCR
 	return w.recognizeImage(tmpPath)
@@ -561,108 +556,7 @@ func (w *WeChatOCRCGO) recognizeImage(imagePath string) ([]TextBlock, error) {
 	}
 
 	// 解析结果 - wcocr 返回的格式可能是 dict 或 list
-	return w.parseOCRResult(resultStr)
-}
-
-// parseOCRResult 解析 OCR 结果
-func (w *WeChatOCRCGO) parseOCRResult(resultStr string) ([]TextBlock, error) {
-	var textBlocks []TextBlock
-
-	// wcocr 返回格式: {"errcode":0,"imgpath":"...","width":1920,"height":1080,"ocr_response":[...]}
-	// ocr_response 中的坐标是浮点数
-	var dictResult struct {
-		Errcode     int `json:"errcode"`
-		OcrResponse []struct {
-			Text   string  `json:"text"`
-			Left   float64 `json:"left"`
-			Top    float64 `json:"top"`
-			Right  float64 `json:"right"`
-			Bottom float64 `json:"bottom"`
-			Rate   float64 `json:"rate"`
-		} `json:"ocr_response"`
-	}
-
-	if err := json.Unmarshal([]byte(resultStr), &dictResult); err == nil {
-		if dictResult.Errcode != 0 {
-			return nil, fmt.Errorf("OCR 错误码: %d", dictResult.Errcode)
-		}
-
-		for _, item := range dictResult.OcrResponse {
-			if item.Text == "" {
-				continue
-			}
-
-			x := int(item.Left)
-			y := int(item.Top)
-			width := int(item.Right - item.Left)
-			height := int(item.Bottom - item.Top)
-
-			if width > 0 && height > 0 {
-				textBlocks = append(textBlocks, TextBlock{
-					Text:   item.Text,
-					X:      x,
-					Y:      y,
-					Width:  width,
-					Height: height,
-				})
-			}
-		}
-
-		fmt.Printf("✓ OCR 识别到 %d 个文本块\n", len(textBlocks))
-		return textBlocks, nil
-	}
-
-	// 尝试解析为 list 格式 [{"text": ..., ...}, ...]
-	var listResult []struct {
-		Text   string `json:"text"`
-		Word   string `json:"word"`
-		Left   int    `json:"left"`
-		Top    int    `json:"top"`
-		Right  int    `json:"right"`
-		Bottom int    `json:"bottom"`
-		X      int    `json:"x"`
-		Y      int    `json:"y"`
-		Width  int    `json:"width"`
-		Height int    `json:"height"`
-	}
-
-	if err := json.Unmarshal([]byte(resultStr), &listResult); err == nil {
-		for _, item := range listResult {
-			text := item.Text
-			if text == "" {
-				text = item.Word
-			}
-			if text == "" {
-				continue
-			}
-
-			var x, y, width, height int
-			if item.Right > 0 || item.Bottom > 0 {
-				x = item.Left
-				y = item.Top
-				width = item.Right - item.Left
-				height = item.Bottom - item.Top
-			} else {
-				x = item.X
-				y = item.Y
-				width = item.Width
-				height = item.Height
-			}
-
-			if width > 0 && height > 0 {
-				textBlocks = append(textBlocks, TextBlock{
-					Text:   text,
-					X:      x,
-					Y:      y,
-					Width:  width,
-					Height: height,
-				})
-			}
-		}
-		return textBlocks, nil
-	}
-
-	return nil, fmt.Errorf("无法解析 OCR 结果: %s", resultStr)
+	return ParseWeChatJSON(resultStr)
 }
 
 // GetError 获取错误信息
@@ -670,6 +564,11 @@ func (w *WeChatOCRCGO) GetError() string {
 	return w.errorMsg
 }
 
+// MaxConcurrency wcocr 通过全局 C 状态（g_ocr_result / g_ocr_done）返回结果，同一时刻只能有一个识别
+func (w *WeChatOCRCGO) MaxConcurrency() int {
+	return 1
+}
+
 // Close 关闭
 func (w *WeChatOCRCGO) Close() {
 	if w.initialized {
@@ -3,10 +3,8 @@
 package ocr
 
 import (
-	"encoding/json"
 	"fmt"
 	"image"
-	"image/png"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -22,6 +20,10 @@ type WindowsOCR struct {
 	scriptPath string
 }
 
+func init() {
+	Register("windows-python", func() Engine { return NewWindowsOCR() })
+}
+
 // NewWindowsOCR 创建 Windows OCR 实例
 func NewWindowsOCR() *WindowsOCR {
 	ocr := &WindowsOCR{}
@@ -196,19 +198,12 @@ func (w *WindowsOCR) Recognize(img image.Image, preprocess bool) ([]TextBlock, e
 		img = preprocessImage(img)
 	}
 
-	// 保存临时文件
-	tmpFile, err := os.CreateTemp("", "ocr_*.png")
+	// 保存临时文件（Python 脚本只接受文件路径，使用无压缩 BMP 减少编码耗时）
+	tmpPath, cleanup, err := TempImage(img, TempBMP)
 	if err != nil {
-		return nil, fmt.Errorf("创建临时文件失败: %w", err)
+		return nil, err
 	}
-	tmpPath := tmpFile.Name()
-	defer os.Remove(tmpPath)
-
-	if err := png.Encode(tmpFile, img); err != nil {
-		tmpFile.Close()
-		return nil, fmt.Errorf("保存图片失败: %w", err)
-	}
-	tmpFile.Close()
+	defer cleanup()
 
 	fmt.Printf("[OCR] 临时文件: %s\n", tmpPath)
 
@@ -239,39 +234,7 @@ func (w *WindowsOCR) runPythonOCR(imagePath string) ([]TextBlock, error) {
 		return []TextBlock{}, nil
 	}
 
-	return w.parseResult(outputStr)
-}
-
-// parseResult 解析 JSON 结果
-func (w *WindowsOCR) parseResult(jsonStr string) ([]TextBlock, error) {
-	jsonStr = strings.TrimSpace(jsonStr)
-
-	if jsonStr == "" || jsonStr == "null" || jsonStr == "[]" {
-		fmt.Println("[OCR] 未识别到文字")
-		return []TextBlock{}, nil
-	}
-
-	// 检查错误
-	if strings.HasPrefix(jsonStr, `{"error"`) {
-		var errResp struct {
-			Error string `json:"error"`
-		}
-		if err := json.Unmarshal([]byte(jsonStr), &errResp); err == nil && errResp.Error != "" {
-			return nil, fmt.Errorf("OCR 错误: %s", errResp.Error)
-		}
-	}
-
-	var blocks []TextBlock
-	if err := json.Unmarshal([]byte(jsonStr), &blocks); err != nil {
-		var block TextBlock
-		if err2 := json.Unmarshal([]byte(jsonStr), &block); err2 == nil {
-			return []TextBlock{block}, nil
-		}
-		return nil, fmt.Errorf("解析失败: %w, 原始: %s", err, jsonStr)
-	}
-
-	fmt.Printf("[OCR] 识别成功，共 %d 个文本块\n", len(blocks))
-	return blocks, nil
+	return ParseBlocksJSON(outputStr)
 }
 
 // GetError 获取错误信息
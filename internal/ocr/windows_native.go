@@ -3,14 +3,17 @@
 package ocr
 
 import (
+	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"image"
-	"image/png"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"syscall"
 )
 
@@ -20,6 +23,14 @@ type WindowsOCRNative struct {
 	errorMsg      string
 	scriptPath    string
 	powershellPath string // 缓存 PowerShell 路径，避免重复查找
+	fileOnly      atomic.Bool // 标准输入传图失败过，之后只用临时文件
+}
+
+// errStdinTransport 标准输入传图本身失败（管道写入失败、脚本没有读到完整有效的帧或脚本没有运行），可以改用临时文件重试
+var errStdinTransport = errors.New("标准输入传图失败")
+
+func init() {
+	Register("windows", func() Engine { return NewWindowsOCRNative() })
 }
 
 // NewWindowsOCRNative 创建 Windows OCR 实例（原生版本，使用 PowerShell）
@@ -121,9 +132,11 @@ func (w *WindowsOCRNative) createScript() (string, error) {
 	// 使用 .NET 方式调用 WinRT API
 	// 注意：所有错误消息使用英文，避免编码问题
 	script := `# Windows OCR PowerShell Script
+# -ImagePath <file>: read an image file
+# -Stdin: read a raw frame from stdin (8-byte header: width, height as little endian int32, then BGRA pixels)
 param(
-    [Parameter(Mandatory=$true)]
-    [string]$ImagePath
+    [string]$ImagePath = "",
+    [switch]$Stdin
 )
 
 $ErrorActionPreference = "Stop"
@@ -148,6 +161,10 @@ try {
         $null = [Windows.Graphics.Imaging.BitmapDecoder, Windows.Graphics.Imaging, ContentType = WindowsRuntime]
     } catch { }
     
+    try {
+        $null = [Windows.Graphics.Imaging.SoftwareBitmap, Windows.Graphics.Imaging, ContentType = WindowsRuntime]
+    } catch { }
+    
     try {
         $null = [Windows.Globalization.Language, Windows.Globalization, ContentType = WindowsRuntime]
     } catch { }
@@ -182,13 +199,6 @@ try {
         exit 1
     }
     
-    # Check if file exists
-    if (-not (Test-Path $ImagePath)) {
-        $errorObj = @{error="Image file not found: $ImagePath"}
-        Write-Output (ConvertTo-Json -InputObject $errorObj -Compress)
-        exit 1
-    }
-    
     # Load Windows Runtime System Extensions for async operations
     Add-Type -AssemblyName System.Runtime.WindowsRuntime | Out-Null
     $backtick = [char]96
@@ -201,40 +211,85 @@ try {
         $netTask.Result
     }
     
-    # Load image file
-    $storageFileTask = [Windows.Storage.StorageFile]::GetFileFromPathAsync($ImagePath)
-    $storageFile = Await $storageFileTask ([Windows.Storage.StorageFile])
-    if ($storageFile -eq $null) {
-        $errorObj = @{error="Failed to load image file"}
-        Write-Output (ConvertTo-Json -InputObject $errorObj -Compress)
-        exit 1
-    }
-    
-    $streamTask = $storageFile.OpenAsync([Windows.Storage.FileAccessMode]::Read)
-    $stream = Await $streamTask ([Windows.Storage.Streams.IRandomAccessStream])
-    if ($stream -eq $null) {
-        $errorObj = @{error="Failed to open image file stream"}
-        Write-Output (ConvertTo-Json -InputObject $errorObj -Compress)
-        exit 1
-    }
-    
-    # Decode image
-    $decoderTask = [Windows.Graphics.Imaging.BitmapDecoder]::CreateAsync($stream)
-    $decoder = Await $decoderTask ([Windows.Graphics.Imaging.BitmapDecoder])
-    if ($decoder -eq $null) {
-        $errorObj = @{error="Failed to create image decoder"}
-        Write-Output (ConvertTo-Json -InputObject $errorObj -Compress)
-        exit 1
-    }
-    
-    $bitmapTask = $decoder.GetSoftwareBitmapAsync()
-    $bitmap = Await $bitmapTask ([Windows.Graphics.Imaging.SoftwareBitmap])
-    if ($bitmap -eq $null) {
-        $errorObj = @{error="Failed to decode image"}
-        Write-Output (ConvertTo-Json -InputObject $errorObj -Compress)
-        exit 1
+    if ($Stdin) {
+        # Read raw frame from stdin, no file is written
+        Function Read-Exact($Stream, [int]$Count) {
+            $buffer = New-Object byte[] $Count
+            $offset = 0
+            while ($offset -lt $Count) {
+                $n = $Stream.Read($buffer, $offset, $Count - $offset)
+                if ($n -le 0) {
+                    throw "Unexpected end of image data"
+                }
+                $offset += $n
+            }
+            return ,$buffer
+        }
+        
+        # Errors while reading the frame are marked with stdin=true so the caller can retry with a file
+        try {
+            $inStream = [Console]::OpenStandardInput()
+            $header = Read-Exact $inStream 8
+            $width = [BitConverter]::ToInt32($header, 0)
+            $height = [BitConverter]::ToInt32($header, 4)
+            if ($width -le 0 -or $height -le 0 -or $width -gt 65536 -or $height -gt 65536) {
+                throw "Invalid image size in header: $width x $height"
+            }
+            $pixels = Read-Exact $inStream ($width * $height * 4)
+            
+            $buffer = [System.Runtime.InteropServices.WindowsRuntime.WindowsRuntimeBufferExtensions]::AsBuffer($pixels)
+            $bitmap = [Windows.Graphics.Imaging.SoftwareBitmap]::CreateCopyFromBuffer($buffer, [Windows.Graphics.Imaging.BitmapPixelFormat]::Bgra8, $width, $height, [Windows.Graphics.Imaging.BitmapAlphaMode]::Premultiplied)
+            if ($bitmap -eq $null) {
+                throw "Failed to create bitmap from stdin"
+            }
+        } catch {
+            $errorObj = @{error=$_.Exception.Message; stdin=$true}
+            Write-Output (ConvertTo-Json -InputObject $errorObj -Compress)
+            exit 1
+        }
+    } else {
+        # Check if file exists
+        if (-not (Test-Path $ImagePath)) {
+            $errorObj = @{error="Image file not found: $ImagePath"}
+            Write-Output (ConvertTo-Json -InputObject $errorObj -Compress)
+            exit 1
+        }
+        
+        # Load image file
+        $storageFileTask = [Windows.Storage.StorageFile]::GetFileFromPathAsync($ImagePath)
+        $storageFile = Await $storageFileTask ([Windows.Storage.StorageFile])
+        if ($storageFile -eq $null) {
+            $errorObj = @{error="Failed to load image file"}
+            Write-Output (ConvertTo-Json -InputObject $errorObj -Compress)
+            exit 1
+        }
+        
+        $streamTask = $storageFile.OpenAsync([Windows.Storage.FileAccessMode]::Read)
+        $stream = Await $streamTask ([Windows.Storage.Streams.IRandomAccessStream])
+        if ($stream -eq $null) {
+            $errorObj = @{error="Failed to open image file stream"}
+            Write-Output (ConvertTo-Json -InputObject $errorObj -Compress)
+            exit 1
+        }
+        
+        # Decode image
+        $decoderTask = [Windows.Graphics.Imaging.BitmapDecoder]::CreateAsync($stream)
+        $decoder = Await $decoderTask ([Windows.Graphics.Imaging.BitmapDecoder])
+        if ($decoder -eq $null) {
+            $errorObj = @{error="Failed to create image decoder"}
+            Write-Output (ConvertTo-Json -InputObject $errorObj -Compress)
+            exit 1
+        }
+        
+        $bitmapTask = $decoder.GetSoftwareBitmapAsync()
+        $bitmap = Await $bitmapTask ([Windows.Graphics.Imaging.SoftwareBitmap])
+        if ($bitmap -eq $null) {
+            $errorObj = @{error="Failed to decode image"}
+            Write-Output (ConvertTo-Json -InputObject $errorObj -Compress)
+            exit 1
+        }
     }
-    
+        
     # Perform OCR
     $resultTask = $engine.RecognizeAsync($bitmap)
     $result = Await $resultTask ([Windows.Media.Ocr.OcrResult])
@@ -307,26 +362,67 @@ func (w *WindowsOCRNative) Recognize(img image.Image, preprocess bool) ([]TextBl
 		img = preprocessImage(img)
 	}
 
-	// 保存临时文件
-	tmpFile, err := os.CreateTemp("", "ocr_*.png")
-	if err != nil {
-		return nil, fmt.Errorf("创建临时文件失败: %w", err)
+	// 优先通过标准输入传递原始像素，图片不落盘
+	if !w.fileOnly.Load() {
+		blocks, err := w.recognizeStdin(img)
+		if !errors.Is(err, errStdinTransport) {
+			// 成功或识别本身出错（引擎已经拿到图片），换成临时文件也不会有不同的结果
+			return blocks, err
+		}
+
+		// 传图失败时用临时文件重试，成功则说明当前环境不支持管道传图
+		fmt.Printf("⚠ Windows OCR 标准输入传图失败，改用临时文件: %v\n", err)
+		blocks, fileErr := w.recognizeFile(img)
+		if fileErr != nil {
+			return nil, fileErr
+		}
+		w.fileOnly.Store(true)
+		return blocks, nil
 	}
-	tmpPath := tmpFile.Name()
-	defer os.Remove(tmpPath)
 
-	if err := png.Encode(tmpFile, img); err != nil {
-		tmpFile.Close()
-		return nil, fmt.Errorf("保存图片失败: %w", err)
+	return w.recognizeFile(img)
+}
+
+// recognizeStdin 通过标准输入把 BGRA 像素传给 PowerShell 脚本，传图失败时返回的错误包含 errStdinTransport
+func (w *WindowsOCRNative) recognizeStdin(img image.Image) ([]TextBlock, error) {
+	pr, pw := io.Pipe()
+	written := make(chan error, 1)
+	go func() {
+		bw := bufio.NewWriterSize(pw, 1<<20)
+		err := WriteRawFrame(bw, img)
+		if err == nil {
+			err = bw.Flush()
+		}
+		pw.CloseWithError(err)
+		written <- err
+	}()
+
+	blocks, err := w.runPowerShellOCR(pr, "-Stdin")
+	// 脚本没有读完就退出时关闭读端，写入方随即返回错误
+	pr.Close()
+	if writeErr := <-written; writeErr != nil {
+		return nil, fmt.Errorf("%w: %v", errStdinTransport, writeErr)
 	}
-	tmpFile.Close()
+	return blocks, err
+}
 
-	// 调用 PowerShell 脚本
-	return w.runPowerShellOCR(tmpPath)
+// recognizeFile 把图片写入临时文件后调用 PowerShell 脚本
+func (w *WindowsOCRNative) recognizeFile(img image.Image) ([]TextBlock, error) {
+	tmpPath, cleanup, err := TempImage(img, TempBMP)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	absPath, err := filepath.Abs(tmpPath)
+	if err != nil {
+		return nil, fmt.Errorf("获取图片绝对路径失败: %w", err)
+	}
+	return w.runPowerShellOCR(nil, "-ImagePath", absPath)
 }
 
-// runPowerShellOCR 调用 PowerShell OCR
-func (w *WindowsOCRNative) runPowerShellOCR(imagePath string) ([]TextBlock, error) {
+// runPowerShellOCR 调用 PowerShell OCR，args 为脚本参数，stdin 为传给脚本的标准输入（可为 nil）
+func (w *WindowsOCRNative) runPowerShellOCR(stdin io.Reader, args ...string) ([]TextBlock, error) {
 	// 使用缓存的 PowerShell 路径，如果为空则重新查找
 	powershellPath := w.powershellPath
 	if powershellPath == "" {
@@ -337,23 +433,20 @@ func (w *WindowsOCRNative) runPowerShellOCR(imagePath string) ([]TextBlock, erro
 		w.powershellPath = powershellPath // 更新缓存
 	}
 
-	absPath, err := filepath.Abs(imagePath)
-	if err != nil {
-		return nil, fmt.Errorf("获取图片绝对路径失败: %w", err)
-	}
-	
 	// 构建 PowerShell 命令
 	// 使用 -ExecutionPolicy Bypass 避免执行策略限制
 	// 使用 -NoProfile 加快启动速度
 	// 使用 -NonInteractive 避免交互提示
 	cmd := exec.Command(
 		powershellPath,
-		"-NoProfile",
-		"-NonInteractive",
-		"-ExecutionPolicy", "Bypass",
-		"-File", w.scriptPath,
-		"-ImagePath", absPath,
+		append([]string{
+			"-NoProfile",
+			"-NonInteractive",
+			"-ExecutionPolicy", "Bypass",
+			"-File", w.scriptPath,
+		}, args...)...,
 	)
+	cmd.Stdin = stdin
 	
 	// 隐藏控制台窗口
 	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
@@ -366,63 +459,43 @@ func (w *WindowsOCRNative) runPowerShellOCR(imagePath string) ([]TextBlock, erro
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err = cmd.Run()
+	err := cmd.Run()
 	outputStr := strings.TrimSpace(stdout.String())
 	errorStr := strings.TrimSpace(stderr.String())
 
 	if err != nil {
-		// 尝试从输出中获取错误信息
-		if outputStr != "" {
-			// 检查是否是 JSON 错误格式
-			if strings.HasPrefix(outputStr, `{"error"`) {
-				return nil, fmt.Errorf("PowerShell OCR 错误: %s", outputStr)
-			}
-			return nil, fmt.Errorf("PowerShell 执行失败: %s", outputStr)
+		// 脚本捕获的错误为 {"error": ...}，stdin 为 true 表示没有从标准输入读到有效的图片
+		var scriptErr struct {
+			Error string `json:"error"`
+			Stdin bool   `json:"stdin"`
 		}
-		// 如果有 stderr，包含在错误信息中
-		if errorStr != "" {
-			return nil, fmt.Errorf("PowerShell 执行失败 (stderr: %s): %w", errorStr, err)
+		if json.Unmarshal([]byte(outputStr), &scriptErr) == nil && scriptErr.Error != "" {
+			if scriptErr.Stdin {
+				return nil, fmt.Errorf("%w: %s", errStdinTransport, scriptErr.Error)
+			}
+			return nil, fmt.Errorf("PowerShell OCR 错误: %s", scriptErr.Error)
 		}
-		return nil, fmt.Errorf("PowerShell 执行失败: %w", err)
-	}
-
-	if outputStr == "" {
-		return []TextBlock{}, nil
-	}
-
-	return w.parseResult(outputStr)
-}
 
-// parseResult 解析 JSON 结果
-func (w *WindowsOCRNative) parseResult(jsonStr string) ([]TextBlock, error) {
-	jsonStr = strings.TrimSpace(jsonStr)
-
-	if jsonStr == "" || jsonStr == "null" || jsonStr == "[]" {
-		fmt.Println("[OCR] 未识别到文字")
-		return []TextBlock{}, nil
-	}
-
-	// 检查错误
-	if strings.HasPrefix(jsonStr, `{"error"`) {
-		var errResp struct {
-			Error string `json:"error"`
+		// 脚本没有输出错误信息，说明没有运行到读取图片（如不接受 -Stdin 参数），标准输入方式按传图失败处理
+		if outputStr != "" {
+			err = fmt.Errorf("PowerShell 执行失败: %s", outputStr)
+		} else if errorStr != "" {
+			// 如果有 stderr，包含在错误信息中
+			err = fmt.Errorf("PowerShell 执行失败 (stderr: %s): %w", errorStr, err)
+		} else {
+			err = fmt.Errorf("PowerShell 执行失败: %w", err)
 		}
-		if err := json.Unmarshal([]byte(jsonStr), &errResp); err == nil && errResp.Error != "" {
-			return nil, fmt.Errorf("OCR 错误: %s", errResp.Error)
+		if stdin != nil {
+			return nil, fmt.Errorf("%w: %w", errStdinTransport, err)
 		}
+		return nil, err
 	}
 
-	var blocks []TextBlock
-	if err := json.Unmarshal([]byte(jsonStr), &blocks); err != nil {
-		var block TextBlock
-		if err2 := json.Unmarshal([]byte(jsonStr), &block); err2 == nil {
-			return []TextBlock{block}, nil
-		}
-		return nil, fmt.Errorf("解析失败: %w, 原始: %s", err, jsonStr)
+	if outputStr == "" {
+		return []TextBlock{}, nil
 	}
 
-	fmt.Printf("[OCR] 识别成功，共 %d 个文本块\n", len(blocks))
-	return blocks, nil
+	return ParseBlocksJSON(outputStr)
 }
 
 // GetError 获取错误信息
@@ -0,0 +1,166 @@
+package ocrformat
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// ALTO v4 文档结构（只包含导出用到的元素）
+type altoDocument struct {
+	XMLName        xml.Name        `xml:"alto"`
+	Xmlns          string          `xml:"xmlns,attr"`
+	XmlnsXsi       string          `xml:"xmlns:xsi,attr"`
+	SchemaLocation string          `xml:"xsi:schemaLocation,attr"`
+	Description    altoDescription `xml:"Description"`
+	Pages          []altoPage      `xml:"Layout>Page"`
+}
+
+type altoDescription struct {
+	MeasurementUnit string `xml:"MeasurementUnit"`
+	FileName        string `xml:"sourceImageInformation>fileName,omitempty"`
+	Source          string `xml:"sourceImageInformation>documentIdentifier,omitempty"`
+	Software        string `xml:"OCRProcessing>ocrProcessingStep>processingSoftware>softwareName"`
+}
+
+type altoPage struct {
+	ID         string         `xml:"ID,attr"`
+	ImageNr    int            `xml:"PHYSICAL_IMG_NR,attr"`
+	Width      int            `xml:"WIDTH,attr"`
+	Height     int            `xml:"HEIGHT,attr"`
+	PrintSpace altoPrintSpace `xml:"PrintSpace"`
+}
+
+type altoPrintSpace struct {
+	HPos   int             `xml:"HPOS,attr"`
+	VPos   int             `xml:"VPOS,attr"`
+	Width  int             `xml:"WIDTH,attr"`
+	Height int             `xml:"HEIGHT,attr"`
+	Blocks []altoTextBlock `xml:"TextBlock"`
+}
+
+type altoTextBlock struct {
+	ID     string         `xml:"ID,attr"`
+	HPos   int            `xml:"HPOS,attr"`
+	VPos   int            `xml:"VPOS,attr"`
+	Width  int            `xml:"WIDTH,attr"`
+	Height int            `xml:"HEIGHT,attr"`
+	Lines  []altoTextLine `xml:"TextLine"`
+}
+
+type altoTextLine struct {
+	ID     string `xml:"ID,attr"`
+	HPos   int    `xml:"HPOS,attr"`
+	VPos   int    `xml:"VPOS,attr"`
+	Width  int    `xml:"WIDTH,attr"`
+	Height int    `xml:"HEIGHT,attr"`
+	Items  []any  // altoString 与 altoSP 交替出现
+}
+
+type altoString struct {
+	XMLName xml.Name `xml:"String"`
+	ID      string   `xml:"ID,attr"`
+	HPos    int      `xml:"HPOS,attr"`
+	VPos    int      `xml:"VPOS,attr"`
+	Width   int      `xml:"WIDTH,attr"`
+	Height  int      `xml:"HEIGHT,attr"`
+	Content string   `xml:"CONTENT,attr"`
+	WC      string   `xml:"WC,attr,omitempty"`
+}
+
+type altoSP struct {
+	XMLName xml.Name `xml:"SP"`
+}
+
+// WriteALTO 把识别结果写为 ALTO v4 XML
+// 层级: Page → PrintSpace → TextBlock（段落）→ TextLine → String，置信度写入 WC（0-1）
+func WriteALTO(w io.Writer, pages ...Page) error {
+	doc := altoDocument{
+		Xmlns:          "http://www.loc.gov/standards/alto/ns-v4#",
+		XmlnsXsi:       "http://www.w3.org/2001/XMLSchema-instance",
+		SchemaLocation: "http://www.loc.gov/standards/alto/ns-v4# http://www.loc.gov/alto/v4/alto-4-2.xsd",
+		Description: altoDescription{
+			MeasurementUnit: "pixel",
+			Software:        Creator,
+		},
+	}
+
+	for i, page := range pages {
+		n := i + 1
+		if doc.Description.FileName == "" {
+			doc.Description.FileName = page.Image
+		}
+		if doc.Description.Source == "" {
+			doc.Description.Source = page.Source
+		}
+		width, height := page.size()
+		ap := altoPage{
+			ID:      fmt.Sprintf("P%d", n),
+			ImageNr: n,
+			Width:   width,
+			Height:  height,
+			PrintSpace: altoPrintSpace{
+				Width:  width,
+				Height: height,
+			},
+		}
+
+		line, word := 0, 0
+		for j, par := range page.paragraphs() {
+			tb := altoTextBlock{
+				ID:     fmt.Sprintf("P%d_TB%d", n, j+1),
+				HPos:   par.X,
+				VPos:   par.Y,
+				Width:  par.Width,
+				Height: par.Height,
+			}
+			for _, l := range par.Lines {
+				line++
+				tl := altoTextLine{
+					ID:     fmt.Sprintf("P%d_TL%d", n, line),
+					HPos:   l.X,
+					VPos:   l.Y,
+					Width:  l.Width,
+					Height: l.Height,
+				}
+				for k, blk := range l.Blocks {
+					word++
+					if k > 0 {
+						tl.Items = append(tl.Items, altoSP{})
+					}
+					s := altoString{
+						ID:      fmt.Sprintf("P%d_ST%d", n, word),
+						HPos:    blk.X,
+						VPos:    blk.Y,
+						Width:   blk.Width,
+						Height:  blk.Height,
+						Content: blk.Text,
+					}
+					if blk.Confidence > 0 {
+						s.WC = formatConfidence(blk.Confidence)
+					}
+					tl.Items = append(tl.Items, s)
+				}
+				tb.Lines = append(tb.Lines, tl)
+			}
+			ap.PrintSpace.Blocks = append(ap.PrintSpace.Blocks, tb)
+		}
+		doc.Pages = append(doc.Pages, ap)
+	}
+
+	return writeXML(w, doc)
+}
+
+// writeXML 输出带 XML 声明的缩进文档
+func writeXML(w io.Writer, doc any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("生成 XML 失败: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,269 @@
+package ocrformat
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"screenocr-wails/internal/ocr"
+)
+
+// WriteHOCR 把识别结果写为 hOCR 1.2（XHTML）
+// 层级: ocr_page → ocr_carea → ocr_par → ocr_line → ocrx_word，置信度写入 x_wconf（0-100）
+func WriteHOCR(w io.Writer, pages ...Page) error {
+	bw := bufio.NewWriter(w)
+
+	// 文档标题使用第一个有来源的页面
+	source := ""
+	for _, page := range pages {
+		if page.Source != "" {
+			source = page.Source
+			break
+		}
+	}
+
+	bw.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Transitional//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-transitional.dtd">
+<html xmlns="http://www.w3.org/1999/xhtml" xml:lang="en" lang="en">
+<head>
+  <title>` + html.EscapeString(source) + `</title>
+  <meta http-equiv="Content-Type" content="text/html;charset=utf-8"/>
+  <meta name="ocr-system" content="` + Creator + `"/>
+  <meta name="ocr-capabilities" content="ocr_page ocr_carea ocr_par ocr_line ocrx_word ocrp_wconf"/>
+</head>
+<body>
+`)
+
+	for i, page := range pages {
+		n := i + 1
+		width, height := page.size()
+		title := bbox(0, 0, width, height) + "; ppageno " + strconv.Itoa(i)
+		if page.Image != "" {
+			title = "image " + strconv.Quote(page.Image) + "; " + title
+		}
+		fmt.Fprintf(bw, "  <div class=\"ocr_page\" id=\"page_%d\" title=\"%s\">\n", n, html.EscapeString(title))
+
+		line, word := 0, 0
+		for j, par := range page.paragraphs() {
+			b := bbox(par.X, par.Y, par.Width, par.Height)
+			fmt.Fprintf(bw, "   <div class=\"ocr_carea\" id=\"block_%d_%d\" title=\"%s\">\n", n, j+1, b)
+			fmt.Fprintf(bw, "    <p class=\"ocr_par\" id=\"par_%d_%d\" title=\"%s\">\n", n, j+1, b)
+			for _, l := range par.Lines {
+				line++
+				fmt.Fprintf(bw, "     <span class=\"ocr_line\" id=\"line_%d_%d\" title=\"%s\">",
+					n, line, bbox(l.X, l.Y, l.Width, l.Height))
+				for k, blk := range l.Blocks {
+					word++
+					if k > 0 {
+						bw.WriteByte(' ')
+					}
+					title := bbox(blk.X, blk.Y, blk.Width, blk.Height)
+					if blk.Confidence > 0 {
+						title += fmt.Sprintf("; x_wconf %d", int(math.Round(blk.Confidence*100)))
+					}
+					fmt.Fprintf(bw, "<span class=\"ocrx_word\" id=\"word_%d_%d\" title=\"%s\">%s</span>",
+						n, word, title, html.EscapeString(blk.Text))
+				}
+				bw.WriteString("</span>\n")
+			}
+			bw.WriteString("    </p>\n   </div>\n")
+		}
+		bw.WriteString("  </div>\n")
+	}
+
+	bw.WriteString("</body>\n</html>\n")
+	return bw.Flush()
+}
+
+// hocrFrame 解析过程中正在读取的 ocr_line / ocrx_word 元素
+type hocrFrame struct {
+	name    string
+	class   string
+	title   hocrTitle
+	text    strings.Builder
+	hasWord bool
+}
+
+// ParseHOCR 解析 hOCR 文档，每个 ocrx_word 转换为一个文本块
+// 没有词级信息的 ocr_line（或 ocrx_line 等行类型）整行作为一个文本块；
+// 解析不要求严格的 XHTML，Tesseract 等引擎的输出可直接导入
+func ParseHOCR(r io.Reader) ([]Page, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	var pages []Page
+	var stack []*hocrFrame
+
+	currentPage := func() *Page {
+		if len(pages) == 0 {
+			pages = append(pages, Page{})
+		}
+		return &pages[len(pages)-1]
+	}
+	addBlock := func(f *hocrFrame) {
+		text := strings.Join(strings.Fields(f.text.String()), " ")
+		x0, y0, x1, y1, ok := f.title.bbox()
+		if text == "" || !ok || x1 <= x0 || y1 <= y0 {
+			return
+		}
+		page := currentPage()
+		page.Blocks = append(page.Blocks, ocr.TextBlock{
+			Text:       text,
+			X:          x0,
+			Y:          y0,
+			Width:      x1 - x0,
+			Height:     y1 - y0,
+			Confidence: f.title.confidence(),
+		})
+	}
+
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("解析 hOCR 失败: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			class, title := "", ""
+			for _, attr := range t.Attr {
+				switch attr.Name.Local {
+				case "class":
+					class = attr.Value
+				case "title":
+					title = attr.Value
+				}
+			}
+			frame := &hocrFrame{name: t.Name.Local, title: parseHOCRTitle(title)}
+			switch {
+			case hasClass(class, "ocr_page"):
+				page := Page{Image: frame.title.image()}
+				if _, _, x1, y1, ok := frame.title.bbox(); ok {
+					page.Width, page.Height = x1, y1
+				}
+				pages = append(pages, page)
+			case hasClass(class, "ocrx_word"):
+				frame.class = "word"
+			case isLineClass(class):
+				frame.class = "line"
+			}
+			stack = append(stack, frame)
+
+		case xml.CharData:
+			for _, f := range stack {
+				if f.class != "" {
+					f.text.Write(t)
+				}
+			}
+
+		case xml.EndElement:
+			// 非严格模式下结束标签可能不匹配，向上找到同名元素
+			i := len(stack) - 1
+			for i >= 0 && stack[i].name != t.Name.Local {
+				i--
+			}
+			if i < 0 {
+				continue
+			}
+			for len(stack) > i {
+				f := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				switch f.class {
+				case "word":
+					addBlock(f)
+					for _, parent := range stack {
+						parent.hasWord = true
+					}
+				case "line":
+					if !f.hasWord {
+						addBlock(f)
+					}
+				}
+			}
+		}
+	}
+
+	return pages, nil
+}
+
+// hasClass 检查 class 属性是否包含指定类名
+func hasClass(class, name string) bool {
+	for _, c := range strings.Fields(class) {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isLineClass 检查是否是 hOCR 行类型元素
+func isLineClass(class string) bool {
+	for _, c := range strings.Fields(class) {
+		switch c {
+		case "ocr_line", "ocrx_line", "ocr_textfloat", "ocr_caption", "ocr_header":
+			return true
+		}
+	}
+	return false
+}
+
+// hocrTitle hOCR title 属性，形如 `image "a.png"; bbox 0 0 100 20; x_wconf 95`
+type hocrTitle map[string]string
+
+// parseHOCRTitle 解析 title 属性为 属性名 → 属性值
+func parseHOCRTitle(title string) hocrTitle {
+	props := hocrTitle{}
+	for _, part := range strings.Split(title, ";") {
+		part = strings.TrimSpace(part)
+		name, value, _ := strings.Cut(part, " ")
+		if name != "" {
+			props[name] = strings.TrimSpace(value)
+		}
+	}
+	return props
+}
+
+// bbox 返回外接矩形 x0 y0 x1 y1
+func (t hocrTitle) bbox() (x0, y0, x1, y1 int, ok bool) {
+	fields := strings.Fields(t["bbox"])
+	if len(fields) != 4 {
+		return 0, 0, 0, 0, false
+	}
+	var v [4]int
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return 0, 0, 0, 0, false
+		}
+		v[i] = n
+	}
+	return v[0], v[1], v[2], v[3], true
+}
+
+// confidence 返回 0-1 的置信度（x_wconf 为 0-100）
+func (t hocrTitle) confidence() float64 {
+	conf, err := strconv.ParseFloat(t["x_wconf"], 64)
+	if err != nil || conf <= 0 {
+		return 0
+	}
+	return math.Min(conf, 100) / 100
+}
+
+// image 返回 image 属性（去掉引号）
+func (t hocrTitle) image() string {
+	value := t["image"]
+	if s, err := strconv.Unquote(value); err == nil {
+		return s
+	}
+	return strings.Trim(value, `"'`)
+}
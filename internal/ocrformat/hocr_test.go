@@ -0,0 +1,181 @@
+package ocrformat
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"screenocr-wails/internal/ocr"
+)
+
+func TestHOCRRoundTrip(t *testing.T) {
+	pages := []Page{
+		{
+			Image:  `shots\2024 "final".png`,
+			Source: `chrome.exe — <新标签页> & "设置"`,
+			Width:  640,
+			Height: 200,
+			Blocks: []ocr.TextBlock{
+				{Text: "if a < b && c > 'd'", X: 10, Y: 10, Width: 200, Height: 20, Confidence: 0.87},
+				{Text: `"quoted"`, X: 220, Y: 12, Width: 80, Height: 18},
+				{Text: "识别结果", X: 10, Y: 40, Width: 90, Height: 20, Confidence: 1},
+			},
+		},
+		// 没有设置尺寸时取文本块的外接范围
+		{
+			Blocks: []ocr.TextBlock{
+				{Text: "second", X: 5, Y: 6, Width: 40, Height: 12, Confidence: 0.5},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteHOCR(&buf, pages...); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParseHOCR(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Page{pages[0], {Width: 45, Height: 18, Blocks: pages[1].Blocks}}
+	want[0].Source = "" // 来源写在文档标题中，不随页面导入
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("导入结果与导出前不一致\n得到 %+v\n期望 %+v", got, want)
+	}
+}
+
+// TestHOCRConfidence x_wconf 为 0-100 的整数，导入后为 0-1
+func TestHOCRConfidence(t *testing.T) {
+	cases := []struct {
+		name  string
+		conf  float64
+		title string
+		want  float64
+	}{
+		{"rounded", 0.876, "x_wconf 88", 0.88},
+		{"full", 1, "x_wconf 100", 1},
+		{"tiny", 0.004, "x_wconf 0", 0},
+		{"absent", 0, "", 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			page := Page{Blocks: []ocr.TextBlock{{Text: "w", Width: 10, Height: 10, Confidence: c.conf}}}
+			if err := WriteHOCR(&buf, page); err != nil {
+				t.Fatal(err)
+			}
+			if c.title != "" && !strings.Contains(buf.String(), c.title) {
+				t.Errorf("输出中没有 %q:\n%s", c.title, buf.String())
+			}
+			if c.title == "" && strings.Contains(buf.String(), "x_wconf") {
+				t.Errorf("没有置信度时不应输出 x_wconf:\n%s", buf.String())
+			}
+			pages, err := ParseHOCR(&buf)
+			if err != nil || len(pages) != 1 || len(pages[0].Blocks) != 1 {
+				t.Fatalf("ParseHOCR = %+v, %v", pages, err)
+			}
+			if got := pages[0].Blocks[0].Confidence; got != c.want {
+				t.Errorf("置信度 %v，期望 %v", got, c.want)
+			}
+		})
+	}
+}
+
+// TestParseHOCRTesseract 导入 tesseract invoice.png out hocr 的输出
+func TestParseHOCRTesseract(t *testing.T) {
+	f, err := os.Open("testdata/tesseract.hocr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	pages, err := ParseHOCR(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Page{{
+		// Tesseract 不转义路径中的反斜杠
+		Image:  `C:\Users\demo\invoice 01.png`,
+		Width:  800,
+		Height: 240,
+		Blocks: []ocr.TextBlock{
+			{Text: "Invoice", X: 40, Y: 20, Width: 150, Height: 32, Confidence: 0.96},
+			{Text: "#2024-07", X: 204, Y: 21, Width: 126, Height: 31, Confidence: 0.93},
+			{Text: "Tom", X: 40, Y: 80, Width: 82, Height: 26, Confidence: 0.91},
+			{Text: "&", X: 134, Y: 80, Width: 26, Height: 26, Confidence: 0.88},
+			{Text: "Jerry's", X: 172, Y: 80, Width: 88, Height: 26, Confidence: 0.9},
+			{Text: "<Ltd>", X: 272, Y: 81, Width: 248, Height: 25, Confidence: 0.57},
+			{Text: "Total:", X: 40, Y: 124, Width: 110, Height: 26, Confidence: 0.95},
+			{Text: "€1,280.00", X: 164, Y: 124, Width: 136, Height: 26, Confidence: 1},
+			// 空白的词（宽度为 0）被跳过
+		},
+	}}
+	if !reflect.DeepEqual(pages, want) {
+		t.Errorf("导入结果\n得到 %+v\n期望 %+v", pages, want)
+	}
+	if text := ocr.JoinText(pages[0].Blocks); text != "Invoice #2024-07\nTom & Jerry's <Ltd>\nTotal: €1,280.00" {
+		t.Errorf("文本 %q", text)
+	}
+}
+
+func TestParseHOCR(t *testing.T) {
+	cases := []struct {
+		name string
+		doc  string
+		want []Page
+	}{
+		{
+			name: "single-quoted",
+			doc: `<div class='ocr_page' title='image &#39;a.png&#39;; bbox 0 0 100 50'>
+<span class='ocrx_word' title='bbox 1 2 30 12; x_wconf 75'>hello</span></div>`,
+			want: []Page{{Image: "a.png", Width: 100, Height: 50, Blocks: []ocr.TextBlock{
+				{Text: "hello", X: 1, Y: 2, Width: 29, Height: 10, Confidence: 0.75},
+			}}},
+		},
+		{
+			// 没有词级信息的行整行作为一个文本块，空白合并为一个空格
+			name: "line-without-words",
+			doc: `<div class="ocr_page" title="bbox 0 0 200 40"><span class="ocr_line" title="bbox 0 0 120 20">
+  first   line </span><span class="ocrx_line" title="bbox 0 20 80 40">second</span></div>`,
+			want: []Page{{Width: 200, Height: 40, Blocks: []ocr.TextBlock{
+				{Text: "first line", Width: 120, Height: 20},
+				{Text: "second", Y: 20, Width: 80, Height: 20},
+			}}},
+		},
+		{
+			// 非 XHTML：没有引号的属性、未闭合的 <br> 与 <p>、HTML 实体
+			name: "html",
+			doc: `<html><body><div class=ocr_page title="bbox 0 0 50 50"><p>
+<span class="ocr_line" title="bbox 0 0 50 10">a&nbsp;&mdash;<br>b</span></div></body></html>`,
+			want: []Page{{Width: 50, Height: 50, Blocks: []ocr.TextBlock{
+				{Text: "a —b", Width: 50, Height: 10}, // &nbsp; 同样按空白合并
+			}}},
+		},
+		{
+			// bbox 缺失、无效或为空的文本块被跳过；x_wconf 超过 100 按 100 计
+			name: "invalid-bbox",
+			doc: `<span class="ocrx_word">no bbox</span>
+<span class="ocrx_word" title="bbox 1 2 x 4">bad</span>
+<span class="ocrx_word" title="bbox 10 10 5 20">inverted</span>
+<span class="ocrx_word" title="bbox 0 0 8 8; x_wconf 120">ok</span>`,
+			want: []Page{{Blocks: []ocr.TextBlock{
+				{Text: "ok", Width: 8, Height: 8, Confidence: 1},
+			}}},
+		},
+		{name: "empty", doc: `<html><body></body></html>`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := ParseHOCR(strings.NewReader(c.doc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("得到 %+v\n期望 %+v", got, c.want)
+			}
+		})
+	}
+}
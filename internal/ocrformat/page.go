@@ -0,0 +1,59 @@
+// Package ocrformat 把 OCR 结果导出为文档处理工具通用的格式（hOCR、ALTO XML、PAGE XML），
+// 并能把外部引擎输出的 hOCR 导入为 ocr.TextBlock
+//
+// 导出时按 ocr.GroupLines / ocr.GroupParagraphs 重建 页 → 段落 → 行 → 词 的层级，
+// 每个 TextBlock 对应一个词；坐标单位为像素，置信度为 0 时视为引擎未提供而省略。
+package ocrformat
+
+import (
+	"fmt"
+	"strconv"
+
+	"screenocr-wails/internal/ocr"
+)
+
+// Creator 写入文档元数据的软件名称
+const Creator = "ScreenOCR"
+
+// Page 一页识别结果
+type Page struct {
+	Image  string          // 图片文件名（可为空）
+	Source string          // 截图来源，如窗口截图的 "chrome.exe — 新标签页"（可为空）
+	Width  int             // 图片宽度，为 0 时取文本块的外接范围
+	Height int             // 图片高度，为 0 时取文本块的外接范围
+	Blocks []ocr.TextBlock // 文本块，坐标相对于图片左上角
+}
+
+// size 返回页面尺寸，未设置时使用文本块的外接范围
+func (p Page) size() (int, int) {
+	width, height := p.Width, p.Height
+	if width > 0 && height > 0 {
+		return width, height
+	}
+	for _, b := range p.Blocks {
+		width = max(width, b.X+b.Width)
+		height = max(height, b.Y+b.Height)
+	}
+	return width, height
+}
+
+// paragraphs 按阅读顺序返回页面的段落
+func (p Page) paragraphs() []ocr.Paragraph {
+	return ocr.GroupParagraphs(ocr.GroupLines(p.Blocks))
+}
+
+// formatConfidence 格式化 0-1 的置信度
+func formatConfidence(c float64) string {
+	return strconv.FormatFloat(c, 'f', -1, 64)
+}
+
+// bbox 格式化 hOCR 外接矩形 "bbox x0 y0 x1 y1"
+func bbox(x, y, width, height int) string {
+	return fmt.Sprintf("bbox %d %d %d %d", x, y, x+width, y+height)
+}
+
+// points 格式化 PAGE XML 多边形坐标（矩形的四个顶点，顺时针）
+func points(x, y, width, height int) string {
+	x1, y1 := x+width, y+height
+	return fmt.Sprintf("%d,%d %d,%d %d,%d %d,%d", x, y, x1, y, x1, y1, x, y1)
+}
@@ -0,0 +1,141 @@
+package ocrformat
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// PAGE XML 2019-07-15 文档结构（只包含导出用到的元素）
+type pcGts struct {
+	XMLName        xml.Name     `xml:"PcGts"`
+	Xmlns          string       `xml:"xmlns,attr"`
+	XmlnsXsi       string       `xml:"xmlns:xsi,attr"`
+	SchemaLocation string       `xml:"xsi:schemaLocation,attr"`
+	Metadata       pageMetadata `xml:"Metadata"`
+	Page           pagePage     `xml:"Page"`
+}
+
+type pageMetadata struct {
+	Creator    string `xml:"Creator"`
+	Created    string `xml:"Created"`
+	LastChange string `xml:"LastChange"`
+	Comments   string `xml:"Comments,omitempty"` // 截图来源
+}
+
+type pagePage struct {
+	ImageFilename string            `xml:"imageFilename,attr"`
+	ImageWidth    int               `xml:"imageWidth,attr"`
+	ImageHeight   int               `xml:"imageHeight,attr"`
+	ReadingOrder  *pageReadingOrder `xml:"ReadingOrder,omitempty"`
+	Regions       []pageTextRegion  `xml:"TextRegion"`
+}
+
+type pageReadingOrder struct {
+	Group pageOrderedGroup `xml:"OrderedGroup"`
+}
+
+type pageOrderedGroup struct {
+	ID   string          `xml:"id,attr"`
+	Refs []pageRegionRef `xml:"RegionRefIndexed"`
+}
+
+type pageRegionRef struct {
+	Index     int    `xml:"index,attr"`
+	RegionRef string `xml:"regionRef,attr"`
+}
+
+type pageCoords struct {
+	Points string `xml:"points,attr"`
+}
+
+type pageTextEquiv struct {
+	Conf    string `xml:"conf,attr,omitempty"`
+	Unicode string `xml:"Unicode"`
+}
+
+type pageTextRegion struct {
+	ID        string         `xml:"id,attr"`
+	Coords    pageCoords     `xml:"Coords"`
+	Lines     []pageTextLine `xml:"TextLine"`
+	TextEquiv pageTextEquiv  `xml:"TextEquiv"`
+}
+
+type pageTextLine struct {
+	ID        string        `xml:"id,attr"`
+	Coords    pageCoords    `xml:"Coords"`
+	Words     []pageWord    `xml:"Word"`
+	TextEquiv pageTextEquiv `xml:"TextEquiv"`
+}
+
+type pageWord struct {
+	ID        string        `xml:"id,attr"`
+	Coords    pageCoords    `xml:"Coords"`
+	TextEquiv pageTextEquiv `xml:"TextEquiv"`
+}
+
+// WritePAGE 把一页识别结果写为 PAGE XML（2019-07-15）
+// 层级: Page → TextRegion（段落）→ TextLine → Word，段落的阅读顺序写入 ReadingOrder
+func WritePAGE(w io.Writer, page Page) error {
+	now := time.Now().UTC().Format("2006-01-02T15:04:05")
+	width, height := page.size()
+
+	doc := pcGts{
+		Xmlns:          "http://schema.primaresearch.org/PAGE/gts/pagecontent/2019-07-15",
+		XmlnsXsi:       "http://www.w3.org/2001/XMLSchema-instance",
+		SchemaLocation: "http://schema.primaresearch.org/PAGE/gts/pagecontent/2019-07-15 http://schema.primaresearch.org/PAGE/gts/pagecontent/2019-07-15/pagecontent.xsd",
+		Metadata: pageMetadata{
+			Creator:    Creator,
+			Created:    now,
+			LastChange: now,
+			Comments:   page.Source,
+		},
+		Page: pagePage{
+			ImageFilename: page.Image,
+			ImageWidth:    width,
+			ImageHeight:   height,
+		},
+	}
+
+	paragraphs := page.paragraphs()
+	if len(paragraphs) > 0 {
+		doc.Page.ReadingOrder = &pageReadingOrder{Group: pageOrderedGroup{ID: "ro_1"}}
+	}
+
+	for i, par := range paragraphs {
+		regionID := fmt.Sprintf("r%d", i+1)
+		region := pageTextRegion{
+			ID:     regionID,
+			Coords: pageCoords{Points: points(par.X, par.Y, par.Width, par.Height)},
+		}
+		for j, l := range par.Lines {
+			lineID := fmt.Sprintf("%s_l%d", regionID, j+1)
+			line := pageTextLine{
+				ID:        lineID,
+				Coords:    pageCoords{Points: points(l.X, l.Y, l.Width, l.Height)},
+				TextEquiv: pageTextEquiv{Unicode: l.Text()},
+			}
+			for k, blk := range l.Blocks {
+				word := pageWord{
+					ID:        fmt.Sprintf("%s_w%d", lineID, k+1),
+					Coords:    pageCoords{Points: points(blk.X, blk.Y, blk.Width, blk.Height)},
+					TextEquiv: pageTextEquiv{Unicode: blk.Text},
+				}
+				if blk.Confidence > 0 {
+					word.TextEquiv.Conf = formatConfidence(blk.Confidence)
+				}
+				line.Words = append(line.Words, word)
+			}
+			if region.TextEquiv.Unicode != "" {
+				region.TextEquiv.Unicode += "\n"
+			}
+			region.TextEquiv.Unicode += line.TextEquiv.Unicode
+			region.Lines = append(region.Lines, line)
+		}
+		doc.Page.Regions = append(doc.Page.Regions, region)
+		doc.Page.ReadingOrder.Group.Refs = append(doc.Page.ReadingOrder.Group.Refs, pageRegionRef{Index: i, RegionRef: regionID})
+	}
+
+	return writeXML(w, doc)
+}
@@ -0,0 +1,64 @@
+package ocrformat
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"screenocr-wails/internal/ocr"
+)
+
+// Formats 支持的文本输出格式
+var Formats = []string{"text", "json", "tsv", "hocr", "alto", "page"}
+
+// Extension 返回输出格式对应的文件扩展名（含点号）
+func Extension(format string) string {
+	switch format {
+	case "text":
+		return ".txt"
+	case "alto":
+		return ".alto.xml"
+	case "page":
+		return ".page.xml"
+	default:
+		return "." + format
+	}
+}
+
+// Write 按指定格式输出一页识别结果
+func Write(w io.Writer, page Page, format string) error {
+	blocks := page.Blocks
+	switch format {
+	case "text":
+		text := ocr.JoinText(blocks)
+		if text != "" {
+			text += "\n"
+		}
+		_, err := io.WriteString(w, text)
+		return err
+	case "json":
+		if blocks == nil {
+			blocks = []ocr.TextBlock{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(blocks)
+	case "tsv":
+		fmt.Fprintln(w, "text\tx\ty\twidth\theight")
+		for _, b := range blocks {
+			text := strings.NewReplacer("\t", " ", "\n", " ").Replace(b.Text)
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\n", text, b.X, b.Y, b.Width, b.Height)
+		}
+		return nil
+	case "hocr":
+		return WriteHOCR(w, page)
+	case "alto":
+		return WriteALTO(w, page)
+	case "page":
+		return WritePAGE(w, page)
+	default:
+		return fmt.Errorf("不支持的输出格式: %s", format)
+	}
+}
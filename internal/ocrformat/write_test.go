@@ -0,0 +1,153 @@
+package ocrformat
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"screenocr-wails/internal/ocr"
+)
+
+var update = flag.Bool("update", false, "更新 testdata 下的期望输出")
+
+// samplePage 两个段落：带置信度与需要转义字符的英文行，以及没有置信度的中文两行
+var samplePage = Page{
+	Image:  "shot.png",
+	Source: "notepad.exe — a&b.txt",
+	Width:  400,
+	Height: 160,
+	Blocks: []ocr.TextBlock{
+		{Text: "Tom", X: 10, Y: 10, Width: 40, Height: 20, Confidence: 0.98},
+		{Text: "&", X: 56, Y: 10, Width: 12, Height: 20, Confidence: 0.5},
+		{Text: `"Jerry"<br>`, X: 74, Y: 11, Width: 90, Height: 19, Confidence: 0.875},
+		{Text: "识别", X: 10, Y: 80, Width: 40, Height: 20},
+		{Text: "结果", X: 52, Y: 80, Width: 40, Height: 20},
+		{Text: "第二行", X: 10, Y: 104, Width: 60, Height: 20},
+	},
+}
+
+// pageTimestamp PAGE XML 中随导出时间变化的元数据
+var pageTimestamp = regexp.MustCompile(`<(Created|LastChange)>[^<]*</`)
+
+func TestWriteGolden(t *testing.T) {
+	for _, format := range []string{"hocr", "alto", "page"} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, samplePage, format); err != nil {
+				t.Fatal(err)
+			}
+			got := buf.Bytes()
+			if format == "page" {
+				got = pageTimestamp.ReplaceAll(got, []byte("<$1>2024-01-02T03:04:05</"))
+			}
+
+			golden := filepath.Join("testdata", "sample"+Extension(format))
+			if *update {
+				if err := os.WriteFile(golden, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("输出与 %s 不一致（go test -update 更新）:\n%s", golden, got)
+			}
+		})
+	}
+}
+
+func TestWritePAGETimestamp(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WritePAGE(&buf, samplePage); err != nil {
+		t.Fatal(err)
+	}
+	m := regexp.MustCompile(`<Created>(\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d)</Created>\s*<LastChange>(.*)</LastChange>`).FindStringSubmatch(buf.String())
+	if m == nil || m[1] != m[2] {
+		t.Errorf("Created / LastChange 格式不正确:\n%s", buf.String())
+	}
+}
+
+func TestWriteEmpty(t *testing.T) {
+	for _, format := range Formats {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, Page{}, format); err != nil {
+				t.Fatal(err)
+			}
+			out := buf.String()
+			switch format {
+			case "text":
+				if out != "" {
+					t.Errorf("输出 %q", out)
+				}
+			case "json":
+				if out != "[]\n" {
+					t.Errorf("输出 %q", out)
+				}
+			case "tsv":
+				if out != "text\tx\ty\twidth\theight\n" {
+					t.Errorf("输出 %q", out)
+				}
+			default:
+				// XML 格式没有段落时仍输出完整的文档
+				if !strings.HasPrefix(out, "<?xml") || strings.Contains(out, "<TextBlock") || strings.Contains(out, "<TextRegion") || strings.Contains(out, `class="ocr_line"`) {
+					t.Errorf("输出:\n%s", out)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	cases := map[string]string{
+		"text": "Tom & \"Jerry\"<br>\n识别结果\n第二行\n",
+		"tsv": "text\tx\ty\twidth\theight\n" +
+			"Tom\t10\t10\t40\t20\n&\t56\t10\t12\t20\n\"Jerry\"<br>\t74\t11\t90\t19\n" +
+			"识别\t10\t80\t40\t20\n结果\t52\t80\t40\t20\n第二行\t10\t104\t60\t20\n",
+	}
+	for format, want := range cases {
+		var buf bytes.Buffer
+		if err := Write(&buf, samplePage, format); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != want {
+			t.Errorf("%s 输出 %q，期望 %q", format, buf.String(), want)
+		}
+	}
+
+	// TSV 中的制表符与换行替换为空格
+	var buf bytes.Buffer
+	Write(&buf, Page{Blocks: []ocr.TextBlock{{Text: "a\tb\nc", Width: 1, Height: 1}}}, "tsv")
+	if !strings.Contains(buf.String(), "\na b c\t") {
+		t.Errorf("TSV 输出 %q", buf.String())
+	}
+
+	// JSON 不转义 HTML 字符
+	buf.Reset()
+	Write(&buf, samplePage, "json")
+	if !strings.Contains(buf.String(), `"text": "\"Jerry\"<br>"`) || !strings.Contains(buf.String(), `"confidence": 0.875`) {
+		t.Errorf("JSON 输出:\n%s", buf.String())
+	}
+
+	if err := Write(&buf, samplePage, "pdf"); err == nil || !strings.Contains(err.Error(), "不支持的输出格式") {
+		t.Errorf("未知格式: %v", err)
+	}
+}
+
+func TestExtension(t *testing.T) {
+	want := map[string]string{
+		"text": ".txt", "json": ".json", "tsv": ".tsv",
+		"hocr": ".hocr", "alto": ".alto.xml", "page": ".page.xml",
+	}
+	for _, format := range Formats {
+		if got := Extension(format); got != want[format] {
+			t.Errorf("Extension(%q) = %q，期望 %q", format, got, want[format])
+		}
+	}
+}
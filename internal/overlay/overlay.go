@@ -5,6 +5,7 @@ package overlay
 import (
 	"fmt"
 	"image"
+	"image/color"
 	"runtime"
 	"sync"
 	"syscall"
@@ -12,6 +13,8 @@ import (
 	"unsafe"
 
 	"screenocr-wails/internal/ocr"
+	"screenocr-wails/internal/raster"
+	"screenocr-wails/internal/screenshot"
 )
 
 var (
@@ -158,6 +161,7 @@ const (
 
 	IDC_ARROW = 32512
 	IDC_IBEAM = 32513
+	IDC_CROSS = 32515
 
 	PM_REMOVE = 0x0001
 
@@ -254,14 +258,17 @@ type Overlay struct {
 	running   bool
 	mu        sync.RWMutex
 
-	// 屏幕尺寸
+	// 窗口范围（与截图范围相同，客户区坐标即截图像素坐标）
 	screenWidth  int
 	screenHeight int
+	windowRect   image.Rectangle
+	layout       *screenshot.Layout // 截图对应的显示器布局
 
 	// 显示状态
 	screenshot *image.RGBA // 截图
 	textBlocks []ocr.TextBlock
-	isReady    bool // OCR 是否完成
+	blockIndex *ocr.Result // textBlocks 的空间索引，用于命中测试和框选
+	isReady    bool        // OCR 是否完成
 
 	// 缓存（优化性能：截图+遮罩只计算一次，与 Python 一致）
 	cachedBackground []byte // 缓存的截图+遮罩混合结果（BGRA 格式，自底向上）
@@ -274,13 +281,24 @@ type Overlay struct {
 	selectionEnd   POINT
 	selectedBlocks []int // 选中的文字块索引
 
+	// 区域选择模式
+	regionSelecting bool            // 正在等待用户框选识别区域
+	region          image.Rectangle // 识别区域（截图像素坐标），显示时不加遮罩
+
+	// 顶部说明文字（如回看历史帧时的时间和操作提示），为空时不显示
+	caption string
+
+	visible bool // 覆盖层正在显示
+
 	// 回调
-	OnTextSelected func(text string, x, y int)
-	OnClose        func()
+	OnTextSelected   func(text string, x, y int)
+	OnRegionSelected func(rect image.Rectangle) // 框选识别区域完成（截图像素坐标）
+	OnClose          func()
 
 	// 光标
 	cursorArrow   uintptr
 	cursorIBeam   uintptr
+	cursorCross   uintptr
 	currentCursor uintptr
 
 	// 窗口线程通信
@@ -292,10 +310,16 @@ type Overlay struct {
 
 // showRequest 显示请求
 type showRequest struct {
-	screenshot image.Image
-	textBlocks []ocr.TextBlock
+	screenshot   image.Image
+	layout       *screenshot.Layout
+	textBlocks   []ocr.TextBlock
+	regionSelect bool
+	caption      string
 }
 
+// minRegionSize 识别区域的最小边长，更小的框选视为误操作
+const minRegionSize = 8
+
 // 全局 overlay 实例（用于窗口过程回调）
 var globalOverlay *Overlay
 
@@ -314,15 +338,49 @@ func NewOverlay() *Overlay {
 	return o
 }
 
-// Show 显示覆盖层
-func (o *Overlay) Show(screenshot image.Image, textBlocks []ocr.TextBlock) error {
-	o.showChan <- showRequest{screenshot: screenshot, textBlocks: textBlocks}
+// Show 显示覆盖层，layout 为截图对应的显示器布局（为 nil 时按系统度量覆盖整个虚拟屏幕）
+func (o *Overlay) Show(img image.Image, layout *screenshot.Layout, textBlocks []ocr.TextBlock) error {
+	o.showChan <- showRequest{screenshot: img, layout: layout, textBlocks: textBlocks}
 	return nil
 }
 
 // ShowWaiting 显示等待状态
-func (o *Overlay) ShowWaiting(screenshot image.Image) error {
-	return o.Show(screenshot, nil)
+func (o *Overlay) ShowWaiting(img image.Image, layout *screenshot.Layout) error {
+	return o.ShowCaptioned(img, layout, "")
+}
+
+// ShowCaptioned 显示等待状态，并在顶部显示说明文字（识别完成后仍保留）
+func (o *Overlay) ShowCaptioned(img image.Image, layout *screenshot.Layout, caption string) error {
+	o.showChan <- showRequest{screenshot: img, layout: layout, caption: caption}
+	return nil
+}
+
+// ShowRegionSelect 显示截图并进入区域选择模式：用户在截图上拖出矩形后调用 OnRegionSelected，
+// 覆盖层随即进入等待状态，识别结果通过 UpdateResults 显示
+func (o *Overlay) ShowRegionSelect(img image.Image, layout *screenshot.Layout) error {
+	o.showChan <- showRequest{screenshot: img, layout: layout, regionSelect: true}
+	return nil
+}
+
+// Snapshot 当前显示的截图、识别结果和选择范围（截图像素坐标）：框选了识别区域时为该区域，
+// 选中了文字时为选中文本块的外接矩形，否则为空。覆盖层没有显示或还没有识别结果时 ok 为 false
+func (o *Overlay) Snapshot() (img *image.RGBA, blocks []ocr.TextBlock, selection image.Rectangle, ok bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	if !o.visible || !o.isReady || o.screenshot == nil {
+		return nil, nil, image.Rectangle{}, false
+	}
+
+	selection = o.region
+	if selection.Empty() {
+		for _, i := range o.selectedBlocks {
+			if i >= 0 && i < len(o.textBlocks) {
+				b := o.textBlocks[i]
+				selection = selection.Union(image.Rect(b.X, b.Y, b.X+b.Width, b.Y+b.Height))
+			}
+		}
+	}
+	return o.screenshot, o.textBlocks, selection, true
 }
 
 // UpdateResults 更新 OCR 结果
@@ -355,6 +413,7 @@ func (o *Overlay) windowThread() {
 	// 在窗口线程中加载光标
 	o.cursorArrow, _, _ = procLoadCursorW.Call(0, IDC_ARROW)
 	o.cursorIBeam, _, _ = procLoadCursorW.Call(0, IDC_IBEAM)
+	o.cursorCross, _, _ = procLoadCursorW.Call(0, IDC_CROSS)
 	o.currentCursor = o.cursorArrow
 
 	o.running = true
@@ -375,7 +434,7 @@ func (o *Overlay) windowThread() {
 		// 检查通道
 		select {
 		case req := <-o.showChan:
-			o.handleShow(req.screenshot, req.textBlocks)
+			o.handleShow(req)
 		case <-o.hideChan:
 			o.handleHide()
 		case blocks := <-o.updateChan:
@@ -391,8 +450,15 @@ func (o *Overlay) windowThread() {
 }
 
 // handleShow 在窗口线程中处理显示
-func (o *Overlay) handleShow(screenshot image.Image, textBlocks []ocr.TextBlock) {
+func (o *Overlay) handleShow(req showRequest) {
+	screenshot, textBlocks := req.screenshot, req.textBlocks
+	layout := req.layout
+	if layout == nil {
+		layout = virtualScreenLayout()
+	}
+
 	o.mu.Lock()
+	o.layout = layout
 	// 保存截图为 RGBA 格式
 	if screenshot != nil {
 		// 尝试直接使用 RGBA 类型
@@ -410,15 +476,27 @@ func (o *Overlay) handleShow(screenshot image.Image, textBlocks []ocr.TextBlock)
 		}
 	}
 	o.textBlocks = textBlocks
+	o.blockIndex = ocr.NewResult(textBlocks)
 	o.isReady = len(textBlocks) > 0
 	o.selectedBlocks = nil
 	o.selecting = false
+	o.regionSelecting = req.regionSelect
+	o.region = image.Rectangle{}
+	o.caption = req.caption
+	o.visible = true
 	o.cacheValid = false // 清除缓存，需要重新计算背景
 	o.mu.Unlock()
 
 	if o.hwnd == 0 {
 		o.createWindow()
 	} else {
+		if layout.Bounds != o.windowRect {
+			// 显示器布局变化（插拔显示器、调整分辨率），窗口跟随新的截图范围
+			o.setWindowRect(layout.Bounds)
+			procSetWindowPos.Call(o.hwnd, HWND_TOPMOST,
+				uintptr(o.windowRect.Min.X), uintptr(o.windowRect.Min.Y),
+				uintptr(o.screenWidth), uintptr(o.screenHeight), SWP_SHOWWINDOW)
+		}
 		procInvalidateRect.Call(o.hwnd, 0, 1)
 		procShowWindow.Call(o.hwnd, SW_SHOW)
 		procSetForegroundWindow.Call(o.hwnd)
@@ -434,6 +512,8 @@ func (o *Overlay) handleHide() {
 	o.mu.Lock()
 	o.selectedBlocks = nil
 	o.selecting = false
+	o.regionSelecting = false
+	o.visible = false
 	o.mu.Unlock()
 	fmt.Println("[Overlay] 隐藏")
 }
@@ -444,7 +524,14 @@ func (o *Overlay) handleUpdate(textBlocks []ocr.TextBlock) {
 	splitBlocks := ocr.SplitTextBlocks(textBlocks)
 
 	o.mu.Lock()
+	if o.regionSelecting {
+		// 之前的全屏识别在用户框选区域时才完成，结果已经过时
+		o.mu.Unlock()
+		fmt.Println("[Overlay] 正在框选识别区域，忽略之前的识别结果")
+		return
+	}
 	o.textBlocks = splitBlocks
+	o.blockIndex = ocr.NewResult(splitBlocks)
 	o.isReady = true
 	o.cacheValid = false // isReady 状态变化，需要重新计算背景（遮罩颜色不同）
 	o.mu.Unlock()
@@ -468,11 +555,8 @@ func (o *Overlay) handleClose() {
 	}
 }
 
-// createWindow 创建窗口
-func (o *Overlay) createWindow() error {
-	globalOverlay = o
-
-	// 获取虚拟屏幕尺寸（支持多显示器，与截图保持一致）
+// virtualScreenLayout 没有截图布局时按系统度量覆盖整个虚拟屏幕
+func virtualScreenLayout() *screenshot.Layout {
 	sx, _, _ := procGetSystemMetrics.Call(SM_XVIRTUALSCREEN)
 	sy, _, _ := procGetSystemMetrics.Call(SM_YVIRTUALSCREEN)
 	sw, _, _ := procGetSystemMetrics.Call(SM_CXVIRTUALSCREEN)
@@ -485,8 +569,27 @@ func (o *Overlay) createWindow() error {
 		sx, sy = 0, 0
 	}
 
-	o.screenWidth = int(sw)
-	o.screenHeight = int(sh)
+	// 虚拟屏幕原点可能为负，需要按 int32 解释
+	x, y := int(int32(sx)), int(int32(sy))
+	return screenshot.NewLayout(nil, image.Rect(x, y, x+int(sw), y+int(sh)))
+}
+
+// setWindowRect 记录窗口范围（虚拟屏幕坐标）
+func (o *Overlay) setWindowRect(rect image.Rectangle) {
+	o.windowRect = rect
+	o.screenWidth = rect.Dx()
+	o.screenHeight = rect.Dy()
+}
+
+// createWindow 创建窗口
+func (o *Overlay) createWindow() error {
+	globalOverlay = o
+
+	// 窗口与截图范围一致（支持多显示器和负原点），客户区坐标即截图像素坐标
+	o.mu.RLock()
+	bounds := o.layout.Bounds
+	o.mu.RUnlock()
+	o.setWindowRect(bounds)
 
 	// 获取模块句柄
 	o.hInstance, _, _ = procGetModuleHandleW.Call(0)
@@ -512,8 +615,8 @@ func (o *Overlay) createWindow() error {
 		uintptr(unsafe.Pointer(className)),
 		0,
 		WS_POPUP|WS_VISIBLE,
-		sx, sy,
-		sw, sh,
+		uintptr(bounds.Min.X), uintptr(bounds.Min.Y),
+		uintptr(bounds.Dx()), uintptr(bounds.Dy()),
 		0, 0,
 		o.hInstance,
 		0,
@@ -590,6 +693,9 @@ func (o *Overlay) onPaint(hwnd uintptr) {
 	textBlocks := o.textBlocks
 	selectedBlocks := o.selectedBlocks
 	screenshot := o.screenshot
+	regionSelecting := o.regionSelecting
+	region := o.region
+	caption := o.caption
 	o.mu.RUnlock()
 
 	width := o.screenWidth
@@ -635,7 +741,7 @@ func (o *Overlay) onPaint(hwnd uintptr) {
 			dataSize := imgWidth * imgHeight * 4
 			if dataSize > 0 {
 				dst := unsafe.Slice((*byte)(unsafe.Pointer(pBits)), dataSize)
-				o.drawScreenshotWithOverlay(dst, screenshot, width, height, isReady, textBlocks, selectedBlocks)
+				o.drawScreenshotWithOverlay(dst, screenshot, width, height, isReady, textBlocks, selectedBlocks, region)
 			}
 		}
 	} else {
@@ -652,15 +758,24 @@ func (o *Overlay) onPaint(hwnd uintptr) {
 		procDeleteObject.Call(brush)
 	}
 
-	// 等待状态显示 "识别中，请稍后..." 文字
-	if !isReady {
-		o.drawWaitingText(memDC, width, height)
+	// 等待状态显示 "识别中，请稍后..." 文字，区域选择模式在开始拖动前显示操作提示
+	switch {
+	case regionSelecting && region.Empty():
+		o.drawHintText(memDC, "拖动鼠标框选识别区域，ESC 取消")
+	case !isReady && !regionSelecting:
+		o.drawHintText(memDC, "识别中，请稍后...")
+	}
+	if caption != "" {
+		o.drawCaption(memDC, caption)
 	}
 
 	// 高亮已在 drawScreenshotWithOverlay 中通过像素混合完成
 
 	// 绘制边框 - 与 Python 版本一致的颜色
 	o.drawBorder(memDC, width, height, isReady)
+	if !region.Empty() {
+		o.drawRegionBorder(memDC, region)
+	}
 
 	// 设置窗口完全不透明（因为图像已经包含遮罩效果）
 	procSetLayeredWindowAttributes.Call(hwnd, 0, 255, LWA_ALPHA)
@@ -671,7 +786,7 @@ func (o *Overlay) onPaint(hwnd uintptr) {
 
 // drawScreenshotWithOverlay 绘制截图并叠加遮罩层和高亮层（与 Python alpha_composite 一致）
 // 优化：截图+遮罩只计算一次并缓存，高亮只处理选中区域的像素
-func (o *Overlay) drawScreenshotWithOverlay(pixelData []byte, screenshot *image.RGBA, width, height int, isReady bool, textBlocks []ocr.TextBlock, selectedBlocks []int) {
+func (o *Overlay) drawScreenshotWithOverlay(pixelData []byte, screenshot *image.RGBA, width, height int, isReady bool, textBlocks []ocr.TextBlock, selectedBlocks []int, region image.Rectangle) {
 	bounds := screenshot.Bounds()
 	imgWidth := bounds.Dx()
 	imgHeight := bounds.Dy()
@@ -716,6 +831,11 @@ func (o *Overlay) drawScreenshotWithOverlay(pixelData []byte, screenshot *image.
 	copy(pixelData, o.cachedBackground)
 	o.mu.RUnlock()
 
+	// 识别区域显示原始截图，突出只识别这一块
+	if !region.Empty() {
+		o.revealRegion(pixelData, screenshot, imgWidth, imgHeight, region)
+	}
+
 	// 只对高亮区域的像素进行额外混合（与 Python 一致：高亮是单独的小图层）
 	if len(selectedBlocks) > 0 {
 		o.applyHighlight(pixelData, imgWidth, imgHeight, textBlocks, selectedBlocks)
@@ -742,50 +862,15 @@ func (o *Overlay) calculateBackground(screenshot *image.RGBA, imgWidth, imgHeigh
 	}
 
 	// 遮罩参数 - 与 Python 保持一致
-	var overlayR, overlayG, overlayB, overlayA uint8
+	mask := color.RGBA{0, 0, 0, 180} // 黑色遮罩 70%
 	if isReady {
-		overlayR, overlayG, overlayB, overlayA = 255, 255, 255, 100 // 白色遮罩 39%
-	} else {
-		overlayR, overlayG, overlayB, overlayA = 0, 0, 0, 180 // 黑色遮罩 70%
+		mask = color.RGBA{255, 255, 255, 100} // 白色遮罩 39%
 	}
 
-	alpha := float64(overlayA) / 255.0
-	invAlpha := 1.0 - alpha
-
-	// 截图已经是从上到下的顺序（负高度 DIB），但 SetDIBitsToDevice 需要自底向上
-	for y := 0; y < imgHeight; y++ {
-		for x := 0; x < imgWidth; x++ {
-			// 使用 Stride 计算源索引
-			srcIdx := y*stride + x*4
-
-			// 边界检查: srcIdx+3 是最后一个访问的索引，必须 < pixLen
-			if srcIdx < 0 || srcIdx+4 > pixLen {
-				continue
-			}
-
-			srcR := screenshot.Pix[srcIdx+0]
-			srcG := screenshot.Pix[srcIdx+1]
-			srcB := screenshot.Pix[srcIdx+2]
-
-			r := uint8(float64(srcR)*invAlpha + float64(overlayR)*alpha)
-			g := uint8(float64(srcG)*invAlpha + float64(overlayG)*alpha)
-			b := uint8(float64(srcB)*invAlpha + float64(overlayB)*alpha)
-
-			// BGRA 格式，自底向上（SetDIBitsToDevice 使用正高度需要翻转）
-			dstY := imgHeight - 1 - y
-			dstIdx := (dstY*imgWidth + x) * 4
-
-			// 边界检查: dstIdx+3 是最后一个访问的索引，必须 < dataSize
-			if dstIdx < 0 || dstIdx+4 > dataSize {
-				continue
-			}
-
-			background[dstIdx+0] = b
-			background[dstIdx+1] = g
-			background[dstIdx+2] = r
-			background[dstIdx+3] = 255
-		}
-	}
+	// 截图是从上到下的 RGBA，DIB 使用正高度需要自底向上的 BGRA：混合、交换通道、翻转一次完成
+	src := &image.RGBA{Pix: screenshot.Pix, Stride: stride, Rect: image.Rect(0, 0, imgWidth, imgHeight)}
+	raster.Blend(raster.FromBGRA(background, imgWidth, imgHeight, imgWidth*4), src, mask,
+		raster.SwapRB|raster.FlipV|raster.Opaque|raster.Parallel)
 
 	o.mu.Lock()
 	o.cachedBackground = background
@@ -799,9 +884,12 @@ func (o *Overlay) calculateBackground(screenshot *image.RGBA, imgWidth, imgHeigh
 // applyHighlight 只对高亮区域的像素进行混合（高效：只处理选中区域）
 func (o *Overlay) applyHighlight(pixelData []byte, imgWidth, imgHeight int, textBlocks []ocr.TextBlock, selectedBlocks []int) {
 	// 高亮参数 - 与 Python 保持一致: (77, 148, 255, 77) = #4D94FF with 30% opacity
-	hlR, hlG, hlB := uint8(77), uint8(148), uint8(255)
-	hlAlpha := float64(77) / 255.0
-	hlInvAlpha := 1.0 - hlAlpha
+	// 像素缓冲区是自底向上的 BGRA，颜色交换 R、B，矩形上下翻转
+	if len(pixelData) < imgWidth*imgHeight*4 {
+		return
+	}
+	highlight := raster.SwapRBColor(color.RGBA{77, 148, 255, 77})
+	dst := raster.FromBGRA(pixelData, imgWidth, imgHeight, imgWidth*4)
 
 	for _, idx := range selectedBlocks {
 		if idx < 0 || idx >= len(textBlocks) {
@@ -809,59 +897,74 @@ func (o *Overlay) applyHighlight(pixelData []byte, imgWidth, imgHeight int, text
 		}
 		block := textBlocks[idx]
 
-		// 高亮区域（带2像素边距）
+		// 高亮区域（带2像素边距），BlendRect 会裁剪到图像边界
 		x1, y1 := block.X-2, block.Y-2
 		x2, y2 := block.X+block.Width+2, block.Y+block.Height+2
+		raster.BlendRect(dst, image.Rect(x1, imgHeight-y2, x2, imgHeight-y1), highlight)
+	}
+}
 
-		// 裁剪到图像边界
-		if x1 < 0 {
-			x1 = 0
-		}
-		if y1 < 0 {
-			y1 = 0
-		}
-		if x2 > imgWidth {
-			x2 = imgWidth
-		}
-		if y2 > imgHeight {
-			y2 = imgHeight
-		}
+// revealRegion 把识别区域内的像素恢复为原始截图（不加遮罩）
+func (o *Overlay) revealRegion(pixelData []byte, screenshot *image.RGBA, imgWidth, imgHeight int, region image.Rectangle) {
+	region = region.Intersect(image.Rect(0, 0, imgWidth, imgHeight))
+	if region.Empty() {
+		return
+	}
+	// 像素缓冲区是自底向上的 BGRA，目标矩形上下翻转
+	dst := raster.FromBGRA(pixelData, imgWidth, imgHeight, imgWidth*4)
+	flipped := image.Rect(region.Min.X, imgHeight-region.Max.Y, region.Max.X, imgHeight-region.Min.Y)
+	src := screenshot.SubImage(region.Add(screenshot.Rect.Min)).(*image.RGBA)
+	raster.Copy(dst.SubImage(flipped).(*image.RGBA), src, raster.SwapRB|raster.FlipV|raster.Opaque)
+}
 
-		// 只处理这个矩形区域的像素
-		dataLen := len(pixelData)
-		for y := y1; y < y2; y++ {
-			dstY := imgHeight - 1 - y // BGRA 自底向上
-			if dstY < 0 || dstY >= imgHeight {
-				continue
-			}
-			for x := x1; x < x2; x++ {
-				dstIdx := (dstY*imgWidth + x) * 4
+// drawRegionBorder 绘制识别区域边框
+func (o *Overlay) drawRegionBorder(hdc uintptr, region image.Rectangle) {
+	brush, _, _ := procCreateSolidBrush.Call(COLOR_HIGHLIGHT)
+	defer procDeleteObject.Call(brush)
 
-				// 边界检查: 访问 dstIdx+0,1,2,3，所以需要 dstIdx+4 <= dataLen
-				if dstIdx < 0 || dstIdx+4 > dataLen {
-					continue
-				}
+	const w = 2
+	x1, y1, x2, y2 := int32(region.Min.X), int32(region.Min.Y), int32(region.Max.X), int32(region.Max.Y)
+	for _, rect := range []RECT{
+		{x1 - w, y1 - w, x2 + w, y1}, // 上
+		{x1 - w, y2, x2 + w, y2 + w}, // 下
+		{x1 - w, y1, x1, y2},         // 左
+		{x2, y1, x2 + w, y2},         // 右
+	} {
+		procFillRect.Call(hdc, uintptr(unsafe.Pointer(&rect)), brush)
+	}
+}
 
-				// 读取当前像素 (BGR)
-				b := pixelData[dstIdx+0]
-				g := pixelData[dstIdx+1]
-				r := pixelData[dstIdx+2]
+// hintArea 提示文字的显示区域（窗口坐标）和 DPI 缩放比例
+func (o *Overlay) hintArea() (image.Rectangle, float64) {
+	o.mu.RLock()
+	layout := o.layout
+	o.mu.RUnlock()
 
-				// Alpha 混合高亮
-				pixelData[dstIdx+0] = uint8(float64(b)*hlInvAlpha + float64(hlB)*hlAlpha)
-				pixelData[dstIdx+1] = uint8(float64(g)*hlInvAlpha + float64(hlG)*hlAlpha)
-				pixelData[dstIdx+2] = uint8(float64(r)*hlInvAlpha + float64(hlR)*hlAlpha)
-			}
+	// 全屏截图时显示在主显示器上；只截取了窗口时显示在窗口内，按窗口所在显示器缩放
+	area := image.Rect(0, 0, o.screenWidth, o.screenHeight)
+	scale := 1.0
+	if primary := layout.Primary(); primary >= 0 {
+		monitor := layout.Monitors[primary]
+		if monitor.Bounds.In(layout.Bounds) {
+			area = layout.ConvertRect(monitor.Bounds, screenshot.ScreenSpace, screenshot.ImageSpace)
+		} else {
+			center := layout.Bounds.Min.Add(layout.Bounds.Size().Div(2))
+			monitor = layout.Monitors[layout.MonitorAt(center)]
 		}
+		scale = monitor.Scale()
 	}
+	return area, scale
 }
 
-// drawWaitingText 绘制等待文字 "识别中，请稍后..."
-func (o *Overlay) drawWaitingText(hdc uintptr, width, height int) {
+// drawHintText 绘制提示文字（显示在主显示器中央，按该显示器的 DPI 缩放）
+func (o *Overlay) drawHintText(hdc uintptr, text string) {
+	area, scale := o.hintArea()
+	scaled := func(px int) int { return int(float64(px) * scale) }
+
 	// 创建字体
 	fontName, _ := syscall.UTF16PtrFromString("Microsoft YaHei UI")
 	hFont, _, _ := procCreateFontW.Call(
-		uintptr(40), 0, 0, 0, // 等待文字 40px
+		uintptr(scaled(40)), 0, 0, 0, // 等待文字 40px
 		400, 0, 0, 0, // 正常粗细
 		1,    // DEFAULT_CHARSET
 		0, 0, // OUT_DEFAULT_PRECIS, CLIP_DEFAULT_PRECIS
@@ -879,12 +982,12 @@ func (o *Overlay) drawWaitingText(hdc uintptr, width, height int) {
 	procSetBkMode.Call(hdc, TRANSPARENT_BK)
 
 	// 计算文字位置（屏幕中心）
-	text := "识别中，请稍后..."
 	textUTF16, _ := syscall.UTF16FromString(text)
 
 	// 计算大致中心位置
-	centerX := width/2 - 100 // 大约文字宽度的一半
-	centerY := height/2 - 16 // 大约文字高度的一半
+	center := area.Min.Add(area.Size().Div(2))
+	centerX := center.X - scaled(20*len(textUTF16)/2) // 大约文字宽度的一半（每个字约 40px）
+	centerY := center.Y - scaled(16)                  // 大约文字高度的一半
 
 	procTextOutW.Call(hdc,
 		uintptr(centerX),
@@ -894,6 +997,56 @@ func (o *Overlay) drawWaitingText(hdc uintptr, width, height int) {
 	)
 }
 
+// drawCaption 在主显示器顶部居中绘制说明文字（深色底条，避免与截图内容混在一起）
+func (o *Overlay) drawCaption(hdc uintptr, text string) {
+	area, scale := o.hintArea()
+	scaled := func(px int) int { return int(float64(px) * scale) }
+
+	fontName, _ := syscall.UTF16PtrFromString("Microsoft YaHei UI")
+	hFont, _, _ := procCreateFontW.Call(
+		uintptr(scaled(24)), 0, 0, 0, // 说明文字 24px
+		400, 0, 0, 0, // 正常粗细
+		1,    // DEFAULT_CHARSET
+		0, 0, // OUT_DEFAULT_PRECIS, CLIP_DEFAULT_PRECIS
+		0, // DEFAULT_QUALITY
+		0, // DEFAULT_PITCH
+		uintptr(unsafe.Pointer(fontName)),
+	)
+	defer procDeleteObject.Call(hFont)
+
+	oldFont, _, _ := procSelectObject.Call(hdc, hFont)
+	defer procSelectObject.Call(hdc, oldFont)
+
+	// 估算文字宽度（全角字约 24px，半角字符约 12px），绘制底条
+	textUTF16, _ := syscall.UTF16FromString(text)
+	textWidth := 0
+	for _, r := range text {
+		if r >= 0x2E80 {
+			textWidth += 24
+		} else {
+			textWidth += 12
+		}
+	}
+	textWidth = scaled(textWidth)
+	centerX := area.Min.X + area.Dx()/2
+	bar := RECT{
+		int32(centerX - textWidth/2 - scaled(16)), int32(area.Min.Y + scaled(16)),
+		int32(centerX + textWidth/2 + scaled(16)), int32(area.Min.Y + scaled(56)),
+	}
+	brush, _, _ := procCreateSolidBrush.Call(0x202020)
+	procFillRect.Call(hdc, uintptr(unsafe.Pointer(&bar)), brush)
+	procDeleteObject.Call(brush)
+
+	procSetTextColor.Call(hdc, 0xFFFFFF)
+	procSetBkMode.Call(hdc, TRANSPARENT_BK)
+	procTextOutW.Call(hdc,
+		uintptr(centerX-textWidth/2),
+		uintptr(int(bar.Top)+scaled(6)),
+		uintptr(unsafe.Pointer(&textUTF16[0])),
+		uintptr(len(textUTF16)-1),
+	)
+}
+
 // drawTextBlockBorders 绘制文字块边框（调试）
 func (o *Overlay) drawTextBlockBorders(hdc uintptr, textBlocks []ocr.TextBlock) {
 	pen, _, _ := procCreatePen.Call(PS_SOLID, 1, 0x00FF00) // 绿色边框
@@ -948,15 +1101,14 @@ func (o *Overlay) drawBorder(hdc uintptr, width, height int, isReady bool) {
 
 // onMouseDown 鼠标按下
 func (o *Overlay) onMouseDown(lParam uintptr) {
-	x := int32(lParam & 0xFFFF)
-	y := int32((lParam >> 16) & 0xFFFF)
+	x, y := clientPoint(lParam)
 
 	o.mu.Lock()
 	isReady := o.isReady
 	blockCount := len(o.textBlocks)
 	fmt.Printf("[Overlay] 鼠标按下: (%d, %d), isReady=%v, blocks=%d\n", x, y, isReady, blockCount)
 
-	if !isReady {
+	if !isReady && !o.regionSelecting {
 		o.mu.Unlock()
 		return
 	}
@@ -975,14 +1127,16 @@ func (o *Overlay) onMouseMove(lParam uintptr) {
 	o.mu.Lock()
 	isReady := o.isReady
 	selecting := o.selecting
+	regionSelecting := o.regionSelecting
 	o.mu.Unlock()
 
-	x := int32(lParam & 0xFFFF)
-	y := int32((lParam >> 16) & 0xFFFF)
+	x, y := clientPoint(lParam)
 
 	// 更新光标（避免重复 SetCursor 导致闪烁）
 	var desiredCursor uintptr
-	if isReady && o.isOverText(int(x), int(y)) {
+	if regionSelecting {
+		desiredCursor = o.cursorCross
+	} else if isReady && o.isOverText(int(x), int(y)) {
 		desiredCursor = o.cursorIBeam
 	} else {
 		desiredCursor = o.cursorArrow
@@ -1003,7 +1157,11 @@ func (o *Overlay) onMouseMove(lParam uintptr) {
 
 	o.mu.Lock()
 	o.selectionEnd = POINT{x, y}
-	o.updateSelection()
+	if regionSelecting {
+		o.region = o.selectionRect()
+	} else {
+		o.updateSelection()
+	}
 	o.mu.Unlock()
 
 	procInvalidateRect.Call(o.hwnd, 0, 0)
@@ -1013,14 +1171,14 @@ func (o *Overlay) onMouseMove(lParam uintptr) {
 func (o *Overlay) onMouseUp(lParam uintptr) {
 	procReleaseCapture.Call()
 
-	x := int32(lParam & 0xFFFF)
-	y := int32((lParam >> 16) & 0xFFFF)
+	x, y := clientPoint(lParam)
 
 	o.mu.Lock()
 	selecting := o.selecting
 	o.selecting = false
 	selectedBlocks := o.selectedBlocks
 	textBlocks := o.textBlocks
+	regionSelecting := o.regionSelecting
 	o.mu.Unlock()
 
 	fmt.Printf("[Overlay] 鼠标释放: (%d, %d), selecting=%v, selected=%d\n", x, y, selecting, len(selectedBlocks))
@@ -1029,6 +1187,11 @@ func (o *Overlay) onMouseUp(lParam uintptr) {
 		return
 	}
 
+	if regionSelecting {
+		o.finishRegion()
+		return
+	}
+
 	// 如果没有选中任何文字块，不做任何事（与 Python 一致）
 	if len(selectedBlocks) == 0 {
 		fmt.Println("[Overlay] 没有选中文字")
@@ -1049,24 +1212,55 @@ func (o *Overlay) onMouseUp(lParam uintptr) {
 	// overlay 只在快捷键松开或按 ESC 时关闭
 	fmt.Println("[Overlay] 已复制，触发翻译回调")
 
-	// 触发回调（异步，避免阻塞窗口线程）
+	// 触发回调（异步，避免阻塞窗口线程），回调使用虚拟屏幕坐标
 	if o.OnTextSelected != nil {
-		go o.OnTextSelected(text, int(x), int(y))
+		o.mu.RLock()
+		pos := o.layout.Convert(image.Pt(int(x), int(y)), screenshot.ImageSpace, screenshot.ScreenSpace)
+		o.mu.RUnlock()
+		go o.OnTextSelected(text, pos.X, pos.Y)
 	}
 }
 
+// finishRegion 结束区域选择：区域有效时进入等待状态并触发 OnRegionSelected
+func (o *Overlay) finishRegion() {
+	o.mu.Lock()
+	region := o.region
+	if region.Dx() < minRegionSize || region.Dy() < minRegionSize {
+		// 单击或框选太小，清除后让用户重新框选
+		o.region = image.Rectangle{}
+		o.mu.Unlock()
+		fmt.Println("[Overlay] 识别区域太小，请重新框选")
+		procInvalidateRect.Call(o.hwnd, 0, 0)
+		return
+	}
+	o.regionSelecting = false
+	o.mu.Unlock()
+
+	fmt.Printf("[Overlay] 识别区域: %v\n", region)
+	procInvalidateRect.Call(o.hwnd, 0, 0)
+
+	if o.OnRegionSelected != nil {
+		go o.OnRegionSelected(region)
+	}
+}
+
+// selectionRect 当前拖动的矩形（截图像素坐标，已裁剪到窗口范围）
+func (o *Overlay) selectionRect() image.Rectangle {
+	r := image.Rect(int(o.selectionStart.X), int(o.selectionStart.Y), int(o.selectionEnd.X), int(o.selectionEnd.Y))
+	return r.Intersect(image.Rect(0, 0, o.screenWidth, o.screenHeight))
+}
+
+// clientPoint 从鼠标消息的 lParam 取出客户区坐标（按有符号数解释，拖到窗口外时可能为负）
+func clientPoint(lParam uintptr) (int32, int32) {
+	return int32(int16(lParam & 0xFFFF)), int32(int16((lParam >> 16) & 0xFFFF))
+}
+
 // isOverText 检查是否在文字上
 func (o *Overlay) isOverText(x, y int) bool {
 	o.mu.RLock()
 	defer o.mu.RUnlock()
 
-	for _, block := range o.textBlocks {
-		if x >= block.X && x <= block.X+block.Width &&
-			y >= block.Y && y <= block.Y+block.Height {
-			return true
-		}
-	}
-	return false
+	return o.blockIndex.At(x, y) >= 0
 }
 
 // updateSelection 更新选区
@@ -1076,16 +1270,7 @@ func (o *Overlay) updateSelection() {
 	minY := min(o.selectionStart.Y, o.selectionEnd.Y)
 	maxY := max(o.selectionStart.Y, o.selectionEnd.Y)
 
-	o.selectedBlocks = nil
-	for i, block := range o.textBlocks {
-		bx1, by1 := block.X, block.Y
-		bx2, by2 := block.X+block.Width, block.Y+block.Height
-
-		// 检查是否相交
-		if !(int(maxX) < bx1 || int(minX) > bx2 || int(maxY) < by1 || int(minY) > by2) {
-			o.selectedBlocks = append(o.selectedBlocks, i)
-		}
-	}
+	o.selectedBlocks = o.blockIndex.Intersect(image.Rect(int(minX), int(minY), int(maxX), int(maxY)))
 }
 
 // mergeSelectedText 合并选中文字
@@ -1109,10 +1294,32 @@ func (o *Overlay) mergeSelectedText(textBlocks []ocr.TextBlock, selected []int)
 
 // copyToClipboard 复制到剪贴板
 func (o *Overlay) copyToClipboard(text string) {
-	ret, _, _ := procOpenClipboard.Call(o.hwnd)
-	if ret == 0 {
+	if err := setClipboardText(o.hwnd, text); err != nil {
 		return
 	}
+
+	fmt.Printf("[Overlay] 已复制到剪贴板: %s\n", text)
+}
+
+// CopyToClipboard 把文本复制到剪贴板，不依赖覆盖层窗口（供后台任务使用）
+// 剪贴板被其他程序占用时会短暂重试
+func CopyToClipboard(text string) error {
+	var err error
+	for i := 0; i < 5; i++ {
+		if err = setClipboardText(0, text); err == nil {
+			return nil
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return err
+}
+
+// setClipboardText 以 owner 为剪贴板所有者写入 Unicode 文本
+func setClipboardText(owner uintptr, text string) error {
+	ret, _, _ := procOpenClipboard.Call(owner)
+	if ret == 0 {
+		return fmt.Errorf("打开剪贴板失败")
+	}
 	defer procCloseClipboard.Call()
 
 	procEmptyClipboard.Call()
@@ -1123,12 +1330,12 @@ func (o *Overlay) copyToClipboard(text string) {
 
 	hMem, _, _ := procGlobalAlloc.Call(GMEM_MOVEABLE, uintptr(size))
 	if hMem == 0 {
-		return
+		return fmt.Errorf("分配剪贴板内存失败")
 	}
 
 	pMem, _, _ := procGlobalLock.Call(hMem)
 	if pMem == 0 {
-		return
+		return fmt.Errorf("锁定剪贴板内存失败")
 	}
 
 	// 复制数据
@@ -1138,8 +1345,7 @@ func (o *Overlay) copyToClipboard(text string) {
 
 	procGlobalUnlock.Call(hMem)
 	procSetClipboardData.Call(CF_UNICODETEXT, hMem)
-
-	fmt.Printf("[Overlay] 已复制到剪贴板: %s\n", text)
+	return nil
 }
 
 func min(a, b int32) int32 {
@@ -4,6 +4,7 @@ package overlay
 
 import (
 	"fmt"
+	"image"
 	"runtime"
 	"strings"
 	"syscall"
@@ -33,6 +34,9 @@ const (
 	DT_WORDBREAK     = 0x00000010
 	DT_NOPREFIX      = 0x00000800
 	DEFAULT_GUI_FONT = 17
+
+	popupWidth  = 420
+	popupHeight = 220
 )
 
 // TranslationPopup 翻译弹窗
@@ -59,6 +63,7 @@ type TranslationPopup struct {
 type popupShowRequest struct {
 	sourceText string
 	x, y       int
+	workArea   image.Rectangle
 }
 
 // 全局弹窗实例
@@ -80,17 +85,24 @@ func NewTranslationPopup() *TranslationPopup {
 	return p
 }
 
-// Show 显示弹窗
-func (p *TranslationPopup) Show(sourceText string, x, y int) error {
-	p.showChan <- popupShowRequest{sourceText: sourceText, x: x, y: y}
+// Show 在虚拟屏幕坐标 (x, y) 附近显示弹窗，弹窗不会超出 workArea（为空时使用主显示器）
+func (p *TranslationPopup) Show(sourceText string, x, y int, workArea image.Rectangle) error {
+	p.showChan <- popupShowRequest{sourceText: sourceText, x: x, y: y, workArea: workArea}
 	return nil
 }
 
-// UpdateTranslation 更新翻译结果
+// UpdateTranslation 更新翻译结果（流式翻译时会连续调用，窗口线程来不及处理时只保留最新的一次）
 func (p *TranslationPopup) UpdateTranslation(text string) {
-	select {
-	case p.updateChan <- text:
-	default:
+	for {
+		select {
+		case p.updateChan <- text:
+			return
+		default:
+		}
+		select {
+		case <-p.updateChan:
+		default:
+		}
 	}
 }
 
@@ -160,10 +172,11 @@ func (p *TranslationPopup) handleShow(req popupShowRequest) {
 	p.targetText = ""
 	p.isLoading = true
 
+	pos := popupPosition(req.x, req.y, req.workArea)
 	if p.hwnd == 0 {
-		p.createWindow(req.x, req.y)
+		p.createWindow(pos)
 	} else {
-		procMoveWindow.Call(p.hwnd, uintptr(req.x+20), uintptr(req.y+20), 420, 220, 1)
+		procMoveWindow.Call(p.hwnd, uintptr(pos.X), uintptr(pos.Y), popupWidth, popupHeight, 1)
 		procShowWindow.Call(p.hwnd, SW_SHOW)
 		procInvalidateRect.Call(p.hwnd, 0, 1)
 	}
@@ -201,8 +214,33 @@ func (p *TranslationPopup) handleClose() {
 	}
 }
 
+// popupPosition 计算弹窗左上角：放在 (x, y) 右下方，超出可用区域时向内移动
+func popupPosition(x, y int, workArea image.Rectangle) image.Point {
+	if workArea.Empty() {
+		// 获取主屏幕尺寸以确保不超出边界
+		screenWidth, _, _ := procGetSystemMetrics.Call(SM_CXSCREEN)
+		screenHeight, _, _ := procGetSystemMetrics.Call(SM_CYSCREEN)
+		workArea = image.Rect(0, 0, int(screenWidth), int(screenHeight))
+	}
+
+	// 调整位置
+	if x+20+popupWidth > workArea.Max.X {
+		x = workArea.Max.X - popupWidth - 20
+	}
+	if y+20+popupHeight > workArea.Max.Y {
+		y = workArea.Max.Y - popupHeight - 60
+	}
+	if x < workArea.Min.X+20 {
+		x = workArea.Min.X + 20
+	}
+	if y < workArea.Min.Y+20 {
+		y = workArea.Min.Y + 20
+	}
+	return image.Pt(x+20, y+20)
+}
+
 // createWindow 创建弹窗
-func (p *TranslationPopup) createWindow(x, y int) error {
+func (p *TranslationPopup) createWindow(pos image.Point) error {
 	p.hInstance, _, _ = procGetModuleHandleW.Call(0)
 
 	// 注册窗口类
@@ -223,35 +261,14 @@ func (p *TranslationPopup) createWindow(x, y int) error {
 
 	procRegisterClassExW.Call(uintptr(unsafe.Pointer(&wc)))
 
-	// 获取屏幕尺寸以确保不超出边界
-	screenWidth, _, _ := procGetSystemMetrics.Call(SM_CXSCREEN)
-	screenHeight, _, _ := procGetSystemMetrics.Call(SM_CYSCREEN)
-
-	popupWidth := 420
-	popupHeight := 220
-
-	// 调整位置
-	if x+20+popupWidth > int(screenWidth) {
-		x = int(screenWidth) - popupWidth - 20
-	}
-	if y+20+popupHeight > int(screenHeight) {
-		y = int(screenHeight) - popupHeight - 60
-	}
-	if x < 20 {
-		x = 20
-	}
-	if y < 20 {
-		y = 20
-	}
-
 	// 创建窗口
 	hwnd, _, err := procCreateWindowExW2.Call(
 		WS_EX_TOPMOST|WS_EX_TOOLWINDOW,
 		uintptr(unsafe.Pointer(className)),
 		0,
 		WS_POPUP|WS_VISIBLE,
-		uintptr(x+20), uintptr(y+20),
-		uintptr(popupWidth), uintptr(popupHeight),
+		uintptr(pos.X), uintptr(pos.Y),
+		popupWidth, popupHeight,
 		0, 0,
 		p.hInstance,
 		0,
@@ -317,8 +334,8 @@ func (p *TranslationPopup) onPaint(hwnd uintptr) {
 	hdc, _, _ := procBeginPaint.Call(hwnd, uintptr(unsafe.Pointer(&ps)))
 	defer procEndPaint.Call(hwnd, uintptr(unsafe.Pointer(&ps)))
 
-	width := 420
-	height := 220
+	width := popupWidth
+	height := popupHeight
 
 	// 现代深色主题配色 (Catppuccin Mocha 风格, BGR 格式)
 	colorBase := uint32(0x2E1E1E)        // #1e1e2e 主背景
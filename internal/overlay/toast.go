@@ -54,6 +54,16 @@ func (t *StartupToast) ShowMessage(title, message string) {
 	go t.run()
 }
 
+// ShowInfo 显示普通（非警告样式）消息
+func (t *StartupToast) ShowInfo(title, message string) {
+	t.title = title
+	t.message = message
+	t.isWarn = false
+
+	// 在新的 goroutine 中运行，避免阻塞
+	go t.run()
+}
+
 func (t *StartupToast) run() {
 	runtime.LockOSThread()
 	defer runtime.UnlockOSThread()
@@ -0,0 +1,295 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// ExtractText 从本包生成的 PDF 中提取每页的文本层（用于校验输出）
+// 按 ToUnicode 映射解码文字，Tm 的纵坐标变化超过半个字号时换行；不是通用的 PDF 解析器
+func ExtractText(data []byte) ([]string, error) {
+	r := &reader{data: data, objects: make(map[int]int)}
+	if err := r.readXref(); err != nil {
+		return nil, err
+	}
+
+	trailer := r.trailer()
+	catalog, _, err := r.object(refIn(trailer, "Root"))
+	if err != nil {
+		return nil, err
+	}
+	pages, _, err := r.object(refIn(catalog, "Pages"))
+	if err != nil {
+		return nil, err
+	}
+
+	kids := regexp.MustCompile(`/Kids\s*\[([^\]]*)\]`).FindSubmatch(pages)
+	if kids == nil {
+		return nil, fmt.Errorf("页面树缺少 Kids")
+	}
+	var texts []string
+	for _, m := range refPattern.FindAllSubmatch(kids[1], -1) {
+		id, _ := strconv.Atoi(string(m[1]))
+		text, err := r.pageText(id)
+		if err != nil {
+			return nil, fmt.Errorf("读取第 %d 页失败: %w", len(texts)+1, err)
+		}
+		texts = append(texts, text)
+	}
+	return texts, nil
+}
+
+var refPattern = regexp.MustCompile(`(\d+) 0 R`)
+
+// refIn 读取字典中 /Key N 0 R 形式的引用
+func refIn(dict []byte, key string) int {
+	m := regexp.MustCompile(`/` + key + `\s+(\d+) 0 R`).FindSubmatch(dict)
+	if m == nil {
+		return 0
+	}
+	id, _ := strconv.Atoi(string(m[1]))
+	return id
+}
+
+// reader 按交叉引用表读取对象
+type reader struct {
+	data    []byte
+	objects map[int]int // 对象编号 → 偏移量
+	xref    int
+}
+
+// readXref 解析交叉引用表
+func (r *reader) readXref() error {
+	m := regexp.MustCompile(`startxref\s+(\d+)\s+%%EOF\s*$`).FindSubmatch(r.data)
+	if m == nil {
+		return fmt.Errorf("不是有效的 PDF: 缺少 startxref")
+	}
+	r.xref, _ = strconv.Atoi(string(m[1]))
+	if r.xref >= len(r.data) || !bytes.HasPrefix(r.data[r.xref:], []byte("xref")) {
+		return fmt.Errorf("不是有效的 PDF: 交叉引用表位置错误")
+	}
+
+	lines := strings.Split(string(r.data[r.xref:]), "\n")
+	if len(lines) < 2 {
+		return fmt.Errorf("交叉引用表不完整")
+	}
+	var first, count int
+	if _, err := fmt.Sscanf(lines[1], "%d %d", &first, &count); err != nil {
+		return fmt.Errorf("解析交叉引用表失败: %w", err)
+	}
+	for i := 0; i < count && i+2 < len(lines); i++ {
+		var offset, gen int
+		var kind string
+		if _, err := fmt.Sscanf(lines[i+2], "%d %d %s", &offset, &gen, &kind); err != nil {
+			return fmt.Errorf("解析交叉引用表失败: %w", err)
+		}
+		if kind == "n" {
+			r.objects[first+i] = offset
+		}
+	}
+	return nil
+}
+
+// trailer 返回 trailer 字典
+func (r *reader) trailer() []byte {
+	rest := r.data[r.xref:]
+	if i := bytes.Index(rest, []byte("trailer")); i >= 0 {
+		return rest[i:]
+	}
+	return nil
+}
+
+// object 返回对象的字典和（解压后的）流数据
+func (r *reader) object(id int) ([]byte, []byte, error) {
+	offset, ok := r.objects[id]
+	if !ok {
+		return nil, nil, fmt.Errorf("对象 %d 不存在", id)
+	}
+	rest := r.data[offset:]
+	header := fmt.Sprintf("%d 0 obj", id)
+	if !bytes.HasPrefix(rest, []byte(header)) {
+		return nil, nil, fmt.Errorf("对象 %d 偏移量错误", id)
+	}
+	rest = rest[len(header):]
+
+	// 找到与第一个 << 匹配的 >>
+	start := bytes.Index(rest, []byte("<<"))
+	if start < 0 {
+		return nil, nil, fmt.Errorf("对象 %d 不是字典", id)
+	}
+	depth, end := 0, -1
+	for i := start; i+1 < len(rest); i++ {
+		if rest[i] == '<' && rest[i+1] == '<' {
+			depth++
+			i++
+		} else if rest[i] == '>' && rest[i+1] == '>' {
+			depth--
+			i++
+			if depth == 0 {
+				end = i + 1
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return nil, nil, fmt.Errorf("对象 %d 字典不完整", id)
+	}
+	dict := rest[start:end]
+
+	after := bytes.TrimLeft(rest[end:], " \r\n")
+	if !bytes.HasPrefix(after, []byte("stream")) {
+		return dict, nil, nil
+	}
+	after = bytes.TrimPrefix(after[len("stream"):], []byte("\r"))
+	after = bytes.TrimPrefix(after, []byte("\n"))
+
+	m := regexp.MustCompile(`/Length (\d+)`).FindSubmatch(dict)
+	if m == nil {
+		return nil, nil, fmt.Errorf("对象 %d 缺少 Length", id)
+	}
+	length, _ := strconv.Atoi(string(m[1]))
+	if length > len(after) {
+		return nil, nil, fmt.Errorf("对象 %d 流数据不完整", id)
+	}
+	stream := after[:length]
+
+	if bytes.Contains(dict, []byte("/FlateDecode")) {
+		zr, err := zlib.NewReader(bytes.NewReader(stream))
+		if err != nil {
+			return nil, nil, fmt.Errorf("对象 %d 解压失败: %w", id, err)
+		}
+		defer zr.Close()
+		if stream, err = io.ReadAll(zr); err != nil {
+			return nil, nil, fmt.Errorf("对象 %d 解压失败: %w", id, err)
+		}
+	}
+	return dict, stream, nil
+}
+
+// pageText 提取一页的文本
+func (r *reader) pageText(id int) (string, error) {
+	pageDict, _, err := r.object(id)
+	if err != nil {
+		return "", err
+	}
+	_, content, err := r.object(refIn(pageDict, "Contents"))
+	if err != nil {
+		return "", err
+	}
+	fontDict, _, err := r.object(refIn(pageDict, "F1"))
+	if err != nil {
+		return "", err
+	}
+	_, cmap, err := r.object(refIn(fontDict, "ToUnicode"))
+	if err != nil {
+		return "", err
+	}
+	toUnicode := parseToUnicode(cmap)
+
+	var sb strings.Builder
+	var operands []string
+	lastY, size := math.NaN(), 0.0
+	for _, tok := range tokenize(content) {
+		switch tok {
+		case "Tf":
+			if len(operands) > 0 {
+				size, _ = strconv.ParseFloat(operands[len(operands)-1], 64)
+			}
+		case "Tm":
+			if len(operands) >= 6 {
+				y, _ := strconv.ParseFloat(operands[len(operands)-1], 64)
+				// 同一行的文本块高度和位置略有差异，基线相差超过半个字号才换行
+				if !math.IsNaN(lastY) && math.Abs(y-lastY) > max(1, size/2) {
+					sb.WriteString("\n")
+				}
+				lastY = y
+			}
+		case "Tj":
+			if len(operands) > 0 {
+				sb.WriteString(decodeCIDs(operands[len(operands)-1], toUnicode))
+			}
+		}
+		if isOperator(tok) {
+			operands = operands[:0]
+		} else {
+			operands = append(operands, tok)
+		}
+	}
+
+	// 每行末尾的词间空格不属于正文
+	lines := strings.Split(sb.String(), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " ")
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// tokenize 把内容流切分为记号（只处理本包生成的内容）
+func tokenize(content []byte) []string {
+	return regexp.MustCompile(`<[0-9A-Fa-f]*>|/?[^\s<>\[\]()/]+`).FindAllString(string(content), -1)
+}
+
+// isOperator 判断记号是否是操作符
+func isOperator(tok string) bool {
+	if tok == "" || tok[0] == '/' || tok[0] == '<' {
+		return false
+	}
+	_, err := strconv.ParseFloat(tok, 64)
+	return err != nil
+}
+
+// parseToUnicode 解析 ToUnicode CMap 的 bfrange 和 bfchar
+func parseToUnicode(cmap []byte) map[uint16]string {
+	m := make(map[uint16]string)
+	hexValue := func(s string) []uint16 {
+		b, _ := hex.DecodeString(strings.Trim(s, "<>"))
+		units := make([]uint16, len(b)/2)
+		for i := range units {
+			units[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+		}
+		return units
+	}
+
+	text := string(cmap)
+	for _, section := range regexp.MustCompile(`(?s)beginbfrange(.*?)endbfrange`).FindAllStringSubmatch(text, -1) {
+		for _, f := range regexp.MustCompile(`(<\w+>)\s*(<\w+>)\s*(<\w+>)`).FindAllStringSubmatch(section[1], -1) {
+			lo, hi, dst := hexValue(f[1]), hexValue(f[2]), hexValue(f[3])
+			if len(lo) != 1 || len(hi) != 1 || len(dst) != 1 {
+				continue
+			}
+			for c := uint32(lo[0]); c <= uint32(hi[0]); c++ {
+				m[uint16(c)] = string(utf16.Decode([]uint16{dst[0] + uint16(c) - lo[0]}))
+			}
+		}
+	}
+	for _, section := range regexp.MustCompile(`(?s)beginbfchar(.*?)endbfchar`).FindAllStringSubmatch(text, -1) {
+		for _, f := range regexp.MustCompile(`(<\w+>)\s*(<\w+>)`).FindAllStringSubmatch(section[1], -1) {
+			src, dst := hexValue(f[1]), hexValue(f[2])
+			if len(src) == 1 {
+				m[src[0]] = string(utf16.Decode(dst))
+			}
+		}
+	}
+	return m
+}
+
+// decodeCIDs 把 <...> 形式的 CID 串解码为文本
+func decodeCIDs(tok string, toUnicode map[uint16]string) string {
+	b, err := hex.DecodeString(strings.Trim(tok, "<>"))
+	if err != nil {
+		return ""
+	}
+	var sb strings.Builder
+	for i := 0; i+1 < len(b); i += 2 {
+		sb.WriteString(toUnicode[uint16(b[i])<<8|uint16(b[i+1])])
+	}
+	return sb.String()
+}
@@ -0,0 +1,143 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+)
+
+// 文本层使用的无字形 TrueType 字体：只有一个空的 .notdef 字形，所有字符的宽度都是 1 em。
+// 文字以不可见模式绘制，嵌入这个字体只是为了让阅读器不必查找或替换系统字体。
+
+const (
+	fontUnitsPerEm = 1000
+	fontAscent     = 800
+	fontDescent    = -200
+)
+
+// glyphlessFont 生成字体文件
+func glyphlessFont() []byte {
+	be := binary.BigEndian
+
+	head := make([]byte, 54)
+	be.PutUint32(head[0:], 0x00010000)  // version
+	be.PutUint32(head[4:], 0x00010000)  // fontRevision
+	be.PutUint32(head[12:], 0x5F0F3CF5) // magicNumber
+	be.PutUint16(head[16:], 0x000B)     // flags
+	be.PutUint16(head[18:], fontUnitsPerEm)
+	be.PutUint16(head[42:], 3) // lowestRecPPEM
+	be.PutUint16(head[44:], 2) // fontDirectionHint
+	// indexToLocFormat = 0（短偏移），glyphDataFormat = 0
+
+	descent := int16(fontDescent)
+	hhea := make([]byte, 36)
+	be.PutUint32(hhea[0:], 0x00010000)
+	be.PutUint16(hhea[4:], uint16(fontAscent))
+	be.PutUint16(hhea[6:], uint16(descent))
+	be.PutUint16(hhea[10:], fontUnitsPerEm) // advanceWidthMax
+	be.PutUint16(hhea[18:], 1)              // caretSlopeRise
+	be.PutUint16(hhea[34:], 1)              // numberOfHMetrics
+
+	maxp := make([]byte, 32)
+	be.PutUint32(maxp[0:], 0x00010000)
+	be.PutUint16(maxp[4:], 1)  // numGlyphs
+	be.PutUint16(maxp[14:], 2) // maxZones
+
+	hmtx := make([]byte, 4)
+	be.PutUint16(hmtx[0:], fontUnitsPerEm)
+
+	loca := make([]byte, 4) // .notdef 没有轮廓，两个偏移都是 0
+
+	// cmap 只有格式 4 必需的结束段（0xFFFF），不映射任何字符
+	cmap := make([]byte, 12+24)
+	be.PutUint16(cmap[2:], 1)  // numTables
+	be.PutUint16(cmap[4:], 3)  // platformID: Windows
+	be.PutUint16(cmap[6:], 1)  // encodingID: Unicode BMP
+	be.PutUint32(cmap[8:], 12) // offset
+	sub := cmap[12:]
+	be.PutUint16(sub[0:], 4)       // format
+	be.PutUint16(sub[2:], 24)      // length
+	be.PutUint16(sub[6:], 2)       // segCountX2
+	be.PutUint16(sub[8:], 2)       // searchRange
+	be.PutUint16(sub[14:], 0xFFFF) // endCode
+	be.PutUint16(sub[18:], 0xFFFF) // startCode
+	be.PutUint16(sub[20:], 1)      // idDelta
+
+	post := make([]byte, 32)
+	be.PutUint32(post[0:], 0x00030000) // 格式 3：不包含字形名称
+
+	tables := map[string][]byte{
+		"head": head,
+		"hhea": hhea,
+		"hmtx": hmtx,
+		"loca": loca,
+		"maxp": maxp,
+		"glyf": {},
+		"cmap": cmap,
+		"post": post,
+	}
+	return buildSFNT(tables)
+}
+
+// buildSFNT 按 TrueType 文件格式组装字体表
+func buildSFNT(tables map[string][]byte) []byte {
+	be := binary.BigEndian
+
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	numTables := len(tags)
+	searchRange, entrySelector := 1, 0
+	for searchRange*2 <= numTables {
+		searchRange *= 2
+		entrySelector++
+	}
+	searchRange *= 16
+
+	header := make([]byte, 12+16*numTables)
+	be.PutUint32(header[0:], 0x00010000)
+	be.PutUint16(header[4:], uint16(numTables))
+	be.PutUint16(header[6:], uint16(searchRange))
+	be.PutUint16(header[8:], uint16(entrySelector))
+	be.PutUint16(header[10:], uint16(numTables*16-searchRange))
+
+	var body bytes.Buffer
+	offset := len(header)
+	headOffset := 0
+	for i, tag := range tags {
+		data := tables[tag]
+		if tag == "head" {
+			headOffset = offset
+		}
+		entry := header[12+16*i:]
+		copy(entry[0:4], tag)
+		be.PutUint32(entry[4:], sfntChecksum(data))
+		be.PutUint32(entry[8:], uint32(offset))
+		be.PutUint32(entry[12:], uint32(len(data)))
+
+		body.Write(data)
+		for body.Len()%4 != 0 {
+			body.WriteByte(0)
+		}
+		offset = len(header) + body.Len()
+	}
+
+	font := append(header, body.Bytes()...)
+	// head.checkSumAdjustment 使整个文件的校验和等于 0xB1B0AFBA
+	be.PutUint32(font[headOffset+8:], 0xB1B0AFBA-sfntChecksum(font))
+	return font
+}
+
+// sfntChecksum 计算表校验和（按 4 字节大端整数求和）
+func sfntChecksum(data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		var word [4]byte
+		copy(word[:], data[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return sum
+}
@@ -0,0 +1,434 @@
+// Package pdf 把截图和 OCR 结果生成可搜索的 PDF：
+// 截图作为页面背景，识别出的文字按 TextBlock 的坐标以不可见模式（3 Tr）绘制在对应位置，
+// 在任意 PDF 阅读器中都可以搜索和选中。纯 Go 实现，不依赖 cgo 和外部工具。
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"screenocr-wails/internal/ocr"
+)
+
+// ImageFormat 页面图片的编码方式
+type ImageFormat int
+
+const (
+	Lossless ImageFormat = iota // Flate 压缩的 RGB 像素，无损
+	JPEG                        // JPEG（DCTDecode），体积更小
+)
+
+// Options PDF 生成选项
+type Options struct {
+	Format  ImageFormat // 图片编码方式
+	Quality int         // JPEG 质量 1-100，默认 85
+	DPI     float64     // 截图分辨率，决定页面的物理尺寸，默认 96
+	Title   string      // 文档标题（可选）
+}
+
+// Document 多页 PDF 文档，每页是一张截图及其文本层
+type Document struct {
+	opts  Options
+	pages []page
+	extra map[rune]uint16 // BMP 之外的字符 → 分配的 CID
+}
+
+// page 已编码的页面
+type page struct {
+	width      float64 // 页面宽度（点）
+	height     float64 // 页面高度（点）
+	pixelsW    int
+	pixelsH    int
+	image      []byte
+	filter     string
+	colorSpace string
+	content    []byte // 页面内容流（未压缩）
+}
+
+// extraCIDBase BMP 之外的字符使用 UTF-16 代理区的 CID（代理区本身不对应任何字符）
+const extraCIDBase = 0xD800
+
+// NewDocument 创建空文档
+func NewDocument(opts Options) *Document {
+	if opts.Quality <= 0 || opts.Quality > 100 {
+		opts.Quality = 85
+	}
+	if opts.DPI <= 0 {
+		opts.DPI = 96
+	}
+	return &Document{opts: opts, extra: make(map[rune]uint16)}
+}
+
+// PageCount 返回页数
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// AddPage 添加一页：img 为页面背景，blocks 的坐标相对于 img 的左上角
+// 图片在添加时即完成编码，调用方之后可以复用 img
+func (d *Document) AddPage(img image.Image, blocks []ocr.TextBlock) error {
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return fmt.Errorf("图片为空")
+	}
+
+	scale := 72 / d.opts.DPI
+	p := page{
+		width:   float64(bounds.Dx()) * scale,
+		height:  float64(bounds.Dy()) * scale,
+		pixelsW: bounds.Dx(),
+		pixelsH: bounds.Dy(),
+	}
+
+	var err error
+	switch d.opts.Format {
+	case JPEG:
+		err = p.encodeJPEG(img, d.opts.Quality)
+	default:
+		err = p.encodeLossless(img)
+	}
+	if err != nil {
+		return err
+	}
+
+	var content bytes.Buffer
+	fmt.Fprintf(&content, "q %s 0 0 %s 0 0 cm /Im0 Do Q\n", num(p.width), num(p.height))
+	d.writeTextLayer(&content, blocks, scale, p.height)
+	p.content = content.Bytes()
+
+	d.pages = append(d.pages, p)
+	return nil
+}
+
+// encodeLossless 以 Flate 压缩的 RGB 像素编码图片
+func (p *page) encodeLossless(img image.Image) error {
+	bounds := img.Bounds()
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	row := make([]byte, bounds.Dx()*3)
+
+	if rgba, ok := img.(*image.RGBA); ok {
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			src := rgba.Pix[rgba.PixOffset(bounds.Min.X, y):]
+			for x := 0; x < bounds.Dx(); x++ {
+				copy(row[x*3:x*3+3], src[x*4:x*4+3])
+			}
+			zw.Write(row)
+		}
+	} else {
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				r, g, b, _ := img.At(x, y).RGBA()
+				i := (x - bounds.Min.X) * 3
+				row[i], row[i+1], row[i+2] = byte(r>>8), byte(g>>8), byte(b>>8)
+			}
+			zw.Write(row)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("压缩图片失败: %w", err)
+	}
+
+	p.image = buf.Bytes()
+	p.filter = "FlateDecode"
+	p.colorSpace = "DeviceRGB"
+	return nil
+}
+
+// encodeJPEG 以 JPEG 编码图片
+func (p *page) encodeJPEG(img image.Image, quality int) error {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return fmt.Errorf("JPEG 编码失败: %w", err)
+	}
+
+	p.image = buf.Bytes()
+	p.filter = "DCTDecode"
+	p.colorSpace = "DeviceRGB"
+	if _, ok := img.(*image.Gray); ok {
+		p.colorSpace = "DeviceGray"
+	}
+	return nil
+}
+
+// writeTextLayer 生成不可见文本层
+// 每个文本块按自身高度设置字号、按宽度设置水平缩放（Tz），使选中区域与截图上的文字重合；
+// 同一行相邻文本块之间补一个空格（中日韩文字之间除外），让复制出的文本保留词间距
+func (d *Document) writeTextLayer(w *bytes.Buffer, blocks []ocr.TextBlock, scale, pageHeight float64) {
+	lines := ocr.GroupLines(blocks)
+	if len(lines) == 0 {
+		return
+	}
+
+	w.WriteString("BT\n3 Tr\n")
+	for _, line := range lines {
+		for i, blk := range line.Blocks {
+			text := strings.TrimSpace(blk.Text)
+			if text == "" || blk.Width <= 0 || blk.Height <= 0 {
+				continue
+			}
+
+			width := blk.Width
+			if i+1 < len(line.Blocks) {
+				next := line.Blocks[i+1]
+				if needsSpace(text, next.Text) {
+					text += " "
+					width = max(width, next.X-blk.X)
+				}
+			}
+
+			size := float64(blk.Height) * scale
+			x := float64(blk.X) * scale
+			// 基线位于文本块底部之上 descent 处，使字形框（descent 到 ascent）正好覆盖文本块
+			y := pageHeight - float64(blk.Y+blk.Height)*scale - size*fontDescent/fontUnitsPerEm
+			tz := 100 * float64(width) * scale / (float64(utf8.RuneCountInString(text)) * size)
+
+			fmt.Fprintf(w, "/F1 %s Tf %s Tz 1 0 0 1 %s %s Tm <%s> Tj\n",
+				num(size), num(tz), num(x), num(y), d.encodeText(text))
+		}
+	}
+	w.WriteString("ET\n")
+}
+
+// needsSpace 判断同一行相邻两个文本块之间是否需要空格
+func needsSpace(a, b string) bool {
+	last, _ := utf8.DecodeLastRuneInString(a)
+	first, _ := utf8.DecodeRuneInString(strings.TrimSpace(b))
+	if first == utf8.RuneError {
+		return false
+	}
+	return !(ocr.DetectScript(string(last)) == ocr.ScriptCJK && ocr.DetectScript(string(first)) == ocr.ScriptCJK)
+}
+
+// encodeText 把文本编码为 Identity-H 的 CID 十六进制串：BMP 字符的 CID 即其码位
+func (d *Document) encodeText(text string) string {
+	var sb strings.Builder
+	for _, r := range text {
+		cid := uint16(0xFFFD)
+		switch {
+		case r < 0x10000 && !utf16.IsSurrogate(r):
+			cid = uint16(r)
+		case r >= 0x10000 && r <= utf8.MaxRune:
+			c, ok := d.extra[r]
+			if !ok && len(d.extra) < 0x800 {
+				c = extraCIDBase + uint16(len(d.extra))
+				d.extra[r] = c
+				ok = true
+			}
+			if ok {
+				cid = c
+			}
+		}
+		fmt.Fprintf(&sb, "%04X", cid)
+	}
+	return sb.String()
+}
+
+// toUnicodeCMap 生成 CID → Unicode 的映射，供阅读器复制和搜索文字
+func (d *Document) toUnicodeCMap() []byte {
+	var b bytes.Buffer
+	b.WriteString(`/CIDInit /ProcSet findresource begin
+12 dict begin
+begincmap
+/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def
+/CMapName /Adobe-Identity-UCS def
+/CMapType 2 def
+1 begincodespacerange
+<0000> <FFFF>
+endcodespacerange
+`)
+
+	// bfrange 的起止编码只能在最后一个字节不同，按高字节分成 256 段（跳过代理区）
+	var ranges []string
+	for hi := 0; hi < 0x100; hi++ {
+		if hi >= 0xD8 && hi <= 0xDF {
+			continue
+		}
+		ranges = append(ranges, fmt.Sprintf("<%02X00> <%02XFF> <%02X00>", hi, hi, hi))
+	}
+	for len(ranges) > 0 {
+		n := min(len(ranges), 100)
+		fmt.Fprintf(&b, "%d beginbfrange\n%s\nendbfrange\n", n, strings.Join(ranges[:n], "\n"))
+		ranges = ranges[n:]
+	}
+
+	var chars []string
+	for r, cid := range d.extra {
+		pair := utf16.Encode([]rune{r})
+		chars = append(chars, fmt.Sprintf("<%04X> <%04X%04X>", cid, pair[0], pair[1]))
+	}
+	for len(chars) > 0 {
+		n := min(len(chars), 100)
+		fmt.Fprintf(&b, "%d beginbfchar\n%s\nendbfchar\n", n, strings.Join(chars[:n], "\n"))
+		chars = chars[n:]
+	}
+
+	b.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return b.Bytes()
+}
+
+// WriteTo 输出 PDF 文件
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		return 0, fmt.Errorf("文档没有页面")
+	}
+
+	pw := &writer{w: w}
+	pw.writeString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+
+	// 对象编号: 1 目录, 2 页面树, 3-8 字体, 9 文档信息, 之后每页 3 个对象（页面、内容、图片）
+	const (
+		catalogObj = iota + 1
+		pagesObj
+		fontObj
+		cidFontObj
+		descriptorObj
+		fontFileObj
+		toUnicodeObj
+		cidToGIDObj
+		infoObj
+		firstPageObj
+	)
+
+	pw.object(catalogObj, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesObj))
+
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPageObj+i*3)
+	}
+	pw.object(pagesObj, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+
+	pw.object(fontObj, fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /GlyphLessFont /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+		cidFontObj, toUnicodeObj))
+	pw.object(cidFontObj, fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /GlyphLessFont /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /DW %d /CIDToGIDMap %d 0 R >>",
+		descriptorObj, fontUnitsPerEm, cidToGIDObj))
+	pw.object(descriptorObj, fmt.Sprintf("<< /Type /FontDescriptor /FontName /GlyphLessFont /Flags 5 /FontBBox [0 %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+		fontDescent, fontUnitsPerEm, fontAscent, fontAscent, fontDescent, fontAscent, fontFileObj))
+
+	font := glyphlessFont()
+	pw.stream(fontFileObj, fmt.Sprintf("/Length1 %d", len(font)), font, true)
+	pw.stream(toUnicodeObj, "", d.toUnicodeCMap(), true)
+	// 所有 CID 都映射到 0 号字形
+	pw.stream(cidToGIDObj, "", make([]byte, 0x10000*2), true)
+
+	title := d.opts.Title
+	if title == "" {
+		title = "ScreenOCR"
+	}
+	pw.object(infoObj, fmt.Sprintf("<< /Title %s /Producer (ScreenOCR) >>", textString(title)))
+
+	for i, p := range d.pages {
+		pageObj := firstPageObj + i*3
+		contentObj, imageObj := pageObj+1, pageObj+2
+
+		pw.object(pageObj, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources << /XObject << /Im0 %d 0 R >> /Font << /F1 %d 0 R >> >> /Contents %d 0 R >>",
+			pagesObj, num(p.width), num(p.height), imageObj, fontObj, contentObj))
+		pw.stream(contentObj, "", p.content, true)
+		pw.stream(imageObj, fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /%s /BitsPerComponent 8 /Filter /%s",
+			p.pixelsW, p.pixelsH, p.colorSpace, p.filter), p.image, false)
+	}
+
+	xref := pw.n
+	fmt.Fprintf(pw, "xref\n0 %d\n0000000000 65535 f \n", len(pw.offsets)+1)
+	for _, off := range pw.offsets {
+		fmt.Fprintf(pw, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(pw, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(pw.offsets)+1, catalogObj, infoObj, xref)
+
+	return pw.n, pw.err
+}
+
+// Save 写入文件
+func (d *Document) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("创建 PDF 文件失败: %w", err)
+	}
+	if _, err := d.WriteTo(f); err != nil {
+		f.Close()
+		return fmt.Errorf("写入 PDF 失败: %w", err)
+	}
+	return f.Close()
+}
+
+// Write 生成单页 PDF
+func Write(w io.Writer, img image.Image, blocks []ocr.TextBlock, opts Options) error {
+	doc := NewDocument(opts)
+	if err := doc.AddPage(img, blocks); err != nil {
+		return err
+	}
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// writer 记录对象偏移量的输出器，对象需按编号顺序写入
+type writer struct {
+	w       io.Writer
+	n       int64
+	offsets []int64
+	err     error
+}
+
+func (pw *writer) Write(p []byte) (int, error) {
+	if pw.err != nil {
+		return 0, pw.err
+	}
+	n, err := pw.w.Write(p)
+	pw.n += int64(n)
+	pw.err = err
+	return n, err
+}
+
+func (pw *writer) writeString(s string) {
+	io.WriteString(pw, s)
+}
+
+// object 写入普通对象
+func (pw *writer) object(id int, body string) {
+	pw.offsets = append(pw.offsets, pw.n)
+	fmt.Fprintf(pw, "%d 0 obj\n%s\nendobj\n", id, body)
+}
+
+// stream 写入流对象，compress 为 true 时使用 Flate 压缩
+func (pw *writer) stream(id int, dict string, data []byte, compress bool) {
+	if compress {
+		var buf bytes.Buffer
+		zw := zlib.NewWriter(&buf)
+		zw.Write(data)
+		zw.Close()
+		data = buf.Bytes()
+		dict = strings.TrimSpace(dict + " /Filter /FlateDecode")
+	}
+
+	pw.offsets = append(pw.offsets, pw.n)
+	fmt.Fprintf(pw, "%d 0 obj\n<< %s /Length %d >>\nstream\n", id, dict, len(data))
+	pw.Write(data)
+	pw.writeString("\nendstream\nendobj\n")
+}
+
+// num 格式化数字（最多两位小数）
+func num(v float64) string {
+	s := fmt.Sprintf("%.2f", v)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// textString 把文本编码为 UTF-16BE 的 PDF 文本字符串
+func textString(s string) string {
+	var sb strings.Builder
+	sb.WriteString("<FEFF")
+	for _, u := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&sb, "%04X", u)
+	}
+	sb.WriteString(">")
+	return sb.String()
+}
@@ -0,0 +1,149 @@
+package pdf
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"screenocr-wails/internal/ocr"
+)
+
+// testImage 白底图片
+func testImage(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	return img
+}
+
+func TestWriteExtractRoundTrip(t *testing.T) {
+	cases := []struct {
+		name   string
+		blocks []ocr.TextBlock
+		want   string
+	}{
+		{
+			name:   "单个文本块",
+			blocks: []ocr.TextBlock{{Text: "Hello", X: 10, Y: 10, Width: 60, Height: 20}},
+			want:   "Hello",
+		},
+		{
+			name: "同一行的英文补空格",
+			blocks: []ocr.TextBlock{
+				{Text: "World", X: 80, Y: 12, Width: 60, Height: 20},
+				{Text: "Hello", X: 10, Y: 10, Width: 60, Height: 20},
+			},
+			want: "Hello World",
+		},
+		{
+			name: "中文之间不补空格",
+			blocks: []ocr.TextBlock{
+				{Text: "屏幕", X: 10, Y: 10, Width: 40, Height: 20},
+				{Text: "文字", X: 55, Y: 10, Width: 40, Height: 20},
+				{Text: "OCR", X: 100, Y: 10, Width: 40, Height: 20},
+			},
+			want: "屏幕文字 OCR",
+		},
+		{
+			name: "多行",
+			blocks: []ocr.TextBlock{
+				{Text: "第一行", X: 10, Y: 10, Width: 60, Height: 20},
+				{Text: "Second line", X: 10, Y: 50, Width: 120, Height: 20},
+				{Text: "third", X: 10, Y: 90, Width: 50, Height: 20},
+			},
+			want: "第一行\nSecond line\nthird",
+		},
+		{
+			name: "BMP 之外的字符",
+			blocks: []ocr.TextBlock{
+				{Text: "Emoji 😀", X: 10, Y: 10, Width: 80, Height: 20},
+				{Text: "𠮷野家", X: 10, Y: 50, Width: 60, Height: 20},
+			},
+			want: "Emoji 😀\n𠮷野家",
+		},
+		{
+			name: "跳过空文本和无效尺寸",
+			blocks: []ocr.TextBlock{
+				{Text: "  ", X: 10, Y: 10, Width: 60, Height: 20},
+				{Text: "zero", X: 10, Y: 50, Width: 0, Height: 20},
+				{Text: "kept", X: 10, Y: 90, Width: 50, Height: 20},
+			},
+			want: "kept",
+		},
+		{
+			name: "没有文字",
+			want: "",
+		},
+	}
+
+	for _, format := range []ImageFormat{Lossless, JPEG} {
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				var buf bytes.Buffer
+				if err := Write(&buf, testImage(200, 120), tc.blocks, Options{Format: format, Title: tc.name}); err != nil {
+					t.Fatalf("生成 PDF 失败: %v", err)
+				}
+				pages, err := ExtractText(buf.Bytes())
+				if err != nil {
+					t.Fatalf("提取文本层失败: %v", err)
+				}
+				if len(pages) != 1 {
+					t.Fatalf("期望 1 页，实际 %d 页", len(pages))
+				}
+				if pages[0] != tc.want {
+					t.Errorf("文本层为 %q，期望 %q", pages[0], tc.want)
+				}
+			})
+		}
+	}
+}
+
+func TestDocumentSaveMultiPage(t *testing.T) {
+	doc := NewDocument(Options{DPI: 144})
+	pages := []struct {
+		blocks []ocr.TextBlock
+		want   string
+	}{
+		{[]ocr.TextBlock{{Text: "Page one", X: 5, Y: 5, Width: 80, Height: 16}}, "Page one"},
+		{[]ocr.TextBlock{{Text: "第二页", X: 5, Y: 5, Width: 48, Height: 16}}, "第二页"},
+		{nil, ""},
+	}
+	for _, p := range pages {
+		if err := doc.AddPage(testImage(160, 90), p.blocks); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if doc.PageCount() != len(pages) {
+		t.Fatalf("页数为 %d，期望 %d", doc.PageCount(), len(pages))
+	}
+
+	path := filepath.Join(t.TempDir(), "out.pdf")
+	if err := doc.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	texts, err := ExtractText(data)
+	if err != nil {
+		t.Fatalf("提取文本层失败: %v", err)
+	}
+	if len(texts) != len(pages) {
+		t.Fatalf("提取到 %d 页，期望 %d 页", len(texts), len(pages))
+	}
+	for i, p := range pages {
+		if texts[i] != p.want {
+			t.Errorf("第 %d 页文本层为 %q，期望 %q", i+1, texts[i], p.want)
+		}
+	}
+}
+
+func TestExtractTextRejectsGarbage(t *testing.T) {
+	if _, err := ExtractText([]byte("not a pdf")); err == nil {
+		t.Error("非 PDF 数据应返回错误")
+	}
+}
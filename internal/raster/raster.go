@@ -0,0 +1,258 @@
+// Package raster 提供 *image.RGBA 上的像素格式转换与合成
+//
+// 截图（GDI 输出 BGRA）、图像预处理和覆盖层绘制（自底向上的 BGRA DIB）都需要逐像素处理整屏图像，
+// 这里把这些循环集中实现：直接操作 Pix 切片、使用整数运算，不分配内存，大图可按行分块并行。
+// BGRA 缓冲区用 FromBGRA 包装成 *image.RGBA 后即可使用同一组函数，通道顺序由调用方通过 SwapRB 指定。
+package raster
+
+import (
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/draw"
+	"runtime"
+	"sync"
+)
+
+// Op 复制或混合时附带的转换
+type Op uint8
+
+const (
+	SwapRB   Op = 1 << iota // 交换 R、B 通道（RGBA ↔ BGRA）
+	FlipV                   // 垂直翻转
+	Opaque                  // alpha 置为 255
+	Parallel                // 按行分块并行处理（小图自动退化为单线程）
+)
+
+// parallelMinPixels 并行处理的最小像素数，更小的图片启动 goroutine 得不偿失
+const parallelMinPixels = 256 * 1024
+
+// FromBGRA 把 BGRA 像素缓冲区包装为 *image.RGBA（共享内存，不复制）
+// 包装后 Pix 中的通道顺序仍是 B、G、R、A，与 RGBA 图像互相转换时使用 SwapRB
+func FromBGRA(pix []byte, width, height, stride int) *image.RGBA {
+	return &image.RGBA{Pix: pix, Stride: stride, Rect: image.Rect(0, 0, width, height)}
+}
+
+// ToRGBA 返回 img 的 *image.RGBA 形式：已经是 *image.RGBA 时直接返回，否则转换为新图像
+func ToRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok {
+		return rgba
+	}
+	rgba := image.NewRGBA(img.Bounds())
+	draw.Draw(rgba, rgba.Rect, img, rgba.Rect.Min, draw.Src)
+	return rgba
+}
+
+// SwapRBColor 交换颜色的 R、B 分量，用于在 BGRA 缓冲区上混合颜色
+func SwapRBColor(c color.RGBA) color.RGBA {
+	return color.RGBA{R: c.B, G: c.G, B: c.R, A: c.A}
+}
+
+// Copy 把 src 复制到 dst 并按 op 转换，dst 与 src 尺寸必须相同（各自的 Rect 起点可以不同）
+// dst 可以与 src 是同一图像（原地转换，包括原地翻转）
+func Copy(dst, src *image.RGBA, op Op) {
+	process(dst, src, op, func(d, s []byte) {
+		copyRow(d, s, op)
+	})
+}
+
+// Blend 把 src 与颜色 c 按 c.A 做 alpha 混合后写入 dst，并按 op 转换
+// c 为非预乘颜色，按 src 的通道顺序解释；混合结果四舍五入，alpha 通道保持 src 的值（除非指定 Opaque）
+func Blend(dst, src *image.RGBA, c color.RGBA, op Op) {
+	if c.A == 0 {
+		Copy(dst, src, op)
+		return
+	}
+	tr, tg, tb := newBlender(c).tables()
+	process(dst, src, op, func(d, s []byte) {
+		mapRow(d, s, &tr, &tg, &tb, op)
+	})
+}
+
+// BlendRect 在 img 的矩形区域内原地混合颜色 c，矩形会裁剪到图像范围内
+func BlendRect(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	r = r.Intersect(img.Rect)
+	if r.Empty() || c.A == 0 {
+		return
+	}
+	k := newBlender(c)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		i := img.PixOffset(r.Min.X, y)
+		row := img.Pix[i : i+r.Dx()*4]
+		k.blendRow(row)
+	}
+}
+
+// LUT 对 src 的 R、G、B 通道做查表映射后写入 dst（alpha 不变），并按 op 转换
+func LUT(dst, src *image.RGBA, table *[256]uint8, op Op) {
+	process(dst, src, op, func(d, s []byte) {
+		lutRow(d, s, table, op)
+	})
+}
+
+// process 按行处理 src → dst，负责翻转、原地翻转和并行分块
+func process(dst, src *image.RGBA, op Op, row func(d, s []byte)) {
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	if dst.Rect.Dx() != w || dst.Rect.Dy() != h {
+		panic("raster: dst 与 src 尺寸不同")
+	}
+	if w == 0 || h == 0 {
+		return
+	}
+
+	rowOf := func(img *image.RGBA, y int) []byte {
+		i := img.PixOffset(img.Rect.Min.X, img.Rect.Min.Y+y)
+		return img.Pix[i : i+w*4 : i+w*4]
+	}
+	flip := op&FlipV != 0
+	inPlace := &dst.Pix[dst.PixOffset(dst.Rect.Min.X, dst.Rect.Min.Y)] == &src.Pix[src.PixOffset(src.Rect.Min.X, src.Rect.Min.Y)]
+
+	rows := func(y0, y1 int) {
+		for y := y0; y < y1; y++ {
+			if !flip {
+				row(rowOf(dst, y), rowOf(src, y))
+				continue
+			}
+			if !inPlace {
+				row(rowOf(dst, h-1-y), rowOf(src, y))
+				continue
+			}
+			// 原地翻转：成对处理第 y 行和第 h-1-y 行
+			a, b := rowOf(src, y), rowOf(src, h-1-y)
+			row(a, a)
+			if y != h-1-y {
+				row(b, b)
+				swapRows(a, b)
+			}
+		}
+	}
+
+	n := h
+	if flip && inPlace {
+		n = (h + 1) / 2
+	}
+	if op&Parallel == 0 || w*h < parallelMinPixels {
+		rows(0, n)
+		return
+	}
+	parallel(n, rows)
+}
+
+// parallel 把 [0, n) 分块交给多个 goroutine 执行
+func parallel(n int, fn func(y0, y1 int)) {
+	workers := min(runtime.GOMAXPROCS(0), n)
+	if workers <= 1 {
+		fn(0, n)
+		return
+	}
+	var wg sync.WaitGroup
+	chunk := (n + workers - 1) / workers
+	for y0 := 0; y0 < n; y0 += chunk {
+		y1 := min(y0+chunk, n)
+		wg.Add(1)
+		go func(y0, y1 int) {
+			defer wg.Done()
+			fn(y0, y1)
+		}(y0, y1)
+	}
+	wg.Wait()
+}
+
+// swapRows 交换两行像素（不分配内存）
+func swapRows(a, b []byte) {
+	var tmp [4096]byte
+	for len(a) > 0 {
+		n := copy(tmp[:], a)
+		copy(a, b[:n])
+		copy(b, tmp[:n])
+		a, b = a[n:], b[n:]
+	}
+}
+
+// copyRow 复制一行像素并交换通道/置不透明
+func copyRow(d, s []byte, op Op) {
+	d = d[:len(s)]
+	if op&SwapRB == 0 {
+		copy(d, s)
+		if op&Opaque != 0 {
+			for i := 3; i < len(d); i += 4 {
+				d[i] = 255
+			}
+		}
+		return
+	}
+
+	var alpha uint32
+	if op&Opaque != 0 {
+		alpha = 0xff000000
+	}
+	for i := 0; i+4 <= len(s); i += 4 {
+		v := binary.LittleEndian.Uint32(s[i : i+4 : i+4])
+		v = v&0xff00ff00 | v>>16&0xff | v&0xff<<16 | alpha
+		binary.LittleEndian.PutUint32(d[i:i+4:i+4], v)
+	}
+}
+
+// mapRow 按通道查表映射一行像素（alpha 不查表），并交换通道/置不透明
+func mapRow(d, s []byte, tr, tg, tb *[256]uint8, op Op) {
+	d = d[:len(s)]
+	var alpha byte
+	if op&Opaque != 0 {
+		alpha = 255
+	}
+	if op&SwapRB != 0 {
+		for i := 0; i+4 <= len(s); i += 4 {
+			p := s[i : i+4 : i+4]
+			q := d[i : i+4 : i+4]
+			q[0], q[1], q[2], q[3] = tb[p[2]], tg[p[1]], tr[p[0]], p[3]|alpha
+		}
+		return
+	}
+	for i := 0; i+4 <= len(s); i += 4 {
+		p := s[i : i+4 : i+4]
+		q := d[i : i+4 : i+4]
+		q[0], q[1], q[2], q[3] = tr[p[0]], tg[p[1]], tb[p[2]], p[3]|alpha
+	}
+}
+
+// lutRow 查表映射一行像素
+func lutRow(d, s []byte, t *[256]uint8, op Op) {
+	mapRow(d, s, t, t, t, op)
+}
+
+// blender 与固定颜色混合：out = (s*(255-a) + c*a) / 255，四舍五入
+type blender struct {
+	inv     uint32
+	r, g, b uint32 // 预先乘好 alpha 的颜色分量
+}
+
+func newBlender(c color.RGBA) blender {
+	a := uint32(c.A)
+	return blender{inv: 255 - a, r: uint32(c.R) * a, g: uint32(c.G) * a, b: uint32(c.B) * a}
+}
+
+// div255 整数除以 255 并四舍五入（v <= 255*255）
+func div255(v uint32) uint8 {
+	v += 128
+	return uint8((v + v>>8) >> 8)
+}
+
+// tables 生成混合查找表，整幅图像混合时查表比逐像素计算更快
+func (k blender) tables() (tr, tg, tb [256]uint8) {
+	for v := uint32(0); v < 256; v++ {
+		tr[v] = div255(v*k.inv + k.r)
+		tg[v] = div255(v*k.inv + k.g)
+		tb[v] = div255(v*k.inv + k.b)
+	}
+	return
+}
+
+// blendRow 原地混合一行中的小段像素（高亮矩形通常很小，直接计算比建表快）
+func (k blender) blendRow(row []byte) {
+	for i := 0; i+4 <= len(row); i += 4 {
+		p := row[i : i+4 : i+4]
+		p[0] = div255(uint32(p[0])*k.inv + k.r)
+		p[1] = div255(uint32(p[1])*k.inv + k.g)
+		p[2] = div255(uint32(p[2])*k.inv + k.b)
+	}
+}
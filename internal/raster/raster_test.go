@@ -0,0 +1,474 @@
+package raster
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+)
+
+// 与改用 raster 包之前的浮点实现相比允许的最大通道差值：
+// 原实现对混合结果截断取整，raster 四舍五入，单次混合最多相差 1；
+// 高亮矩形相互重叠时同一像素会混合多次，误差累积，最多相差 2
+const (
+	legacyBlendTolerance     = 1
+	legacyHighlightTolerance = 2
+)
+
+// randomImage 像素值随机（含各种 alpha）的图像，origin 为 Rect 的左上角
+func randomImage(w, h int, origin image.Point, seed int64) *image.RGBA {
+	img := image.NewRGBA(image.Rectangle{Min: origin, Max: origin.Add(image.Pt(w, h))})
+	rand.New(rand.NewSource(seed)).Read(img.Pix)
+	return img
+}
+
+// subImage 放在更大图像中间的子图：Stride 大于行宽，Pix 不从 0 开始
+func subImage(w, h int, seed int64) *image.RGBA {
+	big := randomImage(w+7, h+5, image.Pt(-3, 11), seed)
+	return big.SubImage(image.Rect(0, 13, w, 13+h)).(*image.RGBA)
+}
+
+func cloneRGBA(img *image.RGBA) *image.RGBA {
+	out := *img
+	out.Pix = bytes.Clone(img.Pix)
+	return &out
+}
+
+// golden 逐像素计算期望输出：pixel 处理单个像素（按 src 的通道顺序），再按 op 交换通道、置不透明和翻转
+func golden(src *image.RGBA, op Op, pixel func(p [4]byte) [4]byte) *image.RGBA {
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	out := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := src.PixOffset(src.Rect.Min.X+x, src.Rect.Min.Y+y)
+			q := pixel([4]byte(src.Pix[i : i+4]))
+			if op&SwapRB != 0 {
+				q[0], q[2] = q[2], q[0]
+			}
+			if op&Opaque != 0 {
+				q[3] = 255
+			}
+			dy := y
+			if op&FlipV != 0 {
+				dy = h - 1 - y
+			}
+			copy(out.Pix[out.PixOffset(x, dy):], q[:])
+		}
+	}
+	return out
+}
+
+// roundBlend 精确的四舍五入混合 (s*(255-a) + c*a) / 255
+func roundBlend(s, c, a byte) byte {
+	v := int(s)*(255-int(a)) + int(c)*int(a)
+	return byte((2*v + 255) / 510)
+}
+
+// maxDiff 比较两幅同尺寸图像的像素，返回最大通道差值
+func maxDiff(t *testing.T, got, want *image.RGBA) int {
+	t.Helper()
+	w, h := want.Rect.Dx(), want.Rect.Dy()
+	if got.Rect.Dx() != w || got.Rect.Dy() != h {
+		t.Fatalf("尺寸 %v，期望 %v", got.Rect.Size(), want.Rect.Size())
+	}
+	diff := 0
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			g := got.Pix[got.PixOffset(got.Rect.Min.X+x, got.Rect.Min.Y+y):]
+			e := want.Pix[want.PixOffset(want.Rect.Min.X+x, want.Rect.Min.Y+y):]
+			for c := 0; c < 4; c++ {
+				diff = max(diff, abs(int(g[c])-int(e[c])))
+			}
+		}
+	}
+	return diff
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// opName 测试名称中的 op
+func opName(op Op) string {
+	if op == 0 {
+		return "none"
+	}
+	var s string
+	for _, f := range []struct {
+		op   Op
+		name string
+	}{{SwapRB, "SwapRB"}, {FlipV, "FlipV"}, {Opaque, "Opaque"}, {Parallel, "Parallel"}} {
+		if op&f.op != 0 {
+			if s != "" {
+				s += "|"
+			}
+			s += f.name
+		}
+	}
+	return s
+}
+
+// runGolden 在各种尺寸、原点和原地 / 另写 dst 的组合上核对 apply 的输出与 golden 完全一致
+func runGolden(t *testing.T, apply func(dst, src *image.RGBA, op Op), pixel func(p [4]byte) [4]byte) {
+	ops := []Op{0, SwapRB, Opaque, FlipV, SwapRB | Opaque, SwapRB | FlipV | Opaque, FlipV | Parallel, SwapRB | Opaque | Parallel}
+	sources := []struct {
+		name string
+		img  func() *image.RGBA
+	}{
+		{"奇数行", func() *image.RGBA { return randomImage(37, 23, image.Point{}, 1) }},
+		{"偶数行", func() *image.RGBA { return randomImage(16, 24, image.Point{}, 2) }},
+		{"单行", func() *image.RGBA { return randomImage(9, 1, image.Point{}, 3) }},
+		{"负原点", func() *image.RGBA { return randomImage(20, 15, image.Pt(-1920, -40), 4) }},
+		{"子图", func() *image.RGBA { return subImage(21, 13, 5) }},
+		{"并行大图", func() *image.RGBA { return randomImage(640, 481, image.Point{}, 6) }},
+	}
+
+	for _, src := range sources {
+		for _, op := range ops {
+			t.Run(src.name+"/"+opName(op), func(t *testing.T) {
+				s := src.img()
+				want := golden(s, op, pixel)
+
+				dst := image.NewRGBA(image.Rect(5, -2, 5+s.Rect.Dx(), -2+s.Rect.Dy()))
+				apply(dst, s, op)
+				if d := maxDiff(t, dst, want); d != 0 {
+					t.Errorf("另写 dst: 与期望输出最多相差 %d", d)
+				}
+
+				inPlace := cloneRGBA(s)
+				apply(inPlace, inPlace, op)
+				if d := maxDiff(t, inPlace, want); d != 0 {
+					t.Errorf("原地处理: 与期望输出最多相差 %d", d)
+				}
+			})
+		}
+	}
+}
+
+func TestCopyGolden(t *testing.T) {
+	runGolden(t, Copy, func(p [4]byte) [4]byte { return p })
+}
+
+func TestBlendGolden(t *testing.T) {
+	for _, c := range []color.RGBA{{255, 255, 255, 100}, {77, 148, 255, 77}, {0, 0, 0, 255}, {12, 200, 99, 1}} {
+		t.Run(fmt.Sprint(c), func(t *testing.T) {
+			runGolden(t, func(dst, src *image.RGBA, op Op) { Blend(dst, src, c, op) }, func(p [4]byte) [4]byte {
+				return [4]byte{roundBlend(p[0], c.R, c.A), roundBlend(p[1], c.G, c.A), roundBlend(p[2], c.B, c.A), p[3]}
+			})
+		})
+	}
+	t.Run("透明颜色等同于复制", func(t *testing.T) {
+		runGolden(t, func(dst, src *image.RGBA, op Op) { Blend(dst, src, color.RGBA{1, 2, 3, 0}, op) },
+			func(p [4]byte) [4]byte { return p })
+	})
+}
+
+func TestLUTGolden(t *testing.T) {
+	var table [256]uint8
+	for v := range table {
+		table[v] = uint8(min(max((float64(v)-128)*1.5+128, 0), 255))
+	}
+	runGolden(t, func(dst, src *image.RGBA, op Op) { LUT(dst, src, &table, op) }, func(p [4]byte) [4]byte {
+		return [4]byte{table[p[0]], table[p[1]], table[p[2]], p[3]}
+	})
+}
+
+func TestBlendRectGolden(t *testing.T) {
+	c := color.RGBA{77, 148, 255, 77}
+	src := randomImage(40, 30, image.Pt(-10, 5), 7)
+	rect := image.Rect(-20, 10, 12, 50) // 超出图像范围的部分被裁剪
+	clip := rect.Intersect(src.Rect)
+
+	got := cloneRGBA(src)
+	BlendRect(got, rect, c)
+
+	want := cloneRGBA(src)
+	for y := clip.Min.Y; y < clip.Max.Y; y++ {
+		for x := clip.Min.X; x < clip.Max.X; x++ {
+			p := want.Pix[want.PixOffset(x, y):]
+			p[0], p[1], p[2] = roundBlend(p[0], c.R, c.A), roundBlend(p[1], c.G, c.A), roundBlend(p[2], c.B, c.A)
+		}
+	}
+	if d := maxDiff(t, got, want); d != 0 {
+		t.Errorf("与期望输出最多相差 %d", d)
+	}
+
+	untouched := cloneRGBA(src)
+	BlendRect(untouched, image.Rect(100, 100, 120, 120), c)
+	BlendRect(untouched, src.Rect, color.RGBA{1, 2, 3, 0})
+	if !bytes.Equal(untouched.Pix, src.Pix) {
+		t.Errorf("图像外的矩形或透明颜色不应修改像素")
+	}
+}
+
+func TestDiv255(t *testing.T) {
+	for v := uint32(0); v <= 255*255; v++ {
+		if got, want := div255(v), byte((2*v+255)/510); got != want {
+			t.Fatalf("div255(%d) = %d，期望 %d", v, got, want)
+		}
+	}
+}
+
+func TestFromBGRAAndSwapRBColor(t *testing.T) {
+	pix := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	img := FromBGRA(pix, 2, 1, 8)
+	img.Pix[0] = 9
+	if pix[0] != 9 || img.Rect != image.Rect(0, 0, 2, 1) {
+		t.Errorf("FromBGRA 应共享像素缓冲区")
+	}
+	if got := SwapRBColor(color.RGBA{1, 2, 3, 4}); got != (color.RGBA{3, 2, 1, 4}) {
+		t.Errorf("SwapRBColor = %v", got)
+	}
+	if rgba := randomImage(2, 2, image.Point{}, 8); ToRGBA(rgba) != rgba {
+		t.Errorf("ToRGBA 对 *image.RGBA 应直接返回")
+	}
+}
+
+func TestSizeMismatchPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("尺寸不同时应 panic")
+		}
+	}()
+	Copy(image.NewRGBA(image.Rect(0, 0, 2, 2)), image.NewRGBA(image.Rect(0, 0, 3, 2)), 0)
+}
+
+// TestLegacyTolerance 核对与原浮点实现的差值不超过容差
+func TestLegacyTolerance(t *testing.T) {
+	const w, h = 320, 200
+	screen := randomImage(w, h, image.Point{}, 9)
+	for i := 3; i < len(screen.Pix); i += 4 {
+		screen.Pix[i] = 255
+	}
+	bgra := make([]byte, w*h*4)
+	Copy(FromBGRA(bgra, w, h, w*4), screen, SwapRB)
+
+	cases := []struct {
+		name      string
+		tolerance int
+		legacy    func(dst []byte)
+		fast      func(dst []byte)
+	}{
+		{"截图 BGRA→RGBA", 0,
+			func(dst []byte) { legacyBGRAToRGBA(dst, bgra, w, h) },
+			func(dst []byte) {
+				Copy(FromBGRA(dst, w, h, w*4), FromBGRA(bgra, w, h, w*4), SwapRB|Opaque)
+			}},
+		{"预处理对比度", 0,
+			func(dst []byte) { legacyContrast(dst, screen) },
+			func(dst []byte) {
+				var table [256]uint8
+				for v := range table {
+					table[v] = uint8(min(max((float64(v)-128)*1.5+128, 0), 255))
+				}
+				LUT(FromBGRA(dst, w, h, w*4), screen, &table, 0)
+			}},
+		{"遮罩混合+翻转", legacyBlendTolerance,
+			func(dst []byte) { legacyBackground(dst, screen, color.RGBA{255, 255, 255, 100}) },
+			func(dst []byte) {
+				Blend(FromBGRA(dst, w, h, w*4), screen, color.RGBA{255, 255, 255, 100}, SwapRB|FlipV|Opaque)
+			}},
+		{"重叠的高亮矩形", legacyHighlightTolerance,
+			func(dst []byte) {
+				copy(dst, bgra)
+				legacyHighlight(dst, w, h, highlightRects(w, h), highlightColor)
+			},
+			func(dst []byte) {
+				copy(dst, bgra)
+				img := FromBGRA(dst, w, h, w*4)
+				for _, r := range highlightRects(w, h) {
+					BlendRect(img, image.Rect(r.Min.X, h-r.Max.Y, r.Max.X, h-r.Min.Y), SwapRBColor(highlightColor))
+				}
+			}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			legacy, fast := make([]byte, w*h*4), make([]byte, w*h*4)
+			c.legacy(legacy)
+			c.fast(fast)
+			d := maxDiff(t, FromBGRA(fast, w, h, w*4), FromBGRA(legacy, w, h, w*4))
+			if d > c.tolerance {
+				t.Errorf("与原实现最多相差 %d，容差 %d", d, c.tolerance)
+			}
+		})
+	}
+}
+
+var highlightColor = color.RGBA{77, 148, 255, 77}
+
+// highlightRects 覆盖层高亮的文本块矩形，部分相互重叠
+func highlightRects(w, h int) []image.Rectangle {
+	rng := rand.New(rand.NewSource(10))
+	rects := make([]image.Rectangle, 200)
+	for i := range rects {
+		x, y := rng.Intn(w), rng.Intn(h)
+		rects[i] = image.Rect(x-2, y-2, x+rng.Intn(40)+2, y+rng.Intn(16)+2)
+	}
+	return rects
+}
+
+// 以下为改用 raster 包之前的实现，作为容差和基准测试的参照
+
+// legacyBGRAToRGBA 原 captureRect 中的 BGRA→RGBA 转换
+func legacyBGRAToRGBA(dst, data []byte, width, height int) {
+	for i := 0; i < width*height; i++ {
+		offset := i * 4
+		dst[offset+0] = data[offset+2]
+		dst[offset+1] = data[offset+1]
+		dst[offset+2] = data[offset+0]
+		dst[offset+3] = 255
+	}
+}
+
+// legacyContrast 原 preprocessImage 中基于 img.At 的对比度增强
+func legacyContrast(dst []byte, img image.Image) {
+	bounds := img.Bounds()
+	result := &image.RGBA{Pix: dst, Stride: bounds.Dx() * 4, Rect: bounds}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			r8 := min(max((float64(r>>8)-128)*1.5+128, 0), 255)
+			g8 := min(max((float64(g>>8)-128)*1.5+128, 0), 255)
+			b8 := min(max((float64(b>>8)-128)*1.5+128, 0), 255)
+			result.Set(x, y, color.RGBA{R: uint8(r8), G: uint8(g8), B: uint8(b8), A: uint8(a >> 8)})
+		}
+	}
+}
+
+// legacyBackground 原 calculateBackground 中的浮点遮罩混合 + 翻转
+func legacyBackground(dst []byte, screenshot *image.RGBA, mask color.RGBA) {
+	width, height := screenshot.Rect.Dx(), screenshot.Rect.Dy()
+	alpha := float64(mask.A) / 255.0
+	invAlpha := 1.0 - alpha
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			srcIdx := y*screenshot.Stride + x*4
+			r := uint8(float64(screenshot.Pix[srcIdx+0])*invAlpha + float64(mask.R)*alpha)
+			g := uint8(float64(screenshot.Pix[srcIdx+1])*invAlpha + float64(mask.G)*alpha)
+			b := uint8(float64(screenshot.Pix[srcIdx+2])*invAlpha + float64(mask.B)*alpha)
+			dstIdx := ((height-1-y)*width + x) * 4
+			dst[dstIdx+0] = b
+			dst[dstIdx+1] = g
+			dst[dstIdx+2] = r
+			dst[dstIdx+3] = 255
+		}
+	}
+}
+
+// legacyHighlight 原 applyHighlight 中的浮点高亮混合（自底向上 BGRA）
+func legacyHighlight(pixelData []byte, width, height int, rects []image.Rectangle, c color.RGBA) {
+	alpha := float64(c.A) / 255.0
+	invAlpha := 1.0 - alpha
+	for _, rect := range rects {
+		rect = rect.Intersect(image.Rect(0, 0, width, height))
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			dstY := height - 1 - y
+			for x := rect.Min.X; x < rect.Max.X; x++ {
+				i := (dstY*width + x) * 4
+				pixelData[i+0] = uint8(float64(pixelData[i+0])*invAlpha + float64(c.B)*alpha)
+				pixelData[i+1] = uint8(float64(pixelData[i+1])*invAlpha + float64(c.G)*alpha)
+				pixelData[i+2] = uint8(float64(pixelData[i+2])*invAlpha + float64(c.R)*alpha)
+			}
+		}
+	}
+}
+
+// 基准测试使用 4K 截图大小
+const benchW, benchH = 3840, 2160
+
+func benchImages() (src *image.RGBA, dst []byte) {
+	src = randomImage(benchW, benchH, image.Point{}, 11)
+	return src, make([]byte, benchW*benchH*4)
+}
+
+func BenchmarkCopy(b *testing.B) {
+	src, out := benchImages()
+	bgra := FromBGRA(bytes.Clone(src.Pix), benchW, benchH, benchW*4)
+	for _, op := range []Op{SwapRB | Opaque, SwapRB | Opaque | Parallel, SwapRB | FlipV | Opaque} {
+		b.Run(opName(op), func(b *testing.B) {
+			b.SetBytes(int64(len(out)))
+			for i := 0; i < b.N; i++ {
+				Copy(FromBGRA(out, benchW, benchH, benchW*4), bgra, op)
+			}
+		})
+	}
+	b.Run("InPlace|FlipV", func(b *testing.B) {
+		b.SetBytes(int64(len(out)))
+		for i := 0; i < b.N; i++ {
+			Copy(bgra, bgra, FlipV)
+		}
+	})
+	b.Run("legacy", func(b *testing.B) {
+		b.SetBytes(int64(len(out)))
+		for i := 0; i < b.N; i++ {
+			legacyBGRAToRGBA(out, bgra.Pix, benchW, benchH)
+		}
+	})
+}
+
+func BenchmarkBlend(b *testing.B) {
+	src, out := benchImages()
+	mask := color.RGBA{255, 255, 255, 100}
+	for _, op := range []Op{SwapRB | FlipV | Opaque, SwapRB | FlipV | Opaque | Parallel} {
+		b.Run(opName(op), func(b *testing.B) {
+			b.SetBytes(int64(len(out)))
+			for i := 0; i < b.N; i++ {
+				Blend(FromBGRA(out, benchW, benchH, benchW*4), src, mask, op)
+			}
+		})
+	}
+	b.Run("legacy", func(b *testing.B) {
+		b.SetBytes(int64(len(out)))
+		for i := 0; i < b.N; i++ {
+			legacyBackground(out, src, mask)
+		}
+	})
+}
+
+func BenchmarkLUT(b *testing.B) {
+	src, out := benchImages()
+	var table [256]uint8
+	for v := range table {
+		table[v] = uint8(min(max((float64(v)-128)*1.5+128, 0), 255))
+	}
+	for _, op := range []Op{0, Parallel} {
+		b.Run(opName(op), func(b *testing.B) {
+			b.SetBytes(int64(len(out)))
+			for i := 0; i < b.N; i++ {
+				LUT(FromBGRA(out, benchW, benchH, benchW*4), src, &table, op)
+			}
+		})
+	}
+	b.Run("legacy", func(b *testing.B) {
+		b.SetBytes(int64(len(out)))
+		for i := 0; i < b.N; i++ {
+			legacyContrast(out, src)
+		}
+	})
+}
+
+func BenchmarkBlendRect(b *testing.B) {
+	src, out := benchImages()
+	copy(out, src.Pix)
+	rects := highlightRects(benchW, benchH)
+	b.Run("raster", func(b *testing.B) {
+		img := FromBGRA(out, benchW, benchH, benchW*4)
+		c := SwapRBColor(highlightColor)
+		for i := 0; i < b.N; i++ {
+			for _, r := range rects {
+				BlendRect(img, r, c)
+			}
+		}
+	})
+	b.Run("legacy", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			legacyHighlight(out, benchW, benchH, rects, highlightColor)
+		}
+	})
+}
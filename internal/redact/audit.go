@@ -0,0 +1,74 @@
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// AuditName 审计日志文件名，保存在配置目录中
+const AuditName = "redact_audit.jsonl"
+
+// AuditEntry 一处打码的审计记录，只记录打码后的文字，不含原文
+type AuditEntry struct {
+	Time   time.Time `json:"time"`
+	Target string    `json:"target"`           // 内容去向：translate / watch / batch 等
+	Source string    `json:"source,omitempty"` // 来源：图片路径、窗口等
+	Kind   Kind      `json:"kind"`
+	Masked string    `json:"masked"`           // 打码后的文字
+	Region string    `json:"region,omitempty"` // 截图中遮盖的区域
+}
+
+// Audit 追加写入的审计日志（每行一条 JSON 记录）
+type Audit struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewAudit 创建写入 path 的审计日志（第一次记录时才创建目录和文件）
+func NewAudit(path string) *Audit {
+	return &Audit{path: path}
+}
+
+// Path 审计日志路径
+func (a *Audit) Path() string {
+	return a.path
+}
+
+// Record 追加记录，Time 为零时使用当前时间
+func (a *Audit) Record(entries []AuditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var data []byte
+	now := time.Now()
+	for _, e := range entries {
+		if e.Time.IsZero() {
+			e.Time = now
+		}
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		data = append(append(data, line...), '\n')
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(a.path), 0755); err != nil {
+		return fmt.Errorf("创建审计日志目录失败: %w", err)
+	}
+	f, err := os.OpenFile(a.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("打开审计日志失败: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("写入审计日志失败: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,50 @@
+package redact
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"unicode/utf8"
+
+	"screenocr-wails/internal/ocr"
+)
+
+// FindingRect 估算敏感内容在截图中的区域：按字符在文本块中的位置等比例换算（全角字符按两个字符宽），
+// 左右各留出半个字高的余量，不超出文本块
+func FindingRect(b ocr.TextBlock, f Finding) image.Rectangle {
+	total := textWidth(b.Text)
+	if total == 0 || b.Width <= 0 {
+		return image.Rect(b.X, b.Y, b.X+b.Width, b.Y+b.Height)
+	}
+	start, end := textWidth(b.Text[:f.Start]), textWidth(b.Text[:f.End])
+	pad := b.Height / 2
+	x0 := max(b.X+b.Width*start/total-pad, b.X)
+	x1 := min(b.X+(b.Width*end+total-1)/total+pad, b.X+b.Width)
+	return image.Rect(x0, b.Y, x1, b.Y+b.Height)
+}
+
+// textWidth 文字的显示宽度（以半角字符为单位）
+func textWidth(s string) int {
+	w := 0
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		s = s[size:]
+		if r >= 0x2E80 {
+			w += 2
+		} else {
+			w++
+		}
+	}
+	return w
+}
+
+// MaskImage 返回遮盖了 rects 区域的图片副本（不修改 img）
+func MaskImage(img image.Image, rects []image.Rectangle) *image.RGBA {
+	b := img.Bounds()
+	out := image.NewRGBA(b)
+	draw.Draw(out, b, img, b.Min, draw.Src)
+	for _, r := range rects {
+		draw.Draw(out, r.Intersect(b), &image.Uniform{color.Black}, image.Point{}, draw.Src)
+	}
+	return out
+}
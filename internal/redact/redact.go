@@ -0,0 +1,231 @@
+// Package redact 敏感内容打码
+//
+// 识别结果离开本机（发送给云端翻译）或写入文件之前，找出其中的银行卡号、身份证号、密钥、密码和邮箱，
+// 把文字替换为打码后的形式，并可同时遮盖截图中对应的区域；每次打码记录一条不含原文的审计日志。
+package redact
+
+import (
+	"fmt"
+	"image"
+	"sort"
+	"strings"
+	"sync"
+
+	"screenocr-wails/internal/ocr"
+)
+
+// Kind 敏感内容类型
+type Kind string
+
+const (
+	KindCard     Kind = "card"     // 银行卡号（Luhn 校验）
+	KindIDCard   Kind = "id_card"  // 中国居民身份证号（出生日期和校验码）
+	KindAPIKey   Kind = "api_key"  // API 密钥、访问令牌、私钥
+	KindPassword Kind = "password" // "密码:" 等标签后面的内容
+	KindEmail    Kind = "email"    // 邮箱地址
+)
+
+// Kinds 支持的全部类型，重叠时排在前面的优先
+var Kinds = []Kind{KindPassword, KindAPIKey, KindIDCard, KindCard, KindEmail}
+
+// Name 类型的中文名称
+func (k Kind) Name() string {
+	switch k {
+	case KindCard:
+		return "银行卡号"
+	case KindIDCard:
+		return "身份证号"
+	case KindAPIKey:
+		return "密钥"
+	case KindPassword:
+		return "密码"
+	case KindEmail:
+		return "邮箱"
+	}
+	return string(k)
+}
+
+// Policy 打码策略
+type Policy struct {
+	Enabled   bool   `json:"enabled"`
+	Kinds     []Kind `json:"kinds"`      // 要打码的类型，为空时全部打码
+	MaskImage bool   `json:"mask_image"` // 保存图片（如 PDF）时同时遮盖对应区域
+	Audit     bool   `json:"audit"`      // 记录审计日志
+}
+
+// DefaultPolicy 默认策略：打码全部类型，遮盖图片并记录审计日志
+func DefaultPolicy() Policy {
+	return Policy{Enabled: true, MaskImage: true, Audit: true}
+}
+
+// Finding 文字中的一处敏感内容
+type Finding struct {
+	Kind   Kind
+	Start  int    // 在文字中的起始字节位置
+	End    int    // 结束字节位置（不含）
+	Masked string // 打码后的文字
+}
+
+// Find 找出文字中策略要求打码的敏感内容（按位置排序，互不重叠）
+func Find(text string, policy Policy) []Finding {
+	var found []Finding
+	for _, kind := range Kinds {
+		if policy.allows(kind) {
+			found = append(found, detectors[kind](text)...)
+		}
+	}
+	if len(found) == 0 {
+		return nil
+	}
+
+	// 重叠时保留先开始的，同时开始时保留类型优先的（稳定排序保持 Kinds 的顺序）
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Start < found[j].Start })
+	out := found[:0]
+	end := 0
+	for _, f := range found {
+		if f.Start >= end {
+			out = append(out, f)
+			end = f.End
+		}
+	}
+	return out
+}
+
+// allows 策略是否打码该类型
+func (p Policy) allows(kind Kind) bool {
+	if len(p.Kinds) == 0 {
+		return true
+	}
+	for _, k := range p.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply 把 findings（来自 Find）替换为打码后的文字
+func Apply(text string, findings []Finding) string {
+	if len(findings) == 0 {
+		return text
+	}
+	var sb strings.Builder
+	last := 0
+	for _, f := range findings {
+		sb.WriteString(text[last:f.Start])
+		sb.WriteString(f.Masked)
+		last = f.End
+	}
+	sb.WriteString(text[last:])
+	return sb.String()
+}
+
+// Redactor 按策略打码并记录审计日志，可被多个 goroutine 同时使用
+// nil 或策略未启用时所有方法原样返回
+type Redactor struct {
+	mu     sync.RWMutex
+	policy Policy
+	audit  *Audit
+}
+
+// New 创建打码器，audit 为 nil 时不记录审计日志
+func New(policy Policy, audit *Audit) *Redactor {
+	return &Redactor{policy: policy, audit: audit}
+}
+
+// SetPolicy 更新打码策略
+func (r *Redactor) SetPolicy(policy Policy) {
+	r.mu.Lock()
+	r.policy = policy
+	r.mu.Unlock()
+}
+
+// Policy 当前的打码策略
+func (r *Redactor) Policy() Policy {
+	if r == nil {
+		return Policy{}
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.policy
+}
+
+// Text 打码文字，target 为内容的去向（translate、watch 等），source 为来源（图片路径、窗口等），用于审计日志
+func (r *Redactor) Text(text, target, source string) string {
+	policy := r.Policy()
+	if !policy.Enabled {
+		return text
+	}
+	findings := Find(text, policy)
+	r.record(policy, target, source, findings, nil)
+	return Apply(text, findings)
+}
+
+// Blocks 打码文本块的文字，返回新的文本块（不修改 blocks）和需要遮盖的图片区域
+// 敏感内容跨越多个文本块时无法识别
+func (r *Redactor) Blocks(blocks []ocr.TextBlock, target, source string) ([]ocr.TextBlock, []image.Rectangle) {
+	policy := r.Policy()
+	if !policy.Enabled {
+		return blocks, nil
+	}
+
+	out := make([]ocr.TextBlock, len(blocks))
+	var all []Finding
+	var rects []image.Rectangle
+	for i, b := range blocks {
+		out[i] = b
+		findings := Find(b.Text, policy)
+		if len(findings) == 0 {
+			continue
+		}
+		out[i].Text = Apply(b.Text, findings)
+		for _, f := range findings {
+			all = append(all, f)
+			rects = append(rects, FindingRect(b, f))
+		}
+	}
+	r.record(policy, target, source, all, rects)
+	return out, rects
+}
+
+// Page 打码文本块，策略要求遮盖图片时同时返回遮盖后的图片副本（没有需要遮盖的内容时返回原图）
+func (r *Redactor) Page(img image.Image, blocks []ocr.TextBlock, target, source string) (image.Image, []ocr.TextBlock) {
+	blocks, rects := r.Blocks(blocks, target, source)
+	if len(rects) == 0 || !r.Policy().MaskImage {
+		return img, blocks
+	}
+	return MaskImage(img, rects), blocks
+}
+
+// record 输出日志并写入审计日志，rects 为空或与 findings 一一对应
+func (r *Redactor) record(policy Policy, target, source string, findings []Finding, rects []image.Rectangle) {
+	if len(findings) == 0 {
+		return
+	}
+
+	counts := make(map[Kind]int)
+	for _, f := range findings {
+		counts[f.Kind]++
+	}
+	var parts []string
+	for _, kind := range Kinds {
+		if n := counts[kind]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", kind.Name(), n))
+		}
+	}
+	fmt.Printf("[打码] %s: %s\n", target, strings.Join(parts, "，"))
+
+	if !policy.Audit || r.audit == nil {
+		return
+	}
+	entries := make([]AuditEntry, len(findings))
+	for i, f := range findings {
+		entries[i] = AuditEntry{Target: target, Source: source, Kind: f.Kind, Masked: f.Masked}
+		if i < len(rects) {
+			entries[i].Region = rects[i].String()
+		}
+	}
+	if err := r.audit.Record(entries); err != nil {
+		fmt.Println("[打码] ⚠ 写入审计日志失败:", err)
+	}
+}
@@ -0,0 +1,151 @@
+package redact
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"screenocr-wails/internal/ocr"
+)
+
+// findCases 样例文字、期望的类型和不应出现在打码结果中的原文：各类型的典型写法，以及容易误判的普通内容
+var findCases = []struct {
+	text   string
+	want   []Kind
+	secret string
+}{
+	{"卡号: 4111 1111 1111 1111", []Kind{KindCard}, "4111 1111 1111"},
+	{"还款账户6222021234567890128已绑定", []Kind{KindCard}, "622202123456789"},
+	{"身份证号 11010519491231002X", []Kind{KindIDCard}, "19491231"},
+	{"SecretId: AKIDz8krbsJ5yKBZQpn74WFkmLPx3gnPhESA", []Kind{KindAPIKey}, "z8krbsJ5yKBZQpn74WFkmLPx3gnPhESA"},
+	{"export OPENAI_API_KEY=sk-proj-4fT9xQ2mLp8RzW6vNc3bYh7K", []Kind{KindAPIKey}, "4fT9xQ2mLp8RzW6vNc3bYh7K"},
+	{"SecretKey Kx9fQ2LmZp7RtY4wNb8VcH3jS6dGa1Ue", []Kind{KindAPIKey}, "Kx9fQ2LmZp7RtY4wNb8VcH3jS6dGa1Ue"},
+	{"Wi-Fi 密码：Passw0rd!2024", []Kind{KindPassword}, "Passw0rd!2024"},
+	{"password = hunter2", []Kind{KindPassword}, "hunter2"},
+	{"联系人 alice.wang@example.com", []Kind{KindEmail}, "alice.wang"},
+	{"卡号 4111-1111-1111-1111，邮箱 bob@corp.cn", []Kind{KindCard, KindEmail}, "4111-1111-1111"},
+	{"订单号 2024101812345678", nil, ""},
+	{"身份证号 110105194912310021", nil, ""},
+	{"电话 138 0013 8000", nil, ""},
+	{"commit 3f5e2a9c1b7d4e8f6a0b2c4d6e8f0a1b2c3d4e5f", nil, ""},
+	{"VeryLongIdentifierNameForTestingPurposes", nil, ""},
+	{"2024-10-18 12:30 会议纪要", nil, ""},
+}
+
+func TestFind(t *testing.T) {
+	for _, c := range findCases {
+		t.Run(c.text, func(t *testing.T) {
+			findings := Find(c.text, DefaultPolicy())
+			var kinds []Kind
+			for _, f := range findings {
+				kinds = append(kinds, f.Kind)
+			}
+			if !slices.Equal(kinds, c.want) {
+				t.Errorf("识别出 %v，期望 %v", kinds, c.want)
+			}
+
+			masked := Apply(c.text, findings)
+			if (len(c.want) == 0) != (masked == c.text) {
+				t.Errorf("打码结果 %q", masked)
+			}
+			if c.secret != "" && strings.Contains(masked, c.secret) {
+				t.Errorf("打码结果 %q 仍包含 %q", masked, c.secret)
+			}
+		})
+	}
+}
+
+func TestFindingRect(t *testing.T) {
+	for _, c := range findCases {
+		if len(c.want) == 0 {
+			continue
+		}
+		t.Run(c.text, func(t *testing.T) {
+			// 每个半角字符 10 像素宽的文本块：遮盖区域应在文本块内，并覆盖敏感内容的估算位置
+			block := ocr.TextBlock{Text: c.text, X: 100, Y: 50, Width: 10 * textWidth(c.text), Height: 20}
+			bounds := image.Rect(block.X, block.Y, block.X+block.Width, block.Y+block.Height)
+			for _, f := range Find(c.text, DefaultPolicy()) {
+				rect := FindingRect(block, f)
+				covered := image.Rect(block.X+10*textWidth(c.text[:f.Start]), block.Y, block.X+10*textWidth(c.text[:f.End]), block.Y+block.Height)
+				if !rect.In(bounds) || !covered.In(rect) {
+					t.Errorf("%s: 遮盖区域 %v，文本块 %v，敏感内容 %v", f.Kind, rect, bounds, covered)
+				}
+			}
+		})
+	}
+}
+
+func TestPolicyKinds(t *testing.T) {
+	text := "卡号 4111-1111-1111-1111，邮箱 bob@corp.cn"
+	findings := Find(text, Policy{Enabled: true, Kinds: []Kind{KindEmail}})
+	if len(findings) != 1 || findings[0].Kind != KindEmail {
+		t.Fatalf("只打码邮箱时得到 %+v", findings)
+	}
+	if masked := Apply(text, findings); !strings.Contains(masked, "4111-1111-1111-1111") {
+		t.Errorf("未选择的类型也被打码: %q", masked)
+	}
+}
+
+func TestRedactorDisabled(t *testing.T) {
+	text := "password = hunter2"
+	var nilRedactor *Redactor
+	if got := nilRedactor.Text(text, "translate", ""); got != text {
+		t.Errorf("nil 打码器修改了文字: %q", got)
+	}
+	r := New(Policy{}, nil)
+	if got := r.Text(text, "translate", ""); got != text {
+		t.Errorf("未启用的打码器修改了文字: %q", got)
+	}
+}
+
+func TestRedactorPageAndAudit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", AuditName)
+	r := New(DefaultPolicy(), NewAudit(path))
+
+	img := image.NewRGBA(image.Rect(0, 0, 400, 100))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+	blocks := []ocr.TextBlock{
+		{Text: "password = hunter2", X: 10, Y: 10, Width: 180, Height: 20},
+		{Text: "会议纪要", X: 10, Y: 50, Width: 80, Height: 20},
+	}
+
+	masked, out := r.Page(img, blocks, "batch", "page.png")
+	if strings.Contains(out[0].Text, "hunter2") || out[1].Text != blocks[1].Text {
+		t.Errorf("打码后的文本块 %+v", out)
+	}
+	if blocks[0].Text != "password = hunter2" {
+		t.Error("Page 修改了传入的文本块")
+	}
+	if img.RGBAAt(180, 20) != (color.RGBA{255, 255, 255, 255}) {
+		t.Error("Page 修改了原图")
+	}
+	if masked.(*image.RGBA).RGBAAt(180, 20) != (color.RGBA{0, 0, 0, 255}) {
+		t.Error("敏感内容所在区域没有被遮盖")
+	}
+	if masked.(*image.RGBA).RGBAAt(20, 60) != (color.RGBA{255, 255, 255, 255}) {
+		t.Error("普通文字所在区域被遮盖")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 || !strings.Contains(lines[0], `"kind":"password"`) || !strings.Contains(lines[0], `"source":"page.png"`) {
+		t.Errorf("审计日志 %s", data)
+	}
+	if strings.Contains(string(data), "hunter2") {
+		t.Error("审计日志中包含原文")
+	}
+
+	// 没有需要遮盖的内容时返回原图
+	if got, _ := r.Page(img, blocks[1:], "batch", ""); got != image.Image(img) {
+		t.Error("没有敏感内容时应返回原图")
+	}
+}
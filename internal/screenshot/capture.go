@@ -7,6 +7,8 @@ import (
 	"image"
 	"syscall"
 	"unsafe"
+
+	"screenocr-wails/internal/raster"
 )
 
 var (
@@ -59,27 +61,37 @@ type BITMAPINFO struct {
 	BmiColors [1]uint32
 }
 
-// Capturer 屏幕截图器
-type Capturer struct {
+// GDICapturer 基于 GDI BitBlt 的 Windows 截图器
+type GDICapturer struct {
 	dpiAware bool
 }
 
-// NewCapturer 创建截图器
-func NewCapturer() *Capturer {
-	c := &Capturer{}
+// NewGDICapturer 创建 GDI 截图器
+func NewGDICapturer() *GDICapturer {
+	c := &GDICapturer{}
 	c.setDPIAware()
 	return c
 }
 
+// newPlatformCapturer Windows 使用 GDI 截图
+func newPlatformCapturer() (Capturer, error) {
+	return NewGDICapturer(), nil
+}
+
 // setDPIAware 设置 DPI 感知
-func (c *Capturer) setDPIAware() {
+func (c *GDICapturer) setDPIAware() {
 	ret, _, _ := procSetProcessDpiAwareness.Call(2) // PROCESS_PER_MONITOR_DPI_AWARE
 	c.dpiAware = ret == 0
 }
 
-// CaptureScreen 捕获整个屏幕
-func (c *Capturer) CaptureScreen() (image.Image, error) {
-	// 获取虚拟屏幕尺寸（支持多显示器）
+// virtualScreen 虚拟屏幕范围（所有显示器的外接矩形）
+func (c *GDICapturer) virtualScreen() image.Rectangle {
+	// 优先使用枚举到的显示器，与 Monitors 和 Layout 的坐标保持一致
+	if monitors, err := c.Monitors(); err == nil {
+		return VirtualBounds(monitors)
+	}
+
+	// 回退到系统度量（支持多显示器）
 	x, _, _ := procGetSystemMetrics.Call(SM_XVIRTUALSCREEN)
 	y, _, _ := procGetSystemMetrics.Call(SM_YVIRTUALSCREEN)
 	width, _, _ := procGetSystemMetrics.Call(SM_CXVIRTUALSCREEN)
@@ -92,11 +104,35 @@ func (c *Capturer) CaptureScreen() (image.Image, error) {
 		x, y = 0, 0
 	}
 
-	return c.captureRect(int(x), int(y), int(width), int(height))
+	// 虚拟屏幕原点可能为负，GetSystemMetrics 返回值需要按 int32 解释
+	return image.Rect(int(int32(x)), int(int32(y)), int(int32(x))+int(width), int(int32(y))+int(height))
+}
+
+// CaptureScreen 捕获整个屏幕
+func (c *GDICapturer) CaptureScreen() (image.Image, error) {
+	screen := c.virtualScreen()
+	return c.captureRect(screen.Min.X, screen.Min.Y, screen.Dx(), screen.Dy())
+}
+
+// CaptureMonitor 捕获第 index 个显示器
+func (c *GDICapturer) CaptureMonitor(index int) (image.Image, error) {
+	return captureMonitor(c, index)
+}
+
+// CaptureRect 捕获虚拟屏幕坐标中的矩形区域
+func (c *GDICapturer) CaptureRect(rect image.Rectangle) (image.Image, error) {
+	rect, err := clipRect(rect, c.virtualScreen())
+	if err != nil {
+		return nil, err
+	}
+	return c.captureRect(rect.Min.X, rect.Min.Y, rect.Dx(), rect.Dy())
 }
 
-// CaptureRect 捕获指定区域（使用 CreateDIBSection，兼容 Win10/Win11）
-func (c *Capturer) captureRect(x, y, width, height int) (image.Image, error) {
+// Close GDI 截图器不持有需要释放的资源
+func (c *GDICapturer) Close() {}
+
+// captureRect 捕获指定区域（使用 CreateDIBSection，兼容 Win10/Win11）
+func (c *GDICapturer) captureRect(x, y, width, height int) (image.Image, error) {
 	// 获取屏幕 DC
 	hdc, _, _ := procGetDC.Call(0)
 	if hdc == 0 {
@@ -162,15 +198,9 @@ func (c *Capturer) captureRect(x, y, width, height int) (image.Image, error) {
 	dataSize := width * height * 4
 	data := unsafe.Slice((*byte)(unsafe.Pointer(pBits)), dataSize)
 
-	// 创建 RGBA 图像并复制数据 (BGRA -> RGBA)
+	// 创建 RGBA 图像并复制数据 (BGRA -> RGBA，GDI 不填 alpha，统一置为不透明)
 	img := image.NewRGBA(image.Rect(0, 0, width, height))
-	for i := 0; i < width*height; i++ {
-		offset := i * 4
-		img.Pix[offset+0] = data[offset+2] // R
-		img.Pix[offset+1] = data[offset+1] // G
-		img.Pix[offset+2] = data[offset+0] // B
-		img.Pix[offset+3] = 255            // A
-	}
+	raster.Copy(img, raster.FromBGRA(data, width, height, width*4), raster.SwapRB|raster.Opaque|raster.Parallel)
 
 	// 清理资源
 	procSelectObject.Call(hdcMem, oldBitmap)
@@ -182,7 +212,7 @@ func (c *Capturer) captureRect(x, y, width, height int) (image.Image, error) {
 }
 
 // GetScreenSize 获取屏幕尺寸
-func (c *Capturer) GetScreenSize() (width, height int) {
+func (c *GDICapturer) GetScreenSize() (width, height int) {
 	w, _, _ := procGetSystemMetrics.Call(SM_CXSCREEN)
 	h, _, _ := procGetSystemMetrics.Call(SM_CYSCREEN)
 	return int(w), int(h)
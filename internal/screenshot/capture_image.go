@@ -0,0 +1,161 @@
+package screenshot
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"sync"
+
+	_ "golang.org/x/image/bmp"
+)
+
+// ImageCapturer 从内存图片或图片文件截图的截图器，用于测试和没有显示器的环境
+// 图片的 Bounds 即虚拟屏幕范围；未指定显示器时整张图片视为一个主显示器
+type ImageCapturer struct {
+	mu       sync.Mutex
+	img      image.Image
+	path     string // 非空时每次截图都重新读取文件
+	monitors []Monitor
+	windows  []Window    // 模拟的顶层窗口，按 Z 序从上到下，第一个为前台窗口
+	cursor   image.Point // 模拟的鼠标位置
+}
+
+// NewImageCapturer 创建返回固定图片的截图器
+func NewImageCapturer(img image.Image, monitors ...Monitor) *ImageCapturer {
+	return &ImageCapturer{img: img, monitors: monitors}
+}
+
+// NewFileCapturer 创建从图片文件截图的截图器，文件在每次截图时重新读取（可在测试中替换）
+func NewFileCapturer(path string, monitors ...Monitor) *ImageCapturer {
+	return &ImageCapturer{path: path, monitors: monitors}
+}
+
+// SetImage 替换之后截图返回的图片
+func (c *ImageCapturer) SetImage(img image.Image) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.img = img
+	c.path = ""
+}
+
+// SetWindows 设置模拟的顶层窗口（按 Z 序从上到下，第一个为前台窗口）
+func (c *ImageCapturer) SetWindows(windows ...Window) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.windows = append([]Window(nil), windows...)
+}
+
+// SetCursor 设置模拟的鼠标位置
+func (c *ImageCapturer) SetCursor(p image.Point) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cursor = p
+}
+
+// screen 返回当前图片（文件模式下重新读取）
+func (c *ImageCapturer) screen() (image.Image, error) {
+	c.mu.Lock()
+	img, path := c.img, c.path
+	c.mu.Unlock()
+
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("打开截图文件失败: %w", err)
+		}
+		defer f.Close()
+		img, _, err = image.Decode(f)
+		if err != nil {
+			return nil, fmt.Errorf("解码截图文件失败: %w", err)
+		}
+	}
+	if img == nil {
+		return nil, fmt.Errorf("截图器没有设置图片")
+	}
+	return img, nil
+}
+
+// Monitors 列出显示器
+func (c *ImageCapturer) Monitors() ([]Monitor, error) {
+	c.mu.Lock()
+	monitors := c.monitors
+	c.mu.Unlock()
+	if len(monitors) > 0 {
+		return append([]Monitor(nil), monitors...), nil
+	}
+
+	img, err := c.screen()
+	if err != nil {
+		return nil, err
+	}
+	return []Monitor{{Index: 0, Name: "image", Bounds: img.Bounds(), WorkArea: img.Bounds(), Primary: true}}, nil
+}
+
+// CaptureScreen 返回整张图片的副本
+func (c *ImageCapturer) CaptureScreen() (image.Image, error) {
+	img, err := c.screen()
+	if err != nil {
+		return nil, err
+	}
+	return copyRect(img, img.Bounds()), nil
+}
+
+// CaptureMonitor 截取第 index 个显示器范围
+func (c *ImageCapturer) CaptureMonitor(index int) (image.Image, error) {
+	return captureMonitor(c, index)
+}
+
+// CaptureRect 截取图片中的矩形区域
+func (c *ImageCapturer) CaptureRect(rect image.Rectangle) (image.Image, error) {
+	img, err := c.screen()
+	if err != nil {
+		return nil, err
+	}
+	rect, err = clipRect(rect, img.Bounds())
+	if err != nil {
+		return nil, err
+	}
+	return copyRect(img, rect), nil
+}
+
+// ForegroundWindow 返回第一个模拟窗口
+func (c *ImageCapturer) ForegroundWindow() (Window, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.windows) == 0 {
+		return Window{}, fmt.Errorf("没有前台窗口")
+	}
+	return c.windows[0], nil
+}
+
+// WindowAt 返回包含 p 的最上层模拟窗口
+func (c *ImageCapturer) WindowAt(p image.Point) (Window, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, w := range c.windows {
+		if p.In(w.Bounds) {
+			return w, nil
+		}
+	}
+	return Window{}, fmt.Errorf("坐标 %v 处没有窗口", p)
+}
+
+// CursorPos 返回模拟的鼠标位置
+func (c *ImageCapturer) CursorPos() (image.Point, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cursor, nil
+}
+
+// Close 无需释放资源
+func (c *ImageCapturer) Close() {}
+
+// copyRect 把 img 中的 rect 区域复制为原点 (0, 0) 的新图片
+func copyRect(img image.Image, rect image.Rectangle) *image.RGBA {
+	out := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(out, out.Rect, img, rect.Min, draw.Src)
+	return out
+}
@@ -0,0 +1,171 @@
+package screenshot
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// gridImage 每个像素的颜色由其虚拟屏幕坐标决定，用于核对截取的位置
+func gridImage(bounds image.Rectangle) *image.RGBA {
+	img := image.NewRGBA(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			img.SetRGBA(x, y, gridColor(x, y))
+		}
+	}
+	return img
+}
+
+func gridColor(x, y int) color.RGBA {
+	return color.RGBA{uint8(x), uint8(y), uint8((x>>8)&0x0f | (y>>8)<<4), 255}
+}
+
+// checkCapture 核对截图原点为 (0, 0)，且内容与虚拟屏幕中的 rect 一致
+func checkCapture(t *testing.T, img image.Image, rect image.Rectangle) {
+	t.Helper()
+	if img.Bounds() != image.Rect(0, 0, rect.Dx(), rect.Dy()) {
+		t.Fatalf("截图范围 %v，期望 %v 大小的图片", img.Bounds(), rect.Size())
+	}
+	for _, p := range []image.Point{rect.Min, {rect.Max.X - 1, rect.Min.Y}, rect.Max.Sub(image.Pt(1, 1)), rect.Min.Add(rect.Max).Div(2)} {
+		got := color.RGBAModel.Convert(img.At(p.X-rect.Min.X, p.Y-rect.Min.Y))
+		if got != gridColor(p.X, p.Y) {
+			t.Errorf("屏幕坐标 %v 的像素 %v，期望 %v", p, got, gridColor(p.X, p.Y))
+		}
+	}
+}
+
+// dualMonitors 左侧为 150% 缩放的副显示器（原点为负），右侧为主显示器
+var dualMonitors = []Monitor{
+	{Index: 0, Name: "left", Bounds: image.Rect(-300, 40, 0, 240), DPI: 144},
+	{Index: 1, Name: "main", Bounds: image.Rect(0, 0, 400, 300), WorkArea: image.Rect(0, 0, 400, 280), DPI: 96, Primary: true},
+}
+
+func TestImageCapturer(t *testing.T) {
+	screen := image.Rect(-300, 0, 400, 300)
+	c := NewImageCapturer(gridImage(screen), dualMonitors...)
+
+	img, err := c.CaptureScreen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCapture(t, img, screen)
+
+	for i, m := range dualMonitors {
+		img, err := c.CaptureMonitor(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkCapture(t, img, m.Bounds)
+	}
+	if _, err := c.CaptureMonitor(2); err == nil || !strings.Contains(err.Error(), "不存在") {
+		t.Errorf("不存在的显示器: %v", err)
+	}
+
+	// 超出屏幕的部分被裁剪，完全在屏幕外时报错
+	img, err = c.CaptureRect(image.Rect(350, 250, 500, 400))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCapture(t, img, image.Rect(350, 250, 400, 300))
+	if _, err := c.CaptureRect(image.Rect(500, 0, 600, 100)); err == nil {
+		t.Error("屏幕外的区域应当报错")
+	}
+
+	// 截图是副本，修改不影响之后的截图
+	img.(*image.RGBA).SetRGBA(0, 0, color.RGBA{})
+	again, _ := c.CaptureRect(image.Rect(350, 250, 500, 400))
+	checkCapture(t, again, image.Rect(350, 250, 400, 300))
+}
+
+func TestImageCapturerDefaultMonitor(t *testing.T) {
+	c := NewImageCapturer(gridImage(image.Rect(0, 0, 64, 48)))
+	monitors, err := c.Monitors()
+	if err != nil || len(monitors) != 1 || !monitors[0].Primary || monitors[0].Bounds != image.Rect(0, 0, 64, 48) {
+		t.Errorf("Monitors = %+v, %v", monitors, err)
+	}
+	if _, err := NewImageCapturer(nil).CaptureScreen(); err == nil {
+		t.Error("没有图片时应当报错")
+	}
+}
+
+func TestFileCapturer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "screen.png")
+	c := NewFileCapturer(path)
+	if _, err := c.CaptureScreen(); err == nil || !strings.Contains(err.Error(), "打开截图文件失败") {
+		t.Errorf("文件不存在: %v", err)
+	}
+
+	// 每次截图重新读取文件，替换文件后得到新内容
+	for _, size := range []image.Point{{80, 60}, {120, 90}} {
+		screen := image.Rectangle{Max: size}
+		writePNG(t, path, gridImage(screen))
+		img, err := c.CaptureScreen()
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkCapture(t, img, screen)
+	}
+
+	if err := os.WriteFile(path, []byte("not an image"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CaptureScreen(); err == nil || !strings.Contains(err.Error(), "解码截图文件失败") {
+		t.Errorf("无效的图片: %v", err)
+	}
+
+	// SetImage 之后不再读取文件
+	c.SetImage(gridImage(image.Rect(0, 0, 10, 10)))
+	if img, err := c.CaptureScreen(); err != nil || img.Bounds().Dx() != 10 {
+		t.Errorf("SetImage 后截图 %v, %v", img.Bounds(), err)
+	}
+}
+
+func writePNG(t *testing.T, path string, img image.Image) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCaptureLayout(t *testing.T) {
+	screen := image.Rect(-300, 0, 400, 300)
+	cases := []struct {
+		name       string
+		screen     image.Rectangle
+		monitors   []Monitor
+		wantBounds image.Rectangle
+	}{
+		{"dual-negative-origin", screen, dualMonitors, screen},
+		// 显示器列表比图片大时以实际截到的范围为准
+		{"clipped", image.Rect(-300, 0, 200, 300), dualMonitors, image.Rect(-300, 0, 200, 300)},
+		{"single", image.Rect(0, 0, 400, 300), nil, image.Rect(0, 0, 400, 300)},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			img, layout, err := CaptureLayout(NewImageCapturer(gridImage(c.screen), c.monitors...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if layout.Bounds != c.wantBounds {
+				t.Errorf("布局范围 %v，期望 %v", layout.Bounds, c.wantBounds)
+			}
+			checkCapture(t, img, c.wantBounds)
+
+			// 截图中的像素换算回屏幕坐标后颜色一致
+			p := image.Pt(150, 120)
+			if s := layout.Convert(p, ImageSpace, ScreenSpace); color.RGBAModel.Convert(img.At(p.X, p.Y)) != gridColor(s.X, s.Y) {
+				t.Errorf("截图坐标 %v 换算到屏幕坐标 %v 后像素不一致", p, s)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !windows && !(linux && cgo)
+
+package screenshot
+
+import "fmt"
+
+// newPlatformCapturer 当前平台没有屏幕截图实现
+func newPlatformCapturer() (Capturer, error) {
+	return nil, fmt.Errorf("当前平台不支持屏幕截图（Linux 需要启用 cgo 以使用 X11）")
+}
@@ -0,0 +1,552 @@
+//go:build linux && cgo
+
+package screenshot
+
+/*
+#cgo LDFLAGS: -lX11 -lXext -ldl
+
+#include <stdlib.h>
+#include <string.h>
+#include <dlfcn.h>
+#include <sys/ipc.h>
+#include <sys/shm.h>
+#include <X11/Xlib.h>
+#include <X11/Xutil.h>
+#include <X11/Xatom.h>
+#include <X11/extensions/XShm.h>
+
+// X 错误默认会终止进程，这里只记录错误码，由调用方检查
+static int sc_x_error = 0;
+
+static int sc_error_handler(Display *dpy, XErrorEvent *ev) {
+    sc_x_error = ev->error_code;
+    return 0;
+}
+
+static void sc_install_error_handler(void) {
+    XSetErrorHandler(sc_error_handler);
+}
+
+static int sc_root_width(Display *dpy) { return DisplayWidth(dpy, DefaultScreen(dpy)); }
+static int sc_root_height(Display *dpy) { return DisplayHeight(dpy, DefaultScreen(dpy)); }
+
+static int sc_has_shm(Display *dpy) {
+    return XShmQueryExtension(dpy) ? 1 : 0;
+}
+
+#define SC_OK          0
+#define SC_GET_FAILED -1
+#define SC_BAD_FORMAT -2
+
+// sc_copy_image 把 32 位 BGRX 的 XImage 复制到紧密排列的 dst
+static int sc_copy_image(XImage *img, unsigned char *dst, int w, int h) {
+    if (img->bits_per_pixel != 32 || img->byte_order != LSBFirst ||
+        img->red_mask != 0xff0000 || img->green_mask != 0xff00 || img->blue_mask != 0xff) {
+        return SC_BAD_FORMAT;
+    }
+    for (int y = 0; y < h; y++) {
+        memcpy(dst + (size_t)y * w * 4, img->data + (size_t)y * img->bytes_per_line, (size_t)w * 4);
+    }
+    return SC_OK;
+}
+
+// sc_capture_shm 通过 MIT-SHM 截图，失败返回 SC_GET_FAILED（调用方改用 XGetImage）
+static int sc_capture_shm(Display *dpy, int x, int y, int w, int h, unsigned char *dst) {
+    int screen = DefaultScreen(dpy);
+    XShmSegmentInfo info;
+    XImage *img = XShmCreateImage(dpy, DefaultVisual(dpy, screen), DefaultDepth(dpy, screen),
+                                  ZPixmap, NULL, &info, w, h);
+    if (img == NULL) {
+        return SC_GET_FAILED;
+    }
+
+    int ret = SC_GET_FAILED;
+    info.shmid = shmget(IPC_PRIVATE, (size_t)img->bytes_per_line * img->height, IPC_CREAT | 0600);
+    if (info.shmid < 0) {
+        XDestroyImage(img);
+        return SC_GET_FAILED;
+    }
+    info.shmaddr = img->data = shmat(info.shmid, NULL, 0);
+    // 标记删除：所有进程分离后自动释放，避免异常退出时泄漏
+    shmctl(info.shmid, IPC_RMID, NULL);
+    if (info.shmaddr == (char *)-1) {
+        img->data = NULL;
+        XDestroyImage(img);
+        return SC_GET_FAILED;
+    }
+    info.readOnly = False;
+
+    sc_x_error = 0;
+    int attached = XShmAttach(dpy, &info);
+    XSync(dpy, False);
+    if (attached && !sc_x_error) {
+        if (XShmGetImage(dpy, RootWindow(dpy, screen), img, x, y, AllPlanes)) {
+            XSync(dpy, False);
+            if (!sc_x_error) {
+                ret = sc_copy_image(img, dst, w, h);
+            }
+        }
+        XShmDetach(dpy, &info);
+        XSync(dpy, False);
+    }
+    shmdt(info.shmaddr);
+    img->data = NULL;
+    XDestroyImage(img);
+    sc_x_error = 0;
+    return ret;
+}
+
+// sc_capture_get 通过 XGetImage 截图（远程 X 服务器等不支持共享内存的情况）
+static int sc_capture_get(Display *dpy, int x, int y, int w, int h, unsigned char *dst) {
+    sc_x_error = 0;
+    XImage *img = XGetImage(dpy, DefaultRootWindow(dpy), x, y, w, h, AllPlanes, ZPixmap);
+    if (img == NULL) {
+        sc_x_error = 0;
+        return SC_GET_FAILED;
+    }
+    int ret = sc_copy_image(img, dst, w, h);
+    XDestroyImage(img);
+    return ret;
+}
+
+// 与 Xrandr 1.5 的 XRRMonitorInfo 布局一致；运行时通过 dlopen 加载 libXrandr，编译时不依赖其头文件
+typedef struct {
+    Atom name;
+    Bool primary;
+    Bool automatic;
+    int noutput;
+    int x, y, width, height;
+    int mwidth, mheight;
+    unsigned long *outputs;
+} sc_monitor_info;
+
+typedef sc_monitor_info *(*sc_get_monitors_fn)(Display *, Window, Bool, int *);
+typedef void (*sc_free_monitors_fn)(sc_monitor_info *);
+
+// sc_monitors 列出显示器，rects 每项依次为 x, y, width, height, primary；
+// names 每项 64 字节；返回显示器数量，Xrandr 不可用时返回 -1
+static int sc_monitors(Display *dpy, int *rects, char *names, int max) {
+    static void *lib = NULL;
+    static sc_get_monitors_fn get_monitors = NULL;
+    static sc_free_monitors_fn free_monitors = NULL;
+    if (lib == NULL) {
+        lib = dlopen("libXrandr.so.2", RTLD_LAZY);
+        if (lib == NULL) {
+            return -1;
+        }
+        get_monitors = (sc_get_monitors_fn)dlsym(lib, "XRRGetMonitors");
+        free_monitors = (sc_free_monitors_fn)dlsym(lib, "XRRFreeMonitors");
+    }
+    if (get_monitors == NULL || free_monitors == NULL) {
+        return -1;
+    }
+
+    int n = 0;
+    sc_monitor_info *info = get_monitors(dpy, DefaultRootWindow(dpy), True, &n);
+    if (info == NULL) {
+        return -1;
+    }
+    if (n > max) {
+        n = max;
+    }
+    for (int i = 0; i < n; i++) {
+        rects[i * 5 + 0] = info[i].x;
+        rects[i * 5 + 1] = info[i].y;
+        rects[i * 5 + 2] = info[i].width;
+        rects[i * 5 + 3] = info[i].height;
+        rects[i * 5 + 4] = info[i].primary ? 1 : 0;
+        names[i * 64] = 0;
+        char *name = info[i].name ? XGetAtomName(dpy, info[i].name) : NULL;
+        if (name != NULL) {
+            strncpy(names + i * 64, name, 63);
+            names[i * 64 + 63] = 0;
+            XFree(name);
+        }
+    }
+    free_monitors(info);
+    return n;
+}
+// sc_window_prop 读取窗口属性，返回数据（调用方 XFree）和元素数量，失败返回 NULL
+static unsigned char *sc_window_prop(Display *dpy, Window w, const char *name, Atom type, unsigned long *n) {
+    Atom prop = XInternAtom(dpy, name, True);
+    if (prop == None) {
+        return NULL;
+    }
+    Atom actual;
+    int format;
+    unsigned long after;
+    unsigned char *data = NULL;
+    sc_x_error = 0;
+    if (XGetWindowProperty(dpy, w, prop, 0, 1024, False, type, &actual, &format, n, &after, &data) != Success ||
+        sc_x_error || actual == None || data == NULL) {
+        if (data != NULL) {
+            XFree(data);
+        }
+        sc_x_error = 0;
+        return NULL;
+    }
+    return data;
+}
+
+// sc_active_window 窗口管理器记录的活动窗口（_NET_ACTIVE_WINDOW），没有时返回 0
+static Window sc_active_window(Display *dpy) {
+    unsigned long n = 0;
+    unsigned char *data = sc_window_prop(dpy, DefaultRootWindow(dpy), "_NET_ACTIVE_WINDOW", XA_WINDOW, &n);
+    if (data == NULL) {
+        return 0;
+    }
+    Window w = n > 0 ? ((Window *)data)[0] : 0;
+    XFree(data);
+    return w;
+}
+
+// sc_client_window 在顶层（窗口管理器框架）窗口下查找带 WM_STATE 的应用窗口，找不到时返回 w
+static Window sc_client_window(Display *dpy, Window w, int depth) {
+    unsigned long n = 0;
+    unsigned char *data = sc_window_prop(dpy, w, "WM_STATE", AnyPropertyType, &n);
+    if (data != NULL) {
+        XFree(data);
+        return w;
+    }
+    if (depth <= 0) {
+        return 0;
+    }
+    Window root, parent, *children = NULL;
+    unsigned int count = 0;
+    if (!XQueryTree(dpy, w, &root, &parent, &children, &count)) {
+        return 0;
+    }
+    Window found = 0;
+    for (int i = (int)count - 1; i >= 0 && found == 0; i--) {
+        found = sc_client_window(dpy, children[i], depth - 1);
+    }
+    if (children != NULL) {
+        XFree(children);
+    }
+    return found;
+}
+
+// sc_window_at 根窗口坐标 (x, y) 处的应用窗口，没有时返回 0
+static Window sc_window_at(Display *dpy, int x, int y) {
+    Window root = DefaultRootWindow(dpy), child = 0;
+    int cx, cy;
+    if (!XTranslateCoordinates(dpy, root, root, x, y, &cx, &cy, &child) || child == 0) {
+        return 0;
+    }
+    Window client = sc_client_window(dpy, child, 4);
+    return client != 0 ? client : child;
+}
+
+// sc_cursor 鼠标在根窗口中的位置，失败返回 0
+static int sc_cursor(Display *dpy, int *x, int *y) {
+    Window root, child;
+    int wx, wy;
+    unsigned int mask;
+    return XQueryPointer(dpy, DefaultRootWindow(dpy), &root, &child, x, y, &wx, &wy, &mask) ? 1 : 0;
+}
+
+// sc_window_geometry 窗口在根窗口中的范围（含 _NET_FRAME_EXTENTS 声明的窗口管理器边框），失败返回 0
+static int sc_window_geometry(Display *dpy, Window w, int *rect) {
+    XWindowAttributes attr;
+    sc_x_error = 0;
+    if (!XGetWindowAttributes(dpy, w, &attr) || sc_x_error) {
+        sc_x_error = 0;
+        return 0;
+    }
+    int x, y;
+    Window child;
+    if (!XTranslateCoordinates(dpy, w, DefaultRootWindow(dpy), 0, 0, &x, &y, &child)) {
+        return 0;
+    }
+    int left = 0, right = 0, top = 0, bottom = 0;
+    unsigned long n = 0;
+    unsigned char *data = sc_window_prop(dpy, w, "_NET_FRAME_EXTENTS", XA_CARDINAL, &n);
+    if (data != NULL) {
+        if (n >= 4) {
+            long *ext = (long *)data;
+            left = ext[0], right = ext[1], top = ext[2], bottom = ext[3];
+        }
+        XFree(data);
+    }
+    rect[0] = x - left;
+    rect[1] = y - top;
+    rect[2] = attr.width + left + right;
+    rect[3] = attr.height + top + bottom;
+    return 1;
+}
+
+// sc_window_title 窗口标题（优先 UTF-8 的 _NET_WM_NAME），写入 dst（最多 max-1 字节）
+static void sc_window_title(Display *dpy, Window w, char *dst, int max) {
+    dst[0] = 0;
+    unsigned long n = 0;
+    Atom utf8 = XInternAtom(dpy, "UTF8_STRING", False);
+    unsigned char *data = sc_window_prop(dpy, w, "_NET_WM_NAME", utf8, &n);
+    if (data == NULL) {
+        data = sc_window_prop(dpy, w, "WM_NAME", AnyPropertyType, &n);
+    }
+    if (data != NULL) {
+        if ((int)n >= max) {
+            n = max - 1;
+        }
+        memcpy(dst, data, n);
+        dst[n] = 0;
+        XFree(data);
+    }
+}
+
+// sc_window_pid 窗口所属进程（_NET_WM_PID），未知时返回 0
+static long sc_window_pid(Display *dpy, Window w) {
+    unsigned long n = 0;
+    unsigned char *data = sc_window_prop(dpy, w, "_NET_WM_PID", XA_CARDINAL, &n);
+    if (data == NULL) {
+        return 0;
+    }
+    long pid = n > 0 ? ((long *)data)[0] : 0;
+    XFree(data);
+    return pid;
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"screenocr-wails/internal/raster"
+)
+
+const (
+	// maxX11Monitors 最多列出的显示器数量
+	maxX11Monitors = 16
+
+	// x11DPI X11 没有按显示器缩放，坐标始终是物理像素，统一按 100% 处理
+	x11DPI = 96
+
+	// maxX11Title 窗口标题最大字节数
+	maxX11Title = 512
+)
+
+// X11Capturer 基于 Xlib 的 Linux 截图器，支持 MIT-SHM 时使用共享内存截图
+type X11Capturer struct {
+	mu      sync.Mutex // Xlib 连接不是线程安全的，所有调用串行执行
+	display *C.Display
+	useShm  bool
+}
+
+// NewX11Capturer 连接 X 服务器，name 为空时使用 $DISPLAY
+func NewX11Capturer(name string) (*X11Capturer, error) {
+	var cname *C.char
+	if name != "" {
+		cname = C.CString(name)
+		defer C.free(unsafe.Pointer(cname))
+	}
+
+	display := C.XOpenDisplay(cname)
+	if display == nil {
+		if name == "" {
+			name = os.Getenv("DISPLAY")
+		}
+		return nil, fmt.Errorf("无法连接 X 服务器（DISPLAY=%q）", name)
+	}
+	C.sc_install_error_handler()
+
+	c := &X11Capturer{display: display, useShm: C.sc_has_shm(display) != 0}
+	fmt.Printf("✓ X11 截图器已连接 (MIT-SHM: %v)\n", c.useShm)
+	return c, nil
+}
+
+// newPlatformCapturer Linux 使用 X11 截图
+func newPlatformCapturer() (Capturer, error) {
+	return NewX11Capturer("")
+}
+
+// rootBounds 根窗口范围（即虚拟屏幕）
+func (c *X11Capturer) rootBounds() image.Rectangle {
+	return image.Rect(0, 0, int(C.sc_root_width(c.display)), int(C.sc_root_height(c.display)))
+}
+
+// Monitors 通过 Xrandr 列出显示器，不可用时把整个根窗口视为一个显示器
+func (c *X11Capturer) Monitors() ([]Monitor, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.display == nil {
+		return nil, fmt.Errorf("截图器已关闭")
+	}
+
+	var rects [maxX11Monitors * 5]C.int
+	var names [maxX11Monitors * 64]C.char
+	n := int(C.sc_monitors(c.display, &rects[0], &names[0], maxX11Monitors))
+	if n <= 0 {
+		bounds := c.rootBounds()
+		return []Monitor{{Index: 0, Name: "screen", Bounds: bounds, WorkArea: bounds, DPI: x11DPI, Primary: true}}, nil
+	}
+
+	monitors := make([]Monitor, n)
+	for i := range monitors {
+		r := rects[i*5 : i*5+5]
+		bounds := image.Rect(int(r[0]), int(r[1]), int(r[0]+r[2]), int(r[1]+r[3]))
+		monitors[i] = Monitor{
+			Index:    i,
+			Name:     C.GoString(&names[i*64]),
+			Bounds:   bounds,
+			WorkArea: bounds,
+			DPI:      x11DPI,
+			Primary:  r[4] != 0,
+		}
+	}
+	return monitors, nil
+}
+
+// CaptureScreen 捕获整个根窗口
+func (c *X11Capturer) CaptureScreen() (image.Image, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.display == nil {
+		return nil, fmt.Errorf("截图器已关闭")
+	}
+	return c.capture(c.rootBounds())
+}
+
+// CaptureMonitor 捕获第 index 个显示器
+func (c *X11Capturer) CaptureMonitor(index int) (image.Image, error) {
+	return captureMonitor(c, index)
+}
+
+// CaptureRect 捕获根窗口中的矩形区域
+func (c *X11Capturer) CaptureRect(rect image.Rectangle) (image.Image, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.display == nil {
+		return nil, fmt.Errorf("截图器已关闭")
+	}
+
+	// 超出根窗口的区域会导致 BadMatch，先裁剪
+	rect, err := clipRect(rect, c.rootBounds())
+	if err != nil {
+		return nil, err
+	}
+	return c.capture(rect)
+}
+
+// capture 截取根窗口中的区域（调用方持有锁且 rect 已裁剪）
+func (c *X11Capturer) capture(rect image.Rectangle) (image.Image, error) {
+	w, h := rect.Dx(), rect.Dy()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	dst := (*C.uchar)(unsafe.Pointer(&img.Pix[0]))
+	x, y := C.int(rect.Min.X), C.int(rect.Min.Y)
+
+	ret := C.int(C.SC_GET_FAILED)
+	if c.useShm {
+		ret = C.sc_capture_shm(c.display, x, y, C.int(w), C.int(h), dst)
+		if ret == C.SC_GET_FAILED {
+			// 例如通过 SSH 转发的远程 X 服务器无法共享内存，之后直接使用 XGetImage
+			fmt.Println("⚠ X11 共享内存截图失败，改用 XGetImage")
+			c.useShm = false
+		}
+	}
+	if ret == C.SC_GET_FAILED {
+		ret = C.sc_capture_get(c.display, x, y, C.int(w), C.int(h), dst)
+	}
+
+	switch ret {
+	case C.SC_OK:
+	case C.SC_BAD_FORMAT:
+		return nil, fmt.Errorf("不支持的 X11 像素格式（仅支持 24/32 位真彩色）")
+	default:
+		return nil, fmt.Errorf("X11 截图失败")
+	}
+
+	// X11 内存布局为 BGRX，转换为 RGBA 并置为不透明
+	raster.Copy(img, img, raster.SwapRB|raster.Opaque|raster.Parallel)
+	return img, nil
+}
+
+// ForegroundWindow 窗口管理器记录的活动窗口（_NET_ACTIVE_WINDOW）
+func (c *X11Capturer) ForegroundWindow() (Window, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.display == nil {
+		return Window{}, fmt.Errorf("截图器已关闭")
+	}
+
+	w := C.sc_active_window(c.display)
+	if w == 0 {
+		return Window{}, fmt.Errorf("没有活动窗口（窗口管理器不支持 _NET_ACTIVE_WINDOW）")
+	}
+	return c.windowInfo(w)
+}
+
+// WindowAt 根窗口坐标 p 处的应用窗口
+func (c *X11Capturer) WindowAt(p image.Point) (Window, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.display == nil {
+		return Window{}, fmt.Errorf("截图器已关闭")
+	}
+
+	w := C.sc_window_at(c.display, C.int(p.X), C.int(p.Y))
+	if w == 0 {
+		return Window{}, fmt.Errorf("坐标 %v 处没有窗口", p)
+	}
+	return c.windowInfo(w)
+}
+
+// CursorPos 鼠标在根窗口中的位置
+func (c *X11Capturer) CursorPos() (image.Point, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.display == nil {
+		return image.Point{}, fmt.Errorf("截图器已关闭")
+	}
+
+	var x, y C.int
+	if C.sc_cursor(c.display, &x, &y) == 0 {
+		return image.Point{}, fmt.Errorf("获取鼠标位置失败")
+	}
+	return image.Pt(int(x), int(y)), nil
+}
+
+// windowInfo 读取窗口的标题、进程和范围（调用方持有锁）
+func (c *X11Capturer) windowInfo(w C.Window) (Window, error) {
+	var rect [4]C.int
+	if C.sc_window_geometry(c.display, w, &rect[0]) == 0 {
+		return Window{}, fmt.Errorf("获取窗口 %#x 的范围失败", uint64(w))
+	}
+
+	var title [maxX11Title]C.char
+	C.sc_window_title(c.display, w, &title[0], maxX11Title)
+	win := Window{
+		Handle: uintptr(w),
+		Title:  C.GoString(&title[0]),
+		PID:    int(C.sc_window_pid(c.display, w)),
+		Bounds: image.Rect(int(rect[0]), int(rect[1]), int(rect[0]+rect[2]), int(rect[1]+rect[3])),
+	}
+	if win.PID > 0 {
+		win.Process = procName(win.PID)
+	}
+	return win, nil
+}
+
+// procName 从 /proc 读取进程名，读取失败时返回空
+func procName(pid int) string {
+	data, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/comm")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// Close 断开与 X 服务器的连接
+func (c *X11Capturer) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.display != nil {
+		C.XCloseDisplay(c.display)
+		c.display = nil
+	}
+}
@@ -0,0 +1,59 @@
+//go:build linux && cgo
+
+package screenshot
+
+import (
+	"image"
+	"os"
+	"testing"
+)
+
+// TestX11Capturer 需要 X 服务器，可在 Xvfb 下运行：
+//
+//	xvfb-run -s "-screen 0 1024x768x24" go test ./internal/screenshot
+func TestX11Capturer(t *testing.T) {
+	if os.Getenv("DISPLAY") == "" {
+		t.Skip("没有设置 DISPLAY，跳过 X11 截图测试")
+	}
+	c, err := NewX11Capturer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	monitors, err := c.Monitors()
+	if err != nil || len(monitors) == 0 {
+		t.Fatalf("Monitors = %+v, %v", monitors, err)
+	}
+	screen := VirtualBounds(monitors)
+
+	img, err := c.CaptureScreen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Min != (image.Point{}) || img.Bounds().Size() != screen.Size() {
+		t.Errorf("整屏截图 %v，虚拟屏幕 %v", img.Bounds(), screen)
+	}
+	// 截图不透明
+	if _, _, _, a := img.At(0, 0).RGBA(); a != 0xffff {
+		t.Errorf("像素 alpha = %#x", a)
+	}
+
+	// 超出屏幕的区域被裁剪
+	rect := image.Rectangle{Min: screen.Max.Sub(image.Pt(50, 40)), Max: screen.Max.Add(image.Pt(100, 100))}
+	img, err = c.CaptureRect(rect)
+	if err != nil || img.Bounds().Size() != image.Pt(50, 40) {
+		t.Errorf("CaptureRect = %v, %v", img.Bounds(), err)
+	}
+
+	layoutImg, layout, err := CaptureLayout(c)
+	if err != nil || layout.Bounds != screen || layoutImg.Bounds().Size() != screen.Size() {
+		t.Errorf("CaptureLayout = %v, %+v, %v", layoutImg.Bounds(), layout, err)
+	}
+
+	// 关闭后的调用报错
+	c.Close()
+	if _, err := c.CaptureScreen(); err == nil {
+		t.Error("关闭后截图应当报错")
+	}
+}
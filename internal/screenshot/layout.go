@@ -0,0 +1,206 @@
+package screenshot
+
+import (
+	"fmt"
+	"image"
+)
+
+// spaceKind 坐标系种类
+type spaceKind int
+
+const (
+	spaceImage spaceKind = iota
+	spaceScreen
+	spaceLogical
+	spaceMonitor
+)
+
+// Space 坐标系
+//
+//   - ImageSpace: 截图像素，原点为截图左上角，OCR 结果和覆盖层窗口的客户区坐标都使用它
+//   - ScreenSpace: 虚拟屏幕物理像素，多显示器时原点可能为负，截图区域和窗口位置使用它
+//   - LogicalSpace: 逻辑像素，以所在显示器左上角为锚点按缩放比例换算，用于按 DPI 缩放的界面
+//   - MonitorSpace(i): 第 i 个显示器内的物理像素，原点为显示器左上角
+type Space struct {
+	kind    spaceKind
+	monitor int
+}
+
+var (
+	ImageSpace   = Space{kind: spaceImage}
+	ScreenSpace  = Space{kind: spaceScreen}
+	LogicalSpace = Space{kind: spaceLogical}
+)
+
+// MonitorSpace 第 index 个显示器内的坐标系
+func MonitorSpace(index int) Space {
+	return Space{kind: spaceMonitor, monitor: index}
+}
+
+// String 坐标系名称
+func (s Space) String() string {
+	switch s.kind {
+	case spaceImage:
+		return "image"
+	case spaceScreen:
+		return "screen"
+	case spaceLogical:
+		return "logical"
+	default:
+		return fmt.Sprintf("monitor%d", s.monitor)
+	}
+}
+
+// Layout 一次截图对应的显示器布局，用于在各坐标系之间转换坐标
+// 截图、OCR 结果和覆盖层命中测试应使用同一个 Layout，避免显示器变化后坐标错位
+type Layout struct {
+	Monitors []Monitor
+	Bounds   image.Rectangle // 截图在虚拟屏幕中的范围（物理像素）
+}
+
+// NewLayout 创建显示器布局，WorkArea 为空的显示器使用 Bounds
+func NewLayout(monitors []Monitor, bounds image.Rectangle) *Layout {
+	l := &Layout{Monitors: make([]Monitor, len(monitors)), Bounds: bounds}
+	for i, m := range monitors {
+		if m.WorkArea.Empty() {
+			m.WorkArea = m.Bounds
+		}
+		l.Monitors[i] = m
+	}
+	return l
+}
+
+// Primary 主显示器序号，没有标记主显示器时返回包含虚拟屏幕原点的显示器
+func (l *Layout) Primary() int {
+	for i, m := range l.Monitors {
+		if m.Primary {
+			return i
+		}
+	}
+	return l.MonitorAt(image.Point{})
+}
+
+// MonitorAt 包含虚拟屏幕坐标 p 的显示器序号，不在任何显示器内时返回最近的，没有显示器时返回 -1
+func (l *Layout) MonitorAt(p image.Point) int {
+	return nearestMonitor(l.Monitors, p, func(m Monitor) image.Rectangle { return m.Bounds })
+}
+
+// Convert 把点 p 从坐标系 from 转换到 to
+// 逻辑坐标按点所在的显示器缩放：物理像素属于包含其左上角的逻辑像素，逻辑像素转换为其中的第一个物理像素，
+// 因此缩放比例不小于 100% 时逻辑坐标转换到其他坐标系再转换回来保持不变；
+// 显示器序号无效时显示器坐标按虚拟屏幕坐标处理
+func (l *Layout) Convert(p image.Point, from, to Space) image.Point {
+	if from == to {
+		return p
+	}
+	screen := l.toScreen(p, from, l.logicalMonitor(p, from))
+	return l.fromScreen(screen, to, l.MonitorAt(screen))
+}
+
+// ConvertRect 把矩形从坐标系 from 转换到 to
+// 跨显示器的矩形按其中心所在的显示器缩放，转换后的矩形覆盖原矩形的所有像素
+func (l *Layout) ConvertRect(r image.Rectangle, from, to Space) image.Rectangle {
+	if from == to {
+		return r
+	}
+	r = r.Canon()
+	center := r.Min.Add(r.Max).Div(2)
+	fromMon := l.logicalMonitor(center, from)
+	toMon := l.MonitorAt(l.toScreen(center, from, fromMon))
+
+	// 右下角是不包含的边界：转换最后一个像素，再加 1
+	one := image.Pt(1, 1)
+	topLeft := l.toScreen(r.Min, from, fromMon)
+	bottomRight := l.toScreen(r.Max, from, fromMon)
+	return image.Rectangle{
+		Min: l.fromScreen(topLeft, to, toMon),
+		Max: l.fromScreen(bottomRight.Sub(one), to, toMon).Add(one),
+	}
+}
+
+// logicalMonitor 逻辑坐标 p 所在的显示器（from 不是逻辑坐标时返回 -1）
+func (l *Layout) logicalMonitor(p image.Point, from Space) int {
+	if from.kind != spaceLogical {
+		return -1
+	}
+	return nearestMonitor(l.Monitors, p, Monitor.LogicalBounds)
+}
+
+// toScreen 转换到虚拟屏幕坐标，mon 为逻辑坐标所在的显示器
+func (l *Layout) toScreen(p image.Point, from Space, mon int) image.Point {
+	switch from.kind {
+	case spaceImage:
+		return p.Add(l.Bounds.Min)
+	case spaceLogical:
+		if mon < 0 {
+			return p
+		}
+		m := l.Monitors[mon]
+		return m.Bounds.Min.Add(toPhysical(p.Sub(m.Bounds.Min), m.dpi()))
+	case spaceMonitor:
+		return p.Add(l.monitorOrigin(from.monitor))
+	}
+	return p
+}
+
+// fromScreen 从虚拟屏幕坐标转换，mon 为逻辑坐标使用的显示器
+func (l *Layout) fromScreen(p image.Point, to Space, mon int) image.Point {
+	switch to.kind {
+	case spaceImage:
+		return p.Sub(l.Bounds.Min)
+	case spaceLogical:
+		if mon < 0 {
+			return p
+		}
+		m := l.Monitors[mon]
+		return m.Bounds.Min.Add(toLogical(p.Sub(m.Bounds.Min), m.dpi()))
+	case spaceMonitor:
+		return p.Sub(l.monitorOrigin(to.monitor))
+	}
+	return p
+}
+
+// monitorOrigin 显示器左上角的虚拟屏幕坐标，序号无效时为 (0, 0)
+func (l *Layout) monitorOrigin(index int) image.Point {
+	if index < 0 || index >= len(l.Monitors) {
+		return image.Point{}
+	}
+	return l.Monitors[index].Bounds.Min
+}
+
+// toLogical 显示器内的物理像素偏移换算为所在逻辑像素的偏移：floor(p * 96 / dpi)
+// 用整数运算，避免 125%、175% 等缩放比例的浮点误差在边缘处多算或少算 1 像素
+func toLogical(p image.Point, dpi int) image.Point {
+	return image.Pt(floorDiv(p.X*96, dpi), floorDiv(p.Y*96, dpi))
+}
+
+// toPhysical 逻辑像素偏移换算为其中第一个物理像素的偏移：ceil(p * dpi / 96)
+func toPhysical(p image.Point, dpi int) image.Point {
+	return image.Pt(-floorDiv(-p.X*dpi, 96), -floorDiv(-p.Y*dpi, 96))
+}
+
+// floorDiv 向下取整的整数除法（b > 0），负数偏移（显示器左侧、上方的点）也向下取整
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
+}
+
+// nearestMonitor 范围包含 p 的显示器序号，都不包含时返回离 p 最近的，没有显示器时返回 -1
+func nearestMonitor(monitors []Monitor, p image.Point, bounds func(Monitor) image.Rectangle) int {
+	best, bestDist := -1, 0
+	for i, m := range monitors {
+		r := bounds(m)
+		if p.In(r) {
+			return i
+		}
+		dx := max(r.Min.X-p.X, 0, p.X-(r.Max.X-1))
+		dy := max(r.Min.Y-p.Y, 0, p.Y-(r.Max.Y-1))
+		if d := dx*dx + dy*dy; best < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
@@ -0,0 +1,181 @@
+package screenshot
+
+import (
+	"image"
+	"math/rand"
+	"testing"
+)
+
+// mixedLayout 三台不同缩放的显示器：左侧 150%（原点为负、比主显示器高出 300 像素），
+// 中间 125% 的主显示器，右侧 175%
+func mixedLayout() *Layout {
+	monitors := []Monitor{
+		{Index: 0, Bounds: image.Rect(-1920, -300, 0, 780), DPI: 144},
+		{Index: 1, Bounds: image.Rect(0, 0, 2560, 1440), WorkArea: image.Rect(0, 0, 2560, 1392), DPI: 120, Primary: true},
+		{Index: 2, Bounds: image.Rect(2560, 200, 4240, 1250), DPI: 168},
+	}
+	return NewLayout(monitors, VirtualBounds(monitors))
+}
+
+func TestLayoutConvert(t *testing.T) {
+	l := mixedLayout()
+	cases := []struct {
+		name     string
+		p        image.Point
+		from, to Space
+		want     image.Point
+	}{
+		{"screen-to-image", image.Pt(100, 100), ScreenSpace, ImageSpace, image.Pt(2020, 400)},
+		{"image-origin", image.Pt(0, 0), ImageSpace, ScreenSpace, image.Pt(-1920, -300)},
+		{"monitor-to-screen", image.Pt(5, 5), MonitorSpace(0), ScreenSpace, image.Pt(-1915, -295)},
+		{"screen-to-monitor", image.Pt(2561, 201), ScreenSpace, MonitorSpace(2), image.Pt(1, 1)},
+		// 显示器序号无效时按虚拟屏幕坐标处理
+		{"invalid-monitor", image.Pt(5, 5), MonitorSpace(7), ScreenSpace, image.Pt(5, 5)},
+
+		// 150%：物理像素 3 位于逻辑像素 2 内（3 / 1.5 = 2）
+		{"150-to-logical", image.Pt(-1917, -300), ScreenSpace, LogicalSpace, image.Pt(-1918, -300)},
+		// 逻辑像素 3 从物理像素 4.5 开始，对应物理像素 5
+		{"150-from-logical", image.Pt(-1917, -299), LogicalSpace, ScreenSpace, image.Pt(-1915, -298)},
+		{"150-last-pixel", image.Pt(-1, 779), ScreenSpace, LogicalSpace, image.Pt(-641, 419)},
+		{"125-last-pixel", image.Pt(2559, 1439), ScreenSpace, LogicalSpace, image.Pt(2047, 1151)},
+		{"125-from-logical", image.Pt(2047, 1151), LogicalSpace, ScreenSpace, image.Pt(2559, 1439)},
+		{"175-to-logical", image.Pt(2562, 210), ScreenSpace, LogicalSpace, image.Pt(2561, 205)},
+		{"175-to-image", image.Pt(2561, 205), LogicalSpace, ImageSpace, image.Pt(4482, 509)},
+		// 不在任何显示器内的点按最近的显示器（左侧）换算，显示器下方的偏移同样向下取整
+		{"outside-nearest", image.Pt(-500, 1000), ScreenSpace, LogicalSpace, image.Pt(-974, 566)},
+		{"left-of-monitor", image.Pt(-1921, -300), ScreenSpace, LogicalSpace, image.Pt(-1921, -300)},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := l.Convert(c.p, c.from, c.to); got != c.want {
+				t.Errorf("Convert(%v, %v → %v) = %v，期望 %v", c.p, c.from, c.to, got, c.want)
+			}
+		})
+	}
+}
+
+func TestLayoutConvertRect(t *testing.T) {
+	l := mixedLayout()
+	cases := []struct {
+		name     string
+		r        image.Rectangle
+		from, to Space
+		want     image.Rectangle
+	}{
+		{"monitor-150", image.Rect(-1920, -300, 0, 780), ScreenSpace, LogicalSpace, image.Rect(-1920, -300, -640, 420)},
+		{"monitor-125", image.Rect(0, 0, 2560, 1440), ScreenSpace, LogicalSpace, image.Rect(0, 0, 2048, 1152)},
+		{"monitor-175", image.Rect(2560, 200, 4240, 1250), ScreenSpace, LogicalSpace, image.Rect(2560, 200, 3520, 800)},
+		// 左上角向下取整、右下角包含最后一个物理像素所在的逻辑像素
+		{"small-150", image.Rect(-1919, -299, -1913, -295), ScreenSpace, LogicalSpace, image.Rect(-1920, -300, -1915, -297)},
+		// 一个逻辑像素在 150% 下覆盖两个物理像素
+		{"one-logical-150", image.Rect(-1920, -300, -1919, -299), LogicalSpace, ScreenSpace, image.Rect(-1920, -300, -1918, -298)},
+		{"logical-125", image.Rect(0, 0, 8, 8), LogicalSpace, ScreenSpace, image.Rect(0, 0, 10, 10)},
+		{"image-to-screen", image.Rect(10, 10, 20, 20), ImageSpace, ScreenSpace, image.Rect(-1910, -290, -1900, -280)},
+		// 跨显示器的矩形按中心所在的主显示器缩放
+		{"across-monitors", image.Rect(-10, 100, 30, 140), ScreenSpace, LogicalSpace, image.Rect(-8, 80, 24, 112)},
+		{"not-canonical", image.Rect(20, 20, 10, 10), ImageSpace, ScreenSpace, image.Rect(-1910, -290, -1900, -280)},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := l.ConvertRect(c.r, c.from, c.to); got != c.want {
+				t.Errorf("ConvertRect(%v, %v → %v) = %v，期望 %v", c.r, c.from, c.to, got, c.want)
+			}
+		})
+	}
+
+	for i, m := range l.Monitors {
+		if got := l.ConvertRect(m.Bounds, ScreenSpace, LogicalSpace); got != m.LogicalBounds() {
+			t.Errorf("显示器 %d 的逻辑范围 %v，LogicalBounds 为 %v", i, got, m.LogicalBounds())
+		}
+	}
+}
+
+// TestLayoutRoundTrip 每台显示器内（含边缘像素）逻辑坐标转换到物理坐标再转换回来保持不变，
+// 物理坐标转换回来后落在同一个逻辑像素内，矩形转换回来后覆盖原矩形
+func TestLayoutRoundTrip(t *testing.T) {
+	l := mixedLayout()
+	rng := rand.New(rand.NewSource(1))
+	for i, m := range l.Monitors {
+		lb := m.LogicalBounds()
+		var points []image.Point
+		for _, x := range []int{lb.Min.X, lb.Min.X + 1, lb.Min.X + 2, lb.Min.X + 3, lb.Max.X - 2, lb.Max.X - 1} {
+			for _, y := range []int{lb.Min.Y, lb.Min.Y + 1, lb.Min.Y + 3, lb.Max.Y - 1} {
+				points = append(points, image.Pt(x, y))
+			}
+		}
+		for j := 0; j < 200; j++ {
+			points = append(points, image.Pt(lb.Min.X+rng.Intn(lb.Dx()), lb.Min.Y+rng.Intn(lb.Dy())))
+		}
+
+		for _, p := range points {
+			s := l.Convert(p, LogicalSpace, ScreenSpace)
+			if !s.In(m.Bounds) {
+				t.Errorf("显示器 %d: 逻辑坐标 %v 转换到 %v，不在显示器内", i, p, s)
+				continue
+			}
+			for _, space := range []Space{ScreenSpace, ImageSpace, MonitorSpace(i)} {
+				q := l.Convert(p, LogicalSpace, space)
+				if back := l.Convert(q, space, LogicalSpace); back != p {
+					t.Errorf("显示器 %d: 逻辑坐标 %v → %v %v → %v", i, p, space, q, back)
+				}
+			}
+		}
+
+		for j := 0; j < 200; j++ {
+			s := image.Pt(m.Bounds.Min.X+rng.Intn(m.Bounds.Dx()), m.Bounds.Min.Y+rng.Intn(m.Bounds.Dy()))
+			logical := l.Convert(s, ScreenSpace, LogicalSpace)
+			back := l.Convert(logical, LogicalSpace, ScreenSpace)
+			if back.X > s.X || back.Y > s.Y || l.Convert(back, ScreenSpace, LogicalSpace) != logical {
+				t.Errorf("显示器 %d: 物理坐标 %v → %v → %v，应为同一逻辑像素中的第一个物理像素", i, s, logical, back)
+			}
+
+			size := image.Pt(1+rng.Intn(40), 1+rng.Intn(40))
+			r := image.Rectangle{Min: s, Max: s.Add(size)}.Intersect(m.Bounds)
+			lr := l.ConvertRect(r, ScreenSpace, LogicalSpace)
+			if sr := l.ConvertRect(lr, LogicalSpace, ScreenSpace); !r.In(sr) || sr.Dx() > r.Dx()+2 || sr.Dy() > r.Dy()+2 {
+				t.Errorf("显示器 %d: 矩形 %v → %v → %v，应覆盖原矩形且最多各边多出 1 像素", i, r, lr, sr)
+			}
+			if again := l.ConvertRect(l.ConvertRect(lr, LogicalSpace, ScreenSpace), ScreenSpace, LogicalSpace); again != lr {
+				t.Errorf("显示器 %d: 逻辑矩形 %v 转换回来为 %v", i, lr, again)
+			}
+		}
+	}
+}
+
+func TestLayoutMonitorAt(t *testing.T) {
+	l := mixedLayout()
+	cases := []struct {
+		p    image.Point
+		want int
+	}{
+		{image.Pt(-1, 0), 0}, {image.Pt(0, 0), 1}, {image.Pt(2560, 200), 2},
+		// 不在任何显示器内时取最近的
+		{image.Pt(5000, 0), 2}, {image.Pt(-100, -200), 0}, {image.Pt(3000, 1400), 2},
+	}
+	for _, c := range cases {
+		if got := l.MonitorAt(c.p); got != c.want {
+			t.Errorf("MonitorAt(%v) = %d，期望 %d", c.p, got, c.want)
+		}
+	}
+	if got := l.Primary(); got != 1 {
+		t.Errorf("Primary = %d", got)
+	}
+	if l.Monitors[0].WorkArea != l.Monitors[0].Bounds || l.Monitors[1].WorkArea.Dy() != 1392 {
+		t.Error("没有工作区的显示器应使用 Bounds，已有的保留")
+	}
+
+	// 没有标记主显示器时取包含原点的显示器
+	unmarked := NewLayout([]Monitor{{Bounds: image.Rect(-800, 0, 0, 600)}, {Bounds: image.Rect(0, 0, 800, 600)}}, image.Rect(-800, 0, 800, 600))
+	if got := unmarked.Primary(); got != 1 {
+		t.Errorf("没有标记时 Primary = %d", got)
+	}
+
+	// 没有显示器时逻辑坐标与屏幕坐标相同
+	empty := NewLayout(nil, image.Rect(0, 0, 100, 100))
+	if got := empty.MonitorAt(image.Pt(1, 1)); got != -1 {
+		t.Errorf("没有显示器时 MonitorAt = %d", got)
+	}
+	if got := empty.Convert(image.Pt(7, 9), ScreenSpace, LogicalSpace); got != image.Pt(7, 9) {
+		t.Errorf("没有显示器时 Convert = %v", got)
+	}
+}
@@ -0,0 +1,104 @@
+//go:build windows
+
+package screenshot
+
+import (
+	"fmt"
+	"image"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	procEnumDisplayMonitors = user32.NewProc("EnumDisplayMonitors")
+	procGetMonitorInfoW     = user32.NewProc("GetMonitorInfoW")
+	procGetDpiForMonitor    = shcore.NewProc("GetDpiForMonitor")
+	procGetDeviceCaps       = gdi32.NewProc("GetDeviceCaps")
+)
+
+const (
+	MONITORINFOF_PRIMARY = 0x1
+	MDT_EFFECTIVE_DPI    = 0
+	LOGPIXELSX           = 88
+)
+
+// RECT Windows 矩形
+type RECT struct {
+	Left, Top, Right, Bottom int32
+}
+
+// MONITORINFOEXW 显示器信息
+type MONITORINFOEXW struct {
+	CbSize    uint32
+	RcMonitor RECT
+	RcWork    RECT
+	DwFlags   uint32
+	SzDevice  [32]uint16
+}
+
+var (
+	// EnumDisplayMonitors 的回调只创建一次（syscall.NewCallback 数量有限），通过 enumTarget 传递结果
+	enumMu       sync.Mutex
+	enumTarget   *[]Monitor
+	enumCallback = syscall.NewCallback(func(hMonitor, hdc, rect, lParam uintptr) uintptr {
+		info := MONITORINFOEXW{}
+		info.CbSize = uint32(unsafe.Sizeof(info))
+		ret, _, _ := procGetMonitorInfoW.Call(hMonitor, uintptr(unsafe.Pointer(&info)))
+		if ret != 0 && enumTarget != nil {
+			*enumTarget = append(*enumTarget, Monitor{
+				Index:    len(*enumTarget),
+				Name:     syscall.UTF16ToString(info.SzDevice[:]),
+				Bounds:   rectToImage(info.RcMonitor),
+				WorkArea: rectToImage(info.RcWork),
+				DPI:      monitorDPI(hMonitor),
+				Primary:  info.DwFlags&MONITORINFOF_PRIMARY != 0,
+			})
+		}
+		return 1 // 继续枚举
+	})
+)
+
+// monitorDPI 显示器的有效 DPI
+// 进程按显示器感知 DPI 时（见 setDPIAware）各显示器可能不同；Windows 8.1 之前没有 GetDpiForMonitor，使用系统 DPI
+func monitorDPI(hMonitor uintptr) int {
+	if procGetDpiForMonitor.Find() == nil {
+		var dpiX, dpiY uint32
+		ret, _, _ := procGetDpiForMonitor.Call(hMonitor, MDT_EFFECTIVE_DPI,
+			uintptr(unsafe.Pointer(&dpiX)), uintptr(unsafe.Pointer(&dpiY)))
+		if ret == 0 && dpiX > 0 { // S_OK
+			return int(dpiX)
+		}
+	}
+
+	hdc, _, _ := procGetDC.Call(0)
+	if hdc == 0 {
+		return 0
+	}
+	defer procReleaseDC.Call(0, hdc)
+	dpi, _, _ := procGetDeviceCaps.Call(hdc, LOGPIXELSX)
+	return int(dpi)
+}
+
+// rectToImage 把 Windows RECT 转为 image.Rectangle
+func rectToImage(r RECT) image.Rectangle {
+	return image.Rect(int(r.Left), int(r.Top), int(r.Right), int(r.Bottom))
+}
+
+// Monitors 通过 EnumDisplayMonitors 列出显示器
+func (c *GDICapturer) Monitors() ([]Monitor, error) {
+	enumMu.Lock()
+	defer enumMu.Unlock()
+
+	var monitors []Monitor
+	enumTarget = &monitors
+	ret, _, err := procEnumDisplayMonitors.Call(0, 0, enumCallback, 0)
+	enumTarget = nil
+	if ret == 0 {
+		return nil, fmt.Errorf("枚举显示器失败: %v", err)
+	}
+	if len(monitors) == 0 {
+		return nil, fmt.Errorf("没有找到显示器")
+	}
+	return monitors, nil
+}
@@ -0,0 +1,114 @@
+// Package screenshot 屏幕截图
+//
+// Capturer 接口屏蔽平台差异：Windows 使用 GDI（GDICapturer），Linux 使用 X11（X11Capturer，需要 cgo），
+// ImageCapturer 从内存图片或图片文件“截图”，用于测试和没有显示器的环境。
+// 所有坐标都是虚拟屏幕坐标（物理像素，多显示器时原点可能为负），返回的图片原点为 (0, 0)；
+// 截图像素、虚拟屏幕、逻辑坐标和显示器内坐标之间的转换见 Layout。
+package screenshot
+
+import (
+	"fmt"
+	"image"
+)
+
+// Monitor 显示器
+type Monitor struct {
+	Index    int             // 在 Monitors() 结果中的序号
+	Name     string          // 系统给出的名称（如 \\.\DISPLAY1、DP-1），可能为空
+	Bounds   image.Rectangle // 虚拟屏幕坐标中的范围
+	WorkArea image.Rectangle // 除去任务栏等之后的可用范围，未知时与 Bounds 相同
+	DPI      int             // 有效 DPI（96 为 100% 缩放），未知时为 0
+	Primary  bool            // 是否为主显示器
+}
+
+// Scale 缩放比例（DPI / 96），DPI 未知时为 1
+func (m Monitor) Scale() float64 {
+	if m.DPI <= 0 {
+		return 1
+	}
+	return float64(m.DPI) / 96
+}
+
+// dpi 有效 DPI，未知时为 96
+func (m Monitor) dpi() int {
+	if m.DPI <= 0 {
+		return 96
+	}
+	return m.DPI
+}
+
+// LogicalBounds 显示器在逻辑坐标中的范围（包含最后一个物理像素所在的逻辑像素）
+func (m Monitor) LogicalBounds() image.Rectangle {
+	last := toLogical(m.Bounds.Size().Sub(image.Pt(1, 1)), m.dpi())
+	return image.Rectangle{Min: m.Bounds.Min, Max: m.Bounds.Min.Add(last).Add(image.Pt(1, 1))}
+}
+
+// Capturer 屏幕截图器
+type Capturer interface {
+	// Monitors 列出所有显示器，主显示器不一定排在第一个
+	Monitors() ([]Monitor, error)
+
+	// CaptureScreen 捕获整个虚拟屏幕（所有显示器的外接矩形）
+	CaptureScreen() (image.Image, error)
+
+	// CaptureMonitor 捕获第 index 个显示器
+	CaptureMonitor(index int) (image.Image, error)
+
+	// CaptureRect 捕获虚拟屏幕坐标中的矩形区域，超出屏幕的部分会被裁剪
+	CaptureRect(rect image.Rectangle) (image.Image, error)
+
+	// Close 释放截图器占用的资源
+	Close()
+}
+
+// NewCapturer 创建当前平台的截图器
+func NewCapturer() (Capturer, error) {
+	return newPlatformCapturer()
+}
+
+// VirtualBounds 返回所有显示器的外接矩形
+func VirtualBounds(monitors []Monitor) image.Rectangle {
+	var bounds image.Rectangle
+	for _, m := range monitors {
+		bounds = bounds.Union(m.Bounds)
+	}
+	return bounds
+}
+
+// CaptureLayout 截取所有显示器的外接矩形，同时返回截图对应的显示器布局
+func CaptureLayout(c Capturer) (image.Image, *Layout, error) {
+	monitors, err := c.Monitors()
+	if err != nil {
+		return nil, nil, err
+	}
+	bounds := VirtualBounds(monitors)
+	img, err := c.CaptureRect(bounds)
+	if err != nil {
+		return nil, nil, err
+	}
+	// CaptureRect 会裁剪到屏幕范围，截图实际范围以返回的图片为准
+	size := img.Bounds().Size()
+	bounds = image.Rectangle{Min: bounds.Min, Max: bounds.Min.Add(size)}
+	return img, NewLayout(monitors, bounds), nil
+}
+
+// captureMonitor 按显示器序号截图，供各实现共用
+func captureMonitor(c Capturer, index int) (image.Image, error) {
+	monitors, err := c.Monitors()
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= len(monitors) {
+		return nil, fmt.Errorf("显示器 %d 不存在（共 %d 个）", index, len(monitors))
+	}
+	return c.CaptureRect(monitors[index].Bounds)
+}
+
+// clipRect 把截图区域裁剪到屏幕范围内
+func clipRect(rect, screen image.Rectangle) (image.Rectangle, error) {
+	clipped := rect.Canon().Intersect(screen)
+	if clipped.Empty() {
+		return clipped, fmt.Errorf("截图区域 %v 不在屏幕范围 %v 内", rect, screen)
+	}
+	return clipped, nil
+}
@@ -0,0 +1,82 @@
+package screenshot
+
+import (
+	"fmt"
+	"image"
+)
+
+// Window 顶层窗口及其来源信息
+type Window struct {
+	Handle  uintptr         // 平台窗口句柄（Windows HWND / X11 Window），0 表示未知
+	Title   string          // 窗口标题，可能为空
+	Process string          // 进程名（如 chrome.exe、firefox），未知时为空
+	PID     int             // 进程 ID，未知时为 0
+	Bounds  image.Rectangle // 虚拟屏幕坐标中的可见范围（不含阴影等不可见边框）
+}
+
+// Label 用于显示和导出的来源标签，如 "chrome.exe — 新标签页"
+func (w Window) Label() string {
+	switch {
+	case w.Process != "" && w.Title != "":
+		return w.Process + " — " + w.Title
+	case w.Title != "":
+		return w.Title
+	case w.Process != "":
+		return w.Process
+	}
+	return fmt.Sprintf("窗口 %#x", w.Handle)
+}
+
+// WindowCapturer 支持按窗口截图的截图器（可选接口，用类型断言检查）
+type WindowCapturer interface {
+	// ForegroundWindow 当前前台（活动）窗口
+	ForegroundWindow() (Window, error)
+
+	// WindowAt 虚拟屏幕坐标 p 处的顶层窗口
+	WindowAt(p image.Point) (Window, error)
+
+	// CursorPos 鼠标位置（虚拟屏幕坐标）
+	CursorPos() (image.Point, error)
+}
+
+// ForegroundWindow 截图器的前台窗口，不支持窗口查询时返回错误
+func ForegroundWindow(c Capturer) (Window, error) {
+	wc, ok := c.(WindowCapturer)
+	if !ok {
+		return Window{}, fmt.Errorf("截图器不支持窗口查询")
+	}
+	return wc.ForegroundWindow()
+}
+
+// WindowUnderCursor 鼠标下的顶层窗口，不支持窗口查询时返回错误
+func WindowUnderCursor(c Capturer) (Window, error) {
+	wc, ok := c.(WindowCapturer)
+	if !ok {
+		return Window{}, fmt.Errorf("截图器不支持窗口查询")
+	}
+	p, err := wc.CursorPos()
+	if err != nil {
+		return Window{}, err
+	}
+	return wc.WindowAt(p)
+}
+
+// CaptureWindow 截取窗口的可见范围，同时返回截图对应的显示器布局
+// 窗口超出屏幕的部分会被裁剪；截图时只取屏幕像素，被其他窗口遮挡的部分会一并截入
+func CaptureWindow(c Capturer, w Window) (image.Image, *Layout, error) {
+	monitors, err := c.Monitors()
+	if err != nil {
+		return nil, nil, err
+	}
+	rect, err := clipRect(w.Bounds, VirtualBounds(monitors))
+	if err != nil {
+		return nil, nil, fmt.Errorf("窗口 %q 不在屏幕内: %w", w.Label(), err)
+	}
+	img, err := c.CaptureRect(rect)
+	if err != nil {
+		return nil, nil, err
+	}
+	size := img.Bounds().Size()
+	bounds := image.Rectangle{Min: rect.Min, Max: rect.Min.Add(size)}
+	return img, NewLayout(monitors, bounds), nil
+}
@@ -0,0 +1,106 @@
+package screenshot
+
+import (
+	"image"
+	"strings"
+	"testing"
+)
+
+// testWindows 按 Z 序从上到下：前台的编辑器、部分移出屏幕的浏览器和完全在屏幕外的窗口
+var testWindows = []Window{
+	{Handle: 0x1001, Title: "notes.txt", Process: "notepad.exe", PID: 10, Bounds: image.Rect(20, 30, 220, 180)},
+	{Handle: 0x1002, Title: "新标签页", Process: "chrome.exe", PID: 20, Bounds: image.Rect(-350, 60, -100, 200)},
+	{Handle: 0x1003, Process: "hidden.exe", Bounds: image.Rect(2000, 0, 2100, 100)},
+}
+
+func TestCaptureWindow(t *testing.T) {
+	c := NewImageCapturer(gridImage(image.Rect(-300, 0, 400, 300)), dualMonitors...)
+	c.SetWindows(testWindows...)
+
+	cases := []struct {
+		name    string
+		window  Window
+		want    image.Rectangle // 截取的屏幕范围
+		wantErr string
+	}{
+		{"inside", testWindows[0], testWindows[0].Bounds, ""},
+		// 超出屏幕的部分被裁剪
+		{"partly-offscreen", testWindows[1], image.Rect(-300, 60, -100, 200), ""},
+		{"offscreen", testWindows[2], image.Rectangle{}, `窗口 "hidden.exe" 不在屏幕内`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			img, layout, err := CaptureWindow(c, tc.window)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Errorf("错误 %v，应包含 %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkCapture(t, img, tc.want)
+			if layout.Bounds != tc.want || len(layout.Monitors) != len(dualMonitors) {
+				t.Errorf("布局 %+v", layout)
+			}
+		})
+	}
+}
+
+func TestWindowQueries(t *testing.T) {
+	c := NewImageCapturer(gridImage(image.Rect(-300, 0, 400, 300)), dualMonitors...)
+	if _, err := ForegroundWindow(c); err == nil {
+		t.Error("没有窗口时应当报错")
+	}
+
+	c.SetWindows(testWindows...)
+	if w, err := ForegroundWindow(c); err != nil || w.Handle != 0x1001 {
+		t.Errorf("ForegroundWindow = %+v, %v", w, err)
+	}
+	cases := []struct {
+		cursor image.Point
+		want   uintptr // 0 表示没有窗口
+	}{
+		{image.Pt(100, 100), 0x1001},
+		{image.Pt(-200, 100), 0x1002},
+		{image.Pt(300, 250), 0},
+	}
+	for _, tc := range cases {
+		c.SetCursor(tc.cursor)
+		w, err := WindowUnderCursor(c)
+		if tc.want == 0 {
+			if err == nil {
+				t.Errorf("%v 处不应有窗口，得到 %+v", tc.cursor, w)
+			}
+			continue
+		}
+		if err != nil || w.Handle != tc.want {
+			t.Errorf("WindowUnderCursor(%v) = %+v, %v", tc.cursor, w, err)
+		}
+	}
+
+	// 不支持窗口查询的截图器
+	var plain struct{ Capturer }
+	plain.Capturer = c
+	if _, err := ForegroundWindow(plain); err == nil || !strings.Contains(err.Error(), "不支持窗口查询") {
+		t.Errorf("ForegroundWindow: %v", err)
+	}
+	if _, err := WindowUnderCursor(plain); err == nil || !strings.Contains(err.Error(), "不支持窗口查询") {
+		t.Errorf("WindowUnderCursor: %v", err)
+	}
+}
+
+func TestWindowLabel(t *testing.T) {
+	cases := map[string]Window{
+		"chrome.exe — 新标签页": {Process: "chrome.exe", Title: "新标签页"},
+		"新标签页":              {Title: "新标签页"},
+		"chrome.exe":        {Process: "chrome.exe"},
+		"窗口 0x1a2b":         {Handle: 0x1a2b},
+	}
+	for want, w := range cases {
+		if got := w.Label(); got != want {
+			t.Errorf("Label = %q，期望 %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+//go:build windows
+
+package screenshot
+
+import (
+	"fmt"
+	"image"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32 = syscall.NewLazyDLL("kernel32.dll")
+	dwmapi   = syscall.NewLazyDLL("dwmapi.dll")
+
+	procGetForegroundWindow        = user32.NewProc("GetForegroundWindow")
+	procWindowFromPoint            = user32.NewProc("WindowFromPoint")
+	procGetAncestor                = user32.NewProc("GetAncestor")
+	procGetCursorPos               = user32.NewProc("GetCursorPos")
+	procGetWindowRect              = user32.NewProc("GetWindowRect")
+	procGetWindowTextW             = user32.NewProc("GetWindowTextW")
+	procGetWindowTextLengthW       = user32.NewProc("GetWindowTextLengthW")
+	procGetWindowThreadProcessId   = user32.NewProc("GetWindowThreadProcessId")
+	procOpenProcess                = kernel32.NewProc("OpenProcess")
+	procCloseHandle                = kernel32.NewProc("CloseHandle")
+	procQueryFullProcessImageNameW = kernel32.NewProc("QueryFullProcessImageNameW")
+	procDwmGetWindowAttribute      = dwmapi.NewProc("DwmGetWindowAttribute")
+)
+
+const (
+	GA_ROOT                           = 2
+	PROCESS_QUERY_LIMITED_INFORMATION = 0x1000
+	DWMWA_EXTENDED_FRAME_BOUNDS       = 9
+)
+
+// POINT Windows 点坐标
+type POINT struct {
+	X, Y int32
+}
+
+// ForegroundWindow 当前前台窗口
+func (c *GDICapturer) ForegroundWindow() (Window, error) {
+	hwnd, _, _ := procGetForegroundWindow.Call()
+	if hwnd == 0 {
+		return Window{}, fmt.Errorf("没有前台窗口")
+	}
+	return windowInfo(hwnd)
+}
+
+// WindowAt 虚拟屏幕坐标 p 处的顶层窗口
+func (c *GDICapturer) WindowAt(p image.Point) (Window, error) {
+	// WindowFromPoint 的参数是按值传递的 POINT，64 位下打包为一个参数
+	pt := uintptr(uint32(int32(p.X))) | uintptr(uint32(int32(p.Y)))<<32
+	hwnd, _, _ := procWindowFromPoint.Call(pt)
+	if hwnd == 0 {
+		return Window{}, fmt.Errorf("坐标 %v 处没有窗口", p)
+	}
+	// 命中的可能是子控件，取其顶层窗口
+	if root, _, _ := procGetAncestor.Call(hwnd, GA_ROOT); root != 0 {
+		hwnd = root
+	}
+	return windowInfo(hwnd)
+}
+
+// CursorPos 鼠标位置
+func (c *GDICapturer) CursorPos() (image.Point, error) {
+	var pt POINT
+	ret, _, err := procGetCursorPos.Call(uintptr(unsafe.Pointer(&pt)))
+	if ret == 0 {
+		return image.Point{}, fmt.Errorf("获取鼠标位置失败: %v", err)
+	}
+	return image.Pt(int(pt.X), int(pt.Y)), nil
+}
+
+// windowInfo 读取窗口的标题、进程和可见范围
+func windowInfo(hwnd uintptr) (Window, error) {
+	bounds, err := windowBounds(hwnd)
+	if err != nil {
+		return Window{}, err
+	}
+
+	w := Window{Handle: hwnd, Title: windowTitle(hwnd), Bounds: bounds}
+	var pid uint32
+	procGetWindowThreadProcessId.Call(hwnd, uintptr(unsafe.Pointer(&pid)))
+	if pid != 0 {
+		w.PID = int(pid)
+		w.Process = processName(pid)
+	}
+	return w, nil
+}
+
+// windowBounds 窗口可见范围
+// Windows 10 起 GetWindowRect 包含不可见的阴影边框，优先使用 DWM 的扩展边框范围
+func windowBounds(hwnd uintptr) (image.Rectangle, error) {
+	var r RECT
+	if procDwmGetWindowAttribute.Find() == nil {
+		ret, _, _ := procDwmGetWindowAttribute.Call(hwnd, DWMWA_EXTENDED_FRAME_BOUNDS,
+			uintptr(unsafe.Pointer(&r)), unsafe.Sizeof(r))
+		if ret == 0 && r.Right > r.Left && r.Bottom > r.Top { // S_OK
+			return rectToImage(r), nil
+		}
+	}
+
+	ret, _, err := procGetWindowRect.Call(hwnd, uintptr(unsafe.Pointer(&r)))
+	if ret == 0 {
+		return image.Rectangle{}, fmt.Errorf("获取窗口范围失败: %v", err)
+	}
+	return rectToImage(r), nil
+}
+
+// windowTitle 窗口标题
+func windowTitle(hwnd uintptr) string {
+	n, _, _ := procGetWindowTextLengthW.Call(hwnd)
+	if n == 0 {
+		return ""
+	}
+	buf := make([]uint16, n+1)
+	procGetWindowTextW.Call(hwnd, uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)))
+	return syscall.UTF16ToString(buf)
+}
+
+// processName 进程可执行文件名，无权限访问（如管理员进程）时返回空
+func processName(pid uint32) string {
+	h, _, _ := procOpenProcess.Call(PROCESS_QUERY_LIMITED_INFORMATION, 0, uintptr(pid))
+	if h == 0 {
+		return ""
+	}
+	defer procCloseHandle.Call(h)
+
+	buf := make([]uint16, syscall.MAX_PATH)
+	size := uint32(len(buf))
+	ret, _, _ := procQueryFullProcessImageNameW.Call(h, 0, uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&size)))
+	if ret == 0 {
+		return ""
+	}
+	return filepath.Base(syscall.UTF16ToString(buf[:size]))
+}
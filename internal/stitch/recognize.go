@@ -0,0 +1,88 @@
+package stitch
+
+import (
+	"fmt"
+	"image"
+	"strings"
+
+	"screenocr-wails/internal/ocr"
+)
+
+const (
+	// TileHeight 分块识别时每块的高度，OCR 引擎对超长图片有尺寸限制（Windows OCR 最大 10000 像素）
+	TileHeight = 2000
+
+	// TileOverlap 相邻两块的重叠高度，需大于一行文字，保证每行至少完整出现在一块中
+	TileOverlap = 200
+)
+
+// Recognize 分块识别拼接后的长图，结果坐标映射回整张图片，重叠区重复识别的文字只保留一份
+// 每块只保留中心落在自己负责范围（相邻两块在重叠区中线分界）内的文本块
+func Recognize(engine ocr.Engine, img image.Image, preprocess bool) ([]ocr.TextBlock, error) {
+	size := img.Bounds().Size()
+	if size.Y <= TileHeight {
+		return ocr.RecognizeRegion(engine, img, image.Rectangle{Max: size}, preprocess)
+	}
+
+	var blocks []ocr.TextBlock
+	for top := 0; top < size.Y; top += TileHeight - TileOverlap {
+		bottom := min(top+TileHeight, size.Y)
+		tile, err := ocr.RecognizeRegion(engine, img, image.Rect(0, top, size.X, bottom), preprocess)
+		if err != nil {
+			return nil, fmt.Errorf("识别第 %d-%d 行失败: %w", top, bottom, err)
+		}
+
+		// 负责范围：与上一块在重叠区中线分界，最后一块负责到底
+		ownTop, ownBottom := top, bottom
+		if top > 0 {
+			ownTop = top + TileOverlap/2
+		}
+		if bottom < size.Y {
+			ownBottom = bottom - TileOverlap/2
+		}
+		for _, b := range tile {
+			if center := b.Y + b.Height/2; center >= ownTop && center < ownBottom {
+				blocks = append(blocks, b)
+			}
+		}
+		if bottom == size.Y {
+			break
+		}
+	}
+	return DedupeBlocks(blocks), nil
+}
+
+// DedupeBlocks 去掉重复的文本块：文字相同（忽略空白）且位置基本重合（交并比 > 0.5）的只保留第一个
+func DedupeBlocks(blocks []ocr.TextBlock) []ocr.TextBlock {
+	out := blocks[:0]
+	for _, b := range blocks {
+		dup := false
+		for _, kept := range out {
+			if sameText(kept.Text, b.Text) && iou(kept, b) > 0.5 {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// sameText 忽略空白比较文字
+func sameText(a, b string) bool {
+	return strings.Join(strings.Fields(a), "") == strings.Join(strings.Fields(b), "")
+}
+
+// iou 两个文本块的交并比
+func iou(a, b ocr.TextBlock) float64 {
+	ra := image.Rect(a.X, a.Y, a.X+a.Width, a.Y+a.Height)
+	rb := image.Rect(b.X, b.Y, b.X+b.Width, b.Y+b.Height)
+	inter := ra.Intersect(rb)
+	if inter.Empty() {
+		return 0
+	}
+	i := inter.Dx() * inter.Dy()
+	return float64(i) / float64(ra.Dx()*ra.Dy()+rb.Dx()*rb.Dy()-i)
+}
@@ -0,0 +1,125 @@
+package stitch
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"sync"
+	"time"
+)
+
+// DefaultInterval 滚动截图的默认截图间隔
+const DefaultInterval = 150 * time.Millisecond
+
+// Recorder 定时截图并拼接，用户在 Start 和 Stop 之间滚动页面；每个 Recorder 只录制一次
+type Recorder struct {
+	capture  func() (image.Image, error)
+	interval time.Duration
+
+	mu       sync.Mutex
+	stitcher *Stitcher
+	rejected int   // 没有重叠而被丢弃的帧数
+	err      error // 截图失败或超过最大高度时的错误，录制随之停止
+
+	stop chan struct{}
+	done chan struct{}
+
+	OnFrame func(added, height int) // 追加了新内容时的回调（added 为新增行数，height 为当前总高度）
+}
+
+// NewRecorder 创建滚动截图录制器，capture 每次返回要拼接的区域（尺寸保持不变），interval 为 0 时使用 DefaultInterval
+func NewRecorder(capture func() (image.Image, error), interval time.Duration, opts Options) *Recorder {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Recorder{capture: capture, interval: interval, stitcher: New(opts)}
+}
+
+// Start 开始录制（立即截取第一帧）
+func (r *Recorder) Start() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stop != nil {
+		return fmt.Errorf("滚动截图已在进行中")
+	}
+
+	img, err := r.capture()
+	if err != nil {
+		return fmt.Errorf("截图失败: %w", err)
+	}
+	if _, err := r.stitcher.Add(img); err != nil {
+		return err
+	}
+
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go r.loop(r.stop, r.done)
+	return nil
+}
+
+// loop 定时截图直到 Stop 或出错
+func (r *Recorder) loop(stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		if !r.step() {
+			return
+		}
+	}
+}
+
+// step 截取并拼接一帧，返回是否继续录制
+func (r *Recorder) step() bool {
+	img, err := r.capture()
+
+	r.mu.Lock()
+	if err != nil {
+		r.err = fmt.Errorf("截图失败: %w", err)
+		r.mu.Unlock()
+		return false
+	}
+	added, err := r.stitcher.Add(img)
+	switch {
+	case errors.Is(err, ErrNoOverlap):
+		r.rejected++
+		r.mu.Unlock()
+		return true
+	case err != nil:
+		r.err = err
+		r.mu.Unlock()
+		return false
+	}
+	height := r.stitcher.Height()
+	onFrame := r.OnFrame
+	r.mu.Unlock()
+
+	if added > 0 && onFrame != nil {
+		onFrame(added, height)
+	}
+	return true
+}
+
+// Stop 停止录制，返回拼接结果和被丢弃的帧数
+// 录制中途出错时仍返回已拼接的部分，同时返回错误
+func (r *Recorder) Stop() (*image.RGBA, int, error) {
+	r.mu.Lock()
+	stop, done := r.stop, r.done
+	r.mu.Unlock()
+	if stop == nil {
+		return nil, 0, fmt.Errorf("滚动截图没有开始")
+	}
+	close(stop)
+	<-done
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stop, r.done = nil, nil
+	return r.stitcher.Image(), r.rejected, r.err
+}
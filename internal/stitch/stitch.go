@@ -0,0 +1,245 @@
+// Package stitch 滚动截图拼接
+//
+// 用户滚动页面时连续截图，Stitcher 按行哈希找出相邻两帧的重叠位置，把新出现的内容追加到长图下方。
+// 窗口标题栏、固定的页头页尾等不随滚动变化的行只保留一份；每行的哈希只取量化后的像素，
+// 抗锯齿和压缩带来的细微差异不影响匹配，右侧滚动条所在的列不参与哈希。
+// 只支持向下滚动，没有重叠（滚动过快）的帧会被拒绝，之后的帧仍与最后拼接成功的帧比较，往回滚动到重叠位置即可继续。
+package stitch
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+)
+
+var (
+	// ErrNoOverlap 新帧与上一帧没有可靠的重叠（滚动过快、向上滚动或页面内容整体变化）
+	ErrNoOverlap = errors.New("与上一帧没有重叠")
+
+	// ErrTooTall 拼接结果超过 Options.MaxHeight
+	ErrTooTall = errors.New("拼接结果超过最大高度")
+)
+
+// Options 拼接参数
+type Options struct {
+	IgnoreRight int     // 右侧不参与匹配的列数（滚动条），默认见 DefaultIgnoreRight
+	MinOverlap  int     // 最少重叠行数，默认 16
+	Tolerance   float64 // 重叠区允许不一致的行比例（闪烁的光标、动画等），默认 0.05
+	MaxHeight   int     // 拼接结果的最大高度，默认 30000
+}
+
+// DefaultIgnoreRight 宽度为 width 的截图默认忽略的右侧列数，覆盖 200% 缩放下的滚动条
+func DefaultIgnoreRight(width int) int {
+	return min(max(24, width/40), width/2)
+}
+
+// withDefaults 补全默认参数
+func (o Options) withDefaults(width int) Options {
+	if o.IgnoreRight <= 0 {
+		o.IgnoreRight = DefaultIgnoreRight(width)
+	}
+	o.IgnoreRight = min(o.IgnoreRight, width/2)
+	if o.MinOverlap <= 0 {
+		o.MinOverlap = 16
+	}
+	if o.Tolerance <= 0 {
+		o.Tolerance = 0.05
+	}
+	if o.MaxHeight <= 0 {
+		o.MaxHeight = 30000
+	}
+	return o
+}
+
+const (
+	// minInformative 重叠区中至少要有多少相同的非纯色行，纯色行（空白背景）与任何位置都能匹配
+	minInformative = 4
+
+	// minAllowed 重叠区至少允许不一致的行数，小窗口按比例算出的容差容不下一个闪烁的光标
+	minAllowed = 16
+
+	// quantizeShift 哈希前每个通道右移的位数
+	quantizeShift = 3
+)
+
+// rowHash 一行像素的哈希
+type rowHash struct {
+	sum  uint64
+	flat bool // 纯色行
+}
+
+// Stitcher 增量拼接滚动截图，所有帧的尺寸必须相同
+type Stitcher struct {
+	opts   Options
+	width  int
+	height int
+
+	pix    []byte    // 已拼接部分（RGBA，每行 width*4 字节），不含页尾
+	footer []byte    // 最新一帧的页尾
+	prev   []rowHash // 上一帧各行的哈希
+
+	header, footerRows int  // 固定页头/页尾的行数，由前两帧确定
+	fixed              bool // 页头页尾是否已确定
+	frames             int  // 已拼接的帧数
+}
+
+// New 创建拼接器
+func New(opts Options) *Stitcher {
+	return &Stitcher{opts: opts}
+}
+
+// Add 加入一帧，返回新追加的行数（0 表示页面没有滚动）
+func (s *Stitcher) Add(img image.Image) (int, error) {
+	frame := toRGBA(img)
+	size := frame.Rect.Size()
+	if size.X <= 0 || size.Y <= 0 {
+		return 0, fmt.Errorf("空白截图")
+	}
+
+	if s.frames == 0 {
+		s.opts = s.opts.withDefaults(size.X)
+		s.width, s.height = size.X, size.Y
+		s.pix = append(s.pix[:0], frame.Pix[:size.Y*size.X*4]...)
+		s.prev = s.hashRows(frame)
+		s.frames = 1
+		return size.Y, nil
+	}
+	if size.X != s.width || size.Y != s.height {
+		return 0, fmt.Errorf("截图尺寸 %dx%d 与第一帧 %dx%d 不同", size.X, size.Y, s.width, s.height)
+	}
+
+	cur := s.hashRows(frame)
+	header, footer := s.header, s.footerRows
+	if !s.fixed {
+		header, footer = staticRows(s.prev, cur)
+		if header+footer >= s.height {
+			return 0, nil // 与上一帧完全相同
+		}
+	}
+
+	shift, ok := s.findShift(s.prev[header:s.height-footer], cur[header:s.height-footer])
+	if !ok {
+		return 0, ErrNoOverlap
+	}
+	if shift == 0 {
+		s.prev = cur
+		return 0, nil
+	}
+
+	rowBytes := s.width * 4
+	if !s.fixed {
+		// 第一次滚动时确定页尾，第一帧的页尾从已拼接部分移到 footer
+		s.header, s.footerRows, s.fixed = header, footer, true
+		s.pix = s.pix[:(s.height-footer)*rowBytes]
+	}
+	if len(s.pix)/rowBytes+shift+footer > s.opts.MaxHeight {
+		return 0, ErrTooTall
+	}
+
+	// 追加新出现的内容：当前帧可滚动区域底部的 shift 行
+	bottom := s.height - footer
+	s.pix = append(s.pix, frame.Pix[(bottom-shift)*rowBytes:bottom*rowBytes]...)
+	s.footer = append(s.footer[:0], frame.Pix[bottom*rowBytes:s.height*rowBytes]...)
+	s.prev = cur
+	s.frames++
+	return shift, nil
+}
+
+// Frames 已拼接的帧数（不含未滚动和被拒绝的帧）
+func (s *Stitcher) Frames() int {
+	return s.frames
+}
+
+// Height 当前拼接结果的高度
+func (s *Stitcher) Height() int {
+	if s.frames == 0 {
+		return 0
+	}
+	return (len(s.pix) + len(s.footer)) / (s.width * 4)
+}
+
+// Image 当前的拼接结果，没有帧时返回 nil
+func (s *Stitcher) Image() *image.RGBA {
+	if s.frames == 0 {
+		return nil
+	}
+	pix := make([]byte, 0, len(s.pix)+len(s.footer))
+	pix = append(pix, s.pix...)
+	pix = append(pix, s.footer...)
+	height := len(pix) / (s.width * 4)
+	return &image.RGBA{Pix: pix, Stride: s.width * 4, Rect: image.Rect(0, 0, s.width, height)}
+}
+
+// hashRows 计算每行（除去右侧忽略的列）量化后的哈希
+func (s *Stitcher) hashRows(img *image.RGBA) []rowHash {
+	cols := s.width - s.opts.IgnoreRight
+	hashes := make([]rowHash, s.height)
+	for y := range hashes {
+		row := img.Pix[y*img.Stride : y*img.Stride+cols*4]
+		const offset, prime = 14695981039346656037, 1099511628211 // FNV-1a
+		sum := uint64(offset)
+		flat := true
+		for i := 0; i < len(row); i += 4 {
+			r, g, b := row[i]>>quantizeShift, row[i+1]>>quantizeShift, row[i+2]>>quantizeShift
+			if flat && (r != row[0]>>quantizeShift || g != row[1]>>quantizeShift || b != row[2]>>quantizeShift) {
+				flat = false
+			}
+			sum = (sum ^ uint64(r)) * prime
+			sum = (sum ^ uint64(g)) * prime
+			sum = (sum ^ uint64(b)) * prime
+		}
+		hashes[y] = rowHash{sum: sum, flat: flat}
+	}
+	return hashes
+}
+
+// staticRows 两帧顶部和底部相同的行数（最多各占三分之一高度），视为固定的页头和页尾
+// 完全相同的两帧返回 header+footer == 高度
+func staticRows(prev, cur []rowHash) (header, footer int) {
+	n := len(cur)
+	for header < n && prev[header].sum == cur[header].sum {
+		header++
+	}
+	if header == n {
+		return n, 0
+	}
+	for footer < n-header && prev[n-1-footer].sum == cur[n-1-footer].sum {
+		footer++
+	}
+	return min(header, n/3), min(footer, n/3)
+}
+
+// findShift 在可滚动区域中查找当前帧相对上一帧向下滚动的行数
+// 上一帧的第 shift+i 行应与当前帧的第 i 行相同；不一致的行不超过容差，且相同的非纯色行明显多于不一致的行时匹配成功，
+// 有多个候选时取重叠最多（滚动最少）的，重复的页面内容（如相同的聊天气泡）因此不会跳过内容
+func (s *Stitcher) findShift(prev, cur []rowHash) (int, bool) {
+	n := len(cur)
+	for shift := 0; shift <= n-s.opts.MinOverlap; shift++ {
+		overlap := n - shift
+		allowed := max(int(float64(overlap)*s.opts.Tolerance), minAllowed)
+		mismatches, informative := 0, 0
+		for i := 0; i < overlap && mismatches <= allowed; i++ {
+			if prev[shift+i].sum != cur[i].sum {
+				mismatches++
+			} else if !cur[i].flat {
+				informative++
+			}
+		}
+		if mismatches <= allowed && informative >= minInformative+2*mismatches {
+			return shift, true
+		}
+	}
+	return 0, false
+}
+
+// toRGBA 转为原点 (0, 0)、各行紧密排列的 *image.RGBA，已经是时直接返回
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) && rgba.Stride == rgba.Rect.Dx()*4 {
+		return rgba
+	}
+	b := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(out, out.Rect, img, b.Min, draw.Src)
+	return out
+}
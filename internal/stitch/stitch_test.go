@@ -0,0 +1,326 @@
+package stitch
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"screenocr-wails/internal/ocr"
+	"screenocr-wails/internal/ocr/ocrtest"
+)
+
+// 合成窗口的布局：固定的标题栏和状态栏，右侧滚动条随滚动位置移动
+const (
+	testHeader    = 32
+	testFooter    = 20
+	testScrollbar = 16
+	testLineH     = 24
+)
+
+// scrollCase 合成滚动场景：page 为整页内容，steps 为每帧相对上一帧的滚动行数（负数表示往回滚动）
+type scrollCase struct {
+	name     string
+	page     *image.RGBA
+	steps    []int
+	rejected int                            // 期望被拒绝的帧数
+	animate  func(frame *image.RGBA, i int) // 在每帧上叠加动画（闪烁的光标等）
+}
+
+// scrollCases 在 width x height 的窗口中模拟各种滚动方式
+func scrollCases(width, height int) []scrollCase {
+	view := height - testHeader - testFooter
+	rng := rand.New(rand.NewSource(3))
+	page := syntheticPage(width-testScrollbar, view*8, 0)
+
+	var steady []int
+	for off := 0; off+view/5 <= page.Rect.Dy()-view; off += view / 5 {
+		steady = append(steady, view/5)
+	}
+
+	var random []int
+	for off := 0; ; {
+		step := rng.Intn(view * 3 / 5)
+		if rng.Intn(6) == 0 {
+			step = 0 // 截图时页面没有滚动
+		}
+		if off+step > page.Rect.Dy()-view {
+			break
+		}
+		off += step
+		random = append(random, step)
+	}
+
+	// 滚动过快的一帧会被拒绝，往回滚动到与上一帧重叠的位置后继续
+	jump := []int{view / 3, view / 3, view + 40, -(view + 40) + view/4, view / 3, view / 3}
+
+	cursor := func(frame *image.RGBA, i int) {
+		if i%2 == 1 {
+			draw.Draw(frame, image.Rect(40, testHeader+30, 42, testHeader+40), image.Black, image.Point{}, draw.Src)
+		}
+	}
+
+	return []scrollCase{
+		{name: "steady", page: page, steps: steady},
+		{name: "random", page: page, steps: random},
+		{name: "too-fast", page: page, steps: jump, rejected: 1},
+		{name: "repeated", page: syntheticPage(width-testScrollbar, view*4, 5*testLineH), steps: []int{90, 90, 90, 90, 90, 90}},
+		{name: "blinking-cursor", page: page, steps: steady[:min(len(steady), 12)], animate: cursor},
+	}
+}
+
+// runScroll 按场景生成各帧并拼接，返回拼接器、被拒绝的帧数和拼接到的最低滚动位置
+func runScroll(t testing.TB, c scrollCase, width, height int) (*Stitcher, int, int) {
+	s := New(Options{})
+	off, bottom, rejected := 0, 0, 0
+	for i := 0; i <= len(c.steps); i++ {
+		if i > 0 {
+			off += c.steps[i-1]
+		}
+		frame := windowFrame(c.page, width, height, off)
+		if c.animate != nil {
+			c.animate(frame, i)
+		}
+		if _, err := s.Add(frame); err != nil {
+			if !errors.Is(err, ErrNoOverlap) {
+				t.Fatalf("第 %d 帧: %v", i, err)
+			}
+			rejected++
+			continue
+		}
+		bottom = max(bottom, off)
+	}
+	return s, rejected, bottom
+}
+
+func TestStitchMatchesPage(t *testing.T) {
+	for _, size := range []image.Point{{1280, 800}, {480, 360}} {
+		for _, c := range scrollCases(size.X, size.Y) {
+			t.Run(fmt.Sprintf("%dx%d/%s", size.X, size.Y, c.name), func(t *testing.T) {
+				s, rejected, bottom := runScroll(t, c, size.X, size.Y)
+				if rejected != c.rejected {
+					t.Errorf("拒绝了 %d 帧，期望 %d 帧", rejected, c.rejected)
+				}
+
+				// 期望图片：标题栏 + 页面开头到拼接到的最低位置 + 状态栏，即一个足够高的窗口
+				view := size.Y - testHeader - testFooter
+				want := windowFrame(c.page, size.X, testHeader+bottom+view+testFooter, 0)
+				got := s.Image()
+				if got.Rect.Dy() != want.Rect.Dy() {
+					t.Fatalf("拼接高度 %d，期望 %d", got.Rect.Dy(), want.Rect.Dy())
+				}
+				if s.Height() != got.Rect.Dy() {
+					t.Errorf("Height() = %d，Image() 高度 %d", s.Height(), got.Rect.Dy())
+				}
+				// 滚动条所在的列不参与比较
+				if bad := diffRows(got, want, size.X-DefaultIgnoreRight(size.X)); bad != 0 {
+					t.Errorf("%d 行与原页面不同", bad)
+				}
+			})
+		}
+	}
+}
+
+func TestStitchSameFrame(t *testing.T) {
+	page := syntheticPage(400-testScrollbar, 2000, 0)
+	frame := windowFrame(page, 400, 300, 0)
+	s := New(Options{})
+	for i := 0; i < 3; i++ {
+		if _, err := s.Add(frame); err != nil {
+			t.Fatalf("第 %d 帧: %v", i, err)
+		}
+	}
+	if s.Height() != 300 {
+		t.Errorf("相同的帧拼接后高度 %d，期望 300", s.Height())
+	}
+}
+
+func TestStitchRejectsSizeMismatch(t *testing.T) {
+	page := syntheticPage(400-testScrollbar, 2000, 0)
+	s := New(Options{})
+	if _, err := s.Add(windowFrame(page, 400, 300, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Add(windowFrame(page, 400, 320, 40)); err == nil {
+		t.Error("尺寸不同的帧应返回错误")
+	}
+	if _, err := s.Add(image.NewRGBA(image.Rect(0, 0, 0, 0))); err == nil {
+		t.Error("空白截图应返回错误")
+	}
+}
+
+func TestStitchMaxHeight(t *testing.T) {
+	page := syntheticPage(400-testScrollbar, 4000, 0)
+	s := New(Options{MaxHeight: 600})
+	var err error
+	for off := 0; off <= 1000 && err == nil; off += 100 {
+		_, err = s.Add(windowFrame(page, 400, 300, off))
+	}
+	if !errors.Is(err, ErrTooTall) {
+		t.Fatalf("超过最大高度时应返回 ErrTooTall，得到 %v", err)
+	}
+	if s.Height() > 600 {
+		t.Errorf("拼接高度 %d 超过最大高度 600", s.Height())
+	}
+}
+
+// TestRecognizeTiles 在拼接好的长图上分块识别，每行文字恰好出现一次（重叠区已去重）
+// 使用读取行号标记的假引擎，不需要真实 OCR 引擎
+func TestRecognizeTiles(t *testing.T) {
+	for _, height := range []int{TileHeight / 2, TileHeight*3 + 77, 8000} {
+		t.Run(strconv.Itoa(height), func(t *testing.T) {
+			page := syntheticPage(1280, height, 0)
+			engine := ocrtest.NewFakeEngine()
+			engine.RecognizeFunc = func(img image.Image, preprocess bool) ([]ocr.TextBlock, error) {
+				return markerBlocks(img.(*image.RGBA)), nil
+			}
+
+			blocks, err := Recognize(engine, page, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := markerBlocks(page)
+			if len(blocks) != len(want) {
+				t.Fatalf("识别到 %d 行，期望 %d 行", len(blocks), len(want))
+			}
+			for i := range want {
+				if blocks[i] != want[i] {
+					t.Errorf("第 %d 行: 得到 %+v，期望 %+v", i, blocks[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRecognizeError(t *testing.T) {
+	engine := ocrtest.NewFakeEngine()
+	engine.RecognizeFunc = func(img image.Image, preprocess bool) ([]ocr.TextBlock, error) {
+		return nil, errors.New("boom")
+	}
+	if _, err := Recognize(engine, syntheticPage(200, TileHeight*2, 0), false); err == nil {
+		t.Error("引擎出错时应返回错误")
+	}
+}
+
+func TestDedupeBlocks(t *testing.T) {
+	blocks := []ocr.TextBlock{
+		{Text: "hello world", X: 0, Y: 0, Width: 100, Height: 20},
+		{Text: "hello  world", X: 2, Y: 1, Width: 100, Height: 20}, // 重复：忽略空白，位置基本重合
+		{Text: "hello world", X: 0, Y: 40, Width: 100, Height: 20}, // 文字相同但位置不同
+		{Text: "other", X: 0, Y: 0, Width: 100, Height: 20},        // 位置相同但文字不同
+	}
+	got := DedupeBlocks(blocks)
+	if len(got) != 3 || got[1].Y != 40 || got[2].Text != "other" {
+		t.Errorf("DedupeBlocks = %+v", got)
+	}
+}
+
+func BenchmarkStitch(b *testing.B) {
+	for _, c := range scrollCases(1280, 800) {
+		b.Run(c.name, func(b *testing.B) {
+			frames := make([]*image.RGBA, 0, len(c.steps)+1)
+			off := 0
+			for i := 0; i <= len(c.steps); i++ {
+				if i > 0 {
+					off += c.steps[i-1]
+				}
+				frame := windowFrame(c.page, 1280, 800, off)
+				if c.animate != nil {
+					c.animate(frame, i)
+				}
+				frames = append(frames, frame)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				s := New(Options{})
+				for _, frame := range frames {
+					s.Add(frame)
+				}
+			}
+			b.ReportMetric(float64(b.Elapsed().Microseconds())/float64(b.N*len(frames)), "µs/frame")
+		})
+	}
+}
+
+// windowFrame 模拟窗口截图：标题栏 + 滚动位置 off 处的页面 + 状态栏，右侧滚动条按位置绘制
+func windowFrame(page *image.RGBA, width, height, off int) *image.RGBA {
+	frame := image.NewRGBA(image.Rect(0, 0, width, height))
+	view := height - testHeader - testFooter
+	draw.Draw(frame, image.Rect(0, 0, width, testHeader), &image.Uniform{color.RGBA{40, 40, 48, 255}}, image.Point{}, draw.Src)
+	for x := 12; x < 200 && x < width; x += 7 {
+		draw.Draw(frame, image.Rect(x, 10, x+4, 22), image.White, image.Point{}, draw.Src) // 标题文字
+	}
+	draw.Draw(frame, image.Rect(0, testHeader, width-testScrollbar, testHeader+view), page, image.Pt(0, off), draw.Src)
+	draw.Draw(frame, image.Rect(0, height-testFooter, width, height), &image.Uniform{color.RGBA{220, 220, 225, 255}}, image.Point{}, draw.Src)
+
+	// 滚动条：轨道 + 按滚动位置移动的滑块
+	track := image.Rect(width-testScrollbar, testHeader, width, testHeader+view)
+	draw.Draw(frame, track, &image.Uniform{color.RGBA{235, 235, 235, 255}}, image.Point{}, draw.Src)
+	pageH := max(page.Rect.Dy(), 1)
+	thumbTop := track.Min.Y + off*view/pageH
+	thumb := image.Rect(track.Min.X+3, thumbTop, track.Max.X-3, thumbTop+max(view*view/pageH, 20)).Intersect(track)
+	draw.Draw(frame, thumb, &image.Uniform{color.RGBA{160, 160, 160, 255}}, image.Point{}, draw.Src)
+	return frame
+}
+
+// syntheticPage 合成的长页面：白底上的文字行，段落之间空一行，每行左侧的标记像素记录行号（供假引擎"识别"）
+// period 大于 0 时页面内容每 period 行重复一次（如相同的聊天气泡）
+func syntheticPage(width, height, period int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Rect, image.White, image.Point{}, draw.Src)
+	rng := rand.New(rand.NewSource(4))
+
+	line := 0
+	for y := 8; y+testLineH <= height; y += testLineH {
+		if period > 0 && y >= period {
+			draw.Draw(img, image.Rect(0, y, width, y+testLineH), img, image.Pt(0, y-period), draw.Src)
+			continue
+		}
+		if rng.Intn(6) == 0 {
+			continue // 段落间空行
+		}
+		img.SetRGBA(0, y, color.RGBA{uint8(line >> 8), uint8(line), 1, 255})
+		end := 16 + rng.Intn(max(width-32, 1))
+		for x := 16; x < end; x += 3 {
+			if rng.Intn(3) == 0 {
+				top := y + 4 + rng.Intn(5)
+				draw.Draw(img, image.Rect(x, top, x+2, top+8+rng.Intn(5)), image.Black, image.Point{}, draw.Src)
+			}
+		}
+		line++
+	}
+	return img
+}
+
+// diffRows 比较两张等高图片的前 cols 列，返回不同的行数
+func diffRows(a, b *image.RGBA, cols int) int {
+	bad := 0
+	for y := 0; y < a.Rect.Dy(); y++ {
+		ra := a.Pix[y*a.Stride : y*a.Stride+cols*4]
+		rb := b.Pix[y*b.Stride : y*b.Stride+cols*4]
+		if string(ra) != string(rb) {
+			bad++
+		}
+	}
+	return bad
+}
+
+// markerBlocks 假引擎：找出完整出现在图片中的行号标记，每行返回一个文本块
+func markerBlocks(img *image.RGBA) []ocr.TextBlock {
+	var blocks []ocr.TextBlock
+	h := img.Rect.Dy()
+	for y := 0; y+testLineH <= h; y++ {
+		c := img.RGBAAt(img.Rect.Min.X, img.Rect.Min.Y+y)
+		if c.B != 1 || c.A != 255 {
+			continue
+		}
+		line := int(c.R)<<8 | int(c.G)
+		blocks = append(blocks, ocr.TextBlock{Text: "L" + strconv.Itoa(line), X: 0, Y: y, Width: img.Rect.Dx(), Height: testLineH})
+	}
+	return blocks
+}
@@ -0,0 +1,225 @@
+package translator
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBaiduEndpoint 百度翻译开放平台的服务地址
+const DefaultBaiduEndpoint = "https://fanyi-api.baidu.com/api/trans/vip"
+
+// BaiduTranslator 百度翻译（通用文本翻译 API）
+type BaiduTranslator struct {
+	endpoint string
+	appID    string
+	secret   string
+	client   *http.Client
+}
+
+// baiduTranslateResponse 通用文本翻译响应，多行文字按行返回
+type baiduTranslateResponse struct {
+	From        string `json:"from"`
+	To          string `json:"to"`
+	TransResult []struct {
+		Src string `json:"src"`
+		Dst string `json:"dst"`
+	} `json:"trans_result"`
+	ErrorCode baiduCode `json:"error_code"`
+	ErrorMsg  string    `json:"error_msg"`
+}
+
+// baiduDetectResponse 语种识别响应
+type baiduDetectResponse struct {
+	ErrorCode baiduCode `json:"error_code"`
+	ErrorMsg  string    `json:"error_msg"`
+	Data      struct {
+		Src string `json:"src"`
+	} `json:"data"`
+}
+
+// baiduCode 错误码：翻译接口返回字符串，语种识别接口返回数字
+type baiduCode string
+
+func (c *baiduCode) UnmarshalJSON(data []byte) error {
+	*c = baiduCode(strings.Trim(string(data), `"`))
+	return nil
+}
+
+// ok 是否成功（没有错误码、0 或 52000）
+func (c baiduCode) ok() bool {
+	return c == "" || c == "0" || c == "52000"
+}
+
+// baiduErrors 错误码说明（见百度翻译开放平台文档）
+var baiduErrors = map[string]string{
+	"52001": "请求超时，请重试",
+	"52002": "系统错误，请重试",
+	"52003": "未授权用户，请检查 APP ID 是否正确、服务是否已开通",
+	"54000": "必填参数为空",
+	"54001": "签名错误，请检查密钥是否正确",
+	"54003": "访问频率受限，请降低调用频率或升级认证",
+	"54004": "账户余额不足",
+	"54005": "长文本请求频繁，请降低长文本的发送频率",
+	"58000": "客户端 IP 非法，请检查控制台中填写的服务器 IP",
+	"58001": "不支持该翻译语言方向",
+	"58002": "服务已关闭，请在控制台开启服务",
+	"58003": "IP 因频繁调用被封禁",
+	"90107": "认证未通过或未生效",
+	"20003": "请求内容存在安全风险",
+}
+
+// baiduCodes 本程序的语言代码与百度翻译语言代码不同的部分
+var baiduCodes = map[string]string{
+	"zh-TW": "cht",
+	"ja":    "jp",
+	"ko":    "kor",
+	"fr":    "fra",
+	"es":    "spa",
+	"vi":    "vie",
+	"ar":    "ara",
+	"ms":    "may",
+}
+
+// baiduLanguages 百度翻译支持的语言（本程序的语言代码）
+var baiduLanguages = []string{
+	"zh", "zh-TW", "en", "ja", "ko", "fr", "es", "th", "ar", "ru",
+	"pt", "de", "it", "el", "nl", "pl", "vi", "id", "ms", "hi", "tr",
+}
+
+func init() {
+	Register(Info{
+		Name:  "baidu",
+		Label: "百度翻译",
+		Fields: []Field{
+			{Key: "secret_id", Label: "APP ID", Placeholder: "请输入 APP ID"},
+			{Key: "secret_key", Label: "密钥", Placeholder: "请输入密钥", Secret: true},
+		},
+		Link: "https://fanyi-api.baidu.com/manage/developer",
+	}, func(s Settings) Provider {
+		return NewBaiduTranslator(s)
+	})
+}
+
+// NewBaiduTranslator 创建百度翻译器，SecretID 为 APP ID，SecretKey 为密钥
+func NewBaiduTranslator(s Settings) *BaiduTranslator {
+	endpoint := strings.TrimRight(strings.TrimSpace(s.Endpoint), "/")
+	if endpoint == "" {
+		endpoint = DefaultBaiduEndpoint
+	}
+	return &BaiduTranslator{
+		endpoint: endpoint,
+		appID:    strings.TrimSpace(s.SecretID),
+		secret:   strings.TrimSpace(s.SecretKey),
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// IsConfigured 检查是否已配置
+func (t *BaiduTranslator) IsConfigured() bool {
+	return t.appID != "" && t.secret != ""
+}
+
+// baiduSign 百度翻译签名：MD5(appid + q + salt + 密钥) 的小写十六进制，q 为未经 URL 编码的 UTF-8 原文
+func baiduSign(appID, q, salt, secret string) string {
+	sum := md5.Sum([]byte(appID + q + salt + secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// Translate 翻译文本
+func (t *BaiduTranslator) Translate(text, source, target string) (string, error) {
+	var result baiduTranslateResponse
+	err := t.call("/translate", url.Values{
+		"q":    {text},
+		"from": {baiduLang(source)},
+		"to":   {baiduLang(target)},
+	}, &result)
+	if err != nil {
+		return "", err
+	}
+	if !result.ErrorCode.ok() {
+		return "", baiduError(result.ErrorCode, result.ErrorMsg)
+	}
+
+	lines := make([]string, len(result.TransResult))
+	for i, r := range result.TransResult {
+		lines[i] = r.Dst
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// Detect 识别文本的语种（语种识别 API）
+func (t *BaiduTranslator) Detect(text string) (string, error) {
+	var result baiduDetectResponse
+	if err := t.call("/language", url.Values{"q": {text}}, &result); err != nil {
+		return "", err
+	}
+	if !result.ErrorCode.ok() {
+		return "", baiduError(result.ErrorCode, result.ErrorMsg)
+	}
+	for app, code := range baiduCodes {
+		if code == result.Data.Src {
+			return app, nil
+		}
+	}
+	return result.Data.Src, nil
+}
+
+// Supports 是否支持从 source 翻译到 target
+func (t *BaiduTranslator) Supports(source, target string) bool {
+	return supportsPair(baiduLanguages, source, target)
+}
+
+// baiduLang 把本程序的语言代码换成百度翻译的写法（如 ja → jp）
+func baiduLang(code string) string {
+	if baidu, ok := baiduCodes[code]; ok {
+		return baidu
+	}
+	return code
+}
+
+// call 签名并以表单 POST 调用百度翻译 API，把响应解析到 out
+func (t *BaiduTranslator) call(path string, params url.Values, out any) error {
+	if !t.IsConfigured() {
+		return fmt.Errorf("百度翻译未配置")
+	}
+
+	salt := strconv.Itoa(rand.Intn(1_000_000_000))
+	params.Set("appid", t.appID)
+	params.Set("salt", salt)
+	params.Set("sign", baiduSign(t.appID, params.Get("q"), salt, t.secret))
+
+	resp, err := t.client.PostForm(t.endpoint+path, params)
+	if err != nil {
+		return fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("读取响应失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("百度翻译 %s", resp.Status)
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("解析响应失败: %w", err)
+	}
+	return nil
+}
+
+// baiduError 按错误码说明生成错误
+func baiduError(code baiduCode, msg string) error {
+	if desc, ok := baiduErrors[string(code)]; ok {
+		return fmt.Errorf("百度翻译 %s: %s", code, desc)
+	}
+	return fmt.Errorf("百度翻译 %s: %s", code, msg)
+}
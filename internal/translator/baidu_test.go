@@ -0,0 +1,177 @@
+package translator
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// 百度翻译、有道智云的模拟服务使用的凭证
+const (
+	stubAppID  = "20240101000000001"
+	stubSecret = "stubSecretKey123"
+)
+
+// newBaiduServer 模拟的百度翻译服务：独立计算 MD5 签名核对请求，q 为错误码时返回该错误码
+func newBaiduServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	check := func(w http.ResponseWriter, r *http.Request) bool {
+		r.ParseForm()
+		f := r.PostForm
+		sum := md5.Sum([]byte(f.Get("appid") + f.Get("q") + f.Get("salt") + stubSecret))
+		switch {
+		case r.Method != http.MethodPost || f.Get("salt") == "":
+			io.WriteString(w, `{"error_code":"54000","error_msg":"PARAM_FROM_TO_OR_Q_EMPTY"}`)
+		case f.Get("appid") != stubAppID:
+			io.WriteString(w, `{"error_code":"52003","error_msg":"UNAUTHORIZED USER"}`)
+		case f.Get("sign") != hex.EncodeToString(sum[:]):
+			io.WriteString(w, `{"error_code":"54001","error_msg":"Invalid Sign"}`)
+		case strings.HasPrefix(f.Get("q"), "code:"):
+			code := strings.TrimPrefix(f.Get("q"), "code:")
+			fmt.Fprintf(w, `{"error_code":%q,"error_msg":"Something New"}`, code)
+		default:
+			return true
+		}
+		return false
+	}
+	mux.HandleFunc("/translate", func(w http.ResponseWriter, r *http.Request) {
+		if !check(w, r) {
+			return
+		}
+		f := r.PostForm
+		var results []map[string]string
+		for _, line := range strings.Split(f.Get("q"), "\n") {
+			results = append(results, map[string]string{"src": line, "dst": fmt.Sprintf("[%s>%s] %s", f.Get("from"), f.Get("to"), line)})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"from": f.Get("from"), "to": f.Get("to"), "trans_result": results})
+	})
+	mux.HandleFunc("/language", func(w http.ResponseWriter, r *http.Request) {
+		if check(w, r) {
+			// 语种识别接口的错误码是数字
+			io.WriteString(w, `{"error_code":0,"error_msg":"success","data":{"src":"jp"}}`)
+		}
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestBaiduSign(t *testing.T) {
+	cases := []struct {
+		appID, q, salt, secret string
+		want                   string
+	}{
+		// 百度翻译开放平台文档中的示例
+		{"2015063000000001", "apple", "1435660288", "12345678", "f89f9594663708c1605f3d736d01d2d4"},
+		// q 按未经 URL 编码的 UTF-8 原文参与签名
+		{"2015063000000001", "苹果 & 梨", "1435660288", "12345678", md5Hex("2015063000000001苹果 & 梨143566028812345678")},
+	}
+	for _, c := range cases {
+		if got := baiduSign(c.appID, c.q, c.salt, c.secret); got != c.want {
+			t.Errorf("baiduSign(%q) = %s，期望 %s", c.q, got, c.want)
+		}
+	}
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestBaiduTranslate(t *testing.T) {
+	server := newBaiduServer(t)
+	tr, err := New("baidu", Settings{SecretID: stubAppID, SecretKey: stubSecret, Endpoint: server.URL + "/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		text, source, target string
+		want                 string
+	}{
+		// 多行文字按行返回，语言代码换成百度的写法
+		{"ファイル\n編集", "ja", "zh-TW", "[jp>cht] ファイル\n[jp>cht] 編集"},
+		{"Save", "auto", "zh", "[auto>zh] Save"},
+		{"Bonjour", "fr", "ko", "[fra>kor] Bonjour"},
+	}
+	for _, c := range cases {
+		got, err := tr.Translate(c.text, c.source, c.target)
+		if err != nil || got != c.want {
+			t.Errorf("Translate(%q, %s, %s) = %q, %v，期望 %q", c.text, c.source, c.target, got, err, c.want)
+		}
+	}
+
+	// 百度的语言代码换回本程序的写法
+	if got, err := tr.Detect("ファイル"); err != nil || got != "ja" {
+		t.Errorf("Detect = %q, %v，期望 ja", got, err)
+	}
+}
+
+func TestBaiduErrors(t *testing.T) {
+	server := newBaiduServer(t)
+	cases := []struct {
+		name     string
+		settings Settings
+		text     string
+		want     string
+	}{
+		{"wrong-secret", Settings{SecretID: stubAppID, SecretKey: "wrong"}, "hello", "百度翻译 54001: 签名错误，请检查密钥是否正确"},
+		{"wrong-app-id", Settings{SecretID: "other", SecretKey: stubSecret}, "hello", "百度翻译 52003: 未授权用户"},
+		{"rate-limit", Settings{SecretID: stubAppID, SecretKey: stubSecret}, "code:54003", "百度翻译 54003: 访问频率受限"},
+		{"balance", Settings{SecretID: stubAppID, SecretKey: stubSecret}, "code:54004", "百度翻译 54004: 账户余额不足"},
+		// 没有说明的错误码使用服务返回的信息
+		{"unknown-code", Settings{SecretID: stubAppID, SecretKey: stubSecret}, "code:59999", "百度翻译 59999: Something New"},
+		{"not-configured", Settings{SecretID: stubAppID}, "hello", "百度翻译未配置"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.settings.Endpoint = server.URL
+			_, err := NewBaiduTranslator(c.settings).Translate(c.text, "en", "zh")
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Errorf("错误 %v，应包含 %q", err, c.want)
+			}
+		})
+	}
+}
+
+func TestBaiduCode(t *testing.T) {
+	cases := []struct {
+		json string
+		ok   bool
+	}{
+		{`{}`, true},
+		{`{"error_code":0}`, true},
+		{`{"error_code":"52000"}`, true},
+		{`{"error_code":"54001"}`, false},
+		{`{"error_code":54001}`, false},
+	}
+	for _, c := range cases {
+		var resp baiduDetectResponse
+		if err := json.Unmarshal([]byte(c.json), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.ErrorCode.ok() != c.ok {
+			t.Errorf("%s: ok = %v，期望 %v", c.json, resp.ErrorCode.ok(), c.ok)
+		}
+	}
+}
+
+func TestBaiduSupports(t *testing.T) {
+	tr := NewBaiduTranslator(Settings{})
+	cases := []struct {
+		source, target string
+		want           bool
+	}{
+		{"auto", "zh", true}, {"ja", "ko", true}, {"en", "xx", false}, {"zh", "zh", false},
+	}
+	for _, c := range cases {
+		if got := tr.Supports(c.source, c.target); got != c.want {
+			t.Errorf("Supports(%s, %s) = %v，期望 %v", c.source, c.target, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,329 @@
+package translator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DeepL API 的服务地址：免费版密钥以 ":fx" 结尾
+const (
+	DeepLFreeEndpoint = "https://api-free.deepl.com/v2"
+	DeepLProEndpoint  = "https://api.deepl.com/v2"
+)
+
+// DeepLTranslator DeepL 翻译器，支持正式程度、标签处理和术语表
+type DeepLTranslator struct {
+	endpoint    string
+	apiKey      string
+	formality   string
+	tagHandling string
+	glossary    string
+	client      *http.Client
+
+	glossaryOnce sync.Once      // 首次使用术语表时获取一次其语言对（失败时不再重试，按不使用术语表处理）
+	glossaryInfo *DeepLGlossary // 术语表的语言对，只在 glossaryOnce 中写入
+}
+
+// DeepLGlossary DeepL 术语表
+type DeepLGlossary struct {
+	ID         string `json:"glossary_id"`
+	Name       string `json:"name"`
+	Ready      bool   `json:"ready"`
+	SourceLang string `json:"source_lang"` // 小写的基本语言代码，如 en
+	TargetLang string `json:"target_lang"`
+	EntryCount int    `json:"entry_count"`
+}
+
+// deeplTranslateRequest /translate 请求
+type deeplTranslateRequest struct {
+	Text        []string `json:"text"`
+	SourceLang  string   `json:"source_lang,omitempty"`
+	TargetLang  string   `json:"target_lang"`
+	Formality   string   `json:"formality,omitempty"`
+	TagHandling string   `json:"tag_handling,omitempty"`
+	GlossaryID  string   `json:"glossary_id,omitempty"`
+}
+
+// deeplTranslateResponse /translate 响应
+type deeplTranslateResponse struct {
+	Translations []struct {
+		DetectedSourceLanguage string `json:"detected_source_language"`
+		Text                   string `json:"text"`
+	} `json:"translations"`
+}
+
+// deeplErrors HTTP 状态码说明（见 DeepL API 文档）
+var deeplErrors = map[int]string{
+	http.StatusBadRequest:            "请求参数错误",
+	http.StatusForbidden:             "认证失败，请检查 API Key 是否正确（免费版密钥以 :fx 结尾）",
+	http.StatusNotFound:              "资源不存在（如术语表 ID 不正确）",
+	http.StatusRequestEntityTooLarge: "文本过长",
+	http.StatusTooManyRequests:       "请求过于频繁，请稍后再试",
+	456:                              "本计费周期的字符额度已用完",
+	http.StatusServiceUnavailable:    "服务暂时不可用，请稍后再试",
+	529:                              "服务繁忙，请稍后再试",
+}
+
+// deeplTargets 作为目标语言时 DeepL 要求的写法，未列出的为大写的本程序语言代码
+var deeplTargets = map[string]string{
+	"zh":    "ZH-HANS",
+	"zh-TW": "ZH-HANT",
+	"en":    "EN-US",
+	"pt":    "PT-BR",
+}
+
+// deeplLanguages DeepL 支持的语言（本程序的语言代码）
+var deeplLanguages = []string{
+	"zh", "zh-TW", "en", "ja", "ko", "fr", "de", "es", "it", "pt", "ru", "nl", "pl",
+	"tr", "id", "ar", "uk", "sv", "da", "fi", "el", "cs", "ro", "hu", "bg", "sk",
+	"sl", "lt", "lv", "et", "nb",
+}
+
+// deeplFormality 支持设置正式程度的目标语言（基本语言代码）；其他语言使用 more / less 时
+// 换成 prefer_more / prefer_less，避免 DeepL 报错
+var deeplFormality = []string{"DE", "FR", "IT", "ES", "NL", "PL", "PT", "JA", "RU"}
+
+func init() {
+	Register(Info{
+		Name:  "deepl",
+		Label: "DeepL",
+		Fields: []Field{
+			{Key: "api_key", Label: "API Key", Placeholder: "请输入 DeepL API Key", Secret: true},
+			{Key: "formality", Label: "正式程度", Type: "select", Optional: true, Options: []Option{
+				{Value: "", Label: "默认"},
+				{Value: "more", Label: "更正式"},
+				{Value: "less", Label: "更随意"},
+				{Value: "prefer_more", Label: "尽量正式"},
+				{Value: "prefer_less", Label: "尽量随意"},
+			}},
+			{Key: "tag_handling", Label: "标签处理", Type: "select", Optional: true, Options: []Option{
+				{Value: "", Label: "纯文本"},
+				{Value: "xml", Label: "XML"},
+				{Value: "html", Label: "HTML"},
+			}},
+			{Key: "glossary", Label: "术语表 ID", Placeholder: "可选，见 screenocr translators -glossaries", Optional: true},
+			{Key: "endpoint", Label: "服务地址", Placeholder: "按 API Key 自动选择免费版 / 专业版", Optional: true},
+		},
+		Link: "https://www.deepl.com/your-account/keys",
+	}, func(s Settings) Provider {
+		return NewDeepLTranslator(s)
+	})
+}
+
+// NewDeepLTranslator 创建 DeepL 翻译器；服务地址为空时按 API Key 选择免费版或专业版
+func NewDeepLTranslator(s Settings) *DeepLTranslator {
+	apiKey := strings.TrimSpace(s.APIKey)
+	endpoint := strings.TrimRight(strings.TrimSpace(s.Endpoint), "/")
+	if endpoint == "" {
+		endpoint = DeepLEndpoint(apiKey)
+	}
+	return &DeepLTranslator{
+		endpoint:    endpoint,
+		apiKey:      apiKey,
+		formality:   strings.TrimSpace(s.Formality),
+		tagHandling: strings.TrimSpace(s.TagHandling),
+		glossary:    strings.TrimSpace(s.Glossary),
+		client:      &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// DeepLEndpoint 按 API Key 选择服务地址：免费版密钥以 ":fx" 结尾
+func DeepLEndpoint(apiKey string) string {
+	if strings.HasSuffix(apiKey, ":fx") {
+		return DeepLFreeEndpoint
+	}
+	return DeepLProEndpoint
+}
+
+// IsConfigured 检查是否已配置
+func (t *DeepLTranslator) IsConfigured() bool {
+	return t.apiKey != ""
+}
+
+// Translate 翻译文本
+func (t *DeepLTranslator) Translate(text, source, target string) (string, error) {
+	req := deeplTranslateRequest{
+		Text:        []string{text},
+		TargetLang:  deeplTarget(target),
+		Formality:   deeplFormalityFor(t.formality, deeplTarget(target)),
+		TagHandling: t.tagHandling,
+	}
+	if source != "" && source != "auto" {
+		req.SourceLang = deeplSource(source)
+	}
+	req.GlossaryID = t.glossaryFor(req.SourceLang, req.TargetLang)
+
+	result, err := t.translate(req)
+	if err != nil {
+		return "", err
+	}
+	return result.Translations[0].Text, nil
+}
+
+// Detect 识别文本的语言：DeepL 没有单独的语种识别接口，翻译为英语后取检测出的源语言（会计入字符额度）
+func (t *DeepLTranslator) Detect(text string) (string, error) {
+	result, err := t.translate(deeplTranslateRequest{Text: []string{text}, TargetLang: "EN-US"})
+	if err != nil {
+		return "", err
+	}
+	lang := strings.ToLower(result.Translations[0].DetectedSourceLanguage)
+	if lang == "" {
+		return "", fmt.Errorf("DeepL 未返回源语言")
+	}
+	return lang, nil
+}
+
+// Supports 是否支持从 source 翻译到 target
+func (t *DeepLTranslator) Supports(source, target string) bool {
+	return supportsPair(deeplLanguages, source, target)
+}
+
+// Usage 查询当前计费周期的字符用量（GET /usage）
+func (t *DeepLTranslator) Usage() (Usage, error) {
+	var usage Usage
+	err := t.call("GET", "/usage", nil, &usage)
+	return usage, err
+}
+
+// Glossaries 列出账户中的术语表（GET /glossaries）
+func (t *DeepLTranslator) Glossaries() ([]DeepLGlossary, error) {
+	var result struct {
+		Glossaries []DeepLGlossary `json:"glossaries"`
+	}
+	err := t.call("GET", "/glossaries", nil, &result)
+	return result.Glossaries, err
+}
+
+// Glossary 查询术语表（GET /glossaries/{id}）
+func (t *DeepLTranslator) Glossary(id string) (DeepLGlossary, error) {
+	var glossary DeepLGlossary
+	err := t.call("GET", "/glossaries/"+url.PathEscape(id), nil, &glossary)
+	return glossary, err
+}
+
+// glossaryFor 术语表的语言对与本次翻译一致时返回术语表 ID。DeepL 要求使用术语表时指定源语言，
+// 自动检测源语言时不使用术语表
+func (t *DeepLTranslator) glossaryFor(sourceLang, targetLang string) string {
+	if t.glossary == "" || sourceLang == "" {
+		return ""
+	}
+
+	// 同时翻译的请求等待第一次查询完成，不各自重复查询
+	t.glossaryOnce.Do(func() {
+		if g, err := t.Glossary(t.glossary); err != nil {
+			fmt.Println("[DeepL] ⚠ 获取术语表失败，不使用术语表:", err)
+		} else {
+			t.glossaryInfo = &g
+		}
+	})
+	g := t.glossaryInfo
+
+	if g == nil || !strings.EqualFold(g.SourceLang, sourceLang) ||
+		!strings.EqualFold(g.TargetLang, baseLang(targetLang)) {
+		return ""
+	}
+	return g.ID
+}
+
+// translate 调用 /translate
+func (t *DeepLTranslator) translate(req deeplTranslateRequest) (*deeplTranslateResponse, error) {
+	var result deeplTranslateResponse
+	if err := t.call("POST", "/translate", req, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Translations) == 0 {
+		return nil, fmt.Errorf("DeepL 没有返回译文")
+	}
+	return &result, nil
+}
+
+// call 调用 DeepL API，body 不为 nil 时以 JSON 发送；把响应解析到 out
+func (t *DeepLTranslator) call(method, path string, body any, out any) error {
+	if t.apiKey == "" {
+		return fmt.Errorf("DeepL API Key 未配置")
+	}
+
+	var reader io.Reader
+	if body != nil {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("序列化请求失败: %w", err)
+		}
+		reader = bytes.NewReader(bodyBytes)
+	}
+	req, err := http.NewRequest(method, t.endpoint+path, reader)
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Authorization", "DeepL-Auth-Key "+t.apiKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("读取响应失败: %w", err)
+	}
+
+	// 出错时 DeepL 返回 {"message": "..."}，附在状态码说明之后
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Message string `json:"message"`
+		}
+		json.Unmarshal(respBody, &failure)
+		desc, ok := deeplErrors[resp.StatusCode]
+		if !ok {
+			desc = resp.Status
+		}
+		if failure.Message != "" {
+			return fmt.Errorf("DeepL %d: %s（%s）", resp.StatusCode, desc, failure.Message)
+		}
+		return fmt.Errorf("DeepL %d: %s", resp.StatusCode, desc)
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("解析响应失败: %w", err)
+	}
+	return nil
+}
+
+// deeplSource 源语言的写法：基本语言代码的大写（zh-TW 的源语言为 ZH）
+func deeplSource(code string) string {
+	return strings.ToUpper(baseLang(code))
+}
+
+// deeplTarget 目标语言的写法（如 zh → ZH-HANS、en → EN-US）
+func deeplTarget(code string) string {
+	if target, ok := deeplTargets[code]; ok {
+		return target
+	}
+	return strings.ToUpper(code)
+}
+
+// deeplFormalityFor 目标语言不支持设置正式程度时，把 more / less 换成 prefer_more / prefer_less
+func deeplFormalityFor(formality, targetLang string) string {
+	if (formality == "more" || formality == "less") && !slices.Contains(deeplFormality, strings.ToUpper(baseLang(targetLang))) {
+		return "prefer_" + formality
+	}
+	return formality
+}
+
+// baseLang 去掉地区或书写系统后缀的基本语言代码（如 zh-TW → zh、EN-US → EN）
+func baseLang(code string) string {
+	base, _, _ := strings.Cut(code, "-")
+	return base
+}
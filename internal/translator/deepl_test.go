@@ -0,0 +1,292 @@
+package translator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// deepLStubKey 模拟服务接受的 API Key；"quota:fx" 模拟额度已用完
+const deepLStubKey = "stub-key:fx"
+
+// deepLServer 模拟的 DeepL API：核对 Authorization，提供 /translate、/usage、/glossaries，
+// 记录最近一次翻译请求和查询术语表的次数
+type deepLServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	last    map[string]any
+	usage   int64
+	lookups int
+}
+
+// deepLTestGlossary 模拟服务中唯一的术语表
+var deepLTestGlossary = DeepLGlossary{ID: "g-en-de", Name: "产品名称", Ready: true, SourceLang: "en", TargetLang: "de", EntryCount: 12}
+
+func newDeepLServer(t *testing.T) *deepLServer {
+	s := &deepLServer{usage: 480000}
+	fail := func(w http.ResponseWriter, status int, message string) {
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string{"message": message})
+	}
+
+	mux := http.NewServeMux()
+	auth := func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.Header.Get("Authorization") {
+			case "DeepL-Auth-Key " + deepLStubKey:
+				next(w, r)
+			case "DeepL-Auth-Key quota:fx":
+				fail(w, 456, "Quota exceeded")
+			default:
+				fail(w, http.StatusForbidden, "Wrong endpoint. Use https://api.deepl.com")
+			}
+		}
+	}
+	mux.HandleFunc("/v2/translate", auth(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&req) != nil {
+			fail(w, http.StatusBadRequest, "Invalid request")
+			return
+		}
+		texts, _ := req["text"].([]any)
+		if len(texts) != 1 {
+			fail(w, http.StatusBadRequest, "Parameter 'text' not specified.")
+			return
+		}
+		if _, ok := req["glossary_id"]; ok && req["source_lang"] == nil {
+			fail(w, http.StatusBadRequest, "Use of a glossary requires the source_lang parameter to be specified.")
+			return
+		}
+		text := texts[0].(string)
+		s.mu.Lock()
+		s.last = req
+		s.usage += int64(len([]rune(text)))
+		s.mu.Unlock()
+
+		detected := "EN"
+		if strings.ContainsFunc(text, func(r rune) bool { return r >= 0x3040 && r <= 0x30FF }) {
+			detected = "JA"
+		}
+		source, ok := req["source_lang"]
+		if !ok {
+			source = "auto"
+		}
+		json.NewEncoder(w).Encode(map[string]any{"translations": []map[string]string{
+			{"detected_source_language": detected, "text": fmt.Sprintf("[%v>%v] %s", source, req["target_lang"], text)},
+		}})
+	}))
+	mux.HandleFunc("/v2/usage", auth(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]int64{"character_count": s.usage, "character_limit": 500000})
+	}))
+	mux.HandleFunc("/v2/glossaries", auth(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"glossaries": []DeepLGlossary{deepLTestGlossary}})
+	}))
+	mux.HandleFunc("/v2/glossaries/", auth(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.lookups++
+		s.mu.Unlock()
+		if strings.TrimPrefix(r.URL.Path, "/v2/glossaries/") != deepLTestGlossary.ID {
+			fail(w, http.StatusNotFound, "Glossary not found")
+			return
+		}
+		json.NewEncoder(w).Encode(deepLTestGlossary)
+	}))
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// request 最近一次翻译请求中的字段，没有该字段时为 "-"
+func (s *deepLServer) request(keys ...string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		values[i] = "-"
+		if v, ok := s.last[key]; ok {
+			values[i] = fmt.Sprint(v)
+		}
+	}
+	return strings.Join(values, " ")
+}
+
+// translator 指向模拟服务的 DeepL 翻译器，change 不为 nil 时修改设置
+func (s *deepLServer) translator(change func(s *Settings)) *DeepLTranslator {
+	settings := Settings{APIKey: deepLStubKey, Endpoint: s.URL + "/v2"}
+	if change != nil {
+		change(&settings)
+	}
+	return NewDeepLTranslator(settings)
+}
+
+func TestDeepLEndpoint(t *testing.T) {
+	if got := DeepLEndpoint("0f3c-1234:fx"); got != DeepLFreeEndpoint {
+		t.Errorf("免费版密钥的地址 %s", got)
+	}
+	if got := DeepLEndpoint("0f3c-1234"); got != DeepLProEndpoint {
+		t.Errorf("专业版密钥的地址 %s", got)
+	}
+}
+
+func TestDeepLTranslate(t *testing.T) {
+	server := newDeepLServer(t)
+	cases := []struct {
+		name                 string
+		change               func(s *Settings)
+		text, source, target string
+		want                 string
+		keys                 []string // 核对的请求字段
+		wantRequest          string
+	}{
+		{"auto-detect", nil, "Save changes", "auto", "zh", "[auto>ZH-HANS] Save changes",
+			[]string{"source_lang", "formality", "glossary_id", "tag_handling"}, "- - - -"},
+		{"formality", func(s *Settings) { s.Formality = "more" }, "How are you?", "en", "de", "[EN>DE] How are you?",
+			[]string{"target_lang", "formality"}, "DE more"},
+		// 中文不支持设置正式程度，换成 prefer_more
+		{"formality-prefer", func(s *Settings) { s.Formality = "more" }, "How are you?", "en", "zh-TW", "[EN>ZH-HANT] How are you?",
+			[]string{"target_lang", "formality"}, "ZH-HANT prefer_more"},
+		{"formality-less", func(s *Settings) { s.Formality = "less" }, "How are you?", "zh-TW", "pt", "[ZH>PT-BR] How are you?",
+			[]string{"source_lang", "target_lang", "formality"}, "ZH PT-BR less"},
+		{"tag-handling", func(s *Settings) { s.TagHandling = "xml" }, "<b>Save</b> changes", "en", "ja", "[EN>JA] <b>Save</b> changes",
+			[]string{"tag_handling"}, "xml"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := server.translator(c.change).Translate(c.text, c.source, c.target)
+			if err != nil || got != c.want {
+				t.Fatalf("Translate = %q, %v，期望 %q", got, err, c.want)
+			}
+			if req := server.request(c.keys...); req != c.wantRequest {
+				t.Errorf("请求中 %v = %q，期望 %q", c.keys, req, c.wantRequest)
+			}
+		})
+	}
+}
+
+func TestDeepLGlossary(t *testing.T) {
+	server := newDeepLServer(t)
+	cases := []struct {
+		name           string
+		glossary       string
+		source, target string
+		want           string // 请求中的 glossary_id
+	}{
+		// 语言对一致时使用术语表，不一致或自动检测源语言时不使用
+		{"same-pair", "g-en-de", "en", "de", "g-en-de"},
+		{"other-target", "g-en-de", "en", "fr", "-"},
+		{"auto-source", "g-en-de", "auto", "de", "-"},
+		{"missing", "missing", "en", "de", "-"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tr := server.translator(func(s *Settings) { s.Glossary = c.glossary })
+			if _, err := tr.Translate("ScreenOCR settings", c.source, c.target); err != nil {
+				t.Fatal(err)
+			}
+			if got := server.request("glossary_id"); got != c.want {
+				t.Errorf("glossary_id = %s，期望 %s", got, c.want)
+			}
+		})
+	}
+
+	tr := server.translator(nil)
+	if _, err := tr.Glossary("missing"); err == nil || !strings.Contains(err.Error(), "资源不存在") {
+		t.Errorf("查询不存在的术语表应报告 404: %v", err)
+	}
+	list, err := tr.Glossaries()
+	if err != nil || len(list) != 1 || list[0] != deepLTestGlossary {
+		t.Errorf("Glossaries = %+v, %v", list, err)
+	}
+}
+
+// TestDeepLGlossaryLookupOnce 同时翻译时只查询一次术语表，查询失败后也不再重复查询
+func TestDeepLGlossaryLookupOnce(t *testing.T) {
+	for _, id := range []string{"g-en-de", "missing"} {
+		server := newDeepLServer(t)
+		tr := server.translator(func(s *Settings) { s.Glossary = id })
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := tr.Translate("Save", "en", "de"); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+		tr.Translate("Save", "en", "de")
+		server.mu.Lock()
+		if server.lookups != 1 {
+			t.Errorf("%s: 查询了 %d 次术语表，应为 1 次", id, server.lookups)
+		}
+		server.mu.Unlock()
+	}
+}
+
+func TestDeepLDetect(t *testing.T) {
+	server := newDeepLServer(t)
+	if got, err := server.translator(nil).Detect("設定を保存"); err != nil || got != "ja" {
+		t.Errorf("Detect = %q, %v，期望 ja", got, err)
+	}
+	// 检测语言时翻译为英语
+	if got := server.request("target_lang", "source_lang"); got != "EN-US -" {
+		t.Errorf("检测请求 %s", got)
+	}
+}
+
+func TestDeepLUsage(t *testing.T) {
+	server := newDeepLServer(t)
+	tr, err := New("deepl", Settings{APIKey: deepLStubKey, Endpoint: server.URL + "/v2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reporter, ok := tr.(UsageReporter)
+	if !ok {
+		t.Fatal("DeepL 应实现 UsageReporter")
+	}
+
+	// 翻译的字符计入用量
+	if _, err := tr.Translate("保存更改", "zh", "en"); err != nil {
+		t.Fatal(err)
+	}
+	usage, err := reporter.Usage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.CharacterCount != 480004 || usage.CharacterLimit != 500000 || usage.Remaining() != 19996 {
+		t.Errorf("用量 %+v，剩余 %d", usage, usage.Remaining())
+	}
+}
+
+func TestDeepLErrors(t *testing.T) {
+	server := newDeepLServer(t)
+	cases := []struct {
+		name   string
+		apiKey string
+		want   string
+	}{
+		{"wrong-key", "wrong", "DeepL 403: 认证失败"},
+		{"quota", "quota:fx", "DeepL 456: 本计费周期的字符额度已用完（Quota exceeded）"},
+		{"not-configured", "", "DeepL API Key 未配置"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tr := server.translator(func(s *Settings) { s.APIKey = c.apiKey })
+			_, err := tr.Translate("Save", "en", "de")
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Errorf("错误 %v，应包含 %q", err, c.want)
+			}
+		})
+	}
+	if NewDeepLTranslator(Settings{}).IsConfigured() {
+		t.Error("没有 API Key 时不应视为已配置")
+	}
+}
@@ -0,0 +1,262 @@
+package translator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LibreTranslator LibreTranslate 翻译器，用于自建（内网）的 LibreTranslate 兼容服务，文字不离开内网
+type LibreTranslator struct {
+	endpoint string // 服务地址，如 http://10.0.0.5:5000
+	apiKey   string // 服务开启 --api-keys 时需要
+	client   *http.Client
+
+	mu           sync.Mutex
+	languages    []LibreLanguage // 服务支持的语言，首次使用时从 /languages 获取
+	languagesErr error           // 上次获取语言列表的错误，retryAt 之前直接返回
+	retryAt      time.Time
+}
+
+// libreRetryDelay 获取语言列表失败后再次请求前的等待时间，避免服务不可用时每次翻译都等到超时
+const libreRetryDelay = 30 * time.Second
+
+// LibreLanguage /languages 返回的一种语言
+type LibreLanguage struct {
+	Code    string   `json:"code"`
+	Name    string   `json:"name"`
+	Targets []string `json:"targets"` // 可以翻译到的语言，旧版服务不返回（视为全部）
+}
+
+// libreTranslateRequest /translate 请求
+type libreTranslateRequest struct {
+	Q      string `json:"q"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Format string `json:"format"`
+	APIKey string `json:"api_key,omitempty"`
+}
+
+// libreTranslateResponse /translate 响应
+type libreTranslateResponse struct {
+	TranslatedText   string `json:"translatedText"`
+	DetectedLanguage *struct {
+		Language string `json:"language"`
+	} `json:"detectedLanguage"`
+}
+
+// libreDetectRequest /detect 请求
+type libreDetectRequest struct {
+	Q      string `json:"q"`
+	APIKey string `json:"api_key,omitempty"`
+}
+
+// libreDetection /detect 响应中的一项（按可信度从高到低）
+type libreDetection struct {
+	Language   string  `json:"language"`
+	Confidence float64 `json:"confidence"`
+}
+
+// libreAliases 本程序的语言代码在不同版本的 LibreTranslate 中的写法
+var libreAliases = map[string][]string{
+	"zh":    {"zh-Hans"},
+	"zh-TW": {"zh-Hant", "zt"},
+}
+
+func init() {
+	Register(Info{
+		Name:  "libretranslate",
+		Label: "LibreTranslate（自建）",
+		Fields: []Field{
+			{Key: "endpoint", Label: "服务地址", Placeholder: "http://localhost:5000"},
+			{Key: "api_key", Label: "API Key", Placeholder: "服务未要求时留空", Secret: true, Optional: true},
+		},
+		Link: "https://github.com/LibreTranslate/LibreTranslate",
+	}, func(s Settings) Provider {
+		return NewLibreTranslator(s.Endpoint, s.APIKey)
+	})
+}
+
+// NewLibreTranslator 创建 LibreTranslate 翻译器；endpoint 未写协议时按 http 处理
+func NewLibreTranslator(endpoint, apiKey string) *LibreTranslator {
+	endpoint = strings.TrimRight(strings.TrimSpace(endpoint), "/")
+	if endpoint != "" && !strings.Contains(endpoint, "://") {
+		endpoint = "http://" + endpoint
+	}
+	return &LibreTranslator{
+		endpoint: endpoint,
+		apiKey:   strings.TrimSpace(apiKey),
+		// 自建服务通常在 CPU 上运行，长文本翻译较慢
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// IsConfigured 检查是否已配置（API Key 可选）
+func (t *LibreTranslator) IsConfigured() bool {
+	return t.endpoint != ""
+}
+
+// Translate 翻译文本
+func (t *LibreTranslator) Translate(text, source, target string) (string, error) {
+	// 语言列表获取失败时按原样传递语言代码，由服务报告不支持的语言
+	langs, _ := t.Languages()
+
+	var result libreTranslateResponse
+	err := t.call("/translate", libreTranslateRequest{
+		Q:      text,
+		Source: libreCode(langs, source),
+		Target: libreCode(langs, target),
+		Format: "text",
+		APIKey: t.apiKey,
+	}, &result)
+	if err != nil {
+		return "", err
+	}
+	return result.TranslatedText, nil
+}
+
+// Detect 识别文本的语言
+func (t *LibreTranslator) Detect(text string) (string, error) {
+	var result []libreDetection
+	if err := t.call("/detect", libreDetectRequest{Q: text, APIKey: t.apiKey}, &result); err != nil {
+		return "", err
+	}
+	if len(result) == 0 {
+		return "", fmt.Errorf("LibreTranslate 未识别出语言")
+	}
+	return appCode(result[0].Language), nil
+}
+
+// Supports 按服务的语言列表判断是否支持从 source 翻译到 target；获取不到语言列表时返回 false
+func (t *LibreTranslator) Supports(source, target string) bool {
+	langs, err := t.Languages()
+	if err != nil || source == target || target == "auto" {
+		return false
+	}
+	target = libreCode(langs, target)
+	hasTarget := slices.ContainsFunc(langs, func(l LibreLanguage) bool { return l.Code == target })
+	if source == "auto" || !hasTarget {
+		return hasTarget
+	}
+
+	source = libreCode(langs, source)
+	for _, l := range langs {
+		if l.Code == source {
+			return l.Targets == nil || slices.Contains(l.Targets, target)
+		}
+	}
+	return false
+}
+
+// Languages 服务支持的语言（GET /languages），成功后缓存；失败时在 libreRetryDelay 内返回同一错误
+func (t *LibreTranslator) Languages() ([]LibreLanguage, error) {
+	t.mu.Lock()
+	langs, err, retryAt := t.languages, t.languagesErr, t.retryAt
+	t.mu.Unlock()
+	if langs != nil {
+		return langs, nil
+	}
+	if err != nil && time.Now().Before(retryAt) {
+		return nil, err
+	}
+
+	// 请求期间不持有锁，服务响应慢时不阻塞其他调用
+	err = t.call("/languages", nil, &langs)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err != nil {
+		t.languagesErr, t.retryAt = err, time.Now().Add(libreRetryDelay)
+		return nil, err
+	}
+	if langs == nil {
+		langs = []LibreLanguage{}
+	}
+	t.languages, t.languagesErr = langs, nil
+	return langs, nil
+}
+
+// call 调用 LibreTranslate API：body 为 nil 时发送 GET，否则 POST JSON；把响应解析到 out
+func (t *LibreTranslator) call(path string, body any, out any) error {
+	if t.endpoint == "" {
+		return fmt.Errorf("LibreTranslate 服务地址未配置")
+	}
+
+	var req *http.Request
+	var err error
+	if body == nil {
+		req, err = http.NewRequest("GET", t.endpoint+path, nil)
+	} else {
+		var bodyBytes []byte
+		if bodyBytes, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("序列化请求失败: %w", err)
+		}
+		req, err = http.NewRequest("POST", t.endpoint+path, bytes.NewReader(bodyBytes))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("读取响应失败: %w", err)
+	}
+
+	// 出错时服务返回 {"error": "..."}
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(respBody, &failure) == nil && failure.Error != "" {
+			return fmt.Errorf("LibreTranslate %d: %s", resp.StatusCode, failure.Error)
+		}
+		return fmt.Errorf("LibreTranslate %s", resp.Status)
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("解析响应失败: %w", err)
+	}
+	return nil
+}
+
+// libreCode 把本程序的语言代码换成服务语言列表中的写法（如 zh → zh-Hans），列表中没有时原样返回
+func libreCode(langs []LibreLanguage, code string) string {
+	has := func(c string) bool {
+		return slices.ContainsFunc(langs, func(l LibreLanguage) bool { return l.Code == c })
+	}
+	if code == "auto" || has(code) {
+		return code
+	}
+	for _, alias := range libreAliases[code] {
+		if has(alias) {
+			return alias
+		}
+	}
+	return code
+}
+
+// appCode 把服务返回的语言代码换回本程序的写法（如 zh-Hans → zh）
+func appCode(code string) string {
+	for app, aliases := range libreAliases {
+		if slices.Contains(aliases, code) {
+			return app
+		}
+	}
+	return code
+}
@@ -0,0 +1,277 @@
+package translator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// libreServer 模拟的 LibreTranslate 服务：新版语言代码（zh-Hans / zh-Hant），apiKey 不为空时要求 API Key，
+// 记录最近一次 /translate 请求和请求 /languages 的次数
+type libreServer struct {
+	*httptest.Server
+	apiKey string
+
+	mu        sync.Mutex
+	last      map[string]any
+	languages int
+}
+
+// newLibreServer 启动模拟服务，prefix 为反向代理下的路径前缀（如 /libre）
+func newLibreServer(t *testing.T, apiKey, prefix string) *libreServer {
+	s := &libreServer{apiKey: apiKey}
+	mux := http.NewServeMux()
+	mux.HandleFunc(prefix+"/languages", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.languages++
+		s.mu.Unlock()
+		if r.Method != http.MethodGet {
+			http.Error(w, `{"error":"method"}`, http.StatusMethodNotAllowed)
+			return
+		}
+		json.NewEncoder(w).Encode([]LibreLanguage{
+			{Code: "en", Name: "English", Targets: []string{"en", "zh-Hans", "zh-Hant", "ja"}},
+			{Code: "zh-Hans", Name: "Chinese", Targets: []string{"zh-Hans", "en", "zh-Hant"}},
+			{Code: "zh-Hant", Name: "Chinese (traditional)", Targets: []string{"zh-Hant", "en", "zh-Hans"}},
+			{Code: "ja", Name: "Japanese", Targets: []string{"ja", "en"}},
+		})
+	})
+	mux.HandleFunc(prefix+"/translate", func(w http.ResponseWriter, r *http.Request) {
+		req, ok := s.decode(w, r)
+		if !ok {
+			return
+		}
+		s.mu.Lock()
+		s.last = req
+		s.mu.Unlock()
+		q, _ := req["q"].(string)
+		source, _ := req["source"].(string)
+		target, _ := req["target"].(string)
+		resp := map[string]any{"translatedText": fmt.Sprintf("[%s>%s] %s", source, target, q)}
+		if source == "auto" {
+			resp["detectedLanguage"] = map[string]any{"language": "zh-Hans", "confidence": 90}
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+	mux.HandleFunc(prefix+"/detect", func(w http.ResponseWriter, r *http.Request) {
+		req, ok := s.decode(w, r)
+		if !ok {
+			return
+		}
+		lang := "en"
+		if q, _ := req["q"].(string); strings.ContainsFunc(q, func(r rune) bool { return r >= 0x4E00 && r <= 0x9FFF }) {
+			lang = "zh-Hans"
+		}
+		json.NewEncoder(w).Encode([]map[string]any{{"language": lang, "confidence": 95}, {"language": "ja", "confidence": 3}})
+	})
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// decode 解析 JSON 请求并核对 API Key，失败时按 LibreTranslate 的格式返回错误
+func (s *libreServer) decode(w http.ResponseWriter, r *http.Request) (map[string]any, bool) {
+	w.Header().Set("Content-Type", "application/json")
+	var req map[string]any
+	body, _ := io.ReadAll(r.Body)
+	if r.Method != http.MethodPost || json.Unmarshal(body, &req) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"error":"Invalid request"}`)
+		return nil, false
+	}
+	if key, _ := req["api_key"].(string); s.apiKey != "" && key != s.apiKey {
+		w.WriteHeader(http.StatusForbidden)
+		io.WriteString(w, `{"error":"Invalid API key"}`)
+		return nil, false
+	}
+	return req, true
+}
+
+// request 最近一次翻译请求中的字段
+func (s *libreServer) request(key string) any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last[key]
+}
+
+// languageRequests 请求 /languages 的次数
+func (s *libreServer) languageRequests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.languages
+}
+
+func TestLibreEndpoint(t *testing.T) {
+	cases := map[string]string{
+		"10.0.0.5:5000":                        "http://10.0.0.5:5000",
+		" https://intranet.example.com/libre/": "https://intranet.example.com/libre",
+		"":                                     "",
+	}
+	for in, want := range cases {
+		if got := NewLibreTranslator(in, "").endpoint; got != want {
+			t.Errorf("NewLibreTranslator(%q) 的地址 %q，期望 %q", in, got, want)
+		}
+	}
+}
+
+func TestLibreTranslate(t *testing.T) {
+	server := newLibreServer(t, "secret", "/libre")
+	tr, err := New("libretranslate", Settings{Endpoint: server.URL + "/libre/", APIKey: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		text, source, target string
+		want                 string
+	}{
+		{"屏幕文字识别", "auto", "en", "[auto>en] 屏幕文字识别"},
+		// 本程序的语言代码换成服务语言列表中的写法
+		{"简体", "zh", "zh-TW", "[zh-Hans>zh-Hant] 简体"},
+		// 服务不支持的语言按原样传递
+		{"Hello", "en", "ko", "[en>ko] Hello"},
+	}
+	for _, c := range cases {
+		got, err := tr.Translate(c.text, c.source, c.target)
+		if err != nil || got != c.want {
+			t.Errorf("Translate(%q, %s, %s) = %q, %v，期望 %q", c.text, c.source, c.target, got, err, c.want)
+		}
+	}
+	if server.request("format") != "text" || server.request("api_key") != "secret" {
+		t.Errorf("请求中 format=%v api_key=%v", server.request("format"), server.request("api_key"))
+	}
+
+	// 服务返回的语言代码换回本程序的写法
+	if got, err := tr.Detect("屏幕文字识别"); err != nil || got != "zh" {
+		t.Errorf("Detect = %q, %v，期望 zh", got, err)
+	}
+
+	langs, err := tr.(*LibreTranslator).Languages()
+	var codes []string
+	for _, l := range langs {
+		codes = append(codes, l.Code)
+	}
+	if err != nil || strings.Join(codes, ",") != "en,zh-Hans,zh-Hant,ja" {
+		t.Errorf("Languages = %v, %v", codes, err)
+	}
+	// 语言列表只获取一次
+	if n := server.languageRequests(); n != 1 {
+		t.Errorf("请求了 %d 次 /languages，应为 1 次", n)
+	}
+}
+
+func TestLibreCode(t *testing.T) {
+	newer := []LibreLanguage{{Code: "en"}, {Code: "zh-Hans"}, {Code: "zh-Hant"}}
+	older := []LibreLanguage{{Code: "en"}, {Code: "zh"}, {Code: "zt"}}
+	cases := []struct {
+		name  string
+		langs []LibreLanguage
+		code  string
+		want  string
+	}{
+		{"newer-zh", newer, "zh", "zh-Hans"},
+		{"newer-zh-TW", newer, "zh-TW", "zh-Hant"},
+		{"older-zh", older, "zh", "zh"},
+		{"older-zh-TW", older, "zh-TW", "zt"},
+		{"auto", newer, "auto", "auto"},
+		// 获取不到语言列表时原样传递
+		{"no-languages", nil, "zh-TW", "zh-TW"},
+	}
+	for _, c := range cases {
+		if got := libreCode(c.langs, c.code); got != c.want {
+			t.Errorf("%s: libreCode(%s) = %s，期望 %s", c.name, c.code, got, c.want)
+		}
+	}
+	for code, want := range map[string]string{"zh-Hans": "zh", "zh-Hant": "zh-TW", "zt": "zh-TW", "ja": "ja"} {
+		if got := appCode(code); got != want {
+			t.Errorf("appCode(%s) = %s，期望 %s", code, got, want)
+		}
+	}
+}
+
+func TestLibreSupports(t *testing.T) {
+	server := newLibreServer(t, "", "")
+	tr := NewLibreTranslator(server.URL, "")
+	cases := []struct {
+		source, target string
+		want           bool
+	}{
+		{"auto", "zh", true}, {"en", "ja", true}, {"zh-TW", "zh", true},
+		// 服务的语言对中没有的组合
+		{"zh", "ja", false}, {"ja", "zh-TW", false}, {"en", "ko", false},
+		{"en", "en", false}, {"en", "auto", false},
+	}
+	for _, c := range cases {
+		if got := tr.Supports(c.source, c.target); got != c.want {
+			t.Errorf("Supports(%s, %s) = %v，期望 %v", c.source, c.target, got, c.want)
+		}
+	}
+}
+
+func TestLibreErrors(t *testing.T) {
+	server := newLibreServer(t, "secret", "")
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	cases := []struct {
+		name             string
+		endpoint, apiKey string
+		want             string
+	}{
+		{"wrong-key", server.URL, "wrong", "LibreTranslate 403: Invalid API key"},
+		{"unreachable", down.URL, "", "请求失败"},
+		{"not-configured", "", "", "LibreTranslate 服务地址未配置"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := NewLibreTranslator(c.endpoint, c.apiKey).Translate("hello", "en", "zh")
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Errorf("错误 %v，应包含 %q", err, c.want)
+			}
+		})
+	}
+	if NewLibreTranslator("", "secret").IsConfigured() {
+		t.Error("没有服务地址时不应视为已配置")
+	}
+}
+
+// TestLibreLanguagesRetry 获取语言列表失败后在等待时间内不再请求，之后重新获取
+func TestLibreLanguagesRetry(t *testing.T) {
+	server := newLibreServer(t, "", "")
+	var mu sync.Mutex
+	failing := true
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if failing {
+			w.WriteHeader(http.StatusBadGateway)
+			io.WriteString(w, `{"error":"upstream unavailable"}`)
+			return
+		}
+		server.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(proxy.Close)
+
+	tr := NewLibreTranslator(proxy.URL, "")
+	_, first := tr.Languages()
+	mu.Lock()
+	failing = false
+	mu.Unlock()
+	if _, err := tr.Languages(); err == nil || err != first || tr.Supports("en", "zh") {
+		t.Errorf("等待时间内应返回上次的错误 %v，实际 %v", first, err)
+	}
+	if n := server.languageRequests(); n != 0 {
+		t.Errorf("等待时间内请求了 %d 次 /languages", n)
+	}
+
+	tr.mu.Lock()
+	tr.retryAt = time.Now().Add(-time.Second)
+	tr.mu.Unlock()
+	if langs, err := tr.Languages(); err != nil || len(langs) != 4 || !tr.Supports("en", "zh") {
+		t.Errorf("等待时间过后应重新获取: %v, %v", langs, err)
+	}
+}
@@ -0,0 +1,324 @@
+package translator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultOpenAIEndpoint OpenAI 官方服务地址；llama.cpp、Ollama 等本地服务填写各自的地址（如 http://localhost:11434/v1）
+const DefaultOpenAIEndpoint = "https://api.openai.com/v1"
+
+// DefaultPrompt 默认的系统提示词模板。占位符：
+//
+//	{source}   源语言名称（自动检测时为 the detected language）
+//	{target}   目标语言名称
+//	{context}  截图中附近的文字说明，没有时为空；模板中没有该占位符时附加在末尾
+const DefaultPrompt = `You are a professional translation engine. Translate the user's text from {source} to {target}.
+Output only the translation, without explanations, quotes or notes. Keep line breaks, numbers, code and product names unchanged.
+{context}`
+
+// OpenAITranslator OpenAI 兼容的大模型翻译器，调用 /chat/completions
+type OpenAITranslator struct {
+	endpoint    string
+	apiKey      string
+	model       string
+	prompt      string
+	temperature *float64
+	stream      bool
+	client      *http.Client
+}
+
+// chatMessage 对话消息
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// chatRequest /chat/completions 请求
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature *float64      `json:"temperature,omitempty"`
+	Stream      bool          `json:"stream,omitempty"`
+}
+
+// chatResponse /chat/completions 响应；流式输出时每个数据块的内容在 Delta 中
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+		Delta   chatMessage `json:"delta"`
+	} `json:"choices"`
+	Error json.RawMessage `json:"error"`
+}
+
+// llmLanguageNames 语言代码对应的英文名称，写入提示词
+var llmLanguageNames = map[string]string{
+	"zh": "Simplified Chinese", "zh-TW": "Traditional Chinese", "en": "English", "ja": "Japanese",
+	"ko": "Korean", "fr": "French", "de": "German", "es": "Spanish", "ru": "Russian", "it": "Italian",
+	"pt": "Portuguese", "vi": "Vietnamese", "th": "Thai", "ar": "Arabic", "id": "Indonesian",
+	"ms": "Malay", "tr": "Turkish", "hi": "Hindi",
+}
+
+// thinking 推理模型（如 DeepSeek-R1、Qwen3）输出的思考过程
+var thinking = regexp.MustCompile(`(?s)<think>.*?</think>`)
+
+func init() {
+	Register(Info{
+		Name:  "openai",
+		Label: "OpenAI 兼容大模型",
+		Fields: []Field{
+			{Key: "endpoint", Label: "服务地址", Placeholder: DefaultOpenAIEndpoint, Optional: true},
+			{Key: "api_key", Label: "API Key", Placeholder: "本地服务可留空", Secret: true, Optional: true},
+			{Key: "model", Label: "模型", Placeholder: "如 gpt-4o-mini、qwen2.5:7b"},
+			{Key: "temperature", Label: "温度", Placeholder: "默认", Type: "number", Optional: true},
+			{Key: "stream", Label: "流式输出", Type: "checkbox", Optional: true},
+			{Key: "prompt", Label: "提示词", Placeholder: "留空使用默认提示词，可用 {source} {target} {context}", Type: "textarea", Optional: true},
+		},
+		Link: "https://platform.openai.com/api-keys",
+	}, func(s Settings) Provider {
+		return NewOpenAITranslator(s)
+	})
+}
+
+// NewOpenAITranslator 创建大模型翻译器；服务地址为空时使用 OpenAI 官方地址
+func NewOpenAITranslator(s Settings) *OpenAITranslator {
+	endpoint := strings.TrimRight(strings.TrimSpace(s.Endpoint), "/")
+	if endpoint == "" {
+		endpoint = DefaultOpenAIEndpoint
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "http://" + endpoint
+	}
+	endpoint = strings.TrimSuffix(endpoint, "/chat/completions")
+
+	return &OpenAITranslator{
+		endpoint:    endpoint,
+		apiKey:      strings.TrimSpace(s.APIKey),
+		model:       strings.TrimSpace(s.Model),
+		prompt:      s.Prompt,
+		temperature: s.Temperature,
+		stream:      s.Stream,
+		// 本地模型生成较慢
+		client: &http.Client{Timeout: 2 * time.Minute},
+	}
+}
+
+// IsConfigured 检查是否已配置：需要模型名称，使用官方服务时还需要 API Key
+func (t *OpenAITranslator) IsConfigured() bool {
+	return t.model != "" && (t.apiKey != "" || t.endpoint != DefaultOpenAIEndpoint)
+}
+
+// Translate 翻译文本
+func (t *OpenAITranslator) Translate(text, source, target string) (string, error) {
+	return t.TranslateContext(text, "", source, target, nil)
+}
+
+// TranslateContext 参考附近的文字翻译 text
+func (t *OpenAITranslator) TranslateContext(text, context, source, target string, onPartial func(partial string)) (string, error) {
+	req := chatRequest{
+		Model: t.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: t.systemPrompt(context, source, target)},
+			{Role: "user", Content: text},
+		},
+		Temperature: t.temperature,
+		Stream:      t.stream,
+	}
+
+	var partial func(string)
+	if onPartial != nil {
+		partial = func(s string) {
+			if s = cleanOutput(s); s != "" {
+				onPartial(s)
+			}
+		}
+	}
+	result, err := t.complete(req, partial)
+	if err != nil {
+		return "", err
+	}
+	return cleanOutput(result), nil
+}
+
+// Detect 让模型识别文本的语言
+func (t *OpenAITranslator) Detect(text string) (string, error) {
+	zero := 0.0
+	result, err := t.complete(chatRequest{
+		Model: t.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: "Identify the language of the user's text. Reply with only its ISO 639-1 code, using zh for Simplified Chinese and zh-TW for Traditional Chinese."},
+			{Role: "user", Content: text},
+		},
+		Temperature: &zero,
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+
+	fields := strings.Fields(strings.Trim(cleanOutput(result), "`'\". "))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("模型未识别出语言")
+	}
+	code := strings.Trim(fields[0], "`'\".,")
+	if strings.EqualFold(code, "zh-TW") || strings.EqualFold(code, "zh-Hant") {
+		return "zh-TW", nil
+	}
+	return strings.ToLower(code), nil
+}
+
+// Supports 大模型可以在任意两种语言之间翻译
+func (t *OpenAITranslator) Supports(source, target string) bool {
+	return target != "" && target != "auto" && source != target
+}
+
+// systemPrompt 按模板生成系统提示词
+func (t *OpenAITranslator) systemPrompt(context, source, target string) string {
+	template := t.prompt
+	if strings.TrimSpace(template) == "" {
+		template = DefaultPrompt
+	}
+
+	sourceName := languageName(source)
+	if source == "" || source == "auto" {
+		sourceName = "the detected language"
+	}
+	contextNote := ""
+	if context = strings.TrimSpace(context); context != "" {
+		contextNote = "The text was captured from a screen. Nearby text on the same screen, for reference only (do not translate it):\n<context>\n" +
+			context + "\n</context>"
+	}
+	if !strings.Contains(template, "{context}") && contextNote != "" {
+		template += "\n" + "{context}"
+	}
+
+	prompt := strings.NewReplacer(
+		"{source}", sourceName,
+		"{target}", languageName(target),
+		"{context}", contextNote,
+	).Replace(template)
+	return strings.TrimSpace(prompt)
+}
+
+// languageName 语言的英文名称，未知的语言代码原样返回
+func languageName(code string) string {
+	if name, ok := llmLanguageNames[code]; ok {
+		return name
+	}
+	return code
+}
+
+// complete 调用 /chat/completions 返回模型输出；req.Stream 为 true 时逐块读取 SSE，每块之后以目前的输出调用 onPartial
+func (t *OpenAITranslator) complete(req chatRequest, onPartial func(string)) (string, error) {
+	if t.model == "" {
+		return "", fmt.Errorf("大模型翻译未配置模型")
+	}
+
+	bodyBytes, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("序列化请求失败: %w", err)
+	}
+	httpReq, err := http.NewRequest("POST", t.endpoint+"/chat/completions", bytes.NewReader(bodyBytes))
+	if err != nil {
+		return "", fmt.Errorf("创建请求失败: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if t.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+t.apiKey)
+	}
+
+	resp, err := t.client.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		var failure chatResponse
+		if json.Unmarshal(respBody, &failure) == nil && errorMessage(failure.Error) != "" {
+			return "", fmt.Errorf("大模型翻译 %d: %s", resp.StatusCode, errorMessage(failure.Error))
+		}
+		return "", fmt.Errorf("大模型翻译 %s", resp.Status)
+	}
+
+	if !req.Stream {
+		var result chatResponse
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return "", fmt.Errorf("解析响应失败: %w", err)
+		}
+		if msg := errorMessage(result.Error); msg != "" {
+			return "", fmt.Errorf("大模型翻译: %s", msg)
+		}
+		if len(result.Choices) == 0 {
+			return "", fmt.Errorf("大模型翻译没有返回结果")
+		}
+		return result.Choices[0].Message.Content, nil
+	}
+
+	// 流式输出：每行 "data: {...}"，以 "data: [DONE]" 结束
+	var sb strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+		var chunk chatResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return "", fmt.Errorf("解析流式响应失败: %w", err)
+		}
+		if msg := errorMessage(chunk.Error); msg != "" {
+			return "", fmt.Errorf("大模型翻译: %s", msg)
+		}
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+		sb.WriteString(chunk.Choices[0].Delta.Content)
+		if onPartial != nil {
+			onPartial(sb.String())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("读取流式响应失败: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// errorMessage 错误信息：OpenAI 为 {"message": ...} 对象，Ollama 等为字符串
+func errorMessage(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var obj struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(raw, &obj) == nil && obj.Message != "" {
+		return obj.Message
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	return string(raw)
+}
+
+// cleanOutput 去掉推理模型的思考过程（流式输出时未结束的思考过程也去掉）和首尾空白
+func cleanOutput(s string) string {
+	s = thinking.ReplaceAllString(s, "")
+	if i := strings.Index(s, "<think>"); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(s)
+}
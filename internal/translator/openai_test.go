@@ -0,0 +1,300 @@
+package translator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// openAIRequest 模拟服务收到的请求
+type openAIRequest struct {
+	auth        string
+	model       string
+	system      string
+	user        string
+	temperature *float64
+	stream      bool
+}
+
+// openAIServer 模拟的 OpenAI 兼容服务：按模型名称返回预设的回复，流式输出时分块发送，记录最近一次请求
+type openAIServer struct {
+	*httptest.Server
+	mu   sync.Mutex
+	last openAIRequest
+}
+
+// openAIReplies 各模型的预设回复（流式输出时按此分块）
+var openAIReplies = map[string][]string{
+	"mock":       {"保存", "更", "改"},
+	"mock-think": {"<think>", "按钮上的文字，", "应译为动词", "</think>", "\n\n保存", "更改"},
+}
+
+func newOpenAIServer(t *testing.T) *openAIServer {
+	s := &openAIServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Model       string        `json:"model"`
+			Messages    []chatMessage `json:"messages"`
+			Temperature *float64      `json:"temperature"`
+			Stream      bool          `json:"stream"`
+		}
+		if r.URL.Path != "/v1/chat/completions" || json.NewDecoder(r.Body).Decode(&req) != nil || len(req.Messages) != 2 {
+			http.Error(w, `{"error":{"message":"invalid request"}}`, http.StatusBadRequest)
+			return
+		}
+		last := openAIRequest{
+			auth: r.Header.Get("Authorization"), model: req.Model, temperature: req.Temperature, stream: req.Stream,
+			system: req.Messages[0].Content, user: req.Messages[1].Content,
+		}
+		s.mu.Lock()
+		s.last = last
+		s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case req.Model == "mock-missing":
+			// Ollama 的错误格式
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"error":"model \"mock-missing\" not found, try pulling it first"}`)
+			return
+		case last.auth != "" && last.auth != "Bearer sk-test":
+			w.WriteHeader(http.StatusUnauthorized)
+			io.WriteString(w, `{"error":{"message":"Incorrect API key provided","type":"invalid_request_error"}}`)
+			return
+		}
+
+		chunks := openAIReplies[req.Model]
+		if strings.HasPrefix(last.system, "Identify the language") {
+			chunks = []string{"zh-TW"}
+			if !strings.ContainsFunc(last.user, func(r rune) bool { return r >= 0x4E00 && r <= 0x9FFF }) {
+				chunks = []string{"`EN`"}
+			}
+		}
+		if !req.Stream {
+			json.NewEncoder(w).Encode(map[string]any{
+				"choices": []map[string]any{{"message": map[string]string{"role": "assistant", "content": strings.Join(chunks, "")}}},
+			})
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"choices":[{"delta":{"role":"assistant"}}]}`+"\n\n")
+		for _, c := range chunks {
+			data, _ := json.Marshal(map[string]any{"choices": []map[string]any{{"delta": map[string]string{"content": c}}}})
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			w.(http.Flusher).Flush()
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// request 最近一次请求
+func (s *openAIServer) request() openAIRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last
+}
+
+// settings 指向模拟服务的设置
+func (s *openAIServer) settings() Settings {
+	temperature := 0.2
+	return Settings{Endpoint: s.URL + "/v1", APIKey: "sk-test", Model: "mock", Temperature: &temperature}
+}
+
+func TestOpenAISystemPrompt(t *testing.T) {
+	const contextNote = "The text was captured from a screen. Nearby text on the same screen, for reference only (do not translate it):\n<context>\nCancel\n</context>"
+	cases := []struct {
+		name            string
+		prompt          string
+		context         string
+		source, target  string
+		want            string
+		wantContains    []string
+		wantNotContains []string
+	}{
+		{name: "default", source: "en", target: "zh",
+			wantContains:    []string{"from English to Simplified Chinese", "Output only the translation"},
+			wantNotContains: []string{"{context}", "<context>"}},
+		{name: "auto-detect", source: "auto", target: "ja",
+			wantContains: []string{"from the detected language to Japanese"}},
+		{name: "unknown-code", source: "en", target: "xx",
+			wantContains: []string{"from English to xx"}},
+		{name: "default-with-context", context: "  Cancel\n", source: "en", target: "zh",
+			wantContains: []string{contextNote}},
+		{name: "custom-placeholder", prompt: "把文字翻译成{target}。{context}", context: "Cancel", source: "en", target: "ja",
+			want: "把文字翻译成Japanese。" + contextNote},
+		// 模板中没有 {context} 时附加在末尾
+		{name: "custom-appended", prompt: "Translate {source} into {target}.", context: "Cancel", source: "en", target: "de",
+			want: "Translate English into German.\n" + contextNote},
+		{name: "custom-no-context", prompt: "Translate into {target}. {context}", source: "en", target: "zh-TW",
+			want: "Translate into Traditional Chinese."},
+		{name: "blank-uses-default", prompt: "  \n", source: "en", target: "zh",
+			wantContains: []string{"You are a professional translation engine"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := NewOpenAITranslator(Settings{Prompt: c.prompt}).systemPrompt(c.context, c.source, c.target)
+			if c.want != "" && got != c.want {
+				t.Errorf("提示词 %q，期望 %q", got, c.want)
+			}
+			for _, s := range c.wantContains {
+				if !strings.Contains(got, s) {
+					t.Errorf("提示词 %q 应包含 %q", got, s)
+				}
+			}
+			for _, s := range c.wantNotContains {
+				if strings.Contains(got, s) {
+					t.Errorf("提示词 %q 不应包含 %q", got, s)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenAITranslate(t *testing.T) {
+	server := newOpenAIServer(t)
+	tr, err := New("openai", server.settings())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := tr.Translate("Save changes", "en", "zh")
+	if err != nil || got != "保存更改" {
+		t.Fatalf("Translate = %q, %v", got, err)
+	}
+	req := server.request()
+	if req.auth != "Bearer sk-test" || req.model != "mock" || req.stream || req.temperature == nil || *req.temperature != 0.2 ||
+		!strings.Contains(req.system, "from English to Simplified Chinese") || req.user != "Save changes" {
+		t.Errorf("请求不正确: %+v", req)
+	}
+}
+
+func TestOpenAIStreaming(t *testing.T) {
+	server := newOpenAIServer(t)
+	cases := []struct {
+		name     string
+		model    string
+		source   string
+		partials []string
+	}{
+		{"stream", "mock", "auto", []string{"保存", "保存更", "保存更改"}},
+		// 推理模型的思考过程不出现在逐步输出和结果中
+		{"strip-thinking", "mock-think", "en", []string{"保存", "保存更改"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			settings := server.settings()
+			settings.Model, settings.Stream = c.model, true
+			var partials []string
+			got, err := TranslateWithContext(NewOpenAITranslator(settings), "Save changes", "", c.source, "zh",
+				func(partial string) { partials = append(partials, partial) })
+			if err != nil || got != "保存更改" {
+				t.Fatalf("TranslateWithContext = %q, %v", got, err)
+			}
+			if !slices.Equal(partials, c.partials) {
+				t.Errorf("逐步输出 %q，期望 %q", partials, c.partials)
+			}
+			if !server.request().stream {
+				t.Error("请求中没有 stream")
+			}
+		})
+	}
+}
+
+func TestOpenAINearbyContext(t *testing.T) {
+	server := newOpenAIServer(t)
+	tr := NewOpenAITranslator(server.settings())
+	nearby := "Do you want to save changes to report.docx?\nDon't Save Cancel"
+
+	if _, err := TranslateWithContext(tr, "Save", nearby, "en", "zh", nil); err != nil {
+		t.Fatal(err)
+	}
+	req := server.request()
+	if !strings.Contains(req.system, "<context>\n"+nearby+"\n</context>") {
+		t.Errorf("提示词中没有附近的文字: %q", req.system)
+	}
+	if req.user != "Save" {
+		t.Errorf("要翻译的文字 %q，附近的文字不应一起翻译", req.user)
+	}
+
+	// 没有附近的文字时提示词中不出现 <context>
+	if _, err := tr.Translate("Save", "en", "zh"); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(server.request().system, "<context>") {
+		t.Error("没有附近的文字时提示词不应包含 <context>")
+	}
+}
+
+func TestOpenAIDetect(t *testing.T) {
+	server := newOpenAIServer(t)
+	tr := NewOpenAITranslator(server.settings())
+	for text, want := range map[string]string{"儲存變更": "zh-TW", "Save changes": "en"} {
+		if got, err := tr.Detect(text); err != nil || got != want {
+			t.Errorf("Detect(%q) = %q, %v，期望 %q", text, got, err, want)
+		}
+	}
+}
+
+func TestOpenAIErrors(t *testing.T) {
+	server := newOpenAIServer(t)
+	cases := []struct {
+		name   string
+		change func(s *Settings)
+		want   string
+	}{
+		{"wrong-key", func(s *Settings) { s.APIKey = "sk-wrong" }, "Incorrect API key provided"},
+		{"ollama-missing-model", func(s *Settings) { s.Model = "mock-missing" }, `model "mock-missing" not found`},
+		{"stream-wrong-key", func(s *Settings) { s.APIKey, s.Stream = "sk-wrong", true }, "Incorrect API key provided"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			settings := server.settings()
+			c.change(&settings)
+			_, err := NewOpenAITranslator(settings).Translate("Save", "en", "zh")
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Errorf("错误 %v，应包含 %q", err, c.want)
+			}
+		})
+	}
+}
+
+func TestOpenAIIsConfigured(t *testing.T) {
+	cases := []struct {
+		name     string
+		settings Settings
+		want     bool
+	}{
+		{"official-without-key", Settings{Model: "gpt-4o-mini"}, false},
+		{"without-model", Settings{APIKey: "sk-test"}, false},
+		{"local-without-key", Settings{Endpoint: "localhost:11434/v1", Model: "qwen"}, true},
+		{"official", Settings{APIKey: "sk-test", Model: "gpt-4o-mini"}, true},
+	}
+	for _, c := range cases {
+		if got := NewOpenAITranslator(c.settings).IsConfigured(); got != c.want {
+			t.Errorf("%s: IsConfigured = %v，期望 %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestCleanOutput(t *testing.T) {
+	cases := map[string]string{
+		"  保存更改\n":                  "保存更改",
+		"<think>推理</think>\n\n保存更改": "保存更改",
+		"<think>还没想完":               "",
+		"保存<think>":                 "保存",
+	}
+	for in, want := range cases {
+		if got := cleanOutput(in); got != want {
+			t.Errorf("cleanOutput(%q) = %q，期望 %q", in, got, want)
+		}
+	}
+}
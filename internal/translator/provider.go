@@ -0,0 +1,175 @@
+package translator
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Provider 翻译服务
+type Provider interface {
+	// Translate 翻译文本，source 为 "auto" 时由服务自动检测源语言
+	Translate(text, source, target string) (string, error)
+
+	// Detect 检测文本的语言，返回语言代码（如 "zh"、"en"）
+	Detect(text string) (string, error)
+
+	// Supports 是否支持从 source 翻译到 target（source 可为 "auto"）
+	Supports(source, target string) bool
+
+	// IsConfigured 是否已填写必需的设置（密钥等）
+	IsConfigured() bool
+}
+
+// ContextTranslator 可以参考周围文字翻译的服务（如大模型）：按钮、菜单等短文本单独翻译时容易译错
+type ContextTranslator interface {
+	// TranslateContext 翻译 text，context 为截图中 text 附近的文字（只作参考，不翻译）；
+	// 服务使用流式输出且 onPartial 不为 nil 时，每收到一段输出就以目前的译文调用一次
+	TranslateContext(text, context, source, target string, onPartial func(partial string)) (string, error)
+}
+
+// TranslateWithContext 服务支持时附带周围文字翻译，否则忽略 context 和 onPartial
+func TranslateWithContext(p Provider, text, context, source, target string, onPartial func(partial string)) (string, error) {
+	if c, ok := p.(ContextTranslator); ok {
+		return c.TranslateContext(text, context, source, target, onPartial)
+	}
+	return p.Translate(text, source, target)
+}
+
+// UsageReporter 可以查询用量的服务（如 DeepL）
+type UsageReporter interface {
+	Usage() (Usage, error)
+}
+
+// Usage 当前计费周期的用量
+type Usage struct {
+	CharacterCount int64 `json:"character_count"` // 已翻译的字符数
+	CharacterLimit int64 `json:"character_limit"` // 字符数上限，0 为不限
+}
+
+// Remaining 剩余的字符数，不限时返回 -1
+func (u Usage) Remaining() int64 {
+	if u.CharacterLimit <= 0 {
+		return -1
+	}
+	return max(u.CharacterLimit-u.CharacterCount, 0)
+}
+
+// Settings 一个翻译服务的设置，各服务只使用其中的部分字段（见 Info.Fields）
+type Settings struct {
+	SecretID  string `json:"secret_id,omitempty"`  // 腾讯云 SecretId 等成对凭证中的 ID
+	SecretKey string `json:"secret_key,omitempty"` // 成对凭证中的密钥
+	APIKey    string `json:"api_key,omitempty"`    // 单一 API 密钥
+	Endpoint  string `json:"endpoint,omitempty"`   // 自定义服务地址
+	Model     string `json:"model,omitempty"`      // 模型名称
+
+	Prompt      string   `json:"prompt,omitempty"`      // 系统提示词模板
+	Temperature *float64 `json:"temperature,omitempty"` // 采样温度，为空时使用服务的默认值
+	Stream      bool     `json:"stream,omitempty"`      // 使用流式输出
+
+	Formality   string `json:"formality,omitempty"`    // 正式程度（DeepL）
+	TagHandling string `json:"tag_handling,omitempty"` // 按 XML / HTML 标签处理文本（DeepL）
+	Glossary    string `json:"glossary,omitempty"`     // 术语表 ID（DeepL）
+}
+
+// Field 翻译服务需要填写的一项设置
+type Field struct {
+	Key         string   `json:"key"`   // Settings 中的 JSON 字段名
+	Label       string   `json:"label"` // 设置界面显示的名称
+	Placeholder string   `json:"placeholder,omitempty"`
+	Type        string   `json:"type,omitempty"`     // 输入方式：text（默认）、number、checkbox、textarea、select
+	Options     []Option `json:"options,omitempty"`  // select 的选项
+	Secret      bool     `json:"secret,omitempty"`   // 以密码框显示
+	Optional    bool     `json:"optional,omitempty"` // 可以不填
+}
+
+// Option 下拉框的一个选项
+type Option struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+}
+
+// Info 翻译服务的说明，设置界面据此显示需要填写的设置
+type Info struct {
+	Name   string  `json:"name"`           // 配置中使用的名称
+	Label  string  `json:"label"`          // 显示名称
+	Fields []Field `json:"fields"`         // 需要填写的设置
+	Link   string  `json:"link,omitempty"` // 获取密钥的页面
+}
+
+// Factory 按设置创建翻译服务
+type Factory func(settings Settings) Provider
+
+// registration 已注册的翻译服务
+type registration struct {
+	info    Info
+	factory Factory
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = make(map[string]registration)
+)
+
+// DefaultProvider 默认的翻译服务
+const DefaultProvider = "tencent"
+
+// Register 注册翻译服务（各服务在 init 中调用）
+func Register(info Info, factory Factory) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[info.Name] = registration{info: info, factory: factory}
+}
+
+// New 按名称和设置创建翻译服务
+func New(name string, settings Settings) (Provider, error) {
+	providersMu.RLock()
+	reg, ok := providers[name]
+	providersMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("未知的翻译服务: %s", name)
+	}
+	return reg.factory(settings), nil
+}
+
+// Providers 已注册的翻译服务（默认服务在前，其余按名称排序）
+func Providers() []Info {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+
+	infos := make([]Info, 0, len(providers))
+	for _, reg := range providers {
+		infos = append(infos, reg.info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if (infos[i].Name == DefaultProvider) != (infos[j].Name == DefaultProvider) {
+			return infos[i].Name == DefaultProvider
+		}
+		return infos[i].Name < infos[j].Name
+	})
+	return infos
+}
+
+// Names 已注册的翻译服务名称（顺序与 Providers 一致）
+func Names() []string {
+	infos := Providers()
+	names := make([]string, len(infos))
+	for i, info := range infos {
+		names[i] = info.Name
+	}
+	return names
+}
+
+// supportsPair 按语言列表判断是否支持从 source 翻译到 target
+func supportsPair(languages []string, source, target string) bool {
+	if source == target || target == "auto" {
+		return false
+	}
+	hasSource, hasTarget := source == "auto", false
+	for _, lang := range languages {
+		hasSource = hasSource || lang == source
+		hasTarget = hasTarget || lang == target
+	}
+	return hasSource && hasTarget
+}
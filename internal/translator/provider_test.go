@@ -0,0 +1,83 @@
+package translator
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	if _, err := New("bing", Settings{}); err == nil || err.Error() != "未知的翻译服务: bing" {
+		t.Errorf("未知的服务: %v", err)
+	}
+	if _, err := New("", Settings{}); err == nil {
+		t.Error("服务名称为空时应当报错")
+	}
+
+	// 默认服务已注册，设置齐全时可用
+	p, err := New(DefaultProvider, Settings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := p.(*TencentTranslator); !ok || p.IsConfigured() {
+		t.Errorf("默认服务 %T，未填写密钥时 IsConfigured = %v", p, p.IsConfigured())
+	}
+	if p, _ := New(DefaultProvider, Settings{SecretID: "AKIDtest", SecretKey: "key"}); !p.IsConfigured() {
+		t.Error("填写密钥后默认服务应当可用")
+	}
+
+	// 每个已注册的服务都能创建，Fields 中的设置都是 Settings 的字段
+	keys := map[string]bool{
+		"secret_id": true, "secret_key": true, "api_key": true, "endpoint": true, "model": true,
+		"prompt": true, "temperature": true, "stream": true, "formality": true, "tag_handling": true, "glossary": true,
+	}
+	for _, info := range Providers() {
+		if p, err := New(info.Name, Settings{}); err != nil || p == nil {
+			t.Errorf("创建 %s: %v", info.Name, err)
+		}
+		if info.Label == "" {
+			t.Errorf("%s 没有显示名称", info.Name)
+		}
+		for _, f := range info.Fields {
+			if !keys[f.Key] {
+				t.Errorf("%s 的设置 %q 不是 Settings 的字段", info.Name, f.Key)
+			}
+		}
+	}
+}
+
+func TestProviders(t *testing.T) {
+	names := Names()
+	for _, want := range []string{"tencent", "baidu", "youdao", "deepl", "libretranslate", "openai"} {
+		found := false
+		for _, name := range names {
+			found = found || name == want
+		}
+		if !found {
+			t.Errorf("%s 没有注册，已注册 %v", want, names)
+		}
+	}
+	// 默认服务在前，其余按名称排序
+	if len(names) == 0 || names[0] != DefaultProvider || !sort.StringsAreSorted(names[1:]) {
+		t.Errorf("顺序 %v", names)
+	}
+
+	// 注册同名服务时替换原来的注册
+	Register(Info{Name: "zz-test", Label: "测试"}, func(Settings) Provider { return nil })
+	Register(Info{Name: "zz-test", Label: "测试 2"}, func(Settings) Provider { return NewTencentTranslator("", "") })
+	t.Cleanup(func() {
+		providersMu.Lock()
+		delete(providers, "zz-test")
+		providersMu.Unlock()
+	})
+	infos := Providers()
+	if last := infos[len(infos)-1]; last.Name != "zz-test" || last.Label != "测试 2" || len(infos) != len(names)+1 {
+		t.Errorf("重复注册后 %+v", infos)
+	}
+	if p, err := New("zz-test", Settings{}); err != nil || p == nil {
+		t.Errorf("New(zz-test) = %v, %v", p, err)
+	}
+	if strings.Join(Names(), ",") != strings.Join(append(names, "zz-test"), ",") {
+		t.Errorf("Names %v", Names())
+	}
+}
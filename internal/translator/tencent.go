@@ -47,6 +47,44 @@ type TranslateResponse struct {
 	} `json:"Response"`
 }
 
+// LanguageDetectRequest 语种识别请求
+type LanguageDetectRequest struct {
+	Text      string `json:"Text"`
+	ProjectId int    `json:"ProjectId"`
+}
+
+// LanguageDetectResponse 语种识别响应
+type LanguageDetectResponse struct {
+	Response struct {
+		Lang      string `json:"Lang"`
+		RequestId string `json:"RequestId"`
+		Error     *struct {
+			Code    string `json:"Code"`
+			Message string `json:"Message"`
+		} `json:"Error"`
+	} `json:"Response"`
+}
+
+// tencentLanguages 腾讯云文本翻译支持的语言
+var tencentLanguages = []string{
+	"zh", "zh-TW", "en", "ja", "ko", "fr", "es", "it", "de", "tr",
+	"ru", "pt", "vi", "id", "th", "ms", "ar", "hi",
+}
+
+func init() {
+	Register(Info{
+		Name:  "tencent",
+		Label: "腾讯云翻译",
+		Fields: []Field{
+			{Key: "secret_id", Label: "SecretId", Placeholder: "请输入 SecretId"},
+			{Key: "secret_key", Label: "SecretKey", Placeholder: "请输入 SecretKey", Secret: true},
+		},
+		Link: "https://console.cloud.tencent.com/cam/capi",
+	}, func(s Settings) Provider {
+		return NewTencentTranslator(s.SecretID, s.SecretKey)
+	})
+}
+
 // NewTencentTranslator 创建翻译器
 func NewTencentTranslator(secretID, secretKey string) *TencentTranslator {
 	return &TencentTranslator{
@@ -93,23 +131,53 @@ func maskString(s string) string {
 
 // Translate 翻译文本
 func (t *TencentTranslator) Translate(text, source, target string) (string, error) {
-	// 获取凭证（带锁）
-	secretID, secretKey := t.getCredentials()
-	if secretID == "" || secretKey == "" {
-		return "", fmt.Errorf("翻译 API 未配置")
-	}
-
-	// 准备请求
-	reqBody := TranslateRequest{
+	var result TranslateResponse
+	err := t.call("TextTranslate", TranslateRequest{
 		SourceText: text,
 		Source:     source,
 		Target:     target,
 		ProjectId:  0,
+	}, &result)
+	if err != nil {
+		return "", err
+	}
+
+	// 检查错误
+	if result.Response.Error != nil {
+		return "", fmt.Errorf("%s: %s", result.Response.Error.Code, result.Response.Error.Message)
+	}
+
+	return result.Response.TargetText, nil
+}
+
+// Detect 识别文本的语种
+func (t *TencentTranslator) Detect(text string) (string, error) {
+	var result LanguageDetectResponse
+	if err := t.call("LanguageDetect", LanguageDetectRequest{Text: text}, &result); err != nil {
+		return "", err
+	}
+	if result.Response.Error != nil {
+		return "", fmt.Errorf("%s: %s", result.Response.Error.Code, result.Response.Error.Message)
+	}
+	return result.Response.Lang, nil
+}
+
+// Supports 是否支持从 source 翻译到 target
+func (t *TencentTranslator) Supports(source, target string) bool {
+	return supportsPair(tencentLanguages, source, target)
+}
+
+// call 签名并调用腾讯云 API，把响应解析到 out
+func (t *TencentTranslator) call(action string, reqBody any, out any) error {
+	// 获取凭证（带锁）
+	secretID, secretKey := t.getCredentials()
+	if secretID == "" || secretKey == "" {
+		return fmt.Errorf("翻译 API 未配置")
 	}
 
 	bodyBytes, err := json.Marshal(reqBody)
 	if err != nil {
-		return "", fmt.Errorf("序列化请求失败: %w", err)
+		return fmt.Errorf("序列化请求失败: %w", err)
 	}
 
 	// 生成签名（使用本地凭证副本，避免并发问题）
@@ -120,12 +188,12 @@ func (t *TencentTranslator) Translate(text, source, target string) (string, erro
 	// 发送请求
 	req, err := http.NewRequest("POST", fmt.Sprintf("https://%s", t.endpoint), bytes.NewReader(bodyBytes))
 	if err != nil {
-		return "", fmt.Errorf("创建请求失败: %w", err)
+		return fmt.Errorf("创建请求失败: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Set("Host", t.endpoint)
-	req.Header.Set("X-TC-Action", "TextTranslate")
+	req.Header.Set("X-TC-Action", action)
 	req.Header.Set("X-TC-Version", t.version)
 	req.Header.Set("X-TC-Timestamp", fmt.Sprintf("%d", timestamp))
 	req.Header.Set("X-TC-Region", t.region)
@@ -134,28 +202,21 @@ func (t *TencentTranslator) Translate(text, source, target string) (string, erro
 	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("请求失败: %w", err)
+		return fmt.Errorf("请求失败: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// 读取响应
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("读取响应失败: %w", err)
+		return fmt.Errorf("读取响应失败: %w", err)
 	}
 
 	// 解析响应
-	var result TranslateResponse
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return "", fmt.Errorf("解析响应失败: %w", err)
-	}
-
-	// 检查错误
-	if result.Response.Error != nil {
-		return "", fmt.Errorf("%s: %s", result.Response.Error.Code, result.Response.Error.Message)
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("解析响应失败: %w", err)
 	}
-
-	return result.Response.TargetText, nil
+	return nil
 }
 
 // signWithCredentials 生成 TC3-HMAC-SHA256 签名（使用传入的凭证）
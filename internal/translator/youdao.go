@@ -0,0 +1,205 @@
+package translator
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultYoudaoEndpoint 有道智云文本翻译的服务地址
+const DefaultYoudaoEndpoint = "https://openapi.youdao.com/api"
+
+// YoudaoTranslator 有道智云文本翻译（v3 签名）
+type YoudaoTranslator struct {
+	endpoint string
+	appKey   string
+	secret   string
+	client   *http.Client
+}
+
+// youdaoResponse 文本翻译响应；L 为 "源语言2目标语言"，如 en2zh-CHS
+type youdaoResponse struct {
+	ErrorCode   string   `json:"errorCode"`
+	Translation []string `json:"translation"`
+	L           string   `json:"l"`
+}
+
+// youdaoErrors 错误码说明（见有道智云文档）
+var youdaoErrors = map[string]string{
+	"101": "缺少必填的参数",
+	"102": "不支持的语言类型",
+	"103": "翻译文本过长",
+	"108": "应用 ID 无效，请检查应用 ID 是否正确",
+	"110": "没有绑定文本翻译服务的有效应用，请在控制台为应用绑定服务",
+	"111": "开发者账号无效",
+	"113": "翻译文本不能为空",
+	"202": "签名检验失败，请检查应用密钥是否正确",
+	"203": "访问 IP 不在可访问 IP 列表中",
+	"206": "时间戳无效，请检查系统时间是否准确",
+	"207": "重放请求",
+	"401": "账户已欠费",
+	"411": "访问频率受限，请稍后访问",
+	"412": "长请求过于频繁，请稍后访问",
+}
+
+// youdaoCodes 本程序的语言代码与有道语言代码不同的部分
+var youdaoCodes = map[string]string{
+	"zh":    "zh-CHS",
+	"zh-TW": "zh-CHT",
+}
+
+// youdaoLanguages 有道文本翻译支持的语言（本程序的语言代码）
+var youdaoLanguages = []string{
+	"zh", "zh-TW", "en", "ja", "ko", "fr", "es", "pt", "it", "ru",
+	"vi", "de", "ar", "id", "th", "ms", "hi", "tr", "nl",
+}
+
+func init() {
+	Register(Info{
+		Name:  "youdao",
+		Label: "有道智云翻译",
+		Fields: []Field{
+			{Key: "secret_id", Label: "应用 ID", Placeholder: "请输入应用 ID"},
+			{Key: "secret_key", Label: "应用密钥", Placeholder: "请输入应用密钥", Secret: true},
+		},
+		Link: "https://ai.youdao.com/console/",
+	}, func(s Settings) Provider {
+		return NewYoudaoTranslator(s)
+	})
+}
+
+// NewYoudaoTranslator 创建有道翻译器，SecretID 为应用 ID，SecretKey 为应用密钥
+func NewYoudaoTranslator(s Settings) *YoudaoTranslator {
+	endpoint := strings.TrimRight(strings.TrimSpace(s.Endpoint), "/")
+	if endpoint == "" {
+		endpoint = DefaultYoudaoEndpoint
+	}
+	return &YoudaoTranslator{
+		endpoint: endpoint,
+		appKey:   strings.TrimSpace(s.SecretID),
+		secret:   strings.TrimSpace(s.SecretKey),
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// IsConfigured 检查是否已配置
+func (t *YoudaoTranslator) IsConfigured() bool {
+	return t.appKey != "" && t.secret != ""
+}
+
+// youdaoSign 有道 v3 签名：SHA256(应用ID + input + salt + curtime + 应用密钥) 的十六进制，
+// input 在 q 不超过 20 个字符时为 q，否则为 q 的前 10 个字符 + q 的字符数 + q 的后 10 个字符
+func youdaoSign(appKey, q, salt, curtime, secret string) string {
+	input := q
+	if n := utf8.RuneCountInString(q); n > 20 {
+		runes := []rune(q)
+		input = string(runes[:10]) + strconv.Itoa(n) + string(runes[n-10:])
+	}
+	sum := sha256.Sum256([]byte(appKey + input + salt + curtime + secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// Translate 翻译文本
+func (t *YoudaoTranslator) Translate(text, source, target string) (string, error) {
+	result, err := t.translate(text, source, target)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(result.Translation, "\n"), nil
+}
+
+// Detect 识别文本的语言：有道没有单独的语种识别接口，自动检测源语言翻译一次后取响应中的源语言
+func (t *YoudaoTranslator) Detect(text string) (string, error) {
+	result, err := t.translate(text, "auto", "en")
+	if err != nil {
+		return "", err
+	}
+	source, _, ok := strings.Cut(result.L, "2")
+	if !ok || source == "" {
+		return "", fmt.Errorf("有道翻译未返回源语言")
+	}
+	for app, code := range youdaoCodes {
+		if code == source {
+			return app, nil
+		}
+	}
+	return source, nil
+}
+
+// Supports 是否支持从 source 翻译到 target
+func (t *YoudaoTranslator) Supports(source, target string) bool {
+	return supportsPair(youdaoLanguages, source, target)
+}
+
+// translate 签名并以表单 POST 调用文本翻译 API
+func (t *YoudaoTranslator) translate(text, source, target string) (*youdaoResponse, error) {
+	if !t.IsConfigured() {
+		return nil, fmt.Errorf("有道翻译未配置")
+	}
+
+	salt := newSalt()
+	curtime := strconv.FormatInt(time.Now().Unix(), 10)
+	params := url.Values{
+		"q":        {text},
+		"from":     {youdaoLang(source)},
+		"to":       {youdaoLang(target)},
+		"appKey":   {t.appKey},
+		"salt":     {salt},
+		"curtime":  {curtime},
+		"signType": {"v3"},
+		"sign":     {youdaoSign(t.appKey, text, salt, curtime, t.secret)},
+	}
+
+	resp, err := t.client.PostForm(t.endpoint, params)
+	if err != nil {
+		return nil, fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("有道翻译 %s", resp.Status)
+	}
+
+	var result youdaoResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("解析响应失败: %w", err)
+	}
+	if result.ErrorCode != "0" {
+		if desc, ok := youdaoErrors[result.ErrorCode]; ok {
+			return nil, fmt.Errorf("有道翻译 %s: %s", result.ErrorCode, desc)
+		}
+		return nil, fmt.Errorf("有道翻译错误码 %s", result.ErrorCode)
+	}
+	return &result, nil
+}
+
+// youdaoLang 把本程序的语言代码换成有道的写法（如 zh → zh-CHS）
+func youdaoLang(code string) string {
+	if youdao, ok := youdaoCodes[code]; ok {
+		return youdao
+	}
+	return code
+}
+
+// newSalt 随机的 UUID 格式字符串，用作签名的 salt
+func newSalt() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	h := hex.EncodeToString(b[:])
+	return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
@@ -0,0 +1,163 @@
+package translator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newYoudaoServer 模拟的有道智云服务：独立计算 v3 签名并核对 curtime，q 为错误码时返回该错误码
+func newYoudaoServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		f := r.PostForm
+		q := []rune(f.Get("q"))
+		input := string(q)
+		if len(q) > 20 {
+			input = string(q[:10]) + strconv.Itoa(len(q)) + string(q[len(q)-10:])
+		}
+		sum := sha256.Sum256([]byte(f.Get("appKey") + input + f.Get("salt") + f.Get("curtime") + stubSecret))
+		curtime, _ := strconv.ParseInt(f.Get("curtime"), 10, 64)
+
+		code := "0"
+		switch {
+		case f.Get("signType") != "v3" || f.Get("salt") == "":
+			code = "101"
+		case f.Get("appKey") != stubAppID:
+			code = "108"
+		case f.Get("sign") != hex.EncodeToString(sum[:]):
+			code = "202"
+		case time.Since(time.Unix(curtime, 0)).Abs() > 5*time.Minute:
+			code = "206"
+		case strings.HasPrefix(string(q), "code:"):
+			code = strings.TrimPrefix(string(q), "code:")
+		}
+		if code != "0" {
+			fmt.Fprintf(w, `{"errorCode":%q}`, code)
+			return
+		}
+		from := f.Get("from")
+		if from == "auto" {
+			from = "zh-CHT"
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"errorCode":   "0",
+			"query":       string(q),
+			"translation": []string{fmt.Sprintf("[%s>%s] %s", f.Get("from"), f.Get("to"), string(q))},
+			"l":           from + "2" + f.Get("to"),
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// youdaoLong 29 个字符的长文本，签名时 input = "屏幕文字识别工具可以" + "29" + "出来并翻译成其他语言"
+const youdaoLong = "屏幕文字识别工具可以把截图中的文字复制出来并翻译成其他语言"
+
+func TestYoudaoSign(t *testing.T) {
+	const (
+		appKey  = "2b2a4b5c6d7e8f90"
+		salt    = "a1b2c3d4-0000-4000-8000-1234567890ab"
+		curtime = "1700000000"
+		secret  = "secretABC123"
+	)
+	// 按文档公式 SHA256(应用ID + input + salt + curtime + 应用密钥) 独立计算的结果
+	cases := []struct {
+		name, q, want string
+	}{
+		{"short", "apple", "7f4629cef1d7213b31669bde0ab34b79ca49fff3565b3b123478f98bd0652a55"},
+		{"long-truncated-input", youdaoLong, "1908614e4ae6301a80f72375f2d61f3787a129e8f36253fcf1f594062d7261cf"},
+		// 正好 20 个字符时不截断
+		{"20-runes", strings.Repeat("字", 20), sha256Hex([]byte(appKey + strings.Repeat("字", 20) + salt + curtime + secret))},
+		{"21-runes", strings.Repeat("字", 21), sha256Hex([]byte(appKey + strings.Repeat("字", 10) + "21" + strings.Repeat("字", 10) + salt + curtime + secret))},
+	}
+	for _, c := range cases {
+		if got := youdaoSign(appKey, c.q, salt, curtime, secret); got != c.want {
+			t.Errorf("%s: youdaoSign = %s，期望 %s", c.name, got, c.want)
+		}
+	}
+}
+
+func TestYoudaoSalt(t *testing.T) {
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	if a, b := newSalt(), newSalt(); !uuid.MatchString(a) || a == b {
+		t.Errorf("newSalt = %q, %q", a, b)
+	}
+}
+
+func TestYoudaoTranslate(t *testing.T) {
+	server := newYoudaoServer(t)
+	tr, err := New("youdao", Settings{SecretID: stubAppID, SecretKey: stubSecret, Endpoint: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		text, source, target string
+		want                 string
+	}{
+		// 长文本签名截断 input，语言代码换成有道的写法
+		{youdaoLong, "zh", "en", "[zh-CHS>en] " + youdaoLong},
+		{"Save", "en", "zh-TW", "[en>zh-CHT] Save"},
+		{"保存", "auto", "ja", "[auto>ja] 保存"},
+	}
+	for _, c := range cases {
+		got, err := tr.Translate(c.text, c.source, c.target)
+		if err != nil || got != c.want {
+			t.Errorf("Translate(%q, %s, %s) = %q, %v，期望 %q", c.text, c.source, c.target, got, err, c.want)
+		}
+	}
+
+	// 从响应的 l 中取源语言，换回本程序的写法
+	if got, err := tr.Detect("儲存變更"); err != nil || got != "zh-TW" {
+		t.Errorf("Detect = %q, %v，期望 zh-TW", got, err)
+	}
+}
+
+func TestYoudaoErrors(t *testing.T) {
+	server := newYoudaoServer(t)
+	cases := []struct {
+		name     string
+		settings Settings
+		text     string
+		want     string
+	}{
+		{"wrong-secret", Settings{SecretID: stubAppID, SecretKey: "wrong"}, "hello", "有道翻译 202: 签名检验失败，请检查应用密钥是否正确"},
+		{"wrong-app-id", Settings{SecretID: "other", SecretKey: stubSecret}, "hello", "有道翻译 108: 应用 ID 无效"},
+		{"rate-limit", Settings{SecretID: stubAppID, SecretKey: stubSecret}, "code:411", "有道翻译 411: 访问频率受限"},
+		{"timestamp", Settings{SecretID: stubAppID, SecretKey: stubSecret}, "code:206", "有道翻译 206: 时间戳无效"},
+		{"unknown-code", Settings{SecretID: stubAppID, SecretKey: stubSecret}, "code:999", "有道翻译错误码 999"},
+		{"not-configured", Settings{SecretKey: stubSecret}, "hello", "有道翻译未配置"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.settings.Endpoint = server.URL
+			_, err := NewYoudaoTranslator(c.settings).Translate(c.text, "en", "zh")
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Errorf("错误 %v，应包含 %q", err, c.want)
+			}
+		})
+	}
+}
+
+func TestYoudaoSupports(t *testing.T) {
+	tr := NewYoudaoTranslator(Settings{})
+	cases := []struct {
+		source, target string
+		want           bool
+	}{
+		{"auto", "zh", true}, {"zh-TW", "nl", true}, {"en", "el", false}, {"en", "en", false},
+	}
+	for _, c := range cases {
+		if got := tr.Supports(c.source, c.target); got != c.want {
+			t.Errorf("Supports(%s, %s) = %v，期望 %v", c.source, c.target, got, c.want)
+		}
+	}
+}
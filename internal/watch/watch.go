@@ -0,0 +1,295 @@
+// Package watch 监视目录中新出现的截图，自动识别并把文字写入同名 .txt 文件
+//
+// 采用轮询而不是系统文件通知：截图工具通常先创建文件再分多次写入，
+// 只有文件大小和修改时间保持不变超过 Settle 时长后才会处理，避免读到写了一半的图片。
+package watch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"screenocr-wails/internal/batch"
+	"screenocr-wails/internal/ocr"
+	"screenocr-wails/internal/redact"
+)
+
+// Options 监视选项
+type Options struct {
+	Interval        time.Duration    // 轮询间隔，默认 500ms
+	Settle          time.Duration    // 文件保持不变多久后才处理，默认 1s
+	Retries         int              // 解码失败（文件可能仍在写入）时的重试次数，默认 3
+	Preprocess      bool             // 启用图像预处理
+	ProcessExisting bool             // 启动时也处理目录中已有且没有 .txt 的图片
+	Redactor        *redact.Redactor // 写入 .txt 和回调前打码敏感内容，为 nil 时不打码
+}
+
+// Result 一张图片的处理结果
+type Result struct {
+	Path     string          // 图片路径
+	Sidecar  string          // 写入的 .txt 路径
+	Text     string          // 按阅读顺序拼接的文字
+	Blocks   []ocr.TextBlock // 文本块
+	Err      error           // 失败原因
+	Duration time.Duration   // 识别耗时
+}
+
+// fileState 文件的观察状态
+type fileState struct {
+	size     int64
+	modTime  time.Time
+	since    time.Time // 当前大小和修改时间首次被观察到的时间
+	attempts int
+	done     bool
+}
+
+// Watcher 目录监视器
+type Watcher struct {
+	OnResult func(Result) // 每处理完一张图片回调一次
+
+	engine ocr.Engine
+	dirs   []string
+	opts   Options
+
+	mu       sync.Mutex
+	files    map[string]*fileState
+	started  bool
+	stopChan chan struct{}
+	doneChan chan struct{}
+}
+
+// NewWatcher 创建目录监视器
+func NewWatcher(engine ocr.Engine, dirs []string, opts Options) *Watcher {
+	if opts.Interval <= 0 {
+		opts.Interval = 500 * time.Millisecond
+	}
+	if opts.Settle <= 0 {
+		opts.Settle = time.Second
+	}
+	if opts.Retries <= 0 {
+		opts.Retries = 3
+	}
+	return &Watcher{
+		engine: engine,
+		dirs:   dirs,
+		opts:   opts,
+		files:  make(map[string]*fileState),
+	}
+}
+
+// Start 开始监视：记录目录中已有的文件，之后只处理新出现或被修改的图片
+func (w *Watcher) Start() {
+	w.mu.Lock()
+	if w.started {
+		w.mu.Unlock()
+		return
+	}
+	w.started = true
+	w.stopChan = make(chan struct{})
+	w.doneChan = make(chan struct{})
+	w.mu.Unlock()
+
+	if !w.opts.ProcessExisting {
+		w.markExisting()
+	}
+
+	go func() {
+		defer close(w.doneChan)
+		ticker := time.NewTicker(w.opts.Interval)
+		defer ticker.Stop()
+
+		w.Poll()
+		for {
+			select {
+			case <-ticker.C:
+				w.Poll()
+			case <-w.stopChan:
+				return
+			}
+		}
+	}()
+
+	fmt.Printf("[Watch] 开始监视 %d 个目录: %s\n", len(w.dirs), strings.Join(w.dirs, ", "))
+}
+
+// Stop 停止监视（等待正在进行的识别完成）
+func (w *Watcher) Stop() {
+	w.mu.Lock()
+	if !w.started {
+		w.mu.Unlock()
+		return
+	}
+	w.started = false
+	close(w.stopChan)
+	w.mu.Unlock()
+
+	<-w.doneChan
+	fmt.Println("[Watch] 已停止监视")
+}
+
+// markExisting 把目录中已有的图片标记为已处理
+func (w *Watcher) markExisting() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, path := range w.list() {
+		if info, err := os.Stat(path); err == nil {
+			w.files[path] = &fileState{size: info.Size(), modTime: info.ModTime(), done: true}
+		}
+	}
+}
+
+// list 列出所有监视目录中的图片
+func (w *Watcher) list() []string {
+	var paths []string
+	for _, dir := range w.dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			// 跳过隐藏文件和临时文件
+			if entry.IsDir() || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "~") {
+				continue
+			}
+			if batch.IsImage(name) {
+				paths = append(paths, filepath.Join(dir, name))
+			}
+		}
+	}
+	return paths
+}
+
+// Poll 扫描一次目录，处理已经写入完成的新图片（Start 会定期调用）
+func (w *Watcher) Poll() {
+	now := time.Now()
+	var ready []string
+
+	w.mu.Lock()
+	present := make(map[string]bool)
+	for _, path := range w.list() {
+		present[path] = true
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+
+		state, ok := w.files[path]
+		if !ok {
+			state = &fileState{size: info.Size(), modTime: info.ModTime(), since: now}
+			w.files[path] = state
+		}
+		if state.size != info.Size() || !state.modTime.Equal(info.ModTime()) {
+			// 文件仍在变化（或被覆盖），重新计时
+			state.size, state.modTime, state.since = info.Size(), info.ModTime(), now
+			state.done = false
+			state.attempts = 0
+		}
+		if state.done || state.size == 0 || now.Sub(state.since) < w.opts.Settle {
+			continue
+		}
+		ready = append(ready, path)
+	}
+	// 已删除的文件不再跟踪
+	for path := range w.files {
+		if !present[path] {
+			delete(w.files, path)
+		}
+	}
+	w.mu.Unlock()
+
+	for _, path := range ready {
+		w.process(path)
+	}
+}
+
+// process 识别一张图片并写入 .txt
+func (w *Watcher) process(path string) {
+	sidecar := SidecarPath(path, "")
+	if _, err := os.Stat(sidecar); err == nil {
+		w.finish(path)
+		return
+	}
+
+	img, err := batch.LoadImage(path)
+	if err != nil {
+		w.mu.Lock()
+		state := w.files[path]
+		retry := state != nil && state.attempts < w.opts.Retries
+		if retry {
+			// 可能还没写完，等待下一个 Settle 周期再试
+			state.attempts++
+			state.since = time.Now()
+		}
+		w.mu.Unlock()
+		if !retry {
+			w.finish(path)
+			w.report(Result{Path: path, Err: err})
+		}
+		return
+	}
+
+	start := time.Now()
+	blocks, err := w.engine.Recognize(img, w.opts.Preprocess)
+	w.finish(path)
+
+	result := Result{Path: path, Blocks: blocks, Err: err, Duration: time.Since(start)}
+	if err == nil {
+		result.Blocks, _ = w.opts.Redactor.Blocks(blocks, "watch", path)
+		result.Text = ocr.JoinText(result.Blocks)
+		if err := WriteSidecar(path, "", result.Text); err != nil {
+			result.Err = err
+		} else {
+			result.Sidecar = sidecar
+		}
+	}
+	w.report(result)
+}
+
+// finish 标记文件已处理
+func (w *Watcher) finish(path string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if state, ok := w.files[path]; ok {
+		state.done = true
+	}
+}
+
+// report 输出日志并回调
+func (w *Watcher) report(r Result) {
+	if r.Err != nil {
+		fmt.Printf("[Watch] ❌ %s: %v\n", filepath.Base(r.Path), r.Err)
+	} else {
+		fmt.Printf("[Watch] ✓ %s: %d 个文本块 (%dms)\n", filepath.Base(r.Path), len(r.Blocks), r.Duration.Milliseconds())
+	}
+	if w.OnResult != nil {
+		w.OnResult(r)
+	}
+}
+
+// SidecarPath 返回图片对应的文本文件路径：name.txt，suffix 非空时为 name.suffix.txt
+func SidecarPath(imagePath, suffix string) string {
+	base := strings.TrimSuffix(imagePath, filepath.Ext(imagePath))
+	if suffix != "" {
+		base += "." + suffix
+	}
+	return base + ".txt"
+}
+
+// WriteSidecar 写入图片对应的文本文件（先写临时文件再重命名）
+func WriteSidecar(imagePath, suffix, text string) error {
+	path := SidecarPath(imagePath, suffix)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(text), 0644); err != nil {
+		return fmt.Errorf("写入文本文件失败: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("写入文本文件失败: %w", err)
+	}
+	return nil
+}
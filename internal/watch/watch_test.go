@@ -0,0 +1,244 @@
+package watch
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"screenocr-wails/internal/ocr"
+	"screenocr-wails/internal/ocr/ocrtest"
+)
+
+const testSettle = 100 * time.Millisecond
+
+// pngBytes 编码一张小图片
+func pngBytes(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 64, 32))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func appendFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// recorder 收集 OnResult 回调
+type recorder struct {
+	mu      sync.Mutex
+	results []Result
+	ch      chan Result
+}
+
+func newRecorder(w *Watcher) *recorder {
+	r := &recorder{ch: make(chan Result, 16)}
+	w.OnResult = func(res Result) {
+		r.mu.Lock()
+		r.results = append(r.results, res)
+		r.mu.Unlock()
+		r.ch <- res
+	}
+	return r
+}
+
+func (r *recorder) all() []Result {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Result(nil), r.results...)
+}
+
+// settle 等待文件稳定后再扫描一次
+func settle(w *Watcher) {
+	time.Sleep(testSettle + 20*time.Millisecond)
+	w.Poll()
+}
+
+func TestWatcherPicksUpNewFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "existing.png"), pngBytes(t))
+
+	block := ocr.TextBlock{Text: "hello watch", X: 1, Y: 1, Width: 40, Height: 10}
+	engine := ocrtest.NewFakeEngine(block)
+	w := NewWatcher(engine, []string{dir}, Options{Interval: 10 * time.Millisecond, Settle: testSettle})
+	rec := newRecorder(w)
+	w.Start()
+	defer w.Stop()
+
+	path := filepath.Join(dir, "new.png")
+	writeFile(t, path, pngBytes(t))
+
+	select {
+	case res := <-rec.ch:
+		if res.Err != nil {
+			t.Fatalf("识别失败: %v", res.Err)
+		}
+		if res.Path != path || res.Sidecar != SidecarPath(path, "") {
+			t.Errorf("结果路径不正确: %+v", res)
+		}
+		data, err := os.ReadFile(res.Sidecar)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != "hello watch" {
+			t.Errorf(".txt 内容为 %q", data)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("新图片没有被处理")
+	}
+
+	// 启动前已有的图片不处理，已处理的图片不重复处理
+	time.Sleep(2 * testSettle)
+	if _, err := os.Stat(SidecarPath(filepath.Join(dir, "existing.png"), "")); !os.IsNotExist(err) {
+		t.Errorf("启动前已有的图片不应被处理")
+	}
+	if n := len(engine.Calls()); n != 1 {
+		t.Errorf("识别调用 %d 次，期望 1 次", n)
+	}
+}
+
+func TestWatcherProcessExisting(t *testing.T) {
+	dir := t.TempDir()
+	done := filepath.Join(dir, "done.png")
+	writeFile(t, done, pngBytes(t))
+	writeFile(t, SidecarPath(done, ""), []byte("已有结果"))
+	writeFile(t, filepath.Join(dir, "todo.png"), pngBytes(t))
+
+	engine := ocrtest.NewFakeEngine(ocr.TextBlock{Text: "x", Width: 1, Height: 1})
+	w := NewWatcher(engine, []string{dir}, Options{Settle: testSettle, ProcessExisting: true})
+	rec := newRecorder(w)
+	w.Poll()
+	settle(w)
+
+	results := rec.all()
+	if len(results) != 1 || filepath.Base(results[0].Path) != "todo.png" {
+		t.Fatalf("只应处理没有 .txt 的图片，实际 %+v", results)
+	}
+	if data, _ := os.ReadFile(SidecarPath(done, "")); string(data) != "已有结果" {
+		t.Errorf("已有的 .txt 被覆盖: %q", data)
+	}
+}
+
+func TestWatcherIgnoresOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	engine := ocrtest.NewFakeEngine(ocr.TextBlock{Text: "x", Width: 1, Height: 1})
+	w := NewWatcher(engine, []string{dir}, Options{Settle: testSettle})
+	rec := newRecorder(w)
+	w.markExisting()
+
+	data := pngBytes(t)
+	for _, name := range []string{"notes.txt", "report.pdf", "image.png.part", ".hidden.png", "~draft.png"} {
+		writeFile(t, filepath.Join(dir, name), data)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "folder.png"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "empty.png"), nil)
+
+	w.Poll()
+	settle(w)
+	settle(w)
+
+	if calls := engine.Calls(); len(calls) != 0 {
+		t.Errorf("不应识别非图片、隐藏、临时或空文件，实际调用 %d 次", len(calls))
+	}
+	if results := rec.all(); len(results) != 0 {
+		t.Errorf("不应产生结果: %+v", results)
+	}
+}
+
+func TestWatcherWaitsForPartialWrites(t *testing.T) {
+	dir := t.TempDir()
+	engine := ocrtest.NewFakeEngine(ocr.TextBlock{Text: "done", Width: 1, Height: 1})
+	w := NewWatcher(engine, []string{dir}, Options{Settle: testSettle})
+	rec := newRecorder(w)
+	w.markExisting()
+
+	data := pngBytes(t)
+	path := filepath.Join(dir, "shot.png")
+	third := len(data) / 3
+
+	// 分三次写入，每次间隔小于 Settle：文件一直在变化，不应处理
+	writeFile(t, path, data[:third])
+	w.Poll()
+	for _, chunk := range [][]byte{data[third : 2*third], data[2*third:]} {
+		time.Sleep(testSettle * 2 / 3)
+		w.Poll()
+		appendFile(t, path, chunk)
+		w.Poll()
+	}
+	time.Sleep(testSettle / 2)
+	w.Poll()
+	if n := len(engine.Calls()); n != 0 {
+		t.Fatalf("文件写入完成前就被识别了 %d 次", n)
+	}
+
+	// 保持不变超过 Settle 后才处理，且只处理一次
+	settle(w)
+	settle(w)
+	results := rec.all()
+	if len(results) != 1 || results[0].Err != nil {
+		t.Fatalf("期望一次成功的结果，实际 %+v", results)
+	}
+	if n := len(engine.Calls()); n != 1 {
+		t.Errorf("识别调用 %d 次，期望 1 次", n)
+	}
+}
+
+func TestWatcherRetriesUndecodableFile(t *testing.T) {
+	dir := t.TempDir()
+	engine := ocrtest.NewFakeEngine(ocr.TextBlock{Text: "ok", Width: 1, Height: 1})
+	w := NewWatcher(engine, []string{dir}, Options{Settle: testSettle, Retries: 2})
+	rec := newRecorder(w)
+	w.markExisting()
+
+	// 写入停顿超过 Settle 的半截图片：解码失败后重试，仍失败时报告错误
+	data := pngBytes(t)
+	path := filepath.Join(dir, "slow.png")
+	writeFile(t, path, data[:len(data)/2])
+	w.Poll()
+	for i := 0; i < 2; i++ {
+		settle(w)
+		if results := rec.all(); len(results) != 0 {
+			t.Fatalf("第 %d 次解码失败时应等待重试，实际已报告 %+v", i+1, results)
+		}
+	}
+	settle(w)
+	results := rec.all()
+	if len(results) != 1 || results[0].Err == nil {
+		t.Fatalf("重试用完后应报告解码错误，实际 %+v", results)
+	}
+	if n := len(engine.Calls()); n != 0 {
+		t.Errorf("解码失败的图片不应交给引擎，实际调用 %d 次", n)
+	}
+
+	// 文件写完（大小变化）后重新计时并处理
+	appendFile(t, path, data[len(data)/2:])
+	w.Poll()
+	settle(w)
+	results = rec.all()
+	if len(results) != 2 || results[1].Err != nil {
+		t.Fatalf("文件写完后应处理成功，实际 %+v", results)
+	}
+}
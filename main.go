@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"os"
 	"syscall"
 	"unsafe"
 
@@ -25,10 +26,37 @@ const (
 
 // Windows API
 var (
-	kernel32         = syscall.NewLazyDLL("kernel32.dll")
-	procCreateMutexW = kernel32.NewProc("CreateMutexW")
+	kernel32          = syscall.NewLazyDLL("kernel32.dll")
+	procCreateMutexW  = kernel32.NewProc("CreateMutexW")
+	procAttachConsole = kernel32.NewProc("AttachConsole")
 )
 
+// ATTACH_PARENT_PROCESS 附加到父进程的控制台
+const ATTACH_PARENT_PROCESS = ^uintptr(0) // (DWORD)-1
+
+// attachParentConsole 命令行模式下附加到启动程序的终端
+// 发布版以 GUI 子系统编译，没有控制台；输出被重定向到管道或文件时保持不变
+func attachParentConsole() {
+	if _, err := os.Stdout.Stat(); err == nil {
+		return
+	}
+
+	ret, _, _ := procAttachConsole.Call(ATTACH_PARENT_PROCESS)
+	if ret == 0 {
+		return
+	}
+
+	if out, err := os.OpenFile("CONOUT$", os.O_WRONLY, 0); err == nil {
+		os.Stdout = out
+		os.Stderr = out
+	}
+	if _, err := os.Stdin.Stat(); err != nil {
+		if in, err := os.OpenFile("CONIN$", os.O_RDONLY, 0); err == nil {
+			os.Stdin = in
+		}
+	}
+}
+
 // checkSingleInstance 检查是否已有实例运行
 func checkSingleInstance() (uintptr, bool) {
 	name, _ := syscall.UTF16PtrFromString(mutexName)
@@ -92,6 +120,11 @@ func startIPCServer() {
 }
 
 func main() {
+	// 命令行模式：screenocr recognize / translate / engines
+	if len(os.Args) > 1 && isCLICommand(os.Args[1]) {
+		os.Exit(runCLI(os.Args[1:]))
+	}
+
 	// 检查单实例
 	mutexHandle, isFirst := checkSingleInstance()
 	if !isFirst {